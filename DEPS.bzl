@@ -2464,6 +2464,16 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/dgryski/go-metro/com_github_dgryski_go_metro-v0.0.0-20180109044635-280f6062b5bc.zip",
         ],
     )
+    go_repository(
+        name = "com_github_dgryski_go_rendezvous",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/dgryski/go-rendezvous",
+        sha256 = "d222258b607d5fcacf09e84069607d8f18fba48b25ad191ec78d380d078e694f",
+        strip_prefix = "github.com/dgryski/go-rendezvous@v0.0.0-20200823014737-9f7001d12a5f",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/dgryski/go-rendezvous/com_github_dgryski_go_rendezvous-v0.0.0-20200823014737-9f7001d12a5f.zip",
+        ],
+    )
     go_repository(
         name = "com_github_dgryski_go_sip13",
         build_file_proto_mode = "disable_global",
@@ -2688,10 +2698,10 @@ def go_deps():
         name = "com_github_eclipse_paho_mqtt_golang",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/eclipse/paho.mqtt.golang",
-        sha256 = "d36337c4b5a2752b91bcd437bd74e0907bf6c9e6c611dab88407bcca8462e918",
-        strip_prefix = "github.com/eclipse/paho.mqtt.golang@v1.2.0",
+        sha256 = "042dfa5ccaae5ca66f3068038ab866dbbad66ed4386ef4a44864246ab81f3d99",
+        strip_prefix = "github.com/eclipse/paho.mqtt.golang@v1.4.3",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/eclipse/paho.mqtt.golang/com_github_eclipse_paho_mqtt_golang-v1.2.0.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/eclipse/paho.mqtt.golang/com_github_eclipse_paho_mqtt_golang-v1.4.3.zip",
         ],
     )
     go_repository(
@@ -4267,10 +4277,10 @@ def go_deps():
         name = "com_github_gorilla_websocket",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/gorilla/websocket",
-        sha256 = "d0d1728deaa06dac190bf4964c9c6395923403eae337cb3305d6dda18ef07337",
-        strip_prefix = "github.com/gorilla/websocket@v1.4.2",
+        sha256 = "690ea4d1ffe00ab5fcb6d63e2ec3783fc5a58e9d0f1789ea5dc9b6663deee6d5",
+        strip_prefix = "github.com/gorilla/websocket@v1.5.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/gorilla/websocket/com_github_gorilla_websocket-v1.4.2.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/gorilla/websocket/com_github_gorilla_websocket-v1.5.0.zip",
         ],
     )
     go_repository(
@@ -6856,10 +6866,10 @@ def go_deps():
         name = "com_github_nats_io_nats_go",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nats.go",
-        sha256 = "34a735d158d70685faad1fc3153f08da0ddc21c0ae42f6a0cb09430d638364b2",
-        strip_prefix = "github.com/nats-io/nats.go@v1.9.1",
+        sha256 = "3cb91adc6c85c2eb2cd55775bc9a857b74ec203cf52c78ff2a60f50a4593a907",
+        strip_prefix = "github.com/nats-io/nats.go@v1.37.0",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.9.1.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nats.go/com_github_nats_io_nats_go-v1.37.0.zip",
         ],
     )
     go_repository(
@@ -6876,10 +6886,10 @@ def go_deps():
         name = "com_github_nats_io_nkeys",
         build_file_proto_mode = "disable_global",
         importpath = "github.com/nats-io/nkeys",
-        sha256 = "291930a7abcd84edcaffc2cadc75aeb830ebf561144313ead6b8e1fcc03b124f",
-        strip_prefix = "github.com/nats-io/nkeys@v0.1.3",
+        sha256 = "b5ea0fc3e87853935f2903cd8222f6ad92944625b795ba3bf8c99c2cfc499b5b",
+        strip_prefix = "github.com/nats-io/nkeys@v0.4.7",
         urls = [
-            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.1.3.zip",
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/nats-io/nkeys/com_github_nats_io_nkeys-v0.4.7.zip",
         ],
     )
     go_repository(
@@ -7729,6 +7739,16 @@ def go_deps():
             "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/rcrowley/go-metrics/com_github_rcrowley_go_metrics-v0.0.0-20201227073835-cf1acfcdf475.zip",
         ],
     )
+    go_repository(
+        name = "com_github_redis_go_redis_v9",
+        build_file_proto_mode = "disable_global",
+        importpath = "github.com/redis/go-redis/v9",
+        sha256 = "3fa9f4814802f3cabfdf9f96519491f4c71e03eed708dda989f58cc7f47743fb",
+        strip_prefix = "github.com/redis/go-redis/v9@v9.6.1",
+        urls = [
+            "https://storage.googleapis.com/cockroach-godeps/gomod/github.com/redis/go-redis/v9/com_github_redis_go_redis_v9-v9.6.1.zip",
+        ],
+    )
     go_repository(
         name = "com_github_remyoudompheng_bigfft",
         build_file_proto_mode = "disable_global",
@@ -395,6 +395,12 @@ func processOptionsForArgs(inOpts tree.BackupOptions, outOpts *tree.BackupOption
 	if inOpts.UpdatesClusterMonitoringMetrics != nil {
 		outOpts.UpdatesClusterMonitoringMetrics = inOpts.UpdatesClusterMonitoringMetrics
 	}
+	if inOpts.Deduplicate != nil {
+		outOpts.Deduplicate = inOpts.Deduplicate
+	}
+	if inOpts.ObjectLockRetention != nil {
+		outOpts.ObjectLockRetention = inOpts.ObjectLockRetention
+	}
 	return nil
 }
 
@@ -481,6 +487,7 @@ func processFullBackupRecurrence(
 			s.fullArgs.UpdatesLastBackupMetric,
 			s.incStmt,
 			s.fullArgs.ChainProtectedTimestampRecords,
+			backupChainMaintenance{},
 		)
 
 		if err != nil {
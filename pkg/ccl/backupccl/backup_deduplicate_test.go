@@ -0,0 +1,111 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackupDeduplicate tests that a chain of backups taken with the
+// deduplicate option, with or without encryption, stores its data in the blobs
+// directory of its collection and can be restored, and that DROP BACKUP deletes
+// the blobs that are no longer referenced by any backup in the collection.
+func TestBackupDeduplicate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	for _, tc := range []struct {
+		name       string
+		collection string
+		opts       string
+	}{
+		{name: "plaintext", collection: "dedup", opts: "deduplicate"},
+		{
+			name:       "encrypted",
+			collection: "dedup-encrypted",
+			opts:       "deduplicate, encryption_passphrase = 'abc'",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			uri := "nodelocal://1/" + tc.collection
+			collectionDir := filepath.Join(dir, tc.collection)
+			restoreOpts := ""
+			if strings.Contains(tc.opts, "encryption_passphrase") {
+				restoreOpts = ", encryption_passphrase = 'abc'"
+			}
+			checkRestore := func(db string) {
+				sqlDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = $2`+restoreOpts, uri, db)
+				sqlDB.CheckQueryResults(t, `SELECT * FROM `+db+`.bank ORDER BY id`,
+					sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+				sqlDB.Exec(t, `DROP DATABASE `+db)
+			}
+
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH `+tc.opts, uri)
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id < 10`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1 WITH `+tc.opts, uri)
+			var dropped string
+			sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN $1]`, uri).Scan(&dropped)
+
+			// The data files of the chain are all stored in the blobs directory.
+			blobs, refs := listDeduplicatedBlobs(t, collectionDir)
+			require.NotEmpty(t, blobs)
+			require.Equal(t, blobs, refs)
+			checkRestore("restored")
+
+			// Dropping the first chain once a second one is taken deletes the blobs
+			// that only it referenced, and keeps those that the second one shares.
+			sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id >= 90`)
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH `+tc.opts, uri)
+			sqlDB.Exec(t, `DROP BACKUP $1 IN $2`, dropped, uri)
+			remaining, refs := listDeduplicatedBlobs(t, collectionDir)
+			require.NotEmpty(t, remaining)
+			require.Equal(t, remaining, refs)
+			checkRestore("restored")
+		})
+	}
+}
+
+// listDeduplicatedBlobs returns the hashes of the blobs stored in the
+// deduplicated blobs directory of the collection in collectionDir, and those
+// of the blobs referenced by its backups.
+func listDeduplicatedBlobs(
+	t *testing.T, collectionDir string,
+) (blobs map[string]struct{}, refs map[string]struct{}) {
+	blobs, refs = make(map[string]struct{}), make(map[string]struct{})
+	require.NoError(t, filepath.WalkDir(collectionDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Base(filepath.Dir(p)) {
+		case backupbase.DeduplicatedBlobsDirectory:
+			blobs[strings.TrimSuffix(d.Name(), ".sst")] = struct{}{}
+		case backupbase.DeduplicatedBlobRefsDirectory:
+			refs[d.Name()] = struct{}{}
+		default:
+			// Backups taken with the deduplicate option write no data files of
+			// their own.
+			require.False(t, strings.HasSuffix(p, ".sst") &&
+				filepath.Base(filepath.Dir(p)) == "data", "unexpected data file %s", p)
+		}
+		return nil
+	}))
+	return blobs, refs
+}
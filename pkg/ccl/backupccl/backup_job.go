@@ -123,6 +123,34 @@ func filterSpans(includes []roachpb.Span, excludes []roachpb.Span) []roachpb.Spa
 	return cov.Slice()
 }
 
+// deduplicatedBlobDir returns the path of the deduplicated blobs directory of
+// the collection of a backup taken with the deduplicate option, relative to the
+// destination of the backup. Both full and incremental backups are stored in
+// subdirectories of their collection unless incremental_location is used,
+// which is not allowed with the deduplicate option.
+func deduplicatedBlobDir(details jobspb.BackupDetails) (string, error) {
+	backupURI, err := url.Parse(details.URI)
+	if err != nil {
+		return "", err
+	}
+	collectionURI, err := url.Parse(details.CollectionURI)
+	if err != nil {
+		return "", err
+	}
+	collectionPath := strings.TrimSuffix(path.Clean("/"+collectionURI.Path), "/")
+	rel, ok := strings.CutPrefix(path.Clean("/"+backupURI.Path), collectionPath+"/")
+	if details.CollectionURI == "" || !ok ||
+		backupURI.Scheme != collectionURI.Scheme || backupURI.Host != collectionURI.Host {
+		return "", errors.Newf("the deduplicate option requires the backup to be stored in its collection %s",
+			backuputils.RedactURIForErrorMessage(details.CollectionURI))
+	}
+	var parents []string
+	for range strings.Split(rel, "/") {
+		parents = append(parents, "..")
+	}
+	return path.Join(append(parents, backupbase.DeduplicatedBlobsDirectory)...), nil
+}
+
 // backup exports a snapshot of every kv entry into ranged sstables.
 //
 // The output is an sstable per range with files in the following locations:
@@ -147,6 +175,8 @@ func backup(
 	encryption *jobspb.BackupEncryptionOptions,
 	statsCache *stats.TableStatisticsCache,
 	execLocality roachpb.Locality,
+	dedupBlobDir string,
+	objectLockRetention time.Duration,
 ) (_ roachpb.RowCount, numBackupInstances int, _ error) {
 	resumerSpan := tracing.SpanFromContext(ctx)
 	var lastCheckpoint time.Time
@@ -248,6 +278,8 @@ func backup(
 		backupManifest.EndTime,
 		backupManifest.ElidedPrefix,
 		backupManifest.ClusterVersion.AtLeast(clusterversion.V24_1.Version()),
+		dedupBlobDir,
+		objectLockRetention,
 	)
 	if err != nil {
 		return roachpb.RowCount{}, 0, err
@@ -562,6 +594,9 @@ func statShouldBeIncludedInBackupRestore(stat *stats.TableStatisticProto) bool {
 type backupResumer struct {
 	job         *jobs.Job
 	backupStats roachpb.RowCount
+	// verifyResult is the result of the job if it was created by VERIFY
+	// BACKUP.
+	verifyResult *verifyBackupResult
 
 	mu struct {
 		syncutil.Mutex
@@ -602,6 +637,16 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		return err
 	}
 
+	// Jobs created by COMPACT BACKUP read an existing backup chain rather than
+	// the cluster.
+	if details.Compaction != nil {
+		return b.compactBackupChain(ctx, p, details)
+	}
+	// Jobs created by VERIFY BACKUP only read an existing backup chain.
+	if details.Verification != nil {
+		return b.verifyBackupChain(ctx, p, details)
+	}
+
 	kmsEnv := backupencryption.MakeBackupKMSEnv(
 		p.ExecCfg().Settings,
 		&p.ExecCfg().ExternalIODirConfig,
@@ -642,6 +687,13 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 	// to re-check and re-write the lock file. In that case
 	// `details.URI` will non-empty.
 	if details.URI == "" && !foundLockFile {
+		if details.ObjectLockRetention > 0 {
+			if err := backupdest.CheckObjectLockRetention(ctx, backupDest, details.ObjectLockRetention,
+				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User()); err != nil {
+				return err
+			}
+		}
+
 		if err := backupinfo.CheckForPreviousBackup(ctx, p.ExecCfg(), backupDest.DefaultURI, b.job.ID(),
 			p.User()); err != nil {
 			return err
@@ -815,6 +867,14 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		}
 	}
 
+	var dedupBlobDir string
+	if details.Deduplicate {
+		dedupBlobDir, err = deduplicatedBlobDir(details)
+		if err != nil {
+			return err
+		}
+	}
+
 	statsCache := p.ExecCfg().TableStatsCache
 	// We retry on pretty generic failures -- any rpc error. If a worker node were
 	// to restart, it would produce this kind of error, but there may be other
@@ -838,6 +898,23 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 	var res roachpb.RowCount
 	var lastProgress float32
 	var numBackupInstances int
+	// The files that make up the backup are locked for the requested retention
+	// as they are written, unlike the checkpoints of its progress.
+	backupStore := defaultStore
+	makeExternalStorage := p.ExecCfg().DistSQLSrv.ExternalStorage
+	if details.ObjectLockRetention > 0 {
+		backupStore = backupdest.WithObjectLockRetention(defaultStore, details.ObjectLockRetention)
+		makeExternalStorage = func(
+			ctx context.Context, dest cloudpb.ExternalStorage, opts ...cloud.ExternalStorageOption,
+		) (cloud.ExternalStorage, error) {
+			store, err := p.ExecCfg().DistSQLSrv.ExternalStorage(ctx, dest, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return backupdest.WithObjectLockRetention(store, details.ObjectLockRetention), nil
+		}
+	}
+
 	for r := retry.StartWithCtx(ctx, retryOpts); r.Next(); {
 		res, numBackupInstances, err = backup(
 			ctx,
@@ -845,14 +922,16 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 			details.URI,
 			details.URIsByLocalityKV,
 			p.ExecCfg().Settings,
-			defaultStore,
+			backupStore,
 			storageByLocalityKV,
 			b,
 			backupManifest,
-			p.ExecCfg().DistSQLSrv.ExternalStorage,
+			makeExternalStorage,
 			details.EncryptionOptions,
 			statsCache,
 			details.ExecutionLocality,
+			dedupBlobDir,
+			details.ObjectLockRetention,
 		)
 		if err == nil {
 			break
@@ -963,6 +1042,16 @@ func (b *backupResumer) Resume(ctx context.Context, execCtx interface{}) error {
 		}
 	}
 
+	// A failure to start a compaction must not fail the backup, which has
+	// already been written; the next incremental backup will try again.
+	if err := maybeStartScheduledCompaction(ctx, p.ExecCfg(), details, p.User()); err != nil {
+		log.Warningf(ctx, "failed to start compaction of backup chain: %v", err)
+	}
+	// Expired chains are dropped in the background, since listing and deleting
+	// them can take a while and has no bearing on the backup. Chains that could
+	// not be dropped are retried after the next full backup of the schedule.
+	dropExpiredBackupsAsync(ctx, p.ExecCfg(), details, p.User())
+
 	b.backupStats = res
 
 	// Collect telemetry.
@@ -1012,6 +1101,10 @@ func (b *backupResumer) ensureClusterIDMatches(clusterID uuid.UUID) error {
 
 // ReportResults implements JobResultsReporter interface.
 func (b *backupResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	if b.verifyResult != nil {
+		details := b.job.Details().(jobspb.BackupDetails)
+		return b.reportVerifyResults(ctx, resultsCh, details.Verification.Fingerprint)
+	}
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -1980,6 +2073,10 @@ func (b *backupResumer) OnFailOrCancel(
 	p := execCtx.(sql.JobExecContext)
 	cfg := p.ExecCfg()
 	details := b.job.Details().(jobspb.BackupDetails)
+	// Jobs created by VERIFY BACKUP do not write anything.
+	if details.Verification != nil {
+		return nil
+	}
 
 	b.deleteCheckpoint(ctx, cfg, p.User())
 	if err := cfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
@@ -13,6 +13,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
@@ -81,6 +82,8 @@ func resolveOptionsForBackupJobDescription(
 		Detached:                        opts.Detached,
 		ExecutionLocality:               opts.ExecutionLocality,
 		UpdatesClusterMonitoringMetrics: opts.UpdatesClusterMonitoringMetrics,
+		Deduplicate:                     opts.Deduplicate,
+		ObjectLockRetention:             opts.ObjectLockRetention,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -406,6 +409,7 @@ func backupTypeCheck(
 			backupStmt.Subdir,
 			backupStmt.Options.EncryptionPassphrase,
 			backupStmt.Options.ExecutionLocality,
+			backupStmt.Options.ObjectLockRetention,
 		},
 		exprutil.StringArrays{
 			tree.Exprs(backupStmt.To),
@@ -417,6 +421,7 @@ func backupTypeCheck(
 			backupStmt.Options.CaptureRevisionHistory,
 			backupStmt.Options.IncludeAllSecondaryTenants,
 			backupStmt.Options.UpdatesClusterMonitoringMetrics,
+			backupStmt.Options.Deduplicate,
 		}); err != nil {
 		return false, nil, err
 	}
@@ -553,6 +558,32 @@ func backupPlanHook(
 		}
 	}
 
+	var deduplicate bool
+	if backupStmt.Options.Deduplicate != nil {
+		deduplicate, err = exprEval.Bool(ctx, backupStmt.Options.Deduplicate)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	var objectLockRetention time.Duration
+	if backupStmt.Options.ObjectLockRetention != nil {
+		retention, err := exprEval.String(ctx, backupStmt.Options.ObjectLockRetention)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		d, err := tree.ParseDInterval(p.SessionData().GetIntervalStyle(), retention)
+		if err != nil {
+			return nil, nil, nil, false, errors.Wrap(err, "failed to evaluate object_lock_retention")
+		}
+		secs, ok := d.Duration.AsInt64()
+		if !ok || secs <= 0 {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.InvalidParameterValue,
+				"object_lock_retention must be a positive interval, got %s", retention)
+		}
+		objectLockRetention = time.Duration(secs) * time.Second
+	}
+
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
 		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
@@ -562,6 +593,16 @@ func backupPlanHook(
 			return errors.Errorf("BACKUP cannot be used inside a multi-statement transaction without DETACHED option")
 		}
 
+		if deduplicate && (!backupStmt.Nested || len(to) > 1 || len(incrementalStorage) > 0) {
+			return errors.New("the deduplicate option is only supported for `BACKUP INTO` a collection " +
+				"that is not locality aware, without the incremental_location option")
+		}
+		// Deduplicated backups skip writing the data files that are already in the
+		// collection, which may be locked for a shorter period, if at all.
+		if deduplicate && objectLockRetention > 0 {
+			return errors.New("the object_lock_retention option is not supported with the deduplicate option")
+		}
+
 		if len(to) > 1 {
 			if err := requireEnterprise(p.ExecCfg(), "partitioned destinations"); err != nil {
 				return err
@@ -665,6 +706,8 @@ func backupPlanHook(
 			ApplicationName:                 p.SessionData().ApplicationName,
 			ExecutionLocality:               executionLocality,
 			UpdatesClusterMonitoringMetrics: updatesClusterMonitoringMetrics,
+			Deduplicate:                     deduplicate,
+			ObjectLockRetention:             objectLockRetention,
 		}
 		if backupStmt.CreatedByInfo != nil {
 			initialDetails.ScheduleID = backupStmt.CreatedByInfo.ScheduleID()
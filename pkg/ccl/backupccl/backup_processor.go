@@ -13,6 +13,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -411,17 +412,33 @@ func runBackupProcessor(
 		enc:      spec.Encryption,
 		progCh:   progCh,
 		settings: &flowCtx.Cfg.Settings.SV,
+
+		dedupBlobDir: spec.DedupBlobDir,
 	}
 	storage, err := flowCtx.Cfg.ExternalStorage(ctx, dest)
 	if err != nil {
 		return err
 	}
 	defer logClose(ctx, storage, "external storage")
+	if spec.ObjectLockRetention > 0 {
+		storage = backupdest.WithObjectLockRetention(storage, spec.ObjectLockRetention)
+	}
 
 	// Start start a group of goroutines which each pull spans off of `todo` and
 	// send export requests. Any spans that encounter lock conflict errors during
 	// Export are put back on the todo queue for later processing.
-	numSenders, release, err := reserveWorkerMemory(ctx, clusterSettings, memAcc)
+	//
+	// The sink of each worker of a backup taken with the deduplicate option
+	// buffers the file it is writing, along with its ciphertext if the backup
+	// is encrypted, see dedupFile.
+	var sinkMemory int64
+	if spec.DedupBlobDir != "" {
+		sinkMemory = targetFileSize.Get(&clusterSettings.SV)
+		if spec.Encryption != nil {
+			sinkMemory *= 2
+		}
+	}
+	numSenders, release, err := reserveWorkerMemory(ctx, clusterSettings, memAcc, sinkMemory)
 	if err != nil {
 		return err
 	}
@@ -749,14 +766,15 @@ func recordExportStats(sp *tracing.Span, resp *kvpb.ExportResponse, requestSentA
 }
 
 // reserveWorkerMemory returns the number of workers after reserving the appropriate
-// amount of memory for each worker.
+// amount of memory for each worker, including the passed amount of memory that
+// its sink holds onto.
 func reserveWorkerMemory(
-	ctx context.Context, settings *cluster.Settings, memAcc *mon.BoundAccount,
+	ctx context.Context, settings *cluster.Settings, memAcc *mon.BoundAccount, sinkMemory int64,
 ) (int, func(), error) {
 	maxWorkerCount := int(workerCount.Get(&settings.SV))
 	// We assume that each worker needs at least enough memory to hold onto
 	// 1 buffer used by the external storage.
-	perWorkerMemory := cloud.WriteChunkSize.Get(&settings.SV)
+	perWorkerMemory := cloud.WriteChunkSize.Get(&settings.SV) + sinkMemory
 	// TODO(ssd): We could also add the size of the SST we might be holding here.
 	// Previously we would reserve a fixed-size buffer, but we left the possibly
 	// in-flight SSTs unaccounted for.
@@ -10,6 +10,7 @@ package backupccl
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
@@ -47,6 +48,8 @@ func distBackupPlanSpecs(
 	startTime, endTime hlc.Timestamp,
 	elide execinfrapb.ElidePrefix,
 	includeValueHeader bool,
+	dedupBlobDir string,
+	objectLockRetention time.Duration,
 ) (map[base.SQLInstanceID]*execinfrapb.BackupDataSpec, error) {
 	var span *tracing.Span
 	ctx, span = tracing.ChildSpan(ctx, "backupccl.distBackupPlanSpecs")
@@ -111,6 +114,8 @@ func distBackupPlanSpecs(
 			UserProto:              user.EncodeProto(),
 			ElidePrefix:            elide,
 			IncludeMVCCValueHeader: includeValueHeader,
+			DedupBlobDir:           dedupBlobDir,
+			ObjectLockRetention:    objectLockRetention,
 		}
 		sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 	}
@@ -134,6 +139,8 @@ func distBackupPlanSpecs(
 				BackupEndTime:          endTime,
 				UserProto:              user.EncodeProto(),
 				IncludeMVCCValueHeader: includeValueHeader,
+				DedupBlobDir:           dedupBlobDir,
+				ObjectLockRetention:    objectLockRetention,
 			}
 			sqlInstanceIDToSpec[partition.SQLInstanceID] = spec
 		}
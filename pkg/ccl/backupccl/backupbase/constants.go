@@ -56,6 +56,19 @@ const (
 	// incremental backups will be written.
 	DefaultIncrementalsSubdir = "incrementals"
 
+	// DeduplicatedBlobsDirectory is the subdirectory of a collection in which
+	// backups taken with the deduplicate option store their data files, named
+	// after the hash of their contents, so that they can be shared by all the
+	// backups in the collection. A file in it is deleted once no backup in the
+	// collection references it.
+	DeduplicatedBlobsDirectory = "blobs"
+
+	// DeduplicatedBlobRefsDirectory is the subdirectory of a backup taken with
+	// the deduplicate option which holds an empty file, named after the hash of
+	// the blob, for every blob in DeduplicatedBlobsDirectory that the backup
+	// references.
+	DeduplicatedBlobRefsDirectory = "blobrefs"
+
 	// ListingDelimDataSlash is used when listing to find backups/backup metadata
 	// and groups all the data sst files in each backup, which start with "data/",
 	// into a single result that can be skipped over quickly.
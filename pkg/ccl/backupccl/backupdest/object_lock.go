@@ -0,0 +1,97 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupdest
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// CheckObjectLockRetention returns an error if the files that a backup writes
+// to the given destination, or to any of its locality aware URIs, cannot be
+// locked against deletion and overwrites for the requested retention. It is
+// called before the backup writes anything to the destination.
+func CheckObjectLockRetention(
+	ctx context.Context,
+	dest ResolvedDestination,
+	retention time.Duration,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+) error {
+	uris := []string{dest.DefaultURI}
+	for _, uri := range dest.URIsByLocalityKV {
+		uris = append(uris, uri)
+	}
+	for _, uri := range uris {
+		if err := func() error {
+			store, err := mkStore(ctx, uri, user)
+			if err != nil {
+				return errors.Wrapf(err, "opening backup destination %s",
+					backuputils.RedactURIForErrorMessage(uri))
+			}
+			defer store.Close()
+			return errors.Wrapf(checkObjectLockRetention(ctx, store, retention),
+				"backup destination %s", backuputils.RedactURIForErrorMessage(uri))
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkObjectLockRetention returns an error if the objects written to the given
+// store cannot be locked for the requested retention.
+func checkObjectLockRetention(
+	ctx context.Context, store cloud.ExternalStorage, retention time.Duration,
+) error {
+	conf, err := cloud.GetObjectLockConfiguration(ctx, store)
+	if err != nil {
+		return errors.Wrap(err, "checking object lock configuration")
+	}
+	if !conf.Enabled {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s storage location does not have object locking enabled", store.Conf().Provider)
+	}
+	if !conf.PerObjectRetention && conf.DefaultRetention < retention {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s storage location locks objects for %s, which is shorter than the requested retention of %s",
+			store.Conf().Provider, conf.DefaultRetention, retention)
+	}
+	return nil
+}
+
+// WithObjectLockRetention returns a store that locks every object it writes
+// for the given retention from the time the object is written.
+func WithObjectLockRetention(
+	store cloud.ExternalStorage, retention time.Duration,
+) cloud.ExternalStorage {
+	return &retainingStorage{ExternalStorage: store, retention: retention}
+}
+
+type retainingStorage struct {
+	cloud.ExternalStorage
+	retention time.Duration
+}
+
+// Writer implements the cloud.ExternalStorage interface.
+func (s *retainingStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	opts = append(opts[:len(opts):len(opts)], cloud.WithRetainUntil(timeutil.Now().Add(s.retention)))
+	return s.ExternalStorage.Writer(ctx, basename, opts...)
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupdest_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+// unlockedStore is an ExternalStorage that cannot lock objects.
+type unlockedStore struct {
+	cloud.ExternalStorage
+}
+
+func (s *unlockedStore) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{Provider: cloudpb.ExternalStorageProvider_nodelocal}
+}
+
+func (s *unlockedStore) Close() error { return nil }
+
+// lockingStore is an ExternalStorage with the given object lock configuration
+// which records the options of the last call to Writer.
+type lockingStore struct {
+	cloud.ExternalStorage
+	conf    cloud.ObjectLockConfiguration
+	written cloud.WriteOptions
+}
+
+var _ cloud.ObjectLockingStorage = &lockingStore{}
+
+func (s *lockingStore) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{Provider: cloudpb.ExternalStorageProvider_s3}
+}
+
+func (s *lockingStore) Close() error { return nil }
+
+func (s *lockingStore) ObjectLockConfiguration(
+	context.Context,
+) (cloud.ObjectLockConfiguration, error) {
+	return s.conf, nil
+}
+
+type nopWriteCloser struct{}
+
+func (nopWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (nopWriteCloser) Close() error                { return nil }
+
+func (s *lockingStore) Writer(
+	_ context.Context, _ string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	s.written = cloud.MakeWriteOptions(opts...)
+	return nopWriteCloser{}, nil
+}
+
+func TestCheckObjectLockRetention(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const day = 24 * time.Hour
+	stores := map[string]cloud.ExternalStorage{
+		"nodelocal://1/unlocked": &unlockedStore{},
+		"s3://disabled/backup":   &lockingStore{},
+		"s3://per-object/backup": &lockingStore{conf: cloud.ObjectLockConfiguration{Enabled: true, PerObjectRetention: true}},
+		"gs://bucket-policy/backup": &lockingStore{conf: cloud.ObjectLockConfiguration{
+			Enabled: true, DefaultRetention: 7 * day,
+		}},
+	}
+	mkStore := func(
+		_ context.Context, uri string, _ username.SQLUsername, _ ...cloud.ExternalStorageOption,
+	) (cloud.ExternalStorage, error) {
+		return stores[uri], nil
+	}
+
+	for _, tc := range []struct {
+		name      string
+		dest      backupdest.ResolvedDestination
+		retention time.Duration
+		err       string
+	}{
+		{
+			name:      "unlocked",
+			dest:      backupdest.ResolvedDestination{DefaultURI: "nodelocal://1/unlocked"},
+			retention: day,
+			err:       "nodelocal storage location does not have object locking enabled",
+		},
+		{
+			name:      "disabled",
+			dest:      backupdest.ResolvedDestination{DefaultURI: "s3://disabled/backup"},
+			retention: day,
+			err:       "s3 storage location does not have object locking enabled",
+		},
+		{
+			name:      "per-object",
+			dest:      backupdest.ResolvedDestination{DefaultURI: "s3://per-object/backup"},
+			retention: 365 * day,
+		},
+		{
+			name:      "bucket-policy-covers-retention",
+			dest:      backupdest.ResolvedDestination{DefaultURI: "gs://bucket-policy/backup"},
+			retention: 7 * day,
+		},
+		{
+			name:      "bucket-policy-too-short",
+			dest:      backupdest.ResolvedDestination{DefaultURI: "gs://bucket-policy/backup"},
+			retention: 30 * day,
+			err:       "locks objects for 168h0m0s, which is shorter than the requested retention of 720h0m0s",
+		},
+		{
+			name: "locality-aware",
+			dest: backupdest.ResolvedDestination{
+				DefaultURI:       "s3://per-object/backup",
+				URIsByLocalityKV: map[string]string{"region=east": "s3://disabled/backup"},
+			},
+			retention: day,
+			err:       "backup destination s3://disabled/backup: s3 storage location does not have object locking enabled",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := backupdest.CheckObjectLockRetention(ctx, tc.dest, tc.retention, mkStore, username.RootUserName())
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+
+	t.Run("writes", func(t *testing.T) {
+		store := &lockingStore{}
+		before := timeutil.Now()
+		retaining := backupdest.WithObjectLockRetention(store, day)
+		require.NoError(t, cloud.WriteFile(ctx, retaining, "file", strings.NewReader("data")))
+		require.False(t, store.written.RetainUntil.Before(before.Add(day)))
+		require.False(t, store.written.RetainUntil.After(timeutil.Now().Add(day)))
+	})
+}
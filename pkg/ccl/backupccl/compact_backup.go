@@ -0,0 +1,1138 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/types"
+)
+
+// compactedChunkSize is the amount of data that is accumulated in memory for
+// a span of a restore span entry before it is handed to the SST sink. Chunks
+// are only cut between rows.
+const compactedChunkSize = 16 << 20
+
+func compactBackupTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	compactStmt, ok := stmt.(*tree.CompactBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	if compactStmt.Options.Detached == tree.DBoolTrue {
+		header = jobs.DetachedJobExecutionResultHeader
+	} else {
+		header = jobs.BulkJobExecutionResultHeader
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "COMPACT BACKUP", p.SemaCtx(),
+		exprutil.Strings{
+			compactStmt.Subdir,
+			compactStmt.Options.EncryptionPassphrase,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(compactStmt.In),
+			tree.Exprs(compactStmt.Options.IncrementalStorage),
+			tree.Exprs(compactStmt.Options.EncryptionKMSURI),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, header, nil
+}
+
+// compactBackupPlanHook implements PlanHookFn for COMPACT BACKUP. The
+// statement creates a BACKUP job which, instead of exporting data from the
+// cluster, merges a chain of backups in a collection into a new full backup.
+func compactBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	compactStmt, ok := stmt.(*tree.CompactBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		"BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	opts := compactStmt.Options
+	for _, unsupported := range []struct {
+		name string
+		set  bool
+	}{
+		{name: "revision_history", set: opts.CaptureRevisionHistory != nil},
+		{name: "include_all_virtual_clusters", set: opts.IncludeAllSecondaryTenants != nil},
+		{name: "execution locality", set: opts.ExecutionLocality != nil},
+		{name: "updates_cluster_monitoring_metrics", set: opts.UpdatesClusterMonitoringMetrics != nil},
+		{name: "deduplicate", set: opts.Deduplicate != nil},
+		{name: "object_lock_retention", set: opts.ObjectLockRetention != nil},
+	} {
+		if unsupported.set {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"COMPACT BACKUP does not support the %s option", unsupported.name)
+		}
+	}
+
+	detached := opts.Detached == tree.DBoolTrue
+	exprEval := p.ExprEvaluator("COMPACT BACKUP")
+
+	subdir, err := exprEval.String(ctx, compactStmt.Subdir)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	collections, err := exprEval.StringArray(ctx, tree.Exprs(compactStmt.In))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	incrementalStorage, err := exprEval.StringArray(ctx, tree.Exprs(opts.IncrementalStorage))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	encryptionParams := jobspb.BackupEncryptionOptions{
+		Mode: jobspb.EncryptionMode_None,
+	}
+	if opts.EncryptionPassphrase != nil {
+		pw, err := exprEval.String(ctx, opts.EncryptionPassphrase)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_Passphrase
+		encryptionParams.RawPassphrase = pw
+	}
+	var kms []string
+	if opts.EncryptionKMSURI != nil {
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, nil, false,
+				errors.New("cannot have both encryption_passphrase and kms option set")
+		}
+		kms, err = exprEval.StringArray(ctx, tree.Exprs(opts.EncryptionKMSURI))
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_KMS
+		encryptionParams.RawKmsUris = kms
+		if err = logAndSanitizeKmsURIs(ctx, kms...); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || detached) {
+			return errors.Errorf("COMPACT BACKUP cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+		if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().Settings, "COMPACT BACKUP"); err != nil {
+			return err
+		}
+		if len(collections) != 1 || len(incrementalStorage) > 1 {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"COMPACT BACKUP does not support locality aware backups")
+		}
+		uris := append(append([]string(nil), collections...), incrementalStorage...)
+		if err := checkPrivilegesForCompactBackup(ctx, p, uris); err != nil {
+			return err
+		}
+
+		var endTime hlc.Timestamp
+		if compactStmt.AsOf.Expr != nil {
+			asOf, err := p.EvalAsOfTimestamp(ctx, compactStmt.AsOf)
+			if err != nil {
+				return err
+			}
+			endTime = asOf.Timestamp
+		}
+
+		// Resolve LATEST now, so that the job compacts the chain that was the
+		// latest one when the statement ran even if a new full backup is taken
+		// while it is running.
+		if strings.EqualFold(subdir, backupbase.LatestFileName) {
+			subdir, err = backupdest.ReadLatestFile(ctx, collections[0],
+				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User())
+			if err != nil {
+				return err
+			}
+		} else {
+			subdir = "/" + strings.TrimPrefix(subdir, "/")
+		}
+
+		if err := logAndSanitizeBackupDestinations(ctx, uris...); err != nil {
+			return errors.Wrap(err, "logging backup destinations")
+		}
+		description, err := compactBackupJobDescription(compactStmt, collections, kms, subdir,
+			incrementalStorage, p.ExtendedEvalContext().Annotations)
+		if err != nil {
+			return err
+		}
+
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		jr := jobs.Record{
+			Description: description,
+			Details: jobspb.BackupDetails{
+				EndTime:           endTime,
+				EncryptionOptions: &encryptionParams,
+				Detached:          detached,
+				ApplicationName:   p.SessionData().ApplicationName,
+				Compaction: &jobspb.BackupDetails_Compaction{
+					Collection:         collections,
+					Subdir:             subdir,
+					IncrementalStorage: incrementalStorage,
+				},
+			},
+			Progress: jobspb.BackupProgress{},
+			Username: p.User(),
+		}
+
+		if detached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(
+				ctx, &sj, jobID, p.InternalSQLTxn(), jr,
+			); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return p.Txn().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	if detached {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
+	return fn, jobs.BulkJobExecutionResultHeader, nil, false, nil
+}
+
+// checkPrivilegesForCompactBackup checks that the user may compact backups in
+// the passed locations. Compaction does not read any data from the cluster, but
+// it produces a backup of everything in the chain, so it requires the same
+// privileges as a cluster backup.
+func checkPrivilegesForCompactBackup(
+	ctx context.Context, p sql.PlanHookState, uris []string,
+) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	if err := p.CheckPrivilegeForUser(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.BACKUP, p.User(),
+	); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InsufficientPrivilege,
+			"only users with the admin role or the BACKUP system privilege are allowed to compact backups")
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, uris)
+}
+
+// compactBackupJobDescription returns the redacted COMPACT BACKUP statement,
+// with LATEST replaced by the resolved subdirectory, to be used as the job
+// description.
+func compactBackupJobDescription(
+	compactStmt *tree.CompactBackup,
+	collections []string,
+	kmsURIs []string,
+	resolvedSubdir string,
+	incrementalStorage []string,
+	ann *tree.Annotations,
+) (string, error) {
+	in, err := sanitizeURIList(collections)
+	if err != nil {
+		return "", err
+	}
+	opts, err := resolveOptionsForBackupJobDescription(compactStmt.Options, kmsURIs,
+		incrementalStorage)
+	if err != nil {
+		return "", err
+	}
+	node := &tree.CompactBackup{
+		Subdir:  tree.NewDString(resolvedSubdir),
+		In:      in,
+		AsOf:    compactStmt.AsOf,
+		Options: opts,
+	}
+	return tree.AsStringWithFlags(
+		node, tree.FmtAlwaysQualifyNames|tree.FmtShowFullURIs, tree.FmtAnnotations(ann),
+	), nil
+}
+
+// compactBackupChain is run by the backup resumer, in place of a regular
+// backup, for jobs created by COMPACT BACKUP. It reads the chain of backups
+// described by details.Compaction up to details.EndTime and writes the state of
+// the backed up spans as of that time to a new full backup in the collection.
+// The cluster's KV is never read or written.
+func (b *backupResumer) compactBackupChain(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails,
+) error {
+	execCfg := p.ExecCfg()
+	user := p.User()
+	compaction := details.Compaction
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	baseDirs, err := backuputils.AppendPaths(compaction.Collection, compaction.Subdir)
+	if err != nil {
+		return err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, compaction.IncrementalStorage, compaction.Collection, compaction.Subdir,
+	)
+	if err != nil {
+		return err
+	}
+	baseStores, cleanupBase, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, baseDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupBase(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupInc, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, incDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupInc(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &execCfg.ExternalIODirConfig, execCfg.InternalDB, user,
+	)
+
+	// The encryption options are the ones provided by the user until the job
+	// has resolved them against the base backup and persisted the result.
+	encryption := details.EncryptionOptions
+	if encryption != nil && encryption.Key == nil && encryption.KMSInfo == nil {
+		encryption, err = backupencryption.GetEncryptionFromBase(
+			ctx, user, mkStore, baseDirs[0], *encryption, &kmsEnv,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	defaultURIs, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+		ctx, &mem, baseStores, incStores, mkStore, baseDirs, incDirs, hlc.Timestamp{},
+		encryption, &kmsEnv, user,
+	)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memReserved)
+	chainLength := len(manifests)
+
+	if !details.EndTime.IsEmpty() {
+		_, manifests, localityInfo, err = backupinfo.ValidateEndTimeAndTruncate(
+			defaultURIs, manifests, localityInfo, details.EndTime,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(manifests) < 2 {
+		return errors.Newf("backup %s has no incremental backups to compact", compaction.Subdir)
+	}
+	for i := range manifests {
+		if len(manifests[i].LocalityKVs) > 0 {
+			return errors.New("COMPACT BACKUP does not support locality aware backups")
+		}
+		if !manifests[i].ClusterID.Equal(execCfg.NodeInfo.LogicalClusterID()) {
+			return errors.Newf("backup %s belongs to cluster %s",
+				compaction.Subdir, manifests[i].ClusterID.String())
+		}
+	}
+	endTime := details.EndTime
+	if endTime.IsEmpty() {
+		endTime = manifests[len(manifests)-1].EndTime
+	}
+
+	// Pick the destination of the compacted backup and lay claim to it the
+	// first time the job runs, persisting everything that was resolved so that
+	// resumptions write to the same place.
+	if details.URI == "" {
+		newSubdir := endTime.GoTime().Format(backupbase.DateBasedIntoFolderName)
+		dest, err := backuputils.AppendPaths(compaction.Collection[:1], newSubdir)
+		if err != nil {
+			return err
+		}
+		if err := backupinfo.CheckForPreviousBackup(ctx, execCfg, dest[0], b.job.ID(), user); err != nil {
+			return err
+		}
+		if err := backupinfo.WriteBackupLock(ctx, execCfg, dest[0], b.job.ID(), user); err != nil {
+			return err
+		}
+
+		details.URI = dest[0]
+		details.CollectionURI = compaction.Collection[0]
+		details.Destination = jobspb.BackupDetails_Destination{Subdir: newSubdir}
+		details.EndTime = endTime
+		details.EncryptionOptions = encryption
+		if err := b.job.NoTxn().Update(ctx, func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			md.Payload.Details = jobspb.WrapPayloadDetails(details)
+			ju.UpdatePayload(md.Payload)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	defaultStore, err := mkStore(ctx, details.URI, user)
+	if err != nil {
+		return errors.Wrapf(err, "make storage")
+	}
+	defer defaultStore.Close()
+
+	// The compacted backup uses the encryption of the chain, so it can be read
+	// with the same passphrase or KMS keys.
+	if encryption != nil {
+		if err := copyEncryptionInfo(ctx, baseStores[0], defaultStore); err != nil {
+			return errors.Wrap(err, "copying encryption info")
+		}
+	}
+
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, execCfg.DistSQLSrv.ExternalStorage, manifests, encryption, &kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	last := manifests[len(manifests)-1]
+	var descs []descpb.Descriptor
+	if err := func() error {
+		it := layerToIterFactory[len(manifests)-1].NewDescIter(ctx)
+		defer it.Close()
+		for ; ; it.Next() {
+			if ok, err := it.Valid(); err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+			descs = append(descs, *it.Value())
+		}
+	}(); err != nil {
+		return err
+	}
+
+	backupManifest := backuppb.BackupManifest{
+		EndTime:            endTime,
+		MVCCFilter:         backuppb.MVCCFilter_Latest,
+		Descriptors:        descs,
+		Tenants:            last.Tenants,
+		CompleteDbs:        last.CompleteDbs,
+		Spans:              last.Spans,
+		FormatVersion:      backupinfo.BackupFormatDescriptorTrackingVersion,
+		BuildInfo:          build.GetInfo(),
+		ClusterVersion:     execCfg.Settings.Version.ActiveVersion(ctx).Version,
+		ClusterID:          execCfg.NodeInfo.LogicalClusterID(),
+		DescriptorCoverage: last.DescriptorCoverage,
+		ElidedPrefix:       manifests[0].ElidedPrefix,
+		ID:                 uuid.MakeV4(),
+	}
+
+	if err := compactBackupSpans(
+		ctx, p, b.job, &backupManifest, manifests, localityInfo, layerToIterFactory,
+		defaultStore, encryption, &kmsEnv,
+	); err != nil {
+		return err
+	}
+
+	if err := backupinfo.WriteBackupManifest(ctx, defaultStore, backupbase.BackupManifestName,
+		encryption, &kmsEnv, &backupManifest); err != nil {
+		return err
+	}
+	if backupinfo.WriteMetadataWithExternalSSTsEnabled.Get(&execCfg.Settings.SV) {
+		if err := backupinfo.WriteMetadataWithExternalSSTs(ctx, defaultStore, encryption,
+			&kmsEnv, &backupManifest); err != nil {
+			return err
+		}
+	}
+
+	// Carry over the table statistics of the newest layer of the chain.
+	lastStore, err := execCfg.DistSQLSrv.ExternalStorage(ctx, last.Dir)
+	if err != nil {
+		return err
+	}
+	defer lastStore.Close()
+	statistics, err := backupinfo.GetStatisticsFromBackup(ctx, lastStore, encryption, &kmsEnv, last)
+	if err != nil {
+		return err
+	}
+	statsTable := backuppb.StatsTable{Statistics: statistics}
+	if err := backupinfo.WriteTableStatistics(ctx, defaultStore, encryption, &kmsEnv, &statsTable); err != nil {
+		return err
+	}
+	if backupinfo.WriteMetadataSST.Get(&execCfg.Settings.SV) {
+		if err := backupinfo.WriteBackupMetadataSST(ctx, defaultStore, encryption, &kmsEnv,
+			&backupManifest, statistics); err != nil {
+			err = errors.Wrap(err, "writing forward-compat metadata sst")
+			if !build.IsRelease() {
+				return err
+			}
+			log.Warningf(ctx, "%+v", err)
+		}
+	}
+
+	if err := maybeAdvanceLatestToCompactedBackup(
+		ctx, execCfg, user, details, incStores[0], chainLength, len(manifests),
+	); err != nil {
+		return err
+	}
+
+	b.backupStats = backupManifest.EntryCounts
+	return nil
+}
+
+// maybeAdvanceLatestToCompactedBackup points the LATEST file of the collection
+// at the compacted backup, if the compacted chain is still the latest one and
+// the compacted backup covers all of it. Subsequent incremental backups INTO
+// LATEST are then appended to the compacted backup.
+func maybeAdvanceLatestToCompactedBackup(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	details jobspb.BackupDetails,
+	incStore cloud.ExternalStorage,
+	chainLength, compactedLayers int,
+) error {
+	if compactedLayers != chainLength {
+		return nil
+	}
+	latest, err := backupdest.ReadLatestFile(ctx, details.CollectionURI,
+		execCfg.DistSQLSrv.ExternalStorageFromURI, user)
+	if err != nil {
+		return err
+	}
+	if latest != details.Compaction.Subdir {
+		log.Infof(ctx, "not updating LATEST to compacted backup %s: latest backup is now %s",
+			details.Destination.Subdir, latest)
+		return nil
+	}
+	// An incremental backup may have been appended to the chain while it was
+	// being compacted, in which case LATEST must keep pointing at the chain.
+	incs, err := backupdest.FindPriorBackups(ctx, incStore, false /* includeManifest */)
+	if err != nil {
+		return err
+	}
+	if len(incs) != chainLength-1 {
+		log.Infof(ctx, "not updating LATEST to compacted backup %s: %d incremental backups "+
+			"were appended to %s during compaction", details.Destination.Subdir,
+			len(incs)-(chainLength-1), details.Compaction.Subdir)
+		return nil
+	}
+
+	c, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, details.CollectionURI, user)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return backupdest.WriteNewLatestFile(ctx, execCfg.Settings, c, details.Destination.Subdir)
+}
+
+// copyEncryptionInfo copies the ENCRYPTION-INFO files from the full backup of a
+// chain to the directory of its compacted backup.
+func copyEncryptionInfo(ctx context.Context, src, dest cloud.ExternalStorage) error {
+	files, err := backupencryption.GetEncryptionInfoFiles(ctx, src)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := func() error {
+			r, _, err := src.ReadFile(ctx, f, cloud.ReadOptions{NoFileSize: true})
+			if err != nil {
+				return err
+			}
+			defer r.Close(ctx)
+			buf, err := ioctx.ReadAll(ctx, r)
+			if err != nil {
+				return err
+			}
+			return cloud.WriteFile(ctx, dest, f, bytes.NewReader(buf))
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compactBackupSpans covers the spans of the compacted backup with restore
+// span entries, exactly like RESTORE does, and writes the latest revision of
+// every key in each entry as of the manifest's end time to SSTs in dest. The
+// files and entry counts are recorded in the passed manifest.
+func compactBackupSpans(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	job *jobs.Job,
+	backupManifest *backuppb.BackupManifest,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory,
+	dest cloud.ExternalStorage,
+	encryption *jobspb.BackupEncryptionOptions,
+	kmsEnv cloud.KMSEnv,
+) error {
+	execCfg := execCtx.ExecCfg()
+	endTime := backupManifest.EndTime
+
+	genSpans, cleanup, err := makeChainSpanGenerator(
+		ctx, execCfg, execCtx.User(), backupManifest.Spans, manifests, localityInfo,
+		layerToIterFactory, endTime,
+	)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var numEntries int
+	countCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+	if err := ctxgroup.GoAndWait(ctx,
+		func(ctx context.Context) error { return genSpans(ctx, countCh) },
+		func(ctx context.Context) error {
+			for range countCh {
+				numEntries++
+			}
+			return nil
+		},
+	); err != nil {
+		return errors.Wrap(err, "counting number of import spans")
+	}
+
+	var fileEncryption *kvpb.FileEncryptionOptions
+	if encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, kmsEnv)
+		if err != nil {
+			return err
+		}
+		fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	pkIDs := make(map[uint64]bool)
+	for i := range backupManifest.Descriptors {
+		if t, _, _, _, _ := descpb.GetDescriptors(&backupManifest.Descriptors[i]); t != nil {
+			pkIDs[kvpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+
+	progressLogger := jobs.NewChunkProgressLoggerForJob(job, numEntries, job.FractionCompleted(), jobs.ProgressUpdateOnly)
+	requestFinishedCh := make(chan struct{}, numEntries)
+	progCh := make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	spanCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+
+	collectFiles := func(ctx context.Context) error {
+		defer close(requestFinishedCh)
+		for progress := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := types.UnmarshalAny(&progress.ProgressDetails, &progDetails); err != nil {
+				return errors.Wrap(err, "unable to unmarshal compaction progress details")
+			}
+			for _, file := range progDetails.Files {
+				backupManifest.Files = append(backupManifest.Files, file)
+				backupManifest.EntryCounts.Add(file.EntryCounts)
+			}
+			for i := int32(0); i < progDetails.CompletedSpans; i++ {
+				requestFinishedCh <- struct{}{}
+			}
+		}
+		return nil
+	}
+	compactEntries := func(ctx context.Context) error {
+		defer close(progCh)
+		sink := makeFileSSTSink(sstSinkConf{
+			progCh:   progCh,
+			enc:      fileEncryption,
+			id:       execCfg.NodeInfo.NodeID.SQLInstanceID(),
+			settings: &execCfg.Settings.SV,
+		}, dest, nil /* pacer */)
+		defer logClose(ctx, sink, "SST sink")
+		sink.elideMode = backupManifest.ElidedPrefix
+
+		w := compactedSpanWriter{
+			sink:     sink,
+			settings: execCfg.Settings,
+			pkIDs:    pkIDs,
+		}
+		for entry := range spanCh {
+			if err := compactRestoreSpanEntry(
+				ctx, execCfg, entry, fileEncryption, endTime, &w,
+			); err != nil {
+				return err
+			}
+		}
+		return sink.flush(ctx)
+	}
+	if err := ctxgroup.GoAndWait(ctx,
+		func(ctx context.Context) error { return genSpans(ctx, spanCh) },
+		compactEntries,
+		collectFiles,
+		func(ctx context.Context) error {
+			return errors.Wrap(progressLogger.Loop(ctx, requestFinishedCh), "updating job progress")
+		},
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+// makeChainSpanGenerator returns a function that covers the passed spans of a
+// chain of backups with restore span entries as of endTime, exactly like
+// RESTORE does, and sends them on the passed channel, which it closes. The
+// returned cleanup function must be called once the function is no longer
+// used.
+func makeChainSpanGenerator(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	spans roachpb.Spans,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory,
+	endTime hlc.Timestamp,
+) (genSpans func(context.Context, chan execinfrapb.RestoreSpanEntry) error, cleanup func(), _ error) {
+	if err := checkCoverage(ctx, spans, manifests); err != nil {
+		return nil, nil, err
+	}
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo, user)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "resolving locality locations")
+	}
+	introducedSpanFrontier, err := createIntroducedSpanFrontier(manifests, endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := makeSpanCoveringFilter(
+		spans,
+		nil, /* checkpointedSpans */
+		nil, /* highWater */
+		introducedSpanFrontier,
+		targetRestoreSpanSize.Get(&execCfg.Settings.SV),
+		maxFileCount.Get(&execCfg.Settings.SV),
+		false, /* useFrontierCheckpointing */
+	)
+	if err != nil {
+		introducedSpanFrontier.Release()
+		return nil, nil, err
+	}
+	cleanup = func() {
+		filter.close()
+		introducedSpanFrontier.Release()
+	}
+
+	var fsc fileSpanComparator = &exclusiveEndKeyComparator{}
+	for _, m := range manifests {
+		if m.ClusterVersion.Less(clusterversion.V24_1.Version()) && m.MVCCFilter == backuppb.MVCCFilter_All {
+			fsc = &inclusiveEndKeyComparator{}
+			break
+		}
+	}
+	genSpans = func(ctx context.Context, spanCh chan execinfrapb.RestoreSpanEntry) error {
+		defer close(spanCh)
+		return errors.Wrap(generateAndSendImportSpans(
+			ctx,
+			spans,
+			manifests,
+			layerToIterFactory,
+			backupLocalityMap,
+			filter,
+			fsc,
+			spanCh,
+		), "generate and send import spans")
+	}
+	return genSpans, cleanup, nil
+}
+
+// compactRestoreSpanEntry writes the latest revision as of endTime of every key
+// in the files of entry to w.
+func compactRestoreSpanEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	entry execinfrapb.RestoreSpanEntry,
+	encryption *kvpb.FileEncryptionOptions,
+	endTime hlc.Timestamp,
+	w *compactedSpanWriter,
+) error {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	defer func() {
+		for _, f := range storeFiles {
+			if err := f.Store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+
+	iterOpts := storage.IterOptions{
+		RangeKeyMaskingBelow: endTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	}
+	sstIter, err := storageccl.ExternalSSTReader(ctx, storeFiles, encryption, iterOpts)
+	if err != nil {
+		return err
+	}
+	iter := storage.NewReadAsOfIterator(sstIter, endTime)
+	defer iter.Close()
+
+	prefix, err := elidedPrefix(entry.Span.Key, entry.ElidedPrefix)
+	if err != nil {
+		return err
+	}
+	w.start(entry.Span.Key)
+	var keyScratch []byte
+	for iter.SeekGE(storage.MVCCKey{Key: bytes.TrimPrefix(entry.Span.Key, prefix)}); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		keyScratch = append(append(keyScratch[:0], prefix...), key.Key...)
+		key.Key = keyScratch
+		if key.Key.Compare(entry.Span.EndKey) >= 0 {
+			break
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		if err := w.add(ctx, key, v); err != nil {
+			return err
+		}
+	}
+	return w.finish(ctx, entry.Span.EndKey, true /* completesEntry */)
+}
+
+// compactedSpanWriter buffers the keys of a restore span entry into in-memory
+// SSTs and hands them to the SST sink, cutting the entry into chunks at rows
+// where the buffer gets large or the elided prefix of the keys changes.
+type compactedSpanWriter struct {
+	sink     *fileSSTSink
+	settings *cluster.Settings
+	pkIDs    map[uint64]bool
+
+	buf        bytes.Buffer
+	sst        storage.SSTWriter
+	open       bool
+	chunkStart roachpb.Key
+	prefix     roachpb.Key
+	lastRow    roachpb.Key
+	rows       storage.RowCounter
+}
+
+// start begins a new entry at the passed key.
+func (w *compactedSpanWriter) start(key roachpb.Key) {
+	w.chunkStart = append(w.chunkStart[:0], key...)
+}
+
+func (w *compactedSpanWriter) add(ctx context.Context, key storage.MVCCKey, value []byte) error {
+	prefix, err := elidedPrefix(key.Key, w.sink.elideMode)
+	if err != nil {
+		return err
+	}
+	row, err := keys.EnsureSafeSplitKey(key.Key)
+	if err != nil {
+		row = key.Key
+	}
+	if w.open && !bytes.Equal(row, w.lastRow) {
+		if !bytes.Equal(prefix, w.prefix) {
+			// The sink elides a single prefix per file span, so the chunk must end
+			// where the keys with the new prefix start.
+			if err := w.finish(ctx, prefix, false /* completesEntry */); err != nil {
+				return err
+			}
+		} else if w.sst.DataSize >= compactedChunkSize {
+			if err := w.finish(ctx, row, false /* completesEntry */); err != nil {
+				return err
+			}
+		}
+	}
+	if !w.open {
+		// Keys before the first key of the chunk might not share its prefix.
+		if !bytes.HasPrefix(w.chunkStart, prefix) {
+			w.chunkStart = append(w.chunkStart[:0], prefix...)
+		}
+		w.prefix = append(w.prefix[:0], prefix...)
+		w.buf.Reset()
+		w.sst = storage.MakeBackupSSTWriter(ctx, w.settings, &w.buf)
+		w.rows = storage.RowCounter{}
+		w.open = true
+	}
+	w.lastRow = append(w.lastRow[:0], row...)
+	if err := w.rows.Count(key.Key); err != nil {
+		return err
+	}
+	w.rows.DataSize += int64(len(key.Key)) + int64(len(value))
+	if key.Timestamp.IsEmpty() {
+		return w.sst.PutUnversioned(key.Key, value)
+	}
+	return w.sst.PutRawMVCC(key, value)
+}
+
+// finish hands the buffered chunk, which ends at endKey, to the sink. The
+// next chunk starts at endKey. completesEntry is true if endKey is the end of
+// the restore span entry, so the sink reports the entry as done.
+func (w *compactedSpanWriter) finish(
+	ctx context.Context, endKey roachpb.Key, completesEntry bool,
+) error {
+	defer func() {
+		w.chunkStart = append(w.chunkStart[:0], endKey...)
+	}()
+	var completedSpans int32
+	if completesEntry {
+		completedSpans = 1
+	}
+	if !w.open {
+		w.sink.writeWithNoData(exportedSpan{completedSpans: completedSpans})
+		return nil
+	}
+	w.open = false
+	if err := w.sst.Finish(); err != nil {
+		return err
+	}
+	w.sst.Close()
+	data := w.buf.Bytes()
+	if _, err := w.sink.write(ctx, exportedSpan{
+		metadata: backuppb.BackupManifest_File{
+			Span:                    roachpb.Span{Key: w.chunkStart, EndKey: endKey}.Clone(),
+			EntryCounts:             countRows(w.rows.BulkOpSummary, w.pkIDs),
+			ApproximatePhysicalSize: uint64(len(data)),
+		},
+		dataSST:        data,
+		completedSpans: completedSpans,
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// maybeStartScheduledCompaction starts a COMPACT BACKUP job for the chain that
+// a scheduled incremental backup was just appended to, if its schedule has the
+// compact_after_incrementals option and the chain has accumulated a multiple
+// of that many incremental backups.
+func maybeStartScheduledCompaction(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	details jobspb.BackupDetails,
+	user username.SQLUsername,
+) error {
+	if details.ScheduleID == jobspb.InvalidScheduleID || details.StartTime.IsEmpty() ||
+		details.CollectionURI == "" || len(details.URIsByLocalityKV) > 0 {
+		return nil
+	}
+
+	args, backupStmt, err := loadScheduledBackup(ctx, execCfg, details.ScheduleID)
+	if err != nil {
+		if jobs.HasScheduledJobNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if args.CompactAfterIncrementals <= 0 {
+		return nil
+	}
+
+	// The statement of the schedule holds its evaluated incremental_location.
+	incrementalStorage, err := scheduledBackupStrings(backupStmt.Options.IncrementalStorage)
+	if err != nil {
+		return err
+	}
+
+	collection := []string{details.CollectionURI}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, incrementalStorage, collection, details.Destination.Subdir,
+	)
+	if err != nil {
+		return err
+	}
+	incStore, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, incDirs[0], user)
+	if err != nil {
+		return err
+	}
+	defer incStore.Close()
+	incs, err := backupdest.FindPriorBackups(ctx, incStore, false /* includeManifest */)
+	if err != nil {
+		return err
+	}
+	if len(incs) == 0 || int64(len(incs))%args.CompactAfterIncrementals != 0 {
+		return nil
+	}
+
+	description, err := compactBackupJobDescription(
+		&tree.CompactBackup{}, collection, nil /* kmsURIs */, details.Destination.Subdir,
+		incrementalStorage, nil, /* ann */
+	)
+	if err != nil {
+		return err
+	}
+	jobID := execCfg.JobRegistry.MakeJobID()
+	jr := jobs.Record{
+		Description: description,
+		Details: jobspb.BackupDetails{
+			EncryptionOptions: details.EncryptionOptions,
+			Detached:          true,
+			Compaction: &jobspb.BackupDetails_Compaction{
+				Collection:         collection,
+				Subdir:             details.Destination.Subdir,
+				IncrementalStorage: incrementalStorage,
+			},
+		},
+		Progress: jobspb.BackupProgress{},
+		Username: user,
+	}
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, jobID, txn)
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Infof(ctx, "started job %d to compact the %d incremental backups of %s",
+		jobID, len(incs), details.Destination.Subdir)
+	return nil
+}
+
+// loadScheduledBackup loads the execution args and the backup statement of the
+// backup schedule with the given ID.
+func loadScheduledBackup(
+	ctx context.Context, execCfg *sql.ExecutorConfig, scheduleID jobspb.ScheduleID,
+) (*backuppb.ScheduledBackupExecutionArgs, *annotatedBackupStatement, error) {
+	env := scheduledjobs.ProdJobSchedulerEnv
+	if knobs, ok := execCfg.DistSQLSrv.TestingKnobs.JobsTestingKnobs.(*jobs.TestingKnobs); ok {
+		if knobs.JobSchedulerEnv != nil {
+			env = knobs.JobSchedulerEnv
+		}
+	}
+
+	var args *backuppb.ScheduledBackupExecutionArgs
+	var backupStmt *annotatedBackupStatement
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		sj, a, err := getScheduledBackupExecutionArgsFromSchedule(
+			ctx, env, jobs.ScheduledJobTxn(txn), scheduleID,
+		)
+		if err != nil {
+			return err
+		}
+		args = a
+		backupStmt, err = extractBackupStatement(sj)
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+	return args, backupStmt, nil
+}
+
+// scheduledBackupStrings returns the values of a list of destinations of the
+// backup statement of a schedule, which are always string literals since they
+// are evaluated when the schedule is created.
+func scheduledBackupStrings(exprs tree.StringOrPlaceholderOptList) ([]string, error) {
+	var res []string
+	for _, e := range exprs {
+		s, ok := e.(*tree.StrVal)
+		if !ok {
+			return nil, errors.Errorf("unexpected %T destination in backup statement", e)
+		}
+		res = append(res, s.RawString())
+	}
+	return res, nil
+}
+
+func init() {
+	sql.AddPlanHook("backupccl.compactBackupPlanHook", compactBackupPlanHook, compactBackupTypeCheck)
+}
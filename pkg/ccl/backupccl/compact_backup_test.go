@@ -0,0 +1,82 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompactBackup tests that COMPACT BACKUP merges a chain of backups into a
+// new full backup that restores to the same data as the chain, and that it
+// advances LATEST so that further incremental backups build on it.
+func TestCompactBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id % 2 = 0`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id % 3 = 0`)
+	sqlDB.Exec(t, `CREATE TABLE data.extra AS SELECT id, balance FROM data.bank`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+
+	var chain string
+	sqlDB.QueryRow(t, `SELECT max(path) FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&chain)
+
+	sqlDB.Exec(t, `COMPACT BACKUP FROM LATEST IN $1`, localFoo)
+
+	var compacted string
+	sqlDB.QueryRow(t, `SELECT max(path) FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&compacted)
+	require.NotEqual(t, chain, compacted)
+
+	var backupTypes []string
+	for _, row := range sqlDB.QueryStr(t,
+		`SELECT DISTINCT backup_type FROM [SHOW BACKUP FROM LATEST IN $1]`, localFoo,
+	) {
+		backupTypes = append(backupTypes, row[0])
+	}
+	require.Equal(t, []string{"full"}, backupTypes)
+
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'compacted'`, localFoo)
+	for _, table := range []string{"bank", "extra"} {
+		sqlDB.CheckQueryResults(t,
+			`SELECT * FROM compacted.`+table+` ORDER BY id`,
+			sqlDB.QueryStr(t, `SELECT * FROM data.`+table+` ORDER BY id`),
+		)
+	}
+
+	// An incremental backup into LATEST is appended to the compacted backup.
+	sqlDB.Exec(t, `INSERT INTO data.bank VALUES (1000, 1000, 'new')`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT backup_type FROM [SHOW BACKUP FROM LATEST IN '`+localFoo+`']
+WHERE object_name = 'bank' ORDER BY end_time`,
+		[][]string{{"full"}, {"incremental"}})
+
+	t.Run("no incrementals", func(t *testing.T) {
+		sqlDB.Exec(t, `BACKUP DATABASE data INTO 'nodelocal://1/bar'`)
+		sqlDB.ExpectErr(t, "has no incremental backups to compact",
+			`COMPACT BACKUP FROM LATEST IN 'nodelocal://1/bar'`)
+	})
+
+	t.Run("unsupported options", func(t *testing.T) {
+		sqlDB.ExpectErr(t, "does not support the revision_history option",
+			`COMPACT BACKUP FROM LATEST IN $1 WITH revision_history`, localFoo)
+		sqlDB.ExpectErr(t, "does not support locality aware backups",
+			`COMPACT BACKUP FROM LATEST IN ($1, 'nodelocal://1/baz?COCKROACH_LOCALITY=dc%3Ddc1')`,
+			localFoo)
+	})
+}
@@ -11,6 +11,7 @@ package backupccl
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
@@ -44,19 +45,21 @@ import (
 const (
 	// TODO(msbutler): move these three constants to scheduleBase package to
 	// remove duplication with changefeed schedules.
-	optFirstRun                = "first_run"
-	optOnExecFailure           = "on_execution_failure"
-	optOnPreviousRunning       = "on_previous_running"
-	optIgnoreExistingBackups   = "ignore_existing_backups"
-	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optFirstRun                 = "first_run"
+	optOnExecFailure            = "on_execution_failure"
+	optOnPreviousRunning        = "on_previous_running"
+	optIgnoreExistingBackups    = "ignore_existing_backups"
+	optUpdatesLastBackupMetric  = "updates_cluster_last_backup_time_metric"
+	optCompactAfterIncrementals = "compact_after_incrementals"
 )
 
 var scheduledBackupOptionExpectValues = map[string]exprutil.KVStringOptValidate{
-	optFirstRun:                exprutil.KVStringOptRequireValue,
-	optOnExecFailure:           exprutil.KVStringOptRequireValue,
-	optOnPreviousRunning:       exprutil.KVStringOptRequireValue,
-	optIgnoreExistingBackups:   exprutil.KVStringOptRequireNoValue,
-	optUpdatesLastBackupMetric: exprutil.KVStringOptRequireNoValue,
+	optFirstRun:                 exprutil.KVStringOptRequireValue,
+	optOnExecFailure:            exprutil.KVStringOptRequireValue,
+	optOnPreviousRunning:        exprutil.KVStringOptRequireValue,
+	optIgnoreExistingBackups:    exprutil.KVStringOptRequireNoValue,
+	optUpdatesLastBackupMetric:  exprutil.KVStringOptRequireNoValue,
+	optCompactAfterIncrementals: exprutil.KVStringOptRequireValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -93,6 +96,19 @@ type scheduledBackupSpec struct {
 	includeAllSecondaryTenants *bool
 	execLoc                    *string
 	updatesMetrics             *bool
+	objectLockRetention        *string
+
+	// Retention of backups in the collection.
+	retainFullBackups int64
+	retainFor         time.Duration
+}
+
+// backupChainMaintenance holds the settings of a backup schedule that govern
+// how the backups it writes to its collection are compacted and removed.
+type backupChainMaintenance struct {
+	compactAfterIncrementals int64
+	retainFullBackups        int64
+	retainFor                time.Duration
 }
 
 // TODO(msbutler): move this function into scheduleBase and remove duplicate function in scheduled changefeeds.
@@ -127,6 +143,24 @@ func scheduleFirstRun(evalCtx *eval.Context, opts map[string]string) (*time.Time
 	return nil, nil
 }
 
+// parseCompactAfterIncrementals returns the number of incremental backups
+// after which the chain should be compacted, or 0 if the option is unset.
+func parseCompactAfterIncrementals(opts map[string]string) (int64, error) {
+	v, ok := opts[optCompactAfterIncrementals]
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "unexpected value for %s: %s", optCompactAfterIncrementals, v)
+	}
+	if n <= 0 {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s must be a positive integer, got %d", optCompactAfterIncrementals, n)
+	}
+	return n, nil
+}
+
 func frequencyFromCron(now time.Time, cronStr string) (time.Duration, error) {
 	expr, err := cron.ParseStandard(cronStr)
 	if err != nil {
@@ -251,6 +285,10 @@ func doCreateBackupSchedules(
 		backupNode.Options.ExecutionLocality = tree.NewStrVal(*eval.execLoc)
 	}
 
+	if eval.objectLockRetention != nil {
+		backupNode.Options.ObjectLockRetention = tree.NewStrVal(*eval.objectLockRetention)
+	}
+
 	// Evaluate encryption KMS URIs if set.
 	// Only one of encryption passphrase and KMS URI should be set, but this check
 	// is done during backup planning so we do not need to worry about it here.
@@ -317,6 +355,15 @@ func doCreateBackupSchedules(
 		return err
 	}
 
+	compactAfterIncrementals, err := parseCompactAfterIncrementals(scheduleOptions)
+	if err != nil {
+		return err
+	}
+	if compactAfterIncrementals > 0 && incRecurrence == nil {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"%s requires a schedule with incremental backups", optCompactAfterIncrementals)
+	}
+
 	unpauseOnSuccessID := jobspb.InvalidScheduleID
 
 	var chainProtectedTimestampRecords bool
@@ -353,7 +400,8 @@ func doCreateBackupSchedules(
 		}
 		inc, incScheduledBackupArgs, err = makeBackupSchedule(
 			env, p.User(), scheduleLabel, incRecurrence, incrementalScheduleDetails, unpauseOnSuccessID,
-			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords,
+			backupChainMaintenance{compactAfterIncrementals: compactAfterIncrementals})
 		if err != nil {
 			return err
 		}
@@ -377,7 +425,8 @@ func doCreateBackupSchedules(
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
-		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords,
+		backupChainMaintenance{retainFullBackups: eval.retainFullBackups, retainFor: eval.retainFor})
 	if err != nil {
 		return err
 	}
@@ -484,6 +533,7 @@ func makeBackupSchedule(
 	updateLastMetricOnSuccess bool,
 	backupNode *tree.Backup,
 	chainProtectedTimestampRecords bool,
+	maintenance backupChainMaintenance,
 ) (*jobs.ScheduledJob, *backuppb.ScheduledBackupExecutionArgs, error) {
 	sj := jobs.NewScheduledJob(env)
 	sj.SetScheduleLabel(label)
@@ -494,6 +544,9 @@ func makeBackupSchedule(
 		UnpauseOnSuccess:               unpauseOnSuccess,
 		UpdatesLastBackupMetric:        updateLastMetricOnSuccess,
 		ChainProtectedTimestampRecords: chainProtectedTimestampRecords,
+		CompactAfterIncrementals:       maintenance.compactAfterIncrementals,
+		RetainFullBackups:              maintenance.retainFullBackups,
+		RetainFor:                      maintenance.retainFor,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = backuppb.ScheduledBackupExecutionArgs_INCREMENTAL
@@ -662,6 +715,32 @@ func makeScheduledBackupSpec(
 		}
 	}
 
+	if schedule.Retention != nil {
+		if schedule.Retention.Duration != nil {
+			s, err := exprEval.String(ctx, schedule.Retention.Duration)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate backup retention")
+			}
+			d, err := tree.ParseDInterval(p.SessionData().GetIntervalStyle(), s)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate backup retention")
+			}
+			secs, ok := d.Duration.AsInt64()
+			if !ok || secs <= 0 {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"backup retention must be a positive interval, got %s", s)
+			}
+			spec.retainFor = time.Duration(secs) * time.Second
+		} else {
+			if schedule.Retention.FullBackups <= 0 {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"backup retention must keep at least one full backup, got %d",
+					schedule.Retention.FullBackups)
+			}
+			spec.retainFullBackups = schedule.Retention.FullBackups
+		}
+	}
+
 	spec.scheduleOpts, err = exprEval.KVOptions(
 		ctx, schedule.ScheduleOptions, scheduledBackupOptionExpectValues,
 	)
@@ -738,6 +817,14 @@ func makeScheduledBackupSpec(
 		spec.updatesMetrics = &updatesMetrics
 	}
 
+	if schedule.BackupOptions.ObjectLockRetention != nil {
+		retention, err := exprEval.String(ctx, schedule.BackupOptions.ObjectLockRetention)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate backup object_lock_retention")
+		}
+		spec.objectLockRetention = &retention
+	}
+
 	return spec, nil
 }
 
@@ -803,10 +890,14 @@ func createBackupScheduleTypeCheck(
 		schedule.Recurrence,
 		schedule.BackupOptions.EncryptionPassphrase,
 		schedule.BackupOptions.ExecutionLocality,
+		schedule.BackupOptions.ObjectLockRetention,
 	}
 	if schedule.FullBackup != nil {
 		stringExprs = append(stringExprs, schedule.FullBackup.Recurrence)
 	}
+	if schedule.Retention != nil {
+		stringExprs = append(stringExprs, schedule.Retention.Duration)
+	}
 	opts := exprutil.KVOptions{
 		KVOptions:  schedule.ScheduleOptions,
 		Validation: scheduledBackupOptionExpectValues,
@@ -820,6 +911,7 @@ func createBackupScheduleTypeCheck(
 		schedule.BackupOptions.CaptureRevisionHistory,
 		schedule.BackupOptions.IncludeAllSecondaryTenants,
 		schedule.BackupOptions.UpdatesClusterMonitoringMetrics,
+		schedule.BackupOptions.Deduplicate,
 	}
 	if err := exprutil.TypeCheck(
 		ctx, scheduleBackupOp, p.SemaCtx(), stringExprs, bools, stringArrays, opts,
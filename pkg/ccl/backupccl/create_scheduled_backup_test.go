@@ -703,6 +703,32 @@ func TestSerializesScheduledBackupExecutionArgs(t *testing.T) {
 			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' WITH encryption_passphrase=$1 RECURRING '@hourly'`,
 			errMsg: "failed to evaluate backup encryption_passphrase",
 		},
+		{
+			name: "compact-without-incrementals",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@daily' FULL BACKUP ALWAYS
+		WITH SCHEDULE OPTIONS compact_after_incrementals = '24'`,
+			errMsg: "compact_after_incrementals requires a schedule with incremental backups",
+		},
+		{
+			name: "compact-non-positive",
+			user: enterpriseUser,
+			query: `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@hourly'
+		WITH SCHEDULE OPTIONS compact_after_incrementals = '0'`,
+			errMsg: "compact_after_incrementals must be a positive integer",
+		},
+		{
+			name:   "retention-no-full-backups",
+			user:   enterpriseUser,
+			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@hourly' RETENTION 0 FULL BACKUPS`,
+			errMsg: "backup retention must keep at least one full backup",
+		},
+		{
+			name:   "retention-negative-interval",
+			user:   enterpriseUser,
+			query:  `CREATE SCHEDULE FOR BACKUP INTO 'foo' RECURRING '@hourly' RETENTION '-1 day'`,
+			errMsg: "backup retention must be a positive interval",
+		},
 	}
 
 	for i, tc := range testCases {
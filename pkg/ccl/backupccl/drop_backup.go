@@ -0,0 +1,553 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+func dropBackupTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	dropStmt, ok := stmt.(*tree.DropBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "DROP BACKUP", p.SemaCtx(),
+		exprutil.Strings{dropStmt.Subdir},
+		exprutil.StringArrays{
+			tree.Exprs(dropStmt.In),
+			tree.Exprs(dropStmt.Options.IncrementalStorage),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, nil, nil
+}
+
+// dropBackupPlanHook implements PlanHookFn for DROP BACKUP. The statement
+// deletes a full backup and all of the incremental backups that were appended
+// to it from a collection.
+func dropBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	dropStmt, ok := stmt.(*tree.DropBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		"DROP BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	// The only option that affects which files make up a chain is the location
+	// of its incremental backups.
+	opts := dropStmt.Options
+	opts.IncrementalStorage = nil
+	if !opts.IsDefault() {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"DROP BACKUP only supports the incremental_location option")
+	}
+
+	exprEval := p.ExprEvaluator("DROP BACKUP")
+	subdir, err := exprEval.String(ctx, dropStmt.Subdir)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	collections, err := exprEval.StringArray(ctx, tree.Exprs(dropStmt.In))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	incrementalStorage, err := exprEval.StringArray(
+		ctx, tree.Exprs(dropStmt.Options.IncrementalStorage),
+	)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().Settings, "DROP BACKUP"); err != nil {
+			return err
+		}
+		uris := append(append([]string(nil), collections...), incrementalStorage...)
+		if err := checkPrivilegesForDropBackup(ctx, p, uris); err != nil {
+			return err
+		}
+		if err := logAndSanitizeBackupDestinations(ctx, uris...); err != nil {
+			return errors.Wrap(err, "logging backup destinations")
+		}
+		return dropBackupChain(ctx, p.ExecCfg(), p.User(), collections, subdir, incrementalStorage)
+	}
+	return fn, nil, nil, false, nil
+}
+
+// checkPrivilegesForDropBackup checks that the user is allowed to delete the
+// backups at the passed URIs. Dropping a backup destroys data that may be the
+// only copy of a cluster, so unlike SHOW BACKUP it is not enough to be able to
+// read the collection: the user must be allowed to take backups, and to access
+// every destination that files are deleted from.
+func checkPrivilegesForDropBackup(ctx context.Context, p sql.PlanHookState, uris []string) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	if err := p.CheckPrivilegeForUser(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.BACKUP, p.User(),
+	); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InsufficientPrivilege,
+			"only users with the admin role or the BACKUP system privilege are allowed to drop backups")
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, uris)
+}
+
+// dropBackupChain deletes the full backup in subdir of the passed collections,
+// along with the incremental backups that were appended to it, which are the
+// only backups that depend on it. The chain is only dropped if it is not the
+// latest backup in the collection and no backup job is still writing to it.
+//
+// The incremental backups are deleted before the full backup, newest first,
+// and the manifest of each backup is deleted before its data. A drop that is
+// interrupted thus never leaves behind a backup that is listed but cannot be
+// restored, and can be completed by running it again.
+func dropBackupChain(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	collections []string,
+	subdir string,
+	incrementalStorage []string,
+) error {
+	if strings.EqualFold(subdir, backupbase.LatestFileName) {
+		return pgerror.New(pgcode.ObjectInUse, "cannot drop the LATEST backup in a collection")
+	}
+	subdir = path.Clean("/" + strings.TrimPrefix(subdir, "/"))
+	if subdir == "/" {
+		return pgerror.New(pgcode.InvalidParameterValue,
+			"DROP BACKUP requires the subdirectory of a full backup")
+	}
+
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+	latest, err := backupdest.ReadLatestFile(ctx, collections[0], mkStore, user)
+	if err != nil && pgerror.GetPGCode(err) != pgcode.UndefinedFile {
+		return err
+	}
+	if err == nil && path.Clean("/"+strings.TrimPrefix(latest, "/")) == subdir {
+		return pgerror.Newf(pgcode.ObjectInUse,
+			"backup %s is the latest backup in the collection and cannot be dropped", subdir)
+	}
+
+	// Only the subdirectories of complete full backups are accepted, so that a
+	// typo in the subdirectory cannot delete a part of the collection that
+	// holds several chains.
+	collectionStore, err := mkStore(ctx, collections[0], user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer collectionStore.Close()
+	fulls, err := backupdest.ListFullBackupsInCollection(ctx, collectionStore)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, full := range fulls {
+		if "/"+strings.TrimPrefix(full, "/") == subdir {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return pgerror.Newf(pgcode.UndefinedFile,
+			"%s is not a full backup in the collection", subdir)
+	}
+
+	baseDirs, err := backuputils.AppendPaths(collections, subdir)
+	if err != nil {
+		return err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, incrementalStorage, collections, subdir,
+	)
+	if err != nil {
+		return err
+	}
+	incStores, cleanupIncStores, err := backupdest.MakeBackupDestinationStores(
+		ctx, user, mkStore, incDirs,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupIncStores(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+	baseStores, cleanupBaseStores, err := backupdest.MakeBackupDestinationStores(
+		ctx, user, mkStore, baseDirs,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupBaseStores(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+
+	// Backup jobs lay claim on the directory they write to with a lock file in
+	// its default locality, which is left behind once the job completes.
+	for _, store := range []cloud.ExternalStorage{baseStores[0], incStores[0]} {
+		jobID, err := findRunningBackupInChain(ctx, execCfg, store)
+		if err != nil {
+			return err
+		}
+		if jobID != jobspb.InvalidJobID {
+			return pgerror.Newf(pgcode.ObjectInUse,
+				"backup %s cannot be dropped while backup job %d is writing to it", subdir, jobID)
+		}
+	}
+
+	for _, stores := range [][]cloud.ExternalStorage{incStores, baseStores} {
+		for _, store := range stores {
+			layers, err := backupdest.FindPriorBackups(ctx, store, false /* includeManifest */)
+			if err != nil {
+				return err
+			}
+			for i := len(layers) - 1; i >= 0; i-- {
+				if err := deleteBackupFiles(ctx, store, layers[i]); err != nil {
+					return err
+				}
+			}
+			if err := deleteBackupFiles(ctx, store, ""); err != nil {
+				return err
+			}
+		}
+	}
+	log.Infof(ctx, "dropped backup %s", subdir)
+
+	// The chain is dropped even if its blobs cannot be collected yet, in which
+	// case they are collected when the next chain is dropped.
+	if err := collectDeduplicatedBlobs(ctx, execCfg, collectionStore); err != nil {
+		log.Warningf(ctx, "failed to delete unreferenced blobs of the collection: %v", err)
+	}
+	return nil
+}
+
+// collectDeduplicatedBlobs deletes the blobs in the deduplicated blobs
+// directory of a collection that are not referenced by any of its backups.
+// Every backup taken with the deduplicate option records a reference to each
+// blob it uses in its own directory, so the references to a blob are dropped
+// along with the backups that hold them.
+//
+// A running backup may be about to reuse a blob that it has not yet recorded a
+// reference to, so no blob is collected while a backup job is writing to the
+// collection.
+func collectDeduplicatedBlobs(
+	ctx context.Context, execCfg *sql.ExecutorConfig, store cloud.ExternalStorage,
+) error {
+	unreferenced := make(map[string]struct{})
+	if err := store.List(ctx, backupbase.DeduplicatedBlobsDirectory+"/", "", func(f string) error {
+		unreferenced[strings.TrimSuffix(path.Base(f), ".sst")] = struct{}{}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "listing deduplicated blobs")
+	}
+	if len(unreferenced) == 0 {
+		return nil
+	}
+
+	// The blobs directory is skipped when looking for references, since it
+	// holds most of the files of the collection.
+	var dirs []string
+	if err := store.List(ctx, "", "/", func(f string) error {
+		f = strings.TrimPrefix(f, "/")
+		if strings.HasSuffix(f, "/") && f != backupbase.DeduplicatedBlobsDirectory+"/" {
+			dirs = append(dirs, f)
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "listing backup collection")
+	}
+	var lockedBy []jobspb.JobID
+	for _, dir := range dirs {
+		if err := store.List(ctx, dir, "", func(f string) error {
+			if path.Base(path.Dir(f)) == backupbase.DeduplicatedBlobRefsDirectory {
+				delete(unreferenced, path.Base(f))
+			}
+			base := path.Base(f)
+			if !strings.HasPrefix(base, backupinfo.BackupLockFilePrefix) {
+				return nil
+			}
+			id, err := strconv.Atoi(strings.TrimPrefix(base, backupinfo.BackupLockFilePrefix))
+			if err != nil {
+				return errors.Wrapf(err, "malformed %s file %s", backupinfo.BackupLockFilePrefix, f)
+			}
+			lockedBy = append(lockedBy, jobspb.JobID(id))
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "listing files in %s", dir)
+		}
+	}
+	if len(unreferenced) == 0 {
+		return nil
+	}
+	jobID, err := findRunningBackup(ctx, execCfg, lockedBy)
+	if err != nil {
+		return err
+	}
+	if jobID != jobspb.InvalidJobID {
+		log.Infof(ctx, "not deleting %d unreferenced blobs while backup job %d is running",
+			len(unreferenced), jobID)
+		return nil
+	}
+	for blob := range unreferenced {
+		name := path.Join(backupbase.DeduplicatedBlobsDirectory, blob+".sst")
+		if err := store.Delete(ctx, name); err != nil {
+			return errors.Wrapf(err, "deleting %s", name)
+		}
+	}
+	log.Infof(ctx, "deleted %d unreferenced blobs", len(unreferenced))
+	return nil
+}
+
+// findRunningBackupInChain returns the ID of a backup job that has a lock file
+// anywhere in store and has not yet completed, or InvalidJobID if there is
+// none.
+func findRunningBackupInChain(
+	ctx context.Context, execCfg *sql.ExecutorConfig, store cloud.ExternalStorage,
+) (jobspb.JobID, error) {
+	var lockedBy []jobspb.JobID
+	if err := store.List(ctx, "", "", func(f string) error {
+		base := path.Base(f)
+		if !strings.HasPrefix(base, backupinfo.BackupLockFilePrefix) {
+			return nil
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(base, backupinfo.BackupLockFilePrefix))
+		if err != nil {
+			return errors.Wrapf(err, "malformed %s file %s", backupinfo.BackupLockFilePrefix, f)
+		}
+		lockedBy = append(lockedBy, jobspb.JobID(id))
+		return nil
+	}); err != nil {
+		return jobspb.InvalidJobID, errors.Wrap(err, "listing backup lock files")
+	}
+	return findRunningBackup(ctx, execCfg, lockedBy)
+}
+
+// findRunningBackup returns the first of the passed backup jobs that has not
+// yet completed, or InvalidJobID if they all have.
+func findRunningBackup(
+	ctx context.Context, execCfg *sql.ExecutorConfig, lockedBy []jobspb.JobID,
+) (jobspb.JobID, error) {
+	for _, jobID := range lockedBy {
+		j, err := execCfg.JobRegistry.LoadJob(ctx, jobID)
+		if err != nil {
+			if jobs.HasJobNotFoundError(err) {
+				continue
+			}
+			return jobspb.InvalidJobID, err
+		}
+		if !j.Status().Terminal() {
+			return jobID, nil
+		}
+	}
+	return jobspb.InvalidJobID, nil
+}
+
+// isBackupManifestFile returns whether the file with the passed name marks
+// the directory that holds it as a complete backup.
+func isBackupManifestFile(name string) bool {
+	base := path.Base(name)
+	return base == backupbase.BackupOldManifestName ||
+		strings.HasPrefix(base, backupbase.BackupManifestName) ||
+		strings.HasPrefix(base, backupbase.BackupMetadataName)
+}
+
+// deleteBackupFiles deletes all files under dir in store, starting with the
+// manifests.
+func deleteBackupFiles(ctx context.Context, store cloud.ExternalStorage, dir string) error {
+	var files []string
+	if err := store.List(ctx, dir, "", func(f string) error {
+		files = append(files, path.Join(dir, strings.TrimPrefix(f, "/")))
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "listing files in %s", dir)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return isBackupManifestFile(files[i]) && !isBackupManifestFile(files[j])
+	})
+	for _, f := range files {
+		if err := store.Delete(ctx, strings.TrimPrefix(f, "/")); err != nil {
+			return errors.Wrapf(err, "deleting %s", f)
+		}
+	}
+	return nil
+}
+
+// dropExpiredBackupsAsync runs maybeDropExpiredBackups in an async task, which
+// only logs its failures.
+func dropExpiredBackupsAsync(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	details jobspb.BackupDetails,
+	user username.SQLUsername,
+) {
+	if details.ScheduleID == jobspb.InvalidScheduleID || !details.StartTime.IsEmpty() {
+		return
+	}
+	taskCtx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	if err := execCfg.Stopper.RunAsyncTask(taskCtx, "drop-expired-backups", func(ctx context.Context) {
+		ctx, cancel := execCfg.Stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		if err := maybeDropExpiredBackups(ctx, execCfg, details, user); err != nil {
+			log.Warningf(ctx, "failed to drop expired backups: %v", err)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "failed to drop expired backups: %v", err)
+	}
+}
+
+// maybeDropExpiredBackups drops the chains of backups in the collection of a
+// scheduled full backup that has just completed which are no longer retained
+// by the RETENTION clause of its schedule. A chain is kept if it is one of the
+// newest RETENTION N FULL BACKUPS chains, or if it was still the newest chain
+// at some point within the RETENTION duration, since then it is needed to
+// restore to that time. The latest chain is never dropped.
+func maybeDropExpiredBackups(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	details jobspb.BackupDetails,
+	user username.SQLUsername,
+) error {
+	if details.ScheduleID == jobspb.InvalidScheduleID || !details.StartTime.IsEmpty() ||
+		details.CollectionURI == "" {
+		return nil
+	}
+
+	args, backupStmt, err := loadScheduledBackup(ctx, execCfg, details.ScheduleID)
+	if err != nil {
+		if jobs.HasScheduledJobNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if args.RetainFullBackups <= 0 && args.RetainFor <= 0 {
+		return nil
+	}
+	collections, err := scheduledBackupStrings(backupStmt.To)
+	if err != nil {
+		return err
+	}
+	// The incremental_location of the schedule is only recorded in the
+	// statement of its incremental schedule.
+	var incrementalStorage []string
+	if args.DependentScheduleID != jobspb.InvalidScheduleID {
+		_, incStmt, err := loadScheduledBackup(ctx, execCfg, args.DependentScheduleID)
+		if err != nil && !jobs.HasScheduledJobNotFoundError(err) {
+			return err
+		}
+		if incStmt != nil {
+			incrementalStorage, err = scheduledBackupStrings(incStmt.Options.IncrementalStorage)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, collections[0], user)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer store.Close()
+	fulls, err := backupdest.ListFullBackupsInCollection(ctx, store)
+	if err != nil {
+		return err
+	}
+	type chain struct {
+		subdir string
+		start  time.Time
+	}
+	var chains []chain
+	for _, full := range fulls {
+		full = "/" + strings.TrimPrefix(full, "/")
+		start, err := time.Parse(backupbase.DateBasedIntoFolderName, full)
+		if err != nil {
+			// Not a backup that was written by BACKUP INTO.
+			continue
+		}
+		chains = append(chains, chain{subdir: full, start: start})
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].start.Before(chains[j].start) })
+
+	cutoff := details.EndTime.GoTime().Add(-args.RetainFor)
+	for i := 0; i < len(chains)-1; i++ {
+		if chains[i].subdir == details.Destination.Subdir {
+			continue
+		}
+		if args.RetainFullBackups > 0 && int64(len(chains)-i) <= args.RetainFullBackups {
+			continue
+		}
+		if args.RetainFor > 0 && chains[i+1].start.After(cutoff) {
+			continue
+		}
+		// A chain that cannot be dropped, e.g. because a backup is still being
+		// appended to it, is retried after the next full backup.
+		if err := dropBackupChain(
+			ctx, execCfg, user, collections, chains[i].subdir, incrementalStorage,
+		); err != nil {
+			log.Warningf(ctx, "failed to drop expired backup %s: %v", chains[i].subdir, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook("backupccl.dropBackupPlanHook", dropBackupPlanHook, dropBackupTypeCheck)
+}
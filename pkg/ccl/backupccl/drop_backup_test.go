@@ -0,0 +1,162 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDropBackup tests that DROP BACKUP deletes a full backup along with its
+// incremental backups, and that it refuses to drop the latest backup or
+// anything that is not a full backup in the collection.
+func TestDropBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	var dropped string
+	sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&dropped)
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	var latest string
+	sqlDB.QueryRow(t, `SELECT max(path) FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&latest)
+	require.NotEqual(t, dropped, latest)
+
+	sqlDB.ExpectErr(t, "is the latest backup in the collection",
+		`DROP BACKUP $1 IN $2`, latest, localFoo)
+	sqlDB.ExpectErr(t, "cannot drop the LATEST backup",
+		`DROP BACKUP 'LATEST' IN $1`, localFoo)
+	sqlDB.ExpectErr(t, "is not a full backup in the collection",
+		`DROP BACKUP '/2000/01/01-000000.00' IN $1`, localFoo)
+	sqlDB.ExpectErr(t, "only supports the incremental_location option",
+		`DROP BACKUP $1 IN $2 WITH revision_history`, dropped, localFoo)
+
+	sqlDB.Exec(t, `DROP BACKUP $1 IN $2`, dropped, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT path FROM [SHOW BACKUPS IN '`+localFoo+`']`, [][]string{{latest}})
+	for _, d := range []string{dropped, filepath.Join("incrementals", dropped)} {
+		entries, err := os.ReadDir(filepath.Join(dir, "foo", d))
+		if !os.IsNotExist(err) {
+			require.NoError(t, err)
+			require.Empty(t, entries)
+		}
+	}
+
+	// The remaining chain is unaffected.
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'restored'`, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM restored.bank`,
+		sqlDB.QueryStr(t, `SELECT count(*) FROM data.bank`))
+}
+
+func TestDropBackupPrivileges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	var dropped string
+	sqlDB.QueryRow(t, `SELECT path FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&dropped)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+
+	sqlDB.Exec(t, `CREATE USER testuser`)
+	testuser := tc.ApplicationLayer(0).SQLConn(t, serverutils.User("testuser"))
+
+	_, err := testuser.Exec(`DROP BACKUP $1 IN $2`, dropped, localFoo)
+	require.True(t, testutils.IsError(err,
+		"only users with the admin role or the BACKUP system privilege are allowed to drop backups"), err)
+
+	// The user must also be allowed to access the collection.
+	sqlDB.Exec(t, `GRANT SYSTEM BACKUP TO testuser`)
+	_, err = testuser.Exec(`DROP BACKUP $1 IN $2`, dropped, localFoo)
+	require.True(t, testutils.IsError(err,
+		"only users with the admin role or the EXTERNALIOIMPLICITACCESS system privilege are allowed to access the specified nodelocal URI"), err)
+
+	sqlDB.Exec(t, `GRANT SYSTEM EXTERNALIOIMPLICITACCESS TO testuser`)
+	_, err = testuser.Exec(`DROP BACKUP $1 IN $2`, dropped, localFoo)
+	require.NoError(t, err)
+	var numBackups int
+	sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&numBackups)
+	require.Equal(t, 1, numBackups)
+}
+
+// TestDropExpiredBackups tests that the chains of backups of a schedule that
+// are no longer retained are dropped once a full backup completes.
+func TestDropExpiredBackups(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+	ctx := context.Background()
+	execCfg := tc.ApplicationLayer(0).ExecutorConfig().(sql.ExecutorConfig)
+
+	// The schedule never runs on its own; its backups are taken below.
+	var scheduleID jobspb.ScheduleID
+	sqlDB.QueryRow(t, `SELECT schedule_id FROM [CREATE SCHEDULE FOR BACKUP DATABASE data INTO '`+localFoo+`'
+		RECURRING '@yearly' FULL BACKUP ALWAYS RETENTION 2 FULL BACKUPS
+		WITH SCHEDULE OPTIONS first_run = '2100-01-01']`).Scan(&scheduleID)
+
+	var paths []string
+	for i := 0; i < 3; i++ {
+		sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+		sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+		var latest string
+		sqlDB.QueryRow(t, `SELECT max(path) FROM [SHOW BACKUPS IN $1]`, localFoo).Scan(&latest)
+		paths = append(paths, latest)
+	}
+	details := func(scheduleID jobspb.ScheduleID) jobspb.BackupDetails {
+		return jobspb.BackupDetails{
+			ScheduleID:    scheduleID,
+			CollectionURI: localFoo,
+			EndTime:       hlc.Timestamp{WallTime: execCfg.Clock.PhysicalNow()},
+			Destination:   jobspb.BackupDetails_Destination{Subdir: paths[len(paths)-1]},
+		}
+	}
+	showBackups := func() [][]string {
+		return sqlDB.QueryStr(t, `SELECT path FROM [SHOW BACKUPS IN $1] ORDER BY path`, localFoo)
+	}
+
+	// Backups which were not taken by a schedule have no retention.
+	require.NoError(t, maybeDropExpiredBackups(ctx, &execCfg, details(jobspb.InvalidScheduleID),
+		username.RootUserName()))
+	require.Len(t, showBackups(), 3)
+
+	require.NoError(t, maybeDropExpiredBackups(ctx, &execCfg, details(scheduleID),
+		username.RootUserName()))
+	require.Equal(t, [][]string{{paths[1]}, {paths[2]}}, showBackups())
+
+	// The backups of a dropped schedule are no longer dropped.
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `DROP SCHEDULE $1`, scheduleID)
+	require.NoError(t, maybeDropExpiredBackups(ctx, &execCfg, details(scheduleID),
+		username.RootUserName()))
+	require.Len(t, showBackups(), 3)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnvelopeEncryptionEndToEnd tests that BACKUP, RESTORE and EXPORT work
+// with nodelocal and userfile URIs that have the ENCRYPTION_KMS parameter, and
+// that the files they write are only readable through such a URI.
+func TestEnvelopeEncryptionEndToEnd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+	ctx := context.Background()
+	execCfg := tc.ApplicationLayer(0).ExecutorConfig().(sql.ExecutorConfig)
+
+	withKMS := func(uri string) string {
+		return uri + "?" + cloud.EnvelopeEncryptionKMSParam + "=" + url.QueryEscape("testkms:///key1")
+	}
+	// readFiles returns the contents of the files under uri, by name.
+	readFiles := func(uri string) map[string]string {
+		store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, username.RootUserName())
+		require.NoError(t, err)
+		defer store.Close()
+		files := make(map[string]string)
+		require.NoError(t, store.List(ctx, "", "", func(f string) error {
+			r, _, err := store.ReadFile(ctx, f, cloud.ReadOptions{NoFileSize: true})
+			if err != nil {
+				return err
+			}
+			defer r.Close(ctx)
+			data, err := ioctx.ReadAll(ctx, r)
+			files[f] = string(data)
+			return err
+		}))
+		return files
+	}
+
+	for _, dir := range []string{"nodelocal://1/envelope", "userfile:///envelope"} {
+		t.Run(strings.Split(dir, ":")[0], func(t *testing.T) {
+			backupURI := withKMS(dir + "/backup")
+			sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, backupURI)
+			sqlDB.Exec(t, `RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'restored'`, backupURI)
+			sqlDB.CheckQueryResults(t, `SELECT * FROM restored.bank ORDER BY id`,
+				sqlDB.QueryStr(t, `SELECT * FROM data.bank ORDER BY id`))
+			sqlDB.Exec(t, `DROP DATABASE restored`)
+
+			exportURI := withKMS(dir + "/export")
+			sqlDB.Exec(t, `EXPORT INTO CSV $1 FROM TABLE data.bank`, exportURI)
+			exported := readFiles(exportURI)
+			require.NotEmpty(t, exported)
+			raw := readFiles(dir + "/export")
+			var numRows int
+			for name, contents := range exported {
+				numRows += strings.Count(contents, "\n")
+				require.NotEqual(t, contents, raw[name])
+				require.NotContains(t, raw[name], contents)
+			}
+			require.Equal(t, numAccounts, numRows)
+		})
+	}
+}
@@ -11,10 +11,15 @@ package backupccl
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	io "io"
+	"path"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
@@ -38,6 +43,9 @@ type sstSinkConf struct {
 	enc      *kvpb.FileEncryptionOptions
 	id       base.SQLInstanceID
 	settings *settings.Values
+	// dedupBlobDir is the deduplicated blobs directory that the files are
+	// written to, if the backup is taken with the deduplicate option.
+	dedupBlobDir string
 }
 
 type fileSSTSink struct {
@@ -51,6 +59,14 @@ type fileSSTSink struct {
 	out     io.WriteCloser
 	outName string
 
+	// dedup buffers the file that is being written if the sink writes to a
+	// deduplicated blobs directory. It is reused across files.
+	dedup *dedupFile
+	// checksum is the hash of the file that is being written, as it is written
+	// to the destination, if the sink does not write to a deduplicated blobs
+	// directory. It is reused across files.
+	checksum hash.Hash
+
 	flushedFiles []backuppb.BackupManifest_File
 	flushedSize  int64
 
@@ -77,6 +93,7 @@ type fileSSTSink struct {
 		oooFlushes  int // number of out of order flushes.
 		sizeFlushes int // number of flushes due to file exceeding targetFileSize.
 		spanGrows   int // number of times a span was extended.
+		dedupHits   int // number of files that were already in the blobs directory.
 	}
 }
 
@@ -91,8 +108,8 @@ func makeFileSSTSink(
 
 func (s *fileSSTSink) Close() error {
 	if log.V(1) && s.ctx != nil {
-		log.Infof(s.ctx, "backup sst sink recv'd %d files, wrote %d (%d due to size, %d due to re-ordering), %d recv files extended prior span",
-			s.stats.files, s.stats.flushes, s.stats.sizeFlushes, s.stats.oooFlushes, s.stats.spanGrows)
+		log.Infof(s.ctx, "backup sst sink recv'd %d files, wrote %d (%d due to size, %d due to re-ordering, %d already stored), %d recv files extended prior span",
+			s.stats.files, s.stats.flushes, s.stats.sizeFlushes, s.stats.oooFlushes, s.stats.dedupHits, s.stats.spanGrows)
 	}
 	if s.cancel != nil {
 		s.cancel()
@@ -150,12 +167,22 @@ func (s *fileSSTSink) flushFile(ctx context.Context) error {
 		log.Warningf(ctx, "failed to close write in fileSSTSink: % #v", pretty.Formatter(err))
 		return errors.Wrap(err, "writing SST")
 	}
+	if s.conf.dedupBlobDir != "" {
+		if err := s.writeDedupFile(ctx); err != nil {
+			return err
+		}
+	}
 	wroteSize := s.sst.Meta.Size
 	s.outName = ""
 	s.out = nil
 
+	var checksum []byte
+	if s.conf.dedupBlobDir == "" {
+		checksum = s.checksum.Sum(nil)
+	}
 	for i := range s.flushedFiles {
 		s.flushedFiles[i].BackingFileSize = wroteSize
+		s.flushedFiles[i].Checksum = checksum
 	}
 
 	progDetails := backuppb.BackupManifest_Progress{
@@ -189,17 +216,30 @@ func (s *fileSSTSink) open(ctx context.Context) error {
 	if s.ctx == nil {
 		s.ctx, s.cancel = context.WithCancel(ctx)
 	}
-	w, err := s.dest.Writer(s.ctx, s.outName)
-	if err != nil {
-		return err
-	}
-	s.out = w
-	if s.conf.enc != nil {
-		e, err := storageccl.EncryptingWriter(w, s.conf.enc.Key)
+	if s.conf.dedupBlobDir != "" {
+		// The file is encrypted once it is complete, see writeDedupFile.
+		if s.dedup == nil {
+			s.dedup = &dedupFile{}
+		}
+		s.dedup.Reset()
+		s.out = s.dedup
+	} else {
+		w, err := s.dest.Writer(s.ctx, s.outName)
 		if err != nil {
 			return err
 		}
-		s.out = e
+		if s.checksum == nil {
+			s.checksum = sha256.New()
+		}
+		s.checksum.Reset()
+		s.out = hashingWriteCloser{WriteCloser: w, hash: s.checksum}
+		if s.conf.enc != nil {
+			e, err := storageccl.EncryptingWriter(s.out, s.conf.enc.Key)
+			if err != nil {
+				return err
+			}
+			s.out = e
+		}
 	}
 	// TODO(dt): make ExternalStorage.Writer return objstorage.Writable.
 	//
@@ -217,6 +257,75 @@ func (s *fileSSTSink) open(ctx context.Context) error {
 	return nil
 }
 
+// writeDedupFile writes the file buffered by the sink to the deduplicated blobs
+// directory, named after the hash of the bytes that are stored, unless a file
+// with that name is already stored there, and points the flushed files to it.
+// If the backup is encrypted, the file is encrypted with an IV derived from its
+// contents, so that the same data encrypted with the same key is stored in the
+// same file, and the hash is computed over the ciphertext.
+func (s *fileSSTSink) writeDedupFile(ctx context.Context) error {
+	data := s.dedup.Bytes()
+	if s.conf.enc != nil {
+		var err error
+		data, err = storageccl.EncryptFileConvergently(data, s.conf.enc.Key)
+		if err != nil {
+			return err
+		}
+	}
+	sum := sha256.Sum256(data)
+	hexSum := hex.EncodeToString(sum[:])
+	// The reference to the blob is recorded before the blob is looked up, so
+	// that a blob that is found is not collected as unreferenced once this
+	// backup's job has completed, see collectDeduplicatedBlobs.
+	ref := path.Join(backupbase.DeduplicatedBlobRefsDirectory, hexSum)
+	if err := cloud.WriteFile(ctx, s.dest, ref, bytes.NewReader(nil)); err != nil {
+		return errors.Wrap(err, "writing blob reference")
+	}
+	name := path.Join(s.conf.dedupBlobDir, hexSum+".sst")
+	r, _, err := s.dest.ReadFile(ctx, name, cloud.ReadOptions{NoFileSize: true})
+	if err == nil {
+		r.Close(ctx)
+		s.stats.dedupHits++
+		log.VEventf(ctx, 2, "not writing backup file %s since it is already stored as %s", s.outName, name)
+	} else if errors.Is(err, cloud.ErrFileDoesNotExist) {
+		if err := cloud.WriteFile(ctx, s.dest, name, bytes.NewReader(data)); err != nil {
+			return errors.Wrap(err, "writing SST")
+		}
+	} else {
+		return err
+	}
+	for i := range s.flushedFiles {
+		s.flushedFiles[i].Path = name
+		s.flushedFiles[i].ContentHash = sum[:]
+	}
+	return nil
+}
+
+// dedupFile buffers a file written by a sink for a backup taken with the
+// deduplicate option until the file is complete and can be named after the
+// hash of its contents, which is why such sinks hold up to a file worth of
+// memory, or twice that while an encrypted file is encrypted.
+type dedupFile struct {
+	bytes.Buffer
+}
+
+// Close implements io.Closer.
+func (*dedupFile) Close() error { return nil }
+
+// hashingWriteCloser adds everything written to it to a hash before passing it
+// on to the writer it wraps.
+type hashingWriteCloser struct {
+	io.WriteCloser
+	hash hash.Hash
+}
+
+// Write implements io.Writer.
+func (w hashingWriteCloser) Write(p []byte) (int, error) {
+	// Writes to a hash never fail.
+	_, _ = w.hash.Write(p)
+	return w.WriteCloser.Write(p)
+}
+
 func (s *fileSSTSink) writeWithNoData(resp exportedSpan) {
 	s.completedSpans += resp.completedSpans
 	s.midRow = false
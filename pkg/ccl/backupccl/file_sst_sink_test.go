@@ -11,24 +11,30 @@ package backupccl
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
 	"reflect"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
 	"github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -548,6 +554,139 @@ func TestFileSSTSinkStats(t *testing.T) {
 	require.NoError(t, sink.flush(ctx))
 }
 
+// TestFileSSTSinkDeduplicate tests that a sink writing files for a backup taken
+// with the deduplicate option names the files it flushes after their contents,
+// and does not upload a file again if the same contents are already stored.
+func TestFileSSTSinkDeduplicate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	sink, store := fileSSTSinkTestSetUp(ctx, t, st)
+	sink.conf.dedupBlobDir = "blobs"
+	defer func() {
+		require.NoError(t, sink.Close())
+	}()
+
+	// writeAndFlush writes a span to the sink, flushes it and returns the file
+	// that was flushed.
+	writeAndFlush := func(es exportedSpan) backuppb.BackupManifest_File {
+		_, err := sink.write(ctx, es)
+		require.NoError(t, err)
+		require.NoError(t, sink.flush(ctx))
+
+		p := <-sink.conf.progCh
+		var progDetails backuppb.BackupManifest_Progress
+		require.NoError(t, types.UnmarshalAny(&p.ProgressDetails, &progDetails))
+		require.Equal(t, 1, len(progDetails.Files))
+		return progDetails.Files[0]
+	}
+	kvs := []kvAndTS{{key: "a", timestamp: 10}, {key: "b", timestamp: 10}}
+
+	first := writeAndFlush(newExportedSpanBuilder("a", "c").withKVs(kvs).build())
+	require.True(t, strings.HasPrefix(first.Path, "blobs/"), first.Path)
+	require.NotEmpty(t, first.ContentHash)
+	require.Empty(t, first.Checksum)
+	require.Equal(t, 0, sink.stats.dedupHits)
+	require.NoError(t, checkFiles(ctx, store, []backuppb.BackupManifest_File{first},
+		[]roachpb.Spans{{first.Span}}, false /* elided */))
+	// The backup records that it references the blob.
+	refReader, _, err := store.ReadFile(ctx, path.Join(
+		backupbase.DeduplicatedBlobRefsDirectory, hex.EncodeToString(first.ContentHash),
+	), cloud.ReadOptions{NoFileSize: true})
+	require.NoError(t, err)
+	require.NoError(t, refReader.Close(ctx))
+
+	// Writing the same data again reuses the stored file.
+	second := writeAndFlush(newExportedSpanBuilder("a", "c").withKVs(kvs).build())
+	require.Equal(t, first.Path, second.Path)
+	require.Equal(t, first.ContentHash, second.ContentHash)
+	require.Equal(t, 1, sink.stats.dedupHits)
+
+	// Different data is stored in a new file.
+	third := writeAndFlush(newExportedSpanBuilder("a", "c").withKVs(
+		[]kvAndTS{{key: "a", timestamp: 20}}).build())
+	require.NotEqual(t, first.Path, third.Path)
+	require.Equal(t, 1, sink.stats.dedupHits)
+
+	// The same data written with encryption is stored in a file of its own,
+	// named after the hash of its ciphertext, which is shared by the files with
+	// the same data encrypted with the same key.
+	encSink, _ := fileSSTSinkTestSetUp(ctx, t, st)
+	encSink.dest = store
+	encSink.conf.dedupBlobDir = "blobs"
+	encSink.conf.enc = &kvpb.FileEncryptionOptions{Key: bytes.Repeat([]byte("k"), 32)}
+	defer func() {
+		require.NoError(t, encSink.Close())
+	}()
+	encWriteAndFlush := func() backuppb.BackupManifest_File {
+		_, err := encSink.write(ctx, newExportedSpanBuilder("a", "c").withKVs(kvs).build())
+		require.NoError(t, err)
+		require.NoError(t, encSink.flush(ctx))
+		p := <-encSink.conf.progCh
+		var progDetails backuppb.BackupManifest_Progress
+		require.NoError(t, types.UnmarshalAny(&p.ProgressDetails, &progDetails))
+		require.Equal(t, 1, len(progDetails.Files))
+		return progDetails.Files[0]
+	}
+	encFirst := encWriteAndFlush()
+	require.NotEqual(t, first.Path, encFirst.Path)
+	require.Equal(t, 0, encSink.stats.dedupHits)
+
+	r, _, err := store.ReadFile(ctx, encFirst.Path, cloud.ReadOptions{NoFileSize: true})
+	require.NoError(t, err)
+	ciphertext, err := ioctx.ReadAll(ctx, r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close(ctx))
+	require.True(t, storageccl.AppearsEncrypted(ciphertext))
+	sum := sha256.Sum256(ciphertext)
+	require.Equal(t, sum[:], encFirst.ContentHash)
+
+	encSecond := encWriteAndFlush()
+	require.Equal(t, encFirst.Path, encSecond.Path)
+	require.Equal(t, 1, encSink.stats.dedupHits)
+}
+
+// TestFileSSTSinkChecksum tests that the files flushed by a sink record the
+// checksum of the file as it was written to the destination.
+func TestFileSSTSinkChecksum(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	for _, enc := range []*kvpb.FileEncryptionOptions{
+		nil, {Key: bytes.Repeat([]byte("k"), 32)},
+	} {
+		sink, store := fileSSTSinkTestSetUp(ctx, t, st)
+		sink.conf.enc = enc
+		for _, kvs := range [][]kvAndTS{
+			{{key: "a", timestamp: 10}, {key: "b", timestamp: 10}},
+			{{key: "d", timestamp: 20}},
+		} {
+			_, err := sink.write(ctx, newExportedSpanBuilder(kvs[0].key, "z").withKVs(kvs).build())
+			require.NoError(t, err)
+			require.NoError(t, sink.flush(ctx))
+
+			p := <-sink.conf.progCh
+			var progDetails backuppb.BackupManifest_Progress
+			require.NoError(t, types.UnmarshalAny(&p.ProgressDetails, &progDetails))
+			require.Equal(t, 1, len(progDetails.Files))
+			file := progDetails.Files[0]
+
+			r, _, err := store.ReadFile(ctx, file.Path, cloud.ReadOptions{NoFileSize: true})
+			require.NoError(t, err)
+			contents, err := ioctx.ReadAll(ctx, r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close(ctx))
+			sum := sha256.Sum256(contents)
+			require.Equal(t, sum[:], file.Checksum)
+		}
+		require.NoError(t, sink.Close())
+	}
+}
+
 func TestFileSSTSinkCopyPointKeys(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -393,6 +393,10 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 		if err != nil {
 			return errors.Wrap(err, "creating key rewriter from rekeys")
 		}
+		rowFilter, err := makeRestoreRowFilter(ctx, rd.FlowCtx, &rd.spec)
+		if err != nil {
+			return errors.Wrap(err, "creating row filter")
+		}
 
 		var sstIter mergedSST
 		for {
@@ -416,7 +420,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 						return done, errors.Wrap(err, "opening SSTs")
 					}
 
-					summary, err := rd.processRestoreSpanEntry(ctx, kr, sstIter)
+					summary, err := rd.processRestoreSpanEntry(ctx, kr, rowFilter, sstIter)
 					if err != nil {
 						return done, errors.Wrap(err, "processing restore span entry")
 					}
@@ -444,7 +448,7 @@ func (rd *restoreDataProcessor) runRestoreWorkers(
 }
 
 func (rd *restoreDataProcessor) processRestoreSpanEntry(
-	ctx context.Context, kr *KeyRewriter, sst mergedSST,
+	ctx context.Context, kr *KeyRewriter, rowFilter *restoreRowFilter, sst mergedSST,
 ) (kvpb.BulkOpSummary, error) {
 	db := rd.FlowCtx.Cfg.DB
 	var summary kvpb.BulkOpSummary
@@ -574,6 +578,17 @@ func (rd *restoreDataProcessor) processRestoreSpanEntry(
 			continue
 		}
 
+		if rowFilter != nil {
+			if ok, err := rowFilter.ingest(ctx, key.Key, value); err != nil {
+				return summary, err
+			} else if !ok {
+				if verbose {
+					log.Infof(ctx, "skipping %s %s filtered by row_filter", key.Key, value.PrettyPrint())
+				}
+				continue
+			}
+		}
+
 		// Rewriting the key means the checksum needs to be updated.
 		value.ClearChecksum()
 		value.InitChecksum(key.Key)
@@ -552,6 +552,8 @@ func restore(
 			numImportSpans:     numImportSpans,
 			execLocality:       details.ExecutionLocality,
 			exclusiveEndKeys:   fsc.isExclusive(),
+			rowFilter:          details.RowFilter,
+			rowFilterTableID:   details.RowFilterTableID,
 		}
 		return errors.Wrap(distRestore(
 			ctx,
@@ -1847,6 +1849,18 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 	if err == nil {
 		remappedStats = remapAndFilterRelevantStatistics(ctx, backupStats, details.DescriptorRewrites,
 			details.TableDescs)
+		if details.RowFilterTableID != descpb.InvalidID {
+			// The statistics of a table restored with a row filter describe the
+			// rows of the backup rather than the ones that were restored, so the
+			// table is left without statistics until they are collected again.
+			filtered := remappedStats[:0]
+			for _, stat := range remappedStats {
+				if stat.TableID != details.RowFilterTableID {
+					filtered = append(filtered, stat)
+				}
+			}
+			remappedStats = filtered
+		}
 	} else {
 		// We don't want to fail the restore if we are unable to resolve statistics
 		// from the backup, since they can be recomputed after the restore has
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/rewrite"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
@@ -955,6 +956,45 @@ func resolveTargetDB(
 	return database.Name, nil
 }
 
+// renameRestoredTable renames the single table restored by a `RESTORE TABLE t
+// AS new_t` statement to new_t, and returns the name of the database that the
+// table is restored into. The table keeps the schema name it had when it was
+// backed up. Index and constraint names are scoped to their table, so they are
+// left untouched.
+func renameRestoredTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	asTable *tree.UnresolvedObjectName,
+	descsByTablePattern map[tree.TablePattern]catalog.Descriptor,
+	tablesByID map[descpb.ID]*tabledesc.Mutable,
+	schemasByID map[descpb.ID]*schemadesc.Mutable,
+) (string, error) {
+	var table *tabledesc.Mutable
+	for _, desc := range descsByTablePattern {
+		table = tablesByID[desc.GetID()]
+	}
+	if len(descsByTablePattern) != 1 || table == nil {
+		return "", errors.AssertionFailedf("expected to restore a single table AS %s", asTable)
+	}
+
+	prefix, _, err := resolver.ResolveTargetObject(ctx, p, asTable)
+	if err != nil {
+		return "", err
+	}
+	schemaName := catconstants.PublicSchemaName
+	if sc, ok := schemasByID[table.GetParentSchemaID()]; ok {
+		schemaName = sc.GetName()
+	}
+	if prefix.Schema.GetName() != schemaName {
+		return "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot restore table %s of schema %s into schema %s",
+			tree.Name(table.GetName()), tree.Name(schemaName), tree.Name(prefix.Schema.GetName()))
+	}
+
+	table.SetName(asTable.Object())
+	return prefix.Database.GetName(), nil
+}
+
 // maybeUpgradeDescriptors performs post-deserialization upgrades on the
 // descriptors.
 //
@@ -1082,6 +1122,7 @@ func resolveOptionsForRestoreJobDescription(
 		ExecutionLocality:                opts.ExecutionLocality,
 		ExperimentalOnline:               opts.ExperimentalOnline,
 		RemoveRegions:                    opts.RemoveRegions,
+		RowFilter:                        opts.RowFilter,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -1137,6 +1178,7 @@ func restoreJobDescription(
 		Targets:            restore.Targets,
 		From:               make([]tree.StringOrPlaceholderOptList, len(restore.From)),
 		Subdir:             tree.NewDString("/" + strings.TrimPrefix(resolvedSubdir, "/")),
+		AsTable:            restore.AsTable,
 	}
 
 	var options tree.RestoreOptions
@@ -1186,6 +1228,7 @@ func restoreTypeCheck(
 			restoreStmt.Options.ForceTenantID,
 			restoreStmt.Options.AsTenant,
 			restoreStmt.Options.ExecutionLocality,
+			restoreStmt.Options.RowFilter,
 		},
 	); err != nil {
 		return false, nil, err
@@ -1270,6 +1313,11 @@ func restorePlanHook(
 		if restoreStmt.DescriptorCoverage == tree.SystemUsers {
 			return nil, nil, nil, false, errors.New("cannot set into_db option when only restoring system users")
 		}
+		if restoreStmt.AsTable != nil {
+			return nil, nil, nil, false, errors.Errorf(
+				"cannot set %s option when restoring a table AS a new name, qualify the new name with "+
+					"the target database instead", restoreOptIntoDB)
+		}
 		var err error
 		intoDB, err = exprEval.String(ctx, restoreStmt.Options.IntoDB)
 		if err != nil {
@@ -1344,6 +1392,29 @@ func restorePlanHook(
 		return nil, nil, nil, false, errors.New("cannot run online restore with verify_backup_table_data")
 	}
 
+	var rowFilter string
+	if restoreStmt.Options.RowFilter != nil {
+		if restoreStmt.DescriptorCoverage != tree.RequestedDescriptors ||
+			restoreStmt.Targets.Tables.TablePatterns == nil {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option can only be used with RESTORE TABLE", restoreOptRowFilter)
+		}
+		if restoreStmt.Options.SchemaOnly || restoreStmt.Options.ExperimentalOnline {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option cannot be used with schema_only or EXPERIMENTAL DEFERRED COPY", restoreOptRowFilter)
+		}
+		// Restore processors running earlier versions would ignore the filter.
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_RestoreRowFilter) {
+			return nil, nil, nil, false, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option is only supported after v24.2 upgrade is finalized", restoreOptRowFilter)
+		}
+		var err error
+		rowFilter, err = exprEval.String(ctx, restoreStmt.Options.RowFilter)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	var newTenantID *roachpb.TenantID
 	var newTenantName *roachpb.TenantName
 	if restoreStmt.Options.AsTenant != nil || restoreStmt.Options.ForceTenantID != nil {
@@ -1431,7 +1502,7 @@ func restorePlanHook(
 
 		return doRestorePlan(
 			ctx, restoreStmt, &exprEval, p, from, incStorage, pw, kms, intoDB,
-			newDBName, newTenantID, newTenantName, endTime, resultsCh, subdir, execLocality,
+			newDBName, newTenantID, newTenantName, endTime, resultsCh, subdir, execLocality, rowFilter,
 		)
 	}
 
@@ -1683,6 +1754,7 @@ func doRestorePlan(
 	resultsCh chan<- tree.Datums,
 	subdir string,
 	execLocality roachpb.Locality,
+	rowFilter string,
 ) error {
 	if len(from) == 0 || len(from[0]) == 0 {
 		return errors.New("invalid base backup specified")
@@ -2004,6 +2076,22 @@ func doRestorePlan(
 		return err
 	}
 
+	if restoreStmt.AsTable != nil {
+		intoDB, err = renameRestoredTable(ctx, p, restoreStmt.AsTable, descsByTablePattern,
+			filteredTablesByID, schemasByID)
+		if err != nil {
+			return err
+		}
+	}
+
+	var rowFilterTable *tabledesc.Mutable
+	if rowFilter != "" {
+		rowFilterTable, rowFilter, err = resolveRestoreRowFilter(ctx, p, rowFilter, filteredTablesByID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// When running a full cluster restore, we drop the defaultdb and postgres
 	// databases that are present in a new cluster.
 	// This is done so that they can be restored the same way any other user
@@ -2139,6 +2227,12 @@ func doRestorePlan(
 		RemoveRegions:                    restoreStmt.Options.RemoveRegions,
 		UnsafeRestoreIncompatibleVersion: restoreStmt.Options.UnsafeRestoreIncompatibleVersion,
 	}
+	if rowFilterTable != nil {
+		// The table descriptor has been rewritten above, so this is the ID of the
+		// table that the restore creates.
+		restoreDetails.RowFilter = rowFilter
+		restoreDetails.RowFilterTableID = rowFilterTable.GetID()
+	}
 
 	jr := jobs.Record{
 		Description: description,
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -45,6 +46,8 @@ type restoreJobMetadata struct {
 	numImportSpans     int
 	execLocality       roachpb.Locality
 	exclusiveEndKeys   bool
+	rowFilter          string
+	rowFilterTableID   descpb.ID
 }
 
 // distRestore plans a 2 stage distSQL flow for a distributed restore. It
@@ -119,6 +122,9 @@ func distRestore(
 			TenantRekeys: md.dataToRestore.getTenantRekeys(),
 			PKIDs:        md.dataToRestore.getPKIDs(),
 			ValidateOnly: md.dataToRestore.isValidateOnly(),
+
+			RowFilter:        md.rowFilter,
+			RowFilterTableID: md.rowFilterTableID,
 		}
 
 		// Plan SplitAndScatter in a round-robin fashion.
@@ -0,0 +1,257 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+const restoreOptRowFilter = "row_filter"
+
+// resolveRestoreRowFilter validates the row_filter option of a RESTORE against
+// the single table that the RESTORE restores, and returns that table along
+// with the type-checked filter.
+//
+// The filter may only reference the columns of the primary key of the table.
+// These columns are encoded in every KV of every index of the table, which
+// allows the restore data processors to evaluate the filter on each KV they
+// ingest on its own, and to keep the secondary indexes of the restored table
+// consistent with its primary index.
+func resolveRestoreRowFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	filter string,
+	tablesByID map[descpb.ID]*tabledesc.Mutable,
+) (*tabledesc.Mutable, string, error) {
+	var table *tabledesc.Mutable
+	for _, t := range tablesByID {
+		if !t.IsPhysicalTable() || t.IsSequence() {
+			continue
+		}
+		if table != nil {
+			return nil, "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option can only be used when restoring a single table", restoreOptRowFilter)
+		}
+		table = t
+	}
+	if table == nil {
+		return nil, "", pgerror.Newf(pgcode.FeatureNotSupported,
+			"%q option can only be used when restoring a single table", restoreOptRowFilter)
+	}
+
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, "", pgerror.Wrapf(err, pgcode.InvalidParameterValue,
+			"invalid %q option", restoreOptRowFilter)
+	}
+	tn := tree.NewUnqualifiedTableName(tree.Name(table.GetName()))
+	serialized, _, colIDs, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx, table, expr, types.Bool, tree.RestoreRowFilterExpr, p.SemaCtx(),
+		volatility.Immutable, tn, p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keyCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	for _, colID := range colIDs.Ordered() {
+		col, err := catalog.MustFindColumnByID(table, colID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !keyCols.Contains(colID) {
+			return nil, "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option may only reference columns of the primary key of %s, but references %s",
+				restoreOptRowFilter, tree.Name(table.GetName()), tree.Name(col.GetName()))
+		}
+		if col.GetType().UserDefined() {
+			return nil, "", pgerror.Newf(pgcode.FeatureNotSupported,
+				"%q option cannot reference column %s of user-defined type %s",
+				restoreOptRowFilter, tree.Name(col.GetName()), col.GetType().SQLString())
+		}
+	}
+	return table, serialized, nil
+}
+
+// restoreRowFilter is used by a restore data processor to decide which of the
+// KVs of the table restored with the row_filter option it ingests. Each KV is
+// decoded on its own into the primary key columns of the row it belongs to,
+// which is all that the filter references.
+type restoreRowFilter struct {
+	codec keys.SQLCodec
+	table catalog.TableDescriptor
+	// colIDs are the IDs of the primary key columns of the table, which are
+	// fetched from every KV.
+	colIDs []descpb.ColumnID
+
+	expr    tree.TypedExpr
+	evalCtx *eval.Context
+	ivars   schemaexpr.RowIndexedVarContainer
+
+	// fetchers holds a fetcher for every index of the table that a KV has been
+	// seen for. It holds nil for the IDs of indexes that are not part of the
+	// table descriptor.
+	fetchers map[descpb.IndexID]*row.Fetcher
+	kvs      row.KVProvider
+	alloc    tree.DatumAlloc
+}
+
+// makeRestoreRowFilter returns the row filter that a restore data processor
+// with the passed spec applies, or nil if the restore has no row filter. A
+// filter may not be used concurrently.
+func makeRestoreRowFilter(
+	ctx context.Context, flowCtx *execinfra.FlowCtx, spec *execinfrapb.RestoreDataSpec,
+) (*restoreRowFilter, error) {
+	if spec.RowFilter == "" {
+		return nil, nil
+	}
+
+	var table catalog.TableDescriptor
+	for _, rekey := range spec.TableRekeys {
+		if rekey.OldID == 0 {
+			continue
+		}
+		var desc descpb.Descriptor
+		if err := protoutil.Unmarshal(rekey.NewDesc, &desc); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling rekey descriptor for old table id %d", rekey.OldID)
+		}
+		if t, _, _, _, _ := descpb.GetDescriptors(&desc); t != nil && t.ID == spec.RowFilterTableID {
+			table = tabledesc.NewBuilder(t).BuildImmutableTable()
+			break
+		}
+	}
+	if table == nil {
+		return nil, errors.AssertionFailedf("table %d filtered by %q not found in rekeys",
+			spec.RowFilterTableID, restoreOptRowFilter)
+	}
+
+	primary := table.GetPrimaryIndex()
+	cols := make([]catalog.Column, primary.NumKeyColumns())
+	colIDs := make([]descpb.ColumnID, primary.NumKeyColumns())
+	var mapping catalog.TableColMap
+	for i := range cols {
+		col, err := catalog.MustFindColumnByID(table, primary.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+		colIDs[i] = col.GetID()
+		mapping.Set(col.GetID(), i)
+	}
+
+	evalCtx := flowCtx.NewEvalCtx()
+	semaCtx := tree.MakeSemaContext(nil /* resolver */)
+	expr, err := schemaexpr.MakeFilterExpr(ctx, spec.RowFilter, cols, table, evalCtx, &semaCtx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %q option", restoreOptRowFilter)
+	}
+
+	return &restoreRowFilter{
+		codec:    flowCtx.Codec(),
+		table:    table,
+		colIDs:   colIDs,
+		expr:     expr,
+		evalCtx:  evalCtx,
+		ivars:    schemaexpr.RowIndexedVarContainer{Cols: cols, Mapping: mapping},
+		fetchers: make(map[descpb.IndexID]*row.Fetcher),
+	}, nil
+}
+
+// ingest returns whether the KV with the passed rewritten key should be
+// ingested. KVs of other tables are always ingested.
+func (f *restoreRowFilter) ingest(
+	ctx context.Context, key roachpb.Key, value roachpb.Value,
+) (bool, error) {
+	_, tableID, indexID, err := f.codec.DecodeIndexPrefix(key)
+	if err != nil {
+		return false, err
+	}
+	if descpb.ID(tableID) != f.table.GetID() {
+		return true, nil
+	}
+	fetcher, err := f.fetcher(ctx, descpb.IndexID(indexID))
+	if err != nil {
+		return false, err
+	}
+	if fetcher == nil {
+		// The KV does not belong to an index of the restored table, so no row
+		// of the table is lost by not ingesting it.
+		return false, nil
+	}
+
+	f.kvs.KVs = append(f.kvs.KVs[:0], roachpb.KeyValue{Key: key, Value: value})
+	if err := fetcher.ConsumeKVProvider(ctx, &f.kvs); err != nil {
+		return false, err
+	}
+	datums, err := fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return false, err
+	}
+	if datums == nil {
+		return false, errors.AssertionFailedf("failed to decode row of key %s", key)
+	}
+
+	f.ivars.CurSourceRow = datums
+	f.evalCtx.PushIVarContainer(&f.ivars)
+	defer f.evalCtx.PopIVarContainer()
+	res, err := eval.Expr(ctx, f.evalCtx, f.expr)
+	if err != nil {
+		return false, errors.Wrapf(err, "evaluating %q option", restoreOptRowFilter)
+	}
+	return res == tree.DBoolTrue, nil
+}
+
+func (f *restoreRowFilter) fetcher(
+	ctx context.Context, indexID descpb.IndexID,
+) (*row.Fetcher, error) {
+	if fetcher, ok := f.fetchers[indexID]; ok {
+		return fetcher, nil
+	}
+	idx := catalog.FindIndexByID(f.table, indexID)
+	if idx == nil {
+		f.fetchers[indexID] = nil
+		return nil, nil
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, f.codec, f.table, idx, f.colIDs); err != nil {
+		return nil, err
+	}
+	fetcher := &row.Fetcher{}
+	if err := fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &f.alloc,
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	f.fetchers[indexID] = fetcher
+	return fetcher, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// TestRestoreRowFilter tests that RESTORE TABLE with the row_filter option only
+// restores the rows that satisfy the filter, in both the primary and the
+// secondary indexes of the table, and none of the statistics of the table.
+func TestRestoreRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TABLE data.t (
+		customer_id INT, id INT, v STRING, PRIMARY KEY (customer_id, id), INDEX v_idx (v)
+	)`)
+	sqlDB.Exec(t, `INSERT INTO data.t SELECT i % 4, i, 'v' || i::STRING FROM generate_series(1, 100) AS g(i)`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.stats.automatic_collection.enabled = false`)
+	sqlDB.Exec(t, `CREATE STATISTICS s FROM data.t`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+
+	sqlDB.ExpectErr(t, "can only be used with RESTORE TABLE",
+		`RESTORE DATABASE data FROM LATEST IN $1 WITH new_db_name = 'x', row_filter = 'customer_id = 2'`, localFoo)
+	sqlDB.ExpectErr(t, "can only be used when restoring a single table",
+		`RESTORE TABLE data.* FROM LATEST IN $1 WITH into_db = 'defaultdb', row_filter = 'customer_id = 2'`, localFoo)
+	sqlDB.ExpectErr(t, "may only reference columns of the primary key",
+		`RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'defaultdb', row_filter = 'v = ''v2'''`, localFoo)
+
+	sqlDB.Exec(t, `CREATE DATABASE x`)
+	sqlDB.Exec(t, `RESTORE TABLE data.t FROM LATEST IN $1 WITH into_db = 'x', row_filter = 'customer_id = 2'`, localFoo)
+
+	expected := sqlDB.QueryStr(t, `SELECT * FROM data.t WHERE customer_id = 2 ORDER BY id`)
+	sqlDB.CheckQueryResults(t, `SELECT * FROM x.t ORDER BY id`, expected)
+	sqlDB.CheckQueryResults(t, `SELECT customer_id, id, v FROM x.t@v_idx ORDER BY id`, expected)
+
+	// The statistics of the backed up table do not describe the restored rows.
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW STATISTICS FOR TABLE x.t]`, [][]string{{"0"}})
+}
@@ -10,6 +10,7 @@ package backupccl
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
@@ -218,6 +220,8 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 
 	recurrence := sj.ScheduleExpr()
 	fullBackup := &tree.FullBackupClause{AlwaysFull: true}
+	compactAfterIncrementals := args.CompactAfterIncrementals
+	retention := makeBackupRetentionClause(args)
 
 	// Check if sj has a dependent full or incremental schedule associated with it.
 	var dependentSchedule *jobs.ScheduledJob
@@ -228,6 +232,11 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 			return "", err
 		}
 
+		depArgs := &backuppb.ScheduledBackupExecutionArgs{}
+		if err := pbtypes.UnmarshalAny(dependentSchedule.ExecutionArgs().Args, depArgs); err != nil {
+			return "", errors.Wrap(err, "un-marshaling args")
+		}
+
 		fullBackup.AlwaysFull = false
 		// If sj refers to the incremental schedule, then the dependentSchedule
 		// refers to the full schedule that sj was created as a child of. In this
@@ -235,6 +244,9 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		// schedules recurrence.
 		if backupNode.AppendToLatest {
 			fullBackup.Recurrence = tree.NewDString(dependentSchedule.ScheduleExpr())
+
+			// The retention is only recorded on the full schedule.
+			retention = makeBackupRetentionClause(depArgs)
 		} else {
 			// If sj refers to the full schedule, then the dependentSchedule refers to
 			// the incremental schedule that was created as a child of sj. In this
@@ -242,6 +254,10 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 			// incremental schedules recurrence.
 			fullBackup.Recurrence = tree.NewDString(recurrence)
 			recurrence = dependentSchedule.ScheduleExpr()
+
+			// The compaction threshold is only recorded on the incremental
+			// schedule.
+			compactAfterIncrementals = depArgs.CompactAfterIncrementals
 		}
 	} else {
 		// If sj does not have a dependent schedule and is an incremental backup
@@ -302,6 +318,12 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 			Value: tree.NewDString(wait),
 		},
 	}
+	if compactAfterIncrementals > 0 {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{
+			Key:   optCompactAfterIncrementals,
+			Value: tree.NewDString(strconv.FormatInt(compactAfterIncrementals, 10)),
+		})
+	}
 
 	var destinations []string
 	for i := range backupNode.To {
@@ -339,6 +361,7 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 		},
 		Recurrence:      tree.NewDString(recurrence),
 		FullBackup:      fullBackup,
+		Retention:       retention,
 		Targets:         redactedBackupNode.Targets,
 		To:              redactedBackupNode.To,
 		BackupOptions:   redactedBackupNode.Options,
@@ -348,6 +371,21 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 	return tree.AsString(node), nil
 }
 
+// makeBackupRetentionClause returns the RETENTION clause of a backup schedule
+// with the passed arguments, or nil if the schedule keeps all backups.
+func makeBackupRetentionClause(
+	args *backuppb.ScheduledBackupExecutionArgs,
+) *tree.BackupRetentionClause {
+	if args.RetainFor > 0 {
+		d := duration.MakeDuration(args.RetainFor.Nanoseconds(), 0 /* days */, 0 /* months */)
+		return &tree.BackupRetentionClause{Duration: tree.NewDString(d.String())}
+	}
+	if args.RetainFullBackups > 0 {
+		return &tree.BackupRetentionClause{FullBackups: args.RetainFullBackups}
+	}
+	return nil
+}
+
 func (e *scheduledBackupExecutor) backupSucceeded(
 	ctx context.Context,
 	txn jobs.ScheduledJobStorage,
@@ -0,0 +1,812 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupinfo"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudprivilege"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprofiler"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+// maxReportedVerificationProblems is the number of problems found by a VERIFY
+// BACKUP job that are included in its error. All of them are logged.
+const maxReportedVerificationProblems = 10
+
+var verifyBackupHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+	{Name: "files", Typ: types.Int},
+	{Name: "bytes", Typ: types.Int},
+}
+
+var verifyBackupFingerprintHeader = colinfo.ResultColumns{
+	{Name: "job_id", Typ: types.Int},
+	{Name: "status", Typ: types.String},
+	{Name: "files", Typ: types.Int},
+	{Name: "bytes", Typ: types.Int},
+	{Name: "table_name", Typ: types.String},
+	{Name: "fingerprint", Typ: types.Int},
+}
+
+// verifyBackupResult is the result of a VERIFY BACKUP job, which is reported
+// to the statement that ran it.
+type verifyBackupResult struct {
+	files, bytes int64
+	// tables are the fingerprints of the tables of the chain, sorted by name, if
+	// the chain was fingerprinted.
+	tables []verifiedTable
+}
+
+type verifiedTable struct {
+	name        string
+	fingerprint uint64
+}
+
+func verifyBackupTypeCheck(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (matched bool, header colinfo.ResultColumns, _ error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return false, nil, nil
+	}
+	switch {
+	case verifyStmt.Options.Detached == tree.DBoolTrue:
+		header = jobs.DetachedJobExecutionResultHeader
+	case verifyStmt.Options.Fingerprint:
+		header = verifyBackupFingerprintHeader
+	default:
+		header = verifyBackupHeader
+	}
+	if err := exprutil.TypeCheck(
+		ctx, "VERIFY BACKUP", p.SemaCtx(),
+		exprutil.Strings{
+			verifyStmt.Subdir,
+			verifyStmt.Options.EncryptionPassphrase,
+		},
+		exprutil.StringArrays{
+			tree.Exprs(verifyStmt.In),
+			tree.Exprs(verifyStmt.Options.IncrementalStorage),
+			tree.Exprs(verifyStmt.Options.DecryptionKMSURI),
+		},
+	); err != nil {
+		return false, nil, err
+	}
+	return true, header, nil
+}
+
+// verifyBackupPlanHook implements PlanHookFn for VERIFY BACKUP. The statement
+// creates a BACKUP job which, instead of exporting data from the cluster, reads
+// every file of a chain of backups in a collection, and checks that it is
+// intact and only contains the data the manifests say it does.
+func verifyBackupPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	verifyStmt, ok := stmt.(*tree.VerifyBackup)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if err := featureflag.CheckEnabled(
+		ctx,
+		p.ExecCfg(),
+		featureBackupEnabled,
+		"BACKUP",
+	); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	opts := verifyStmt.Options
+	detached := opts.Detached == tree.DBoolTrue
+	if detached && opts.Fingerprint {
+		return nil, nil, nil, false, pgerror.New(pgcode.FeatureNotSupported,
+			"VERIFY BACKUP cannot compute fingerprints with the DETACHED option")
+	}
+	exprEval := p.ExprEvaluator("VERIFY BACKUP")
+
+	subdir, err := exprEval.String(ctx, verifyStmt.Subdir)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	collections, err := exprEval.StringArray(ctx, tree.Exprs(verifyStmt.In))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	incrementalStorage, err := exprEval.StringArray(ctx, tree.Exprs(opts.IncrementalStorage))
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	encryptionParams := jobspb.BackupEncryptionOptions{
+		Mode: jobspb.EncryptionMode_None,
+	}
+	if opts.EncryptionPassphrase != nil {
+		pw, err := exprEval.String(ctx, opts.EncryptionPassphrase)
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_Passphrase
+		encryptionParams.RawPassphrase = pw
+	}
+	var kms []string
+	if opts.DecryptionKMSURI != nil {
+		if encryptionParams.Mode != jobspb.EncryptionMode_None {
+			return nil, nil, nil, false,
+				errors.New("cannot have both encryption_passphrase and kms option set")
+		}
+		kms, err = exprEval.StringArray(ctx, tree.Exprs(opts.DecryptionKMSURI))
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+		encryptionParams.Mode = jobspb.EncryptionMode_KMS
+		encryptionParams.RawKmsUris = kms
+		if err = logAndSanitizeKmsURIs(ctx, kms...); err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
+	header := verifyBackupHeader
+	switch {
+	case detached:
+		header = jobs.DetachedJobExecutionResultHeader
+	case opts.Fingerprint:
+		header = verifyBackupFingerprintHeader
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if !(p.ExtendedEvalContext().TxnIsSingleStmt || detached) {
+			return errors.Errorf("VERIFY BACKUP cannot be used inside a multi-statement transaction without DETACHED option")
+		}
+		if err := utilccl.CheckEnterpriseEnabled(p.ExecCfg().Settings, "VERIFY BACKUP"); err != nil {
+			return err
+		}
+		uris := append(append([]string(nil), collections...), incrementalStorage...)
+		if err := checkPrivilegesForVerifyBackup(ctx, p, uris); err != nil {
+			return err
+		}
+
+		var endTime hlc.Timestamp
+		if verifyStmt.AsOf.Expr != nil {
+			asOf, err := p.EvalAsOfTimestamp(ctx, verifyStmt.AsOf)
+			if err != nil {
+				return err
+			}
+			endTime = asOf.Timestamp
+		}
+
+		// Resolve LATEST now, so that the job verifies the chain that was the
+		// latest one when the statement ran even if a new full backup is taken
+		// while it is running.
+		if strings.EqualFold(subdir, backupbase.LatestFileName) {
+			subdir, err = backupdest.ReadLatestFile(ctx, collections[0],
+				p.ExecCfg().DistSQLSrv.ExternalStorageFromURI, p.User())
+			if err != nil {
+				return err
+			}
+		} else {
+			subdir = "/" + strings.TrimPrefix(subdir, "/")
+		}
+
+		if err := logAndSanitizeBackupDestinations(ctx, uris...); err != nil {
+			return errors.Wrap(err, "logging backup destinations")
+		}
+		description, err := verifyBackupJobDescription(verifyStmt, collections, kms, subdir,
+			incrementalStorage, p.ExtendedEvalContext().Annotations)
+		if err != nil {
+			return err
+		}
+
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		jr := jobs.Record{
+			Description: description,
+			Details: jobspb.BackupDetails{
+				EndTime:           endTime,
+				EncryptionOptions: &encryptionParams,
+				Detached:          detached,
+				ApplicationName:   p.SessionData().ApplicationName,
+				Verification: &jobspb.BackupDetails_Verification{
+					Collection:         collections,
+					Subdir:             subdir,
+					IncrementalStorage: incrementalStorage,
+					Fingerprint:        opts.Fingerprint,
+				},
+			},
+			Progress: jobspb.BackupProgress{},
+			Username: p.User(),
+		}
+
+		if detached {
+			if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(
+				ctx, jr, jobID, p.InternalSQLTxn(),
+			); err != nil {
+				return err
+			}
+			resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+			return nil
+		}
+		var sj *jobs.StartableJob
+		if err := func() (err error) {
+			defer func() {
+				if err == nil || sj == nil {
+					return
+				}
+				if cleanupErr := sj.CleanupOnRollback(ctx); cleanupErr != nil {
+					log.Errorf(ctx, "failed to cleanup job: %v", cleanupErr)
+				}
+			}()
+			if err := p.ExecCfg().JobRegistry.CreateStartableJobWithTxn(
+				ctx, &sj, jobID, p.InternalSQLTxn(), jr,
+			); err != nil {
+				return err
+			}
+			// We commit the transaction here so that the job can be started. This
+			// is safe because we're in an implicit transaction.
+			return p.Txn().Commit(ctx)
+		}(); err != nil {
+			return err
+		}
+		if err := sj.Start(ctx); err != nil {
+			return err
+		}
+		if err := sj.AwaitCompletion(ctx); err != nil {
+			return err
+		}
+		return sj.ReportExecutionResults(ctx, resultsCh)
+	}
+
+	return fn, header, nil, false, nil
+}
+
+// checkPrivilegesForVerifyBackup checks that the user may verify backups in
+// the passed locations. Verification reads, and may fingerprint, all the data
+// of the chain, so it requires the same privileges as a cluster backup.
+func checkPrivilegesForVerifyBackup(
+	ctx context.Context, p sql.PlanHookState, uris []string,
+) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	if err := p.CheckPrivilegeForUser(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.BACKUP, p.User(),
+	); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InsufficientPrivilege,
+			"only users with the admin role or the BACKUP system privilege are allowed to verify backups")
+	}
+	return cloudprivilege.CheckDestinationPrivileges(ctx, p, uris)
+}
+
+// verifyBackupJobDescription returns the redacted VERIFY BACKUP statement,
+// with LATEST replaced by the resolved subdirectory, to be used as the job
+// description.
+func verifyBackupJobDescription(
+	verifyStmt *tree.VerifyBackup,
+	collections []string,
+	kmsURIs []string,
+	resolvedSubdir string,
+	incrementalStorage []string,
+	ann *tree.Annotations,
+) (string, error) {
+	in, err := sanitizeURIList(collections)
+	if err != nil {
+		return "", err
+	}
+	opts := tree.VerifyBackupOptions{
+		Detached:    verifyStmt.Options.Detached,
+		Fingerprint: verifyStmt.Options.Fingerprint,
+	}
+	if verifyStmt.Options.EncryptionPassphrase != nil {
+		opts.EncryptionPassphrase = tree.NewDString("redacted")
+	}
+	if opts.DecryptionKMSURI, err = sanitizeURIList(kmsURIs); err != nil {
+		return "", err
+	}
+	if opts.IncrementalStorage, err = sanitizeURIList(incrementalStorage); err != nil {
+		return "", err
+	}
+	node := &tree.VerifyBackup{
+		Subdir:  tree.NewDString(resolvedSubdir),
+		In:      in,
+		AsOf:    verifyStmt.AsOf,
+		Options: opts,
+	}
+	return tree.AsStringWithFlags(
+		node, tree.FmtAlwaysQualifyNames|tree.FmtShowFullURIs, tree.FmtAnnotations(ann),
+	), nil
+}
+
+// verifyBackupChain is run by the backup resumer, in place of a regular
+// backup, for jobs created by VERIFY BACKUP. It reads every file of the chain
+// of backups described by details.Verification up to details.EndTime on all
+// the nodes of the cluster, checking each file against the checksums recorded
+// in the manifests and the spans and end times of the backups that wrote it.
+// If requested, it also fingerprints the data of every table as of the end
+// time, the way crdb_internal.fingerprint does with stripped fingerprints, so
+// that the result can be compared with the fingerprints of the backed up
+// tables. The cluster's KV is never read or written.
+func (b *backupResumer) verifyBackupChain(
+	ctx context.Context, p sql.JobExecContext, details jobspb.BackupDetails,
+) error {
+	execCfg := p.ExecCfg()
+	user := p.User()
+	verification := details.Verification
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	baseDirs, err := backuputils.AppendPaths(verification.Collection, verification.Subdir)
+	if err != nil {
+		return err
+	}
+	incDirs, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, user, execCfg, verification.IncrementalStorage, verification.Collection,
+		verification.Subdir,
+	)
+	if err != nil {
+		return err
+	}
+	baseStores, cleanupBase, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, baseDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupBase(); err != nil {
+			log.Warningf(ctx, "failed to close base store: %+v", err)
+		}
+	}()
+	incStores, cleanupInc, err := backupdest.MakeBackupDestinationStores(ctx, user, mkStore, incDirs)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanupInc(); err != nil {
+			log.Warningf(ctx, "failed to close incremental store: %+v", err)
+		}
+	}()
+
+	kmsEnv := backupencryption.MakeBackupKMSEnv(
+		execCfg.Settings, &execCfg.ExternalIODirConfig, execCfg.InternalDB, user,
+	)
+	encryption := details.EncryptionOptions
+	if encryption != nil && encryption.Key == nil && encryption.KMSInfo == nil {
+		encryption, err = backupencryption.GetEncryptionFromBase(
+			ctx, user, mkStore, baseDirs[0], *encryption, &kmsEnv,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	defaultURIs, manifests, localityInfo, memReserved, err := backupdest.ResolveBackupManifests(
+		ctx, &mem, baseStores, incStores, mkStore, baseDirs, incDirs, hlc.Timestamp{},
+		encryption, &kmsEnv, user,
+	)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memReserved)
+	if !details.EndTime.IsEmpty() {
+		_, manifests, localityInfo, err = backupinfo.ValidateEndTimeAndTruncate(
+			defaultURIs, manifests, localityInfo, details.EndTime,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	endTime := details.EndTime
+	if endTime.IsEmpty() {
+		endTime = manifests[len(manifests)-1].EndTime
+	}
+
+	layerToIterFactory, err := backupinfo.GetBackupManifestIterFactories(
+		ctx, execCfg.DistSQLSrv.ExternalStorage, manifests, encryption, &kmsEnv,
+	)
+	if err != nil {
+		return err
+	}
+	files, err := makeVerifyBackupFiles(ctx, manifests, localityInfo, layerToIterFactory, user)
+	if err != nil {
+		return err
+	}
+
+	var entries []execinfrapb.RestoreSpanEntry
+	var tableNames map[uint32]string
+	if verification.Fingerprint {
+		last := manifests[len(manifests)-1]
+		genSpans, cleanup, err := makeChainSpanGenerator(
+			ctx, execCfg, user, last.Spans, manifests, localityInfo, layerToIterFactory, endTime,
+		)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		spanCh := make(chan execinfrapb.RestoreSpanEntry, 1000)
+		if err := ctxgroup.GoAndWait(ctx,
+			func(ctx context.Context) error { return genSpans(ctx, spanCh) },
+			func(ctx context.Context) error {
+				for entry := range spanCh {
+					entries = append(entries, entry)
+				}
+				return nil
+			},
+		); err != nil {
+			return err
+		}
+		if tableNames, err = verifiedTableNames(ctx, layerToIterFactory[len(manifests)-1]); err != nil {
+			return err
+		}
+	}
+
+	var fileEncryption *kvpb.FileEncryptionOptions
+	if encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, encryption, &kmsEnv)
+		if err != nil {
+			return err
+		}
+		fileEncryption = &kvpb.FileEncryptionOptions{Key: key}
+	}
+
+	dsp := p.DistSQLPlanner()
+	planCtx, sqlInstanceIDs, err := dsp.SetupAllNodesPlanning(ctx, p.ExtendedEvalContext(), execCfg)
+	if err != nil {
+		return err
+	}
+	// Verification only reads from external storage, so the files and entries
+	// are assigned to the nodes round-robin.
+	specs := make(map[base.SQLInstanceID]*execinfrapb.VerifyBackupDataSpec, len(sqlInstanceIDs))
+	specFor := func(i int) *execinfrapb.VerifyBackupDataSpec {
+		id := sqlInstanceIDs[i%len(sqlInstanceIDs)]
+		spec, ok := specs[id]
+		if !ok {
+			spec = &execinfrapb.VerifyBackupDataSpec{
+				JobID:      int64(b.job.ID()),
+				EndTime:    endTime,
+				Encryption: fileEncryption,
+				UserProto:  user.EncodeProto(),
+			}
+			specs[id] = spec
+		}
+		return spec
+	}
+	for i := range files {
+		spec := specFor(i)
+		spec.Files = append(spec.Files, files[i])
+	}
+	for i := range entries {
+		spec := specFor(i)
+		spec.Entries = append(spec.Entries, entries[i])
+	}
+
+	numChunks := len(files) + len(entries)
+	progressLogger := jobs.NewChunkProgressLoggerForJob(b.job, numChunks, b.job.FractionCompleted(), jobs.ProgressUpdateOnly)
+	requestFinishedCh := make(chan struct{}, numChunks)
+	progCh := make(chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+
+	var result verifyBackupResult
+	var uncheckedFiles int64
+	var problems []string
+	fingerprints := make(map[uint32]uint64)
+	collectProgress := func(ctx context.Context) error {
+		defer close(requestFinishedCh)
+		for progress := range progCh {
+			var progDetails backuppb.VerifyBackupProgress
+			if err := gogotypes.UnmarshalAny(&progress.ProgressDetails, &progDetails); err != nil {
+				return errors.Wrap(err, "unable to unmarshal verification progress details")
+			}
+			result.files += progDetails.Files
+			result.bytes += progDetails.Bytes
+			uncheckedFiles += progDetails.UncheckedFiles
+			problems = append(problems, progDetails.Problems...)
+			for id, fp := range progDetails.TableFingerprints {
+				fingerprints[id] ^= fp
+			}
+			for i := int64(0); i < progDetails.Files+int64(progDetails.CompletedEntries); i++ {
+				requestFinishedCh <- struct{}{}
+			}
+		}
+		return nil
+	}
+	if err := ctxgroup.GoAndWait(ctx,
+		func(ctx context.Context) error {
+			return distVerifyBackup(ctx, p, planCtx, dsp, progCh, specs)
+		},
+		collectProgress,
+		func(ctx context.Context) error {
+			return errors.Wrap(progressLogger.Loop(ctx, requestFinishedCh), "updating job progress")
+		},
+	); err != nil {
+		return err
+	}
+
+	if uncheckedFiles > 0 {
+		log.Infof(ctx, "%d of %d files of backup %s have no recorded checksum", uncheckedFiles,
+			result.files, verification.Subdir)
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Warningf(ctx, "verification of backup %s: %s", verification.Subdir, problem)
+		}
+		reported := problems
+		if len(reported) > maxReportedVerificationProblems {
+			reported = reported[:maxReportedVerificationProblems]
+		}
+		return errors.Newf("backup verification found problems with %d files: %s", len(problems),
+			strings.Join(reported, "; "))
+	}
+
+	for id, name := range tableNames {
+		result.tables = append(result.tables, verifiedTable{name: name, fingerprint: fingerprints[id]})
+	}
+	sort.Slice(result.tables, func(i, j int) bool {
+		return result.tables[i].name < result.tables[j].name
+	})
+	b.verifyResult = &result
+	return nil
+}
+
+// makeVerifyBackupFiles returns the files of the chain to verify. Manifest
+// files that are backed by the same file in external storage, either because
+// the file contains several spans or because it is in the deduplicated blobs
+// directory of the collection, are verified once, against all their spans.
+func makeVerifyBackupFiles(
+	ctx context.Context,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	layerToIterFactory backupinfo.LayerToBackupManifestFileIterFactory,
+	user username.SQLUsername,
+) ([]execinfrapb.VerifyBackupDataSpec_File, error) {
+	backupLocalityMap, err := makeBackupLocalityMap(localityInfo, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving locality locations")
+	}
+	var files []execinfrapb.VerifyBackupDataSpec_File
+	byKey := make(map[string]int)
+	for layer := range manifests {
+		m := &manifests[layer]
+		inclusiveEndKeys := m.ClusterVersion.Less(clusterversion.V24_1.Version()) &&
+			m.MVCCFilter == backuppb.MVCCFilter_All
+		it, err := layerToIterFactory[layer].NewFileIter(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := func() error {
+			defer it.Close()
+			for ; ; it.Next() {
+				if ok, err := it.Valid(); err != nil {
+					return err
+				} else if !ok {
+					return nil
+				}
+				f := it.Value()
+				dir := m.Dir
+				if d, ok := backupLocalityMap[layer][f.LocalityKV]; ok {
+					dir = d
+				}
+				key := fmt.Sprintf("%d/%s/%s", layer, f.LocalityKV, f.Path)
+				if len(f.ContentHash) > 0 {
+					key = hex.EncodeToString(f.ContentHash)
+				}
+				if i, ok := byKey[key]; ok {
+					file := &files[i]
+					file.Spans = append(file.Spans, f.Span)
+					file.InclusiveEndKeys = file.InclusiveEndKeys || inclusiveEndKeys
+					if m.EndTime.Less(file.EndTime) {
+						file.EndTime = m.EndTime
+					}
+					continue
+				}
+				byKey[key] = len(files)
+				files = append(files, execinfrapb.VerifyBackupDataSpec_File{
+					Dir:              dir,
+					Path:             f.Path,
+					Spans:            []roachpb.Span{f.Span},
+					InclusiveEndKeys: inclusiveEndKeys,
+					EndTime:          m.EndTime,
+					ElidedPrefix:     m.ElidedPrefix,
+					Checksum:         f.Checksum,
+					ContentHash:      f.ContentHash,
+				})
+			}
+		}(); err != nil {
+			return nil, err
+		}
+	}
+	for i := range files {
+		spans := files[i].Spans
+		sort.Slice(spans, func(a, b int) bool { return spans[a].Key.Compare(spans[b].Key) < 0 })
+	}
+	return files, nil
+}
+
+// verifiedTableNames returns the fully qualified names of the tables in the
+// passed layer of a chain, by ID.
+func verifiedTableNames(
+	ctx context.Context, iterFactory *backupinfo.IterFactory,
+) (map[uint32]string, error) {
+	dbNames := make(map[descpb.ID]string)
+	schemaNames := map[descpb.ID]string{keys.PublicSchemaID: catconstants.PublicSchemaName}
+	var tables []*descpb.TableDescriptor
+	it := iterFactory.NewDescIter(ctx)
+	defer it.Close()
+	for ; ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		table, db, _, schema, _ := descpb.GetDescriptors(it.Value())
+		switch {
+		case table != nil:
+			if !table.IsView() && !table.Dropped() {
+				tables = append(tables, table)
+			}
+		case db != nil:
+			dbNames[db.ID] = db.Name
+		case schema != nil:
+			schemaNames[schema.ID] = schema.Name
+		}
+	}
+	names := make(map[uint32]string, len(tables))
+	for _, t := range tables {
+		name := tree.MakeTableNameWithSchema(
+			tree.Name(dbNames[t.ParentID]), tree.Name(schemaNames[t.UnexposedParentSchemaID]),
+			tree.Name(t.Name),
+		)
+		names[uint32(t.ID)] = name.FQString()
+	}
+	return names, nil
+}
+
+// distVerifyBackup runs the VerifyBackupData processors of the passed specs
+// and sends their progress on progCh, which it closes.
+func distVerifyBackup(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	planCtx *sql.PlanningCtx,
+	dsp *sql.DistSQLPlanner,
+	progCh chan *execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+	specs map[base.SQLInstanceID]*execinfrapb.VerifyBackupDataSpec,
+) error {
+	ctx, span := tracing.ChildSpan(ctx, "backupccl.distVerifyBackup")
+	defer span.Finish()
+	defer close(progCh)
+	evalCtx := execCtx.ExtendedEvalContext()
+	var noTxn *kv.Txn
+
+	if len(specs) == 0 {
+		return nil
+	}
+
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, 0, len(specs))
+	var jobID jobspb.JobID
+	for sqlInstanceID, spec := range specs {
+		jobID = jobspb.JobID(spec.JobID)
+		corePlacement = append(corePlacement, physicalplan.ProcessorCorePlacement{
+			SQLInstanceID: sqlInstanceID,
+			Core:          execinfrapb.ProcessorCoreUnion{VerifyBackupData: spec},
+		})
+	}
+
+	p := planCtx.NewPhysicalPlan()
+	// All of the progress information is sent through the metadata stream, so we
+	// have an empty result stream.
+	p.AddNoInputStage(corePlacement, execinfrapb.PostProcessSpec{}, []*types.T{}, execinfrapb.Ordering{})
+	p.PlanToStreamColMap = []int{}
+
+	sql.FinalizePlan(ctx, planCtx, p)
+
+	metaFn := func(_ context.Context, meta *execinfrapb.ProducerMetadata) error {
+		if meta.BulkProcessorProgress != nil {
+			progCh <- meta.BulkProcessorProgress
+		}
+		return nil
+	}
+	rowResultWriter := sql.NewRowResultWriter(nil)
+	recv := sql.MakeDistSQLReceiver(
+		ctx,
+		sql.NewMetadataCallbackWriter(rowResultWriter, metaFn),
+		tree.Rows,
+		nil,   /* rangeCache */
+		noTxn, /* txn - the flow does not read or write the database */
+		nil,   /* clockUpdater */
+		evalCtx.Tracing,
+	)
+	defer recv.Release()
+
+	execCfg := execCtx.ExecCfg()
+	jobsprofiler.StorePlanDiagram(ctx, execCfg.DistSQLSrv.Stopper, p, execCfg.InternalDB, jobID)
+
+	// Copy the evalCtx, as dsp.Run() might change it.
+	evalCtxCopy := *evalCtx
+	dsp.Run(ctx, planCtx, noTxn, p, recv, &evalCtxCopy, nil /* finishedSetupFn */)
+	return rowResultWriter.Err()
+}
+
+// reportVerifyResults reports the result of a VERIFY BACKUP job: the number of
+// files and bytes that were verified and, if the chain was fingerprinted, the
+// fingerprint of each table, one row per table.
+func (b *backupResumer) reportVerifyResults(
+	ctx context.Context, resultsCh chan<- tree.Datums, fingerprint bool,
+) error {
+	res := b.verifyResult
+	row := tree.Datums{
+		tree.NewDInt(tree.DInt(b.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDInt(tree.DInt(res.files)),
+		tree.NewDInt(tree.DInt(res.bytes)),
+	}
+	if !fingerprint {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- row:
+			return nil
+		}
+	}
+	for _, t := range res.tables {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- append(row[:len(row):len(row)],
+			tree.NewDString(t.name), tree.NewDInt(tree.DInt(int64(t.fingerprint)))):
+		}
+	}
+	return nil
+}
+
+func init() {
+	sql.AddPlanHook("backupccl.verifyBackupPlanHook", verifyBackupPlanHook, verifyBackupTypeCheck)
+}
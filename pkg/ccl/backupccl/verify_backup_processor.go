@@ -0,0 +1,459 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	gogotypes "github.com/gogo/protobuf/types"
+)
+
+const verifyBackupProcessorName = "verifyBackupDataProcessor"
+
+// verifyBackupDataProcessor verifies the files of a chain of backups assigned
+// to it, and fingerprints the restore span entries assigned to it, for a
+// VERIFY BACKUP job. It streams back its progress, one file or entry at a time,
+// through the metadata channel provided by DistSQL.
+type verifyBackupDataProcessor struct {
+	execinfra.ProcessorBase
+
+	spec execinfrapb.VerifyBackupDataSpec
+
+	// cancelAndWaitForWorker cancels the producer goroutine and waits for it to
+	// finish. It can be called multiple times.
+	cancelAndWaitForWorker func()
+	progCh                 chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress
+	verifyErr              error
+
+	// memAcc accounts for the file that is being verified, which is read into
+	// memory in its entirety.
+	memAcc *mon.BoundAccount
+}
+
+var (
+	_ execinfra.Processor = &verifyBackupDataProcessor{}
+	_ execinfra.RowSource = &verifyBackupDataProcessor{}
+)
+
+func newVerifyBackupDataProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.VerifyBackupDataSpec,
+	post *execinfrapb.PostProcessSpec,
+) (execinfra.Processor, error) {
+	ba := flowCtx.Cfg.BackupMonitor.MakeBoundAccount()
+	vp := &verifyBackupDataProcessor{
+		spec:   spec,
+		progCh: make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress),
+		memAcc: &ba,
+	}
+	if err := vp.Init(ctx, vp, post, backupOutputTypes, flowCtx, processorID, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				vp.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+	return vp, nil
+}
+
+// Start is part of the RowSource interface.
+func (vp *verifyBackupDataProcessor) Start(ctx context.Context) {
+	ctx = logtags.AddTag(ctx, "job", vp.spec.JobID)
+	ctx = vp.StartInternal(ctx, verifyBackupProcessorName)
+	ctx, cancel := context.WithCancel(ctx)
+
+	vp.cancelAndWaitForWorker = func() {
+		cancel()
+		for range vp.progCh {
+		}
+	}
+	if err := vp.FlowCtx.Stopper().RunAsyncTaskEx(ctx, stop.TaskOpts{
+		TaskName: "verifyBackupDataProcessor.runVerifyBackupProcessor",
+		SpanOpt:  stop.ChildSpan,
+	}, func(ctx context.Context) {
+		vp.verifyErr = runVerifyBackupProcessor(ctx, vp.FlowCtx, &vp.spec, vp.progCh, vp.memAcc)
+		cancel()
+		close(vp.progCh)
+	}); err != nil {
+		// The closure above hasn't run, so we have to do the cleanup.
+		vp.verifyErr = err
+		cancel()
+		close(vp.progCh)
+	}
+}
+
+// Next is part of the RowSource interface.
+func (vp *verifyBackupDataProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	if vp.State != execinfra.StateRunning {
+		return nil, vp.DrainHelper()
+	}
+	prog, ok := <-vp.progCh
+	if !ok {
+		vp.MoveToDraining(vp.verifyErr)
+		return nil, vp.DrainHelper()
+	}
+	prog.NodeID = vp.FlowCtx.NodeID.SQLInstanceID()
+	prog.FlowID = vp.FlowCtx.ID
+	return nil, &execinfrapb.ProducerMetadata{BulkProcessorProgress: &prog}
+}
+
+func (vp *verifyBackupDataProcessor) close() {
+	if vp.cancelAndWaitForWorker != nil {
+		vp.cancelAndWaitForWorker()
+	}
+	if vp.InternalClose() {
+		vp.memAcc.Close(vp.Ctx())
+	}
+}
+
+// ConsumerClosed is part of the RowSource interface. We have to override the
+// implementation provided by ProcessorBase.
+func (vp *verifyBackupDataProcessor) ConsumerClosed() {
+	vp.close()
+}
+
+func runVerifyBackupProcessor(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.VerifyBackupDataSpec,
+	progCh chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress,
+	memAcc *mon.BoundAccount,
+) error {
+	sendProgress := func(progDetails *backuppb.VerifyBackupProgress) error {
+		details, err := gogotypes.MarshalAny(progDetails)
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case progCh <- execinfrapb.RemoteProducerMetadata_BulkProcessorProgress{ProgressDetails: *details}:
+			return nil
+		}
+	}
+
+	for i := range spec.Files {
+		file := &spec.Files[i]
+		progDetails := backuppb.VerifyBackupProgress{Files: 1}
+		size, checked, err := verifyBackupFile(ctx, flowCtx, spec, file, memAcc)
+		memAcc.Clear(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			if !errors.Is(err, errVerificationFailed) {
+				// The file could not be read, which is a problem with the backup as
+				// long as the storage is accessible.
+				log.Warningf(ctx, "failed to verify backup file %s: %+v", file.Path, err)
+			}
+			progDetails.Problems = []string{fmt.Sprintf("%s: %v", file.Path, err)}
+		}
+		progDetails.Bytes = size
+		if !checked {
+			progDetails.UncheckedFiles = 1
+		}
+		if err := sendProgress(&progDetails); err != nil {
+			return err
+		}
+	}
+
+	for i := range spec.Entries {
+		fingerprints, err := fingerprintRestoreSpanEntry(ctx, flowCtx, spec, &spec.Entries[i])
+		if err != nil {
+			return errors.Wrapf(err, "fingerprinting %s", spec.Entries[i].Span)
+		}
+		if err := sendProgress(&backuppb.VerifyBackupProgress{
+			CompletedEntries:  1,
+			TableFingerprints: fingerprints,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errVerificationFailed marks the errors of files which could be read but whose
+// contents are not what the manifests say they are.
+var errVerificationFailed = errors.New("verification failed")
+
+// verifyBackupFile reads a file of the chain and verifies it against its
+// recorded checksum, if any, as well as every key in it against the spans and
+// end time of the backups that wrote it. It returns the size of the file and
+// whether its contents could be checked against a recorded checksum.
+func verifyBackupFile(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.VerifyBackupDataSpec,
+	file *execinfrapb.VerifyBackupDataSpec_File,
+	memAcc *mon.BoundAccount,
+) (size int64, checked bool, _ error) {
+	store, err := flowCtx.Cfg.ExternalStorage(ctx, file.Dir)
+	if err != nil {
+		return 0, false, err
+	}
+	defer logClose(ctx, store, "external storage")
+	r, _, err := store.ReadFile(ctx, file.Path, cloud.ReadOptions{NoFileSize: true})
+	if err != nil {
+		return 0, false, err
+	}
+	defer r.Close(ctx)
+	data, err := mon.ReadAll(ctx, r, memAcc)
+	if err != nil {
+		return 0, false, err
+	}
+	size = int64(len(data))
+
+	if len(file.Checksum) > 0 {
+		if sum := sha256.Sum256(data); !bytes.Equal(sum[:], file.Checksum) {
+			return size, true, errors.Mark(errors.Newf(
+				"checksum mismatch: expected %x, got %x", file.Checksum, sum), errVerificationFailed)
+		}
+		checked = true
+	}
+	if len(file.ContentHash) > 0 {
+		// Files in the deduplicated blobs directory are named after the hash of
+		// the bytes that are stored, see writeDedupFile.
+		if sum := sha256.Sum256(data); !bytes.Equal(sum[:], file.ContentHash) {
+			return size, true, errors.Mark(errors.Newf(
+				"content hash mismatch: expected %x, got %x", file.ContentHash, sum), errVerificationFailed)
+		}
+		checked = true
+	}
+	if spec.Encryption != nil {
+		if data, err = storageccl.DecryptFile(ctx, data, spec.Encryption.Key, memAcc); err != nil {
+			return size, checked, errors.Mark(err, errVerificationFailed)
+		}
+	}
+
+	if err := verifySSTKeys(data, file); err != nil {
+		return size, checked, errors.Mark(err, errVerificationFailed)
+	}
+	return size, checked, nil
+}
+
+// verifySSTKeys checks that every key in an SST of a backup is within the
+// spans of the manifest files backed by it, is no more recent than the end time
+// of its backup, and, for point keys, has a value with a valid checksum.
+func verifySSTKeys(data []byte, file *execinfrapb.VerifyBackupDataSpec_File) error {
+	// The checksums of the values are verified below rather than by the
+	// iterator, since they are computed over the keys before their prefix was
+	// elided.
+	iter, err := storage.NewMemSSTIterator(data, false /* verify */, storage.IterOptions{
+		KeyTypes:   storage.IterKeyTypePointsAndRanges,
+		UpperBound: keys.MaxKey,
+	})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	var prefix []byte
+	if len(file.Spans) > 0 {
+		if prefix, err = elidedPrefix(file.Spans[0].Key, file.ElidedPrefix); err != nil {
+			return err
+		}
+	}
+	withPrefix := func(key []byte) roachpb.Key {
+		return append(append(roachpb.Key(nil), prefix...), key...)
+	}
+	var keyScratch roachpb.Key
+	for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		hasPoint, hasRange := iter.HasPointAndRange()
+		if hasRange && iter.RangeKeyChanged() {
+			rangeKeys := iter.RangeKeys()
+			bounds := roachpb.Span{
+				Key:    withPrefix(rangeKeys.Bounds.Key),
+				EndKey: withPrefix(rangeKeys.Bounds.EndKey),
+			}
+			if !spansContain(file.Spans, bounds.Key, false /* isEndKey */, file.InclusiveEndKeys) ||
+				!spansContain(file.Spans, bounds.EndKey, true /* isEndKey */, file.InclusiveEndKeys) {
+				return errors.Newf("range key %s is outside of the spans of the file", bounds)
+			}
+			if newest := rangeKeys.Newest(); file.EndTime.Less(newest) {
+				return errors.Newf("range key %s at %s is more recent than the end time %s of the backup",
+					bounds, newest, file.EndTime)
+			}
+		}
+		if !hasPoint {
+			continue
+		}
+		key := iter.UnsafeKey()
+		keyScratch = append(append(keyScratch[:0], prefix...), key.Key...)
+		if !spansContain(file.Spans, keyScratch, false /* isEndKey */, file.InclusiveEndKeys) {
+			return errors.Newf("key %s is outside of the spans of the file", keyScratch)
+		}
+		if key.Timestamp.IsEmpty() {
+			return errors.Newf("key %s has no timestamp", keyScratch)
+		}
+		if file.EndTime.Less(key.Timestamp) {
+			return errors.Newf("key %s at %s is more recent than the end time %s of the backup",
+				keyScratch, key.Timestamp, file.EndTime)
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return err
+		}
+		mvccValue, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return errors.Wrapf(err, "decoding value of key %s", keyScratch)
+		}
+		if err := mvccValue.Value.Verify(keyScratch); err != nil {
+			return err
+		}
+	}
+}
+
+// spansContain returns whether key is within one of the passed sorted spans. If
+// isEndKey is set, key is the exclusive end of a range, which may be the end
+// key of a span. If inclusiveEndKeys is set, keys may also be equal to the end
+// key of a span.
+func spansContain(spans roachpb.Spans, key roachpb.Key, isEndKey, inclusiveEndKeys bool) bool {
+	// Find the first span that ends after key, or at key if key may be equal to
+	// the end key of a span.
+	i := sort.Search(len(spans), func(i int) bool {
+		if isEndKey || inclusiveEndKeys {
+			return spans[i].EndKey.Compare(key) >= 0
+		}
+		return spans[i].EndKey.Compare(key) > 0
+	})
+	if i == len(spans) {
+		return false
+	}
+	if isEndKey {
+		return spans[i].Key.Compare(key) < 0
+	}
+	return spans[i].Key.Compare(key) <= 0
+}
+
+// fingerprintRestoreSpanEntry returns the fingerprints, by table ID, of the
+// latest revision as of the end time of the spec of every key in the span of
+// entry. The fingerprints are the stripped fingerprints computed by
+// crdb_internal.fingerprint, so that the fingerprint of a table is the XOR of
+// the fingerprints of all the entries that cover it.
+func fingerprintRestoreSpanEntry(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	spec *execinfrapb.VerifyBackupDataSpec,
+	entry *execinfrapb.RestoreSpanEntry,
+) (map[uint32]uint64, error) {
+	storeFiles := make([]storageccl.StoreFile, 0, len(entry.Files))
+	defer func() {
+		for _, f := range storeFiles {
+			logClose(ctx, f.Store, "external storage")
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := flowCtx.Cfg.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return nil, err
+		}
+		storeFiles = append(storeFiles, storageccl.StoreFile{Store: dir, FilePath: file.Path})
+	}
+
+	iterOpts := storage.IterOptions{
+		RangeKeyMaskingBelow: spec.EndTime,
+		KeyTypes:             storage.IterKeyTypePointsAndRanges,
+		LowerBound:           keys.LocalMax,
+		UpperBound:           keys.MaxKey,
+	}
+	sstIter, err := storageccl.ExternalSSTReader(ctx, storeFiles, spec.Encryption, iterOpts)
+	if err != nil {
+		return nil, err
+	}
+	iter := storage.NewReadAsOfIterator(sstIter, spec.EndTime)
+	defer iter.Close()
+
+	prefix, err := elidedPrefix(entry.Span.Key, entry.ElidedPrefix)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints := make(map[uint32]uint64)
+	hasher := fnv.New64()
+	var keyScratch roachpb.Key
+	for iter.SeekGE(storage.MVCCKey{Key: bytes.TrimPrefix(entry.Span.Key, prefix)}); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		keyScratch = append(append(keyScratch[:0], prefix...), iter.UnsafeKey().Key...)
+		if keyScratch.Compare(entry.Span.EndKey) >= 0 {
+			break
+		}
+		noTenantPrefix, err := keys.StripTenantPrefix(keyScratch)
+		if err != nil {
+			return nil, err
+		}
+		// Like crdb_internal.fingerprint, ignore the tables that hold ephemeral
+		// state of the cluster.
+		_, tableID, _, _ := keys.DecodeTableIDIndexID(noTenantPrefix)
+		if tableID == keys.SqllivenessID || tableID == keys.LeaseTableID ||
+			tableID == keys.SQLInstancesTableID {
+			continue
+		}
+		v, err := iter.UnsafeValue()
+		if err != nil {
+			return nil, err
+		}
+		mvccValue, err := storage.DecodeMVCCValue(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "decoding value of key %s", keyScratch)
+		}
+
+		hasher.Reset()
+		stripped, err := keys.StripIndexPrefix(keyScratch)
+		if err != nil {
+			stripped = keyScratch
+		}
+		_, _ = hasher.Write(stripped)
+		// Skip the checksum of the value, which depends on the full key.
+		value := mvccValue.Value.RawBytes
+		if len(value) >= 4 {
+			value = value[4:]
+		}
+		_, _ = hasher.Write(value)
+		fingerprints[tableID] ^= hasher.Sum64()
+	}
+	return fingerprints, nil
+}
+
+func init() {
+	rowexec.NewVerifyBackupDataProcessor = newVerifyBackupDataProcessor
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyBackup tests that VERIFY BACKUP succeeds on an intact chain of
+// backups, that its fingerprints match the ones of the backed up tables, and
+// that it detects a corrupted file.
+func TestVerifyBackup(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `UPDATE data.bank SET balance = balance + 1 WHERE id % 2 = 0`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id % 3 = 0`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+
+	var files, size int
+	sqlDB.QueryRow(t, `SELECT files, bytes FROM [VERIFY BACKUP FROM LATEST IN $1]`, localFoo).Scan(&files, &size)
+	require.Greater(t, files, 0)
+	require.Greater(t, size, 0)
+
+	t.Run("fingerprint", func(t *testing.T) {
+		var expected int64
+		sqlDB.QueryRow(t, `SELECT crdb_internal.fingerprint(
+  crdb_internal.table_span('data.bank'::regclass::oid::int), true)`).Scan(&expected)
+		var fingerprint int64
+		sqlDB.QueryRow(t, `SELECT fingerprint FROM [VERIFY BACKUP FROM LATEST IN $1 WITH fingerprint]
+WHERE table_name = 'data.public.bank'`, localFoo).Scan(&fingerprint)
+		require.Equal(t, expected, fingerprint)
+
+		sqlDB.ExpectErr(t, "cannot compute fingerprints with the DETACHED option",
+			`VERIFY BACKUP FROM LATEST IN $1 WITH fingerprint, detached`, localFoo)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		var sst string
+		require.NoError(t, filepath.WalkDir(filepath.Join(dir, "foo"),
+			func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if sst == "" && strings.Contains(path, "/data/") && strings.HasSuffix(path, ".sst") {
+					sst = path
+				}
+				return nil
+			}))
+		require.NotEmpty(t, sst)
+		data, err := os.ReadFile(sst)
+		require.NoError(t, err)
+		data[len(data)/2] ^= 0xff
+		require.NoError(t, os.WriteFile(sst, data, 0644))
+
+		sqlDB.ExpectErr(t, "backup verification found problems with 1 file",
+			`VERIFY BACKUP FROM LATEST IN $1`, localFoo)
+	})
+}
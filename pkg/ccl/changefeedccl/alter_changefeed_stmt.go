@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedvalidators"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsauth"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -125,6 +126,23 @@ func alterChangefeedPlanHook(
 			return errors.Errorf(`job %d is not changefeed job`, jobID)
 		}
 
+		// A running changefeed can be rescanned without pausing it, by its
+		// change frontier. Any other alteration requires the job to be paused.
+		if job.Status() == jobs.StatusRunning && isRescanOnly(alterChangefeedStmt.Cmds) {
+			if err := requestRescan(ctx, p, job, alterChangefeedStmt.Cmds); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case resultsCh <- tree.Datums{
+				tree.NewDInt(tree.DInt(jobID)),
+				tree.NewDString(jobPayload.Description),
+			}:
+				return nil
+			}
+		}
+
 		if job.Status() != jobs.StatusPaused {
 			return errors.Errorf(`job %d is not paused`, jobID)
 		}
@@ -163,6 +181,14 @@ func alterChangefeedPlanHook(
 		}
 		newChangefeedStmt.Targets = newTargets
 
+		newJobProgress, newStatementTime, rescanFilter, err := generateRescanProgress(
+			ctx, p, alterChangefeedStmt.Cmds, newOptions, prevDetails, *newProgress, newStatementTime,
+		)
+		if err != nil {
+			return err
+		}
+		newProgress = &newJobProgress
+
 		if prevDetails.Select != "" {
 			query, err := cdceval.ParseChangefeedExpression(prevDetails.Select)
 			if err != nil {
@@ -218,6 +244,7 @@ func alterChangefeedPlanHook(
 		// newStatementTime will either be the StatementTime of the job prior to the
 		// alteration, or it will be the high watermark of the job.
 		newDetails.StatementTime = newStatementTime
+		newDetails.RescanFilter = rescanFilter
 
 		newPayload := job.Payload()
 		newPayload.Details = jobspb.WrapPayloadDetails(newDetails)
@@ -836,6 +863,312 @@ func generateNewProgress(
 	return newProgress, prevStatementTime, nil
 }
 
+// generateRescanProgress updates the progress of a changefeed job so that the
+// current values of the rows of the targets named by a RESCAN command are
+// emitted again once the job is resumed. Like an initial scan on newly added
+// targets, the statement time of the job is moved up to the high watermark,
+// which is reset, and the spans of the targets which are not rescanned are
+// added to the checkpoint so that they are skipped. The rescan is tracked by
+// the checkpoint, so it survives restarts.
+//
+// If the RESCAN command has a WHERE clause, the returned filter restricts the
+// rows emitted by the rescan to those which match it. If the changefeed is
+// not being rescanned, the filter of the previous rescan, if any, is returned
+// since that rescan may not have completed yet.
+func generateRescanProgress(
+	ctx context.Context,
+	p sql.PlanHookState,
+	alterCmds tree.AlterChangefeedCmds,
+	opts changefeedbase.StatementOptions,
+	prevDetails jobspb.ChangefeedDetails,
+	prevProgress jobspb.Progress,
+	prevStatementTime hlc.Timestamp,
+) (jobspb.Progress, hlc.Timestamp, *jobspb.ChangefeedRescanFilter, error) {
+	rescan, err := findRescan(alterCmds)
+	if err != nil {
+		return prevProgress, prevStatementTime, nil, err
+	}
+	if rescan == nil {
+		return prevProgress, prevStatementTime, prevDetails.RescanFilter, nil
+	}
+
+	prevHighWater := prevProgress.GetHighWater()
+	if prevHighWater == nil || prevHighWater.IsEmpty() {
+		return prevProgress, prevStatementTime, nil, errors.New(
+			`cannot rescan a changefeed before its initial scan completes, ` +
+				`please unpause the changefeed and wait until the high watermark is set to rescan it.`,
+		)
+	}
+	changefeedProgress := prevProgress.GetChangefeed()
+	if changefeedProgress != nil && changefeedProgress.Checkpoint != nil &&
+		len(changefeedProgress.Checkpoint.Spans) != 0 {
+		return prevProgress, prevStatementTime, nil, errors.Errorf(
+			`cannot rescan a changefeed while the checkpoint is non-empty, `+
+				`please unpause the changefeed and wait until the high watermark progresses past the current value %s to rescan it.`,
+			eval.TimestampToDecimalDatum(*prevHighWater).Decimal.String(),
+		)
+	}
+	ptsRecord := uuid.UUID{}
+	if changefeedProgress != nil {
+		ptsRecord = changefeedProgress.ProtectedTimestampRecord
+	}
+
+	req, err := makeRescanRequest(ctx, p, rescan, opts, prevDetails, *prevHighWater)
+	if err != nil {
+		return prevProgress, prevStatementTime, nil, err
+	}
+	if req.Filter != nil {
+		req.Filter.Timestamp = *prevHighWater
+	}
+
+	newProgress := jobspb.Progress{
+		Progress: &jobspb.Progress_HighWater{},
+		Details: &jobspb.Progress_Changefeed{
+			Changefeed: &jobspb.ChangefeedProgress{
+				Checkpoint: &jobspb.ChangefeedProgress_Checkpoint{
+					Spans: req.SkippedSpans,
+				},
+				ProtectedTimestampRecord: ptsRecord,
+			},
+		},
+	}
+	telemetry.Count(telemetryPath + `.rescan`)
+	return newProgress, *prevHighWater, req.Filter, nil
+}
+
+// findRescan returns the RESCAN command of an ALTER CHANGEFEED statement, or
+// nil if there is none.
+func findRescan(alterCmds tree.AlterChangefeedCmds) (*tree.AlterChangefeedRescan, error) {
+	var rescan *tree.AlterChangefeedRescan
+	var modifiesTargets bool
+	for _, cmd := range alterCmds {
+		switch v := cmd.(type) {
+		case *tree.AlterChangefeedAddTarget, *tree.AlterChangefeedDropTarget:
+			modifiesTargets = true
+		case *tree.AlterChangefeedRescan:
+			if rescan != nil {
+				return nil, pgerror.New(
+					pgcode.InvalidParameterValue, `cannot specify RESCAN more than once`,
+				)
+			}
+			rescan = v
+		}
+	}
+	if rescan == nil {
+		return nil, nil
+	}
+	if modifiesTargets {
+		return nil, pgerror.New(
+			pgcode.InvalidParameterValue, `cannot combine RESCAN with ADD or DROP`,
+		)
+	}
+	if rescan.Where != nil && rescan.Target == nil {
+		return nil, pgerror.New(
+			pgcode.InvalidParameterValue, `RESCAN with a WHERE clause requires a TABLE`,
+		)
+	}
+	return rescan, nil
+}
+
+// makeRescanRequest resolves the table and the WHERE clause of a RESCAN
+// command as of the high watermark of the changefeed. The timestamp of the
+// filter of the returned request is left for the caller to set to the time
+// as of which the rows are rescanned.
+func makeRescanRequest(
+	ctx context.Context,
+	p sql.PlanHookState,
+	rescan *tree.AlterChangefeedRescan,
+	opts changefeedbase.StatementOptions,
+	prevDetails jobspb.ChangefeedDetails,
+	highWater hlc.Timestamp,
+) (*jobspb.ChangefeedRescanRequest, error) {
+	var targetIDs []descpb.ID
+	for _, spec := range prevDetails.TargetSpecifications {
+		targetIDs = append(targetIDs, spec.TableID)
+	}
+	rescanIDs := targetIDs
+	var filter *jobspb.ChangefeedRescanFilter
+	if rescan.Target != nil {
+		allDescs, err := backupresolver.LoadAllDescs(ctx, p.ExecCfg(), highWater)
+		if err != nil {
+			return nil, err
+		}
+		descResolver, err := backupresolver.NewDescriptorResolver(allDescs)
+		if err != nil {
+			return nil, err
+		}
+		desc, found, err := getTargetDesc(ctx, p, descResolver, rescan.Target.TableName)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, errors.Errorf(
+				`target %q cannot be resolved as of the high water mark`,
+				tree.ErrString(rescan.Target),
+			)
+		}
+		var targetSpec jobspb.ChangefeedTargetSpecification
+		var watched bool
+		for _, spec := range prevDetails.TargetSpecifications {
+			if spec.TableID == desc.GetID() {
+				targetSpec, watched = spec, true
+				break
+			}
+		}
+		if !watched {
+			return nil, pgerror.Newf(
+				pgcode.InvalidParameterValue,
+				`target %q not watched by changefeed`,
+				tree.ErrString(rescan.Target),
+			)
+		}
+		rescanIDs = []descpb.ID{desc.GetID()}
+
+		if rescan.Where != nil {
+			tableDesc, ok := desc.(catalog.TableDescriptor)
+			if !ok {
+				return nil, errors.AssertionFailedf(
+					`expected table descriptor for %q, got %T`, tree.ErrString(rescan.Target), desc,
+				)
+			}
+			tbName, err := getQualifiedTableNameObj(ctx, p.ExecCfg(), p.Txn(), tableDesc)
+			if err != nil {
+				return nil, err
+			}
+			sc := &tree.SelectClause{
+				Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+				From:  tree.From{Tables: tree.TableExprs{&tbName}},
+				Where: rescan.Where,
+			}
+			norm, withDiff, err := cdceval.NormalizeExpression(ctx, p, tableDesc, highWater,
+				targetSpec, sc, opts.IsSet(changefeedbase.OptSplitColumnFamilies))
+			if err != nil {
+				return nil, err
+			}
+			if withDiff {
+				return nil, pgerror.New(
+					pgcode.InvalidParameterValue, `RESCAN WHERE clause cannot reference cdc_prev`,
+				)
+			}
+			filter = &jobspb.ChangefeedRescanFilter{
+				TableID: desc.GetID(),
+				Select:  cdceval.AsStringUnredacted(norm),
+			}
+		}
+	}
+
+	var skippedSpans roachpb.SpanGroup
+	skippedSpans.Add(fetchSpansForDescs(p, targetIDs)...)
+	skippedSpans.Sub(fetchSpansForDescs(p, rescanIDs)...)
+	return &jobspb.ChangefeedRescanRequest{
+		SkippedSpans: skippedSpans.Slice(),
+		Filter:       filter,
+	}, nil
+}
+
+// isRescanOnly returns true if the commands of an ALTER CHANGEFEED statement
+// only request a rescan, which running changefeeds can apply.
+func isRescanOnly(alterCmds tree.AlterChangefeedCmds) bool {
+	for _, cmd := range alterCmds {
+		if _, ok := cmd.(*tree.AlterChangefeedRescan); !ok {
+			return false
+		}
+	}
+	return len(alterCmds) > 0
+}
+
+// requestRescan records the rescan requested by ALTER CHANGEFEED ... RESCAN
+// on a running changefeed in the progress of its job. Its change frontier
+// picks the request up the next time it persists its progress without a span
+// checkpoint, and applies it with applyRescanRequest.
+func requestRescan(
+	ctx context.Context, p sql.PlanHookState, job *jobs.Job, alterCmds tree.AlterChangefeedCmds,
+) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_ChangefeedRescanRunning) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			`ALTER CHANGEFEED ... RESCAN of a running changefeed is only supported after v24.2 upgrade is finalized, `+
+				`please pause job %d to rescan it.`, job.ID())
+	}
+	rescan, err := findRescan(alterCmds)
+	if err != nil {
+		return err
+	}
+	prevDetails := job.Details().(jobspb.ChangefeedDetails)
+	prevHighWater := job.Progress().GetHighWater()
+	if prevHighWater == nil || prevHighWater.IsEmpty() {
+		return errors.New(
+			`cannot rescan a changefeed before its initial scan completes, ` +
+				`please wait until the high watermark is set to rescan it.`,
+		)
+	}
+	req, err := makeRescanRequest(ctx, p, rescan, changefeedbase.MakeStatementOptions(prevDetails.Opts),
+		prevDetails, *prevHighWater)
+	if err != nil {
+		return err
+	}
+	if err := job.WithTxn(p.InternalSQLTxn()).Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		changefeedProgress := md.Progress.GetChangefeed()
+		if changefeedProgress == nil {
+			return errors.AssertionFailedf(`job %d has no changefeed progress`, job.ID())
+		}
+		if changefeedProgress.RescanRequest != nil {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				`a rescan of changefeed %d is already in progress`, job.ID())
+		}
+		changefeedProgress.RescanRequest = req
+		ju.UpdateProgress(md.Progress)
+		return nil
+	}); err != nil {
+		return err
+	}
+	telemetry.Count(telemetryPath + `.rescan`)
+	return nil
+}
+
+// errChangefeedRescanned is returned by the change frontier once it applied a
+// rescan request, so that the changefeed restarts its flow with the new
+// details and progress of its job.
+var errChangefeedRescanned = errors.New(`changefeed rescan requested`)
+
+// applyRescanRequest applies the rescan request recorded in the progress of a
+// running changefeed as generateRescanProgress does when the changefeed is
+// paused: the statement time of the job is moved up to the given timestamp,
+// up to which the changefeed has emitted every change, and the high watermark
+// is reset so that the spans which are not skipped are scanned when the flow
+// of the changefeed restarts. It returns the updated payload of the job.
+func applyRescanRequest(
+	payload jobspb.Payload, progress *jobspb.Progress, ts hlc.Timestamp,
+) jobspb.Payload {
+	changefeedProgress := progress.GetChangefeed()
+	req := changefeedProgress.RescanRequest
+
+	details := *payload.GetChangefeed()
+	details.Opts = make(map[string]string, len(details.Opts))
+	for k, v := range payload.GetChangefeed().Opts {
+		details.Opts[k] = v
+	}
+	delete(details.Opts, changefeedbase.OptNoInitialScan)
+	delete(details.Opts, changefeedbase.OptInitialScanOnly)
+	details.Opts[changefeedbase.OptInitialScan] = ``
+	details.StatementTime = ts
+	details.RescanFilter = nil
+	if req.Filter != nil {
+		filter := *req.Filter
+		filter.Timestamp = ts
+		details.RescanFilter = &filter
+	}
+	payload.Details = jobspb.WrapPayloadDetails(details)
+
+	progress.Progress = &jobspb.Progress_HighWater{}
+	changefeedProgress.Checkpoint = &jobspb.ChangefeedProgress_Checkpoint{
+		Spans: req.SkippedSpans,
+	}
+	changefeedProgress.RescanRequest = nil
+	return payload
+}
+
 func removeSpansFromProgress(prevProgress jobspb.Progress, spansToRemove []roachpb.Span) {
 	changefeedProgress := prevProgress.GetChangefeed()
 	if changefeedProgress == nil {
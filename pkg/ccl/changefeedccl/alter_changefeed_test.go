@@ -1513,6 +1513,138 @@ func TestAlterChangefeedInitialScan(t *testing.T) {
 	}
 }
 
+func TestAlterChangefeedRescan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1), (2)`)
+		sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo, bar WITH resolved = '1s', no_initial_scan`)
+		defer closeFeed(t, testFeed)
+
+		expectResolvedTimestamp(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.ExpectErr(t, `cannot specify RESCAN more than once`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN RESCAN TABLE foo`, feed.JobID()))
+		sqlDB.ExpectErr(t, `cannot combine RESCAN with ADD or DROP`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d ADD baz RESCAN`, feed.JobID()))
+		sqlDB.ExpectErr(t, `RESCAN with a WHERE clause requires a TABLE`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN WHERE a > 1`, feed.JobID()))
+		sqlDB.ExpectErr(t, `target "baz" not watched by changefeed`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN TABLE baz`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN TABLE foo WHERE a > 1`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (3)`)
+		assertPayloads(t, testFeed, []string{
+			`bar: [3]->{"after": {"a": 3}}`,
+		})
+
+		expectResolvedTimestamp(t, testFeed)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+			`bar: [1]->{"after": {"a": 1}}`,
+			`bar: [2]->{"after": {"a": 2}}`,
+			`bar: [3]->{"after": {"a": 3}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
+}
+
+func TestAlterChangefeedRescanRunning(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (1), (2)`)
+		sqlDB.Exec(t, `CREATE TABLE baz (a INT PRIMARY KEY)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo, bar `+
+			`WITH resolved = '100ms', min_checkpoint_frequency = '100ms', no_initial_scan`)
+		defer closeFeed(t, testFeed)
+
+		expectResolvedTimestamp(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		// Only RESCAN applies to a running changefeed.
+		sqlDB.ExpectErr(t, `job \d+ is not paused`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d ADD baz`, feed.JobID()))
+		sqlDB.ExpectErr(t, `job \d+ is not paused`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN SET resolved = '2s'`, feed.JobID()))
+		sqlDB.ExpectErr(t, `target "baz" not watched by changefeed`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN TABLE baz`, feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN TABLE foo WHERE a > 1`, feed.JobID()))
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (3)`)
+		assertPayloads(t, testFeed, []string{
+			`bar: [3]->{"after": {"a": 3}}`,
+		})
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		// The request is cleared once applied, so the changefeed can be
+		// rescanned again once the high watermark is set.
+		testutils.SucceedsSoon(t, func() error {
+			_, err := s.DB.Exec(fmt.Sprintf(`ALTER CHANGEFEED %d RESCAN`, feed.JobID()))
+			return err
+		})
+
+		assertPayloads(t, testFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "c"}}`,
+			`bar: [1]->{"after": {"a": 1}}`,
+			`bar: [2]->{"after": {"a": 2}}`,
+			`bar: [3]->{"after": {"a": 3}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks, feedTestNoExternalConnection)
+}
+
 // This test checks that the time used to get table descriptors in alter
 // changefeed is the time from which changefeed will resume (check
 // validateNewTargets for more info on how this time is calculated).
@@ -169,6 +169,9 @@ type avroMetadata map[string]interface{}
 type avroEnvelopeOpts struct {
 	beforeField, afterField, recordField bool
 	updatedField, resolvedField          bool
+	// opField, sourceField and tsMsField are the fields of the debezium
+	// envelope.
+	opField, sourceField, tsMsField bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -178,6 +181,7 @@ type avroEnvelopeRecord struct {
 
 	opts                  avroEnvelopeOpts
 	before, after, record *avroDataRecord
+	source                *avroRecord
 }
 
 // typeToAvroSchema converts a database type to an avro field
@@ -946,6 +950,31 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, afterField)
 	}
+	if opts.opField {
+		opField := &avroSchemaField{
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+			Name:       `op`,
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, opField)
+	}
+	if opts.sourceField {
+		schema.source = debeziumSourceToAvroSchema(topic, namespace)
+		sourceField := &avroSchemaField{
+			Name:       `source`,
+			SchemaType: []avroSchemaType{avroSchemaNull, schema.source},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, sourceField)
+	}
+	if opts.tsMsField {
+		tsMsField := &avroSchemaField{
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaLong},
+			Name:       `ts_ms`,
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, tsMsField)
+	}
 	if opts.updatedField {
 		updatedField := &avroSchemaField{
 			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
@@ -983,6 +1012,45 @@ func envelopeToAvroSchema(
 	return schema, nil
 }
 
+// debeziumSourceToAvroSchema creates the avro record schema of the `source`
+// block of the debezium envelope.
+func debeziumSourceToAvroSchema(topic string, namespace string) *avroRecord {
+	schema := &avroRecord{
+		Name:       SQLNameToAvroName(topic) + `_source`,
+		SchemaType: `record`,
+		Namespace:  namespace,
+	}
+	for _, name := range debeziumSourceFields {
+		var typ avroSchemaType = avroSchemaString
+		if name == debeziumSourceTsMsField {
+			typ = avroSchemaLong
+		}
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			SchemaType: []avroSchemaType{avroSchemaNull, typ},
+			Name:       name,
+			Default:    nil,
+		})
+	}
+	return schema
+}
+
+// nativeFromDebeziumSource returns the go native representation of the
+// `source` block of the debezium envelope.
+func nativeFromDebeziumSource(s debeziumSource) map[string]interface{} {
+	str := func(v string) interface{} {
+		return goavro.Union(avroUnionKey(avroSchemaString), v)
+	}
+	return map[string]interface{}{
+		debeziumSourceConnectorField: str(debeziumConnector),
+		debeziumSourceDBField:        str(s.database),
+		debeziumSourceSchemaField:    str(s.schema),
+		debeziumSourceTableField:     str(s.table),
+		debeziumSourceSnapshotField:  str(s.snapshotString()),
+		debeziumSourceTsMsField:      goavro.Union(avroUnionKey(avroSchemaLong), debeziumTsMs(s.mvcc)),
+		debeziumSourceMVCCField:      str(s.mvcc.AsOfSystemTime()),
+	}
+}
+
 // BinaryFromRow encodes the given metadata and row data into avro's defined
 // binary format.
 func (r *avroEnvelopeRecord) BinaryFromRow(
@@ -1025,6 +1093,42 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 		}
 	}
 
+	if r.opts.opField {
+		native[`op`] = nil
+		if o, ok := meta[`op`]; ok {
+			delete(meta, `op`)
+			op, ok := o.(string)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata op type: %T`, o))
+			}
+			native[`op`] = goavro.Union(avroUnionKey(avroSchemaString), op)
+		}
+	}
+	if r.opts.sourceField {
+		native[`source`] = nil
+		if s, ok := meta[`source`]; ok {
+			delete(meta, `source`)
+			source, ok := s.(debeziumSource)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata source type: %T`, s))
+			}
+			native[`source`] = goavro.Union(avroUnionKey(r.source), nativeFromDebeziumSource(source))
+		}
+	}
+	if r.opts.tsMsField {
+		native[`ts_ms`] = nil
+		if u, ok := meta[`ts_ms`]; ok {
+			delete(meta, `ts_ms`)
+			ts, ok := u.(hlc.Timestamp)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata timestamp type: %T`, u))
+			}
+			native[`ts_ms`] = goavro.Union(avroUnionKey(avroSchemaLong), debeziumTsMs(ts))
+		}
+	}
 	if r.opts.updatedField {
 		native[`updated`] = nil
 		if u, ok := meta[`updated`]; ok {
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/admission"
@@ -44,6 +45,17 @@ type SinkClient interface {
 	CheckConnection(ctx context.Context) error
 }
 
+// transactionalSinkClient is implemented by SinkClients that write messages in
+// transactions.
+type transactionalSinkClient interface {
+	SinkClient
+	// CommitTransaction commits the messages flushed since the last commit. It
+	// is called by the CommitTransaction method of the batching sink, once all
+	// the messages emitted before a Flush of the sink were flushed, and never
+	// concurrently with Flush.
+	CommitTransaction(ctx context.Context) error
+}
+
 // BatchBuffer is an interface to aggregate KVs into a payload that can be sent
 // to the sink.
 type BatchBuffer interface {
@@ -86,6 +98,11 @@ type batchingSink struct {
 	pacer        *admission.Pacer
 	pacerFactory func() *admission.Pacer
 
+	// deadLetters is set when the changefeed has a dead letter queue. The rows
+	// of batches which the client permanently fails to flush are recorded to
+	// it rather than failing the sink.
+	deadLetters deadLetterQueue
+
 	termErr error
 	wg      ctxgroup.Group
 	hasher  hash.Hash32
@@ -148,6 +165,16 @@ func (s *batchingSink) Flush(ctx context.Context) error {
 
 var _ Sink = (*batchingSink)(nil)
 
+// CommitTransaction implements the transactionalSink interface.
+func (s *batchingSink) CommitTransaction(ctx context.Context) error {
+	if tc, ok := s.client.(transactionalSinkClient); ok {
+		return tc.CommitTransaction(ctx)
+	}
+	return nil
+}
+
+var _ transactionalSink = (*batchingSink)(nil)
+
 // Topics gives the names of all topics that have been initialized
 // and will receive resolved timestamps.
 func (s *batchingSink) Topics() []string {
@@ -226,10 +253,13 @@ func (s *batchingSink) EmitResolvedTimestamp(
 	if err != nil {
 		return err
 	}
-	// Flush the buffered rows.
+	// Flush and commit the buffered rows, which precede the resolved timestamp.
 	if err = s.Flush(ctx); err != nil {
 		return err
 	}
+	if err = s.CommitTransaction(ctx); err != nil {
+		return err
+	}
 
 	return s.client.FlushResolvedPayload(ctx, data, s.topicNamer.Each, s.retryOpts)
 }
@@ -267,6 +297,13 @@ type sinkBatch struct {
 
 	alloc  kvevent.Alloc
 	hasher hash.Hash32
+
+	// topic is the topic of the rows of the batch.
+	topic string
+	// rows are the rows of the batch, retained when the sink has a dead letter
+	// queue so that they can be flushed in smaller batches if the client
+	// rejects the batch.
+	rows []deadLetter
 }
 
 // FinalizePayload closes the writer to produce a payload that is ready to be
@@ -321,6 +358,88 @@ func (sb *sinkBatch) Append(e *rowEvent) {
 	sb.alloc.Merge(&e.alloc)
 }
 
+var _ deadLetterSink = (*batchingSink)(nil)
+
+// setDeadLetterQueue implements the deadLetterSink interface.
+func (s *batchingSink) setDeadLetterQueue(dlq deadLetterQueue) {
+	s.deadLetters = dlq
+}
+
+// isBatchRejection returns true if the sink has a dead letter queue and the
+// client rejected a batch for the contents of some of its rows, in which case
+// the other rows may be accepted in smaller batches.
+func (s *batchingSink) isBatchRejection(err error) bool {
+	return s.deadLetters != nil && changefeedbase.IsPermanentRowError(err)
+}
+
+// flushRejectedRows flushes the rows of a batch which the client rejected with
+// the given error. The rows are bisected until the batches are accepted, or
+// until the rows which the client rejects are isolated, in which case they are
+// recorded to the dead letter queue.
+func (s *batchingSink) flushRejectedRows(
+	ctx context.Context, topic string, rows []deadLetter, cause error,
+) error {
+	var rejected []deadLetter
+	if err := s.bisectRejectedRows(ctx, topic, rows, cause, &rejected); err != nil {
+		return err
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+	if err := s.deadLetters.Log(ctx, rejected...); err != nil {
+		return errors.CombineErrors(cause, err)
+	}
+	return nil
+}
+
+// bisectRejectedRows flushes the halves of the given rows which the client
+// rejected with the given error, recursively, and appends the rows which the
+// client rejects on their own to rejected.
+func (s *batchingSink) bisectRejectedRows(
+	ctx context.Context, topic string, rows []deadLetter, cause error, rejected *[]deadLetter,
+) error {
+	if len(rows) == 1 {
+		dl := rows[0]
+		dl.reason = cause
+		*rejected = append(*rejected, dl)
+		return nil
+	}
+	mid := len(rows) / 2
+	for _, half := range [][]deadLetter{rows[:mid], rows[mid:]} {
+		err := s.flushRows(ctx, topic, half)
+		if err != nil && s.isBatchRejection(err) {
+			err = s.bisectRejectedRows(ctx, topic, half, err, rejected)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushRows flushes the given rows in a single batch, retrying unless the
+// client rejects the batch.
+func (s *batchingSink) flushRows(ctx context.Context, topic string, rows []deadLetter) error {
+	buffer := s.client.MakeBatchBuffer(topic)
+	for _, dl := range rows {
+		buffer.Append(dl.key, dl.value, attributes{tableName: dl.topic.GetTableName()})
+	}
+	payload, err := buffer.Close()
+	if err != nil {
+		return err
+	}
+	for r := retry.StartWithCtx(ctx, s.retryOpts); r.Next(); {
+		err = s.client.Flush(ctx, payload)
+		if err == nil || s.isBatchRejection(err) {
+			return err
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
 func (s *batchingSink) handleError(err error) {
 	if s.termErr == nil {
 		s.termErr = err
@@ -329,6 +448,7 @@ func (s *batchingSink) handleError(err error) {
 
 func (s *batchingSink) newBatchBuffer(topic string) *sinkBatch {
 	batch := newSinkBatch()
+	batch.topic = topic
 	batch.buffer = s.client.MakeBatchBuffer(topic)
 	batch.hasher = s.hasher
 	return batch
@@ -350,7 +470,13 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 		batch, _ := req.(*sinkBatch)
 		defer s.metrics.recordSinkIOInflightChange(int64(-batch.numMessages))
 		s.metrics.recordSinkIOInflightChange(int64(batch.numMessages))
-		return s.client.Flush(ctx, batch.payload)
+		err := s.client.Flush(ctx, batch.payload)
+		if err != nil && s.isBatchRejection(err) {
+			// The rows are flushed again while the keys of the batch are still
+			// in flight, so that they are not reordered with later batches.
+			return s.flushRejectedRows(ctx, batch.topic, batch.rows, err)
+		}
+		return err
 	}
 	ioEmitter := NewParallelIO(ctx, s.retryOpts, s.ioWorkers, ioHandler, s.metrics, s.settings)
 	defer ioEmitter.Close()
@@ -364,12 +490,13 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 		req, err := result.Consume()
 		batch, _ := req.(*sinkBatch)
 
-		if err != nil {
-			s.handleError(err)
-		} else {
+		switch {
+		case err == nil:
 			s.metrics.recordEmittedBatch(
 				batch.bufferTime, batch.numMessages, batch.mvcc, batch.numKVBytes, sinkDoesNotCompress,
 			)
+		default:
+			s.handleError(err)
 		}
 
 		inflight -= batch.numMessages
@@ -492,6 +619,15 @@ func (s *batchingSink) runBatchingWorker(ctx context.Context) {
 				}
 
 				batchBuffer.Append(r)
+				if s.deadLetters != nil {
+					batchBuffer.rows = append(batchBuffer.rows, deadLetter{
+						topic:   r.topicDescriptor,
+						key:     r.key,
+						value:   r.val,
+						updated: r.mvcc,
+						mvcc:    r.mvcc,
+					})
+				}
 				if s.knobs.OnAppend != nil {
 					s.knobs.OnAppend(r)
 				}
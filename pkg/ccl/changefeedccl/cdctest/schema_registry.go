@@ -10,6 +10,7 @@ package cdctest
 
 import (
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
 	"net/http"
@@ -18,9 +19,16 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/errors"
 	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 // SchemaRegistry is the kafka schema registry used in tests.
@@ -29,9 +37,10 @@ type SchemaRegistry struct {
 	statusCode int
 	mu         struct {
 		syncutil.Mutex
-		idAlloc  int32
-		schemas  map[int32]string
-		subjects map[string]int32
+		idAlloc     int32
+		schemas     map[int32]string
+		schemaTypes map[int32]string
+		subjects    map[string]int32
 	}
 }
 
@@ -73,6 +82,7 @@ func StartTestSchemaRegistryWithTLS(
 func makeTestSchemaRegistry() *SchemaRegistry {
 	r := &SchemaRegistry{}
 	r.mu.schemas = make(map[int32]string)
+	r.mu.schemaTypes = make(map[int32]string)
 	r.mu.subjects = make(map[string]int32)
 	r.server = httptest.NewUnstartedServer(http.HandlerFunc(r.requestHandler))
 	return r
@@ -105,13 +115,22 @@ func (r *SchemaRegistry) SchemaForSubject(subject string) string {
 	return r.mu.schemas[r.mu.subjects[subject]]
 }
 
-func (r *SchemaRegistry) registerSchema(subject string, schema string) int32 {
+// SchemaTypeForSubject returns the type of the schema registered for the
+// specified subject. It is empty for avro schemas.
+func (r *SchemaRegistry) SchemaTypeForSubject(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.schemaTypes[r.mu.subjects[subject]]
+}
+
+func (r *SchemaRegistry) registerSchema(subject string, schemaType string, schema string) int32 {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := r.mu.idAlloc
 	r.mu.idAlloc++
 	r.mu.schemas[id] = schema
+	r.mu.schemaTypes[id] = schemaType
 	r.mu.subjects[subject] = id
 	return id
 }
@@ -157,7 +176,8 @@ func (r *SchemaRegistry) requestHandler(hw http.ResponseWriter, hr *http.Request
 // register is an http handler for the underlying server which registers schemas.
 func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType,omitempty"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
@@ -173,7 +193,7 @@ func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err
 	}
 
 	subject := strings.Split(hr.URL.Path, "/")[2]
-	id := r.registerSchema(subject, req.Schema)
+	id := r.registerSchema(subject, req.SchemaType, req.Schema)
 	res, err := json.Marshal(confluentSchemaVersionResponse{ID: id})
 	if err != nil {
 		return err
@@ -230,3 +250,105 @@ func (r *SchemaRegistry) AvroToJSON(avroBytes []byte) ([]byte, error) {
 	// which sorts its object keys and so is deterministic.
 	return json.Marshal(native)
 }
+
+// EncodedProtobufToNative decodes bytes that were previously encoded by the
+// confluent protobuf encoder into a dynamic message of the registered type.
+func (r *SchemaRegistry) EncodedProtobufToNative(b []byte) (*dynamicpb.Message, error) {
+	if len(b) == 0 || b[0] != changefeedbase.ConfluentProtobufWireFormatMagic {
+		return nil, errors.Errorf(`bad magic byte`)
+	}
+	b = b[1:]
+	if len(b) < 4 {
+		return nil, errors.Errorf(`missing registry id`)
+	}
+	id := int32(binary.BigEndian.Uint32(b[:4]))
+	b = b[4:]
+	indexes, b, err := decodeProtobufMessageIndexes(b)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	schema, schemaType := r.mu.schemas[id], r.mu.schemaTypes[id]
+	r.mu.Unlock()
+	if schemaType != `PROTOBUF` {
+		return nil, errors.Errorf(`schema %d is not a protobuf schema: %q`, id, schemaType)
+	}
+	encodedFile, err := base64.StdEncoding.DecodeString(schema)
+	if err != nil {
+		return nil, err
+	}
+	var fdp descriptorpb.FileDescriptorProto
+	if err := protoutil.TODOUnmarshal(encodedFile, &fdp); err != nil {
+		return nil, err
+	}
+	fd, err := protodesc.NewFile(&fdp, new(protoregistry.Files))
+	if err != nil {
+		return nil, err
+	}
+	// Resolve the message indexes, which are the index of the message among
+	// the top-level messages of the file followed by the indexes of the nested
+	// messages.
+	var md protoreflect.MessageDescriptor
+	msgs := fd.Messages()
+	for _, idx := range indexes {
+		if idx < 0 || idx >= msgs.Len() {
+			return nil, errors.Errorf(`bad message indexes %v`, indexes)
+		}
+		md = msgs.Get(idx)
+		msgs = md.Messages()
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := protoutil.TODOUnmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// decodeProtobufMessageIndexes decodes the message indexes that follow the
+// schema ID in the confluent protobuf wire format and returns them along with
+// the remaining bytes. The indexes are a zigzag varint count followed by as
+// many zigzag varint indexes, except that a count of 0 stands for the first
+// top-level message, i.e. the indexes [0].
+func decodeProtobufMessageIndexes(b []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(b)
+	if n <= 0 || count < 0 {
+		return nil, nil, errors.Errorf(`bad message indexes`)
+	}
+	b = b[n:]
+	if count == 0 {
+		return []int{0}, b, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		idx, n := binary.Varint(b)
+		if n <= 0 {
+			return nil, nil, errors.Errorf(`bad message indexes`)
+		}
+		indexes[i] = int(idx)
+		b = b[n:]
+	}
+	return indexes, b, nil
+}
+
+// ProtobufToJSON converts protobuf bytes to their JSON representation.
+func (r *SchemaRegistry) ProtobufToJSON(protoBytes []byte) ([]byte, error) {
+	if len(protoBytes) == 0 {
+		return nil, nil
+	}
+	msg, err := r.EncodedProtobufToNative(protoBytes)
+	if err != nil {
+		return nil, err
+	}
+	j, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	// protojson deliberately randomizes its whitespace. Round trip through
+	// encoding/json, which sorts object keys and is deterministic.
+	var native interface{}
+	if err := json.Unmarshal(j, &native); err != nil {
+		return nil, err
+	}
+	return json.Marshal(native)
+}
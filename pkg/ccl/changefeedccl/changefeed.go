@@ -115,6 +115,8 @@ func init() {
 				changefeedbase.SinkParamClientCert,
 				changefeedbase.SinkParamConfluentAPISecret,
 				changefeedbase.SinkParamAzureAccessKey,
+				changefeedbase.SinkParamNATSToken,
+				changefeedbase.SinkParamNATSNKeySeed,
 			})
 			if err != nil {
 				return nil, err
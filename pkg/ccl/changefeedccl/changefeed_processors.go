@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"sync"
 	"time"
@@ -84,6 +85,12 @@ type changeAggregator struct {
 	// resolvedSpanBuf contains resolved span updates to send to changeFrontier.
 	// If sink is a bufferSink, it must be emptied before these are sent.
 	resolvedSpanBuf encDatumRowBuffer
+	// deadLetters, if non-nil, records the rows which permanently fail to be
+	// encoded or emitted.
+	deadLetters deadLetterQueue
+	// txnSink is set if the sink may write rows in transactions, which are
+	// committed when the resolved spans are forwarded to changeFrontier.
+	txnSink transactionalSink
 	// lastPush records the time when we last pushed data to the coordinator.
 	lastPush time.Time
 
@@ -339,7 +346,15 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	}
 
 	ca.sink, err = getEventSink(ctx, ca.FlowCtx.Cfg, ca.spec.Feed, timestampOracle,
-		ca.spec.User(), ca.spec.JobID, recorder)
+		ca.spec.User(), ca.spec.JobID, ca.sinkInstanceID(), recorder)
+	if err != nil {
+		err = changefeedbase.MarkRetryableError(err)
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return
+	}
+
+	ca.deadLetters, err = makeDeadLetterQueue(ctx, ca.FlowCtx.Cfg, ca.spec, timestampOracle, ca.sliMetrics)
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
 		ca.MoveToDraining(err)
@@ -352,6 +367,12 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	if b, ok := ca.sink.(*bufferSink); ok {
 		ca.changedRowBuf = &b.buf
 	}
+	if s, ok := ca.sink.(deadLetterSink); ok && ca.deadLetters != nil {
+		s.setDeadLetterQueue(ca.deadLetters)
+	}
+	if s, ok := ca.sink.(transactionalSink); ok {
+		ca.txnSink = s
+	}
 
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
@@ -380,7 +401,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 	ca.eventConsumer, ca.sink, err = newEventConsumer(
 		ctx, ca.FlowCtx.Cfg, ca.spec, feed, ca.frontier, kvFeedHighWater,
-		ca.sink, ca.metrics, ca.sliMetrics, ca.knobs)
+		ca.sink, ca.deadLetters, ca.metrics, ca.sliMetrics, ca.knobs)
 	if err != nil {
 		ca.MoveToDraining(err)
 		ca.cancel()
@@ -496,6 +517,7 @@ func (ca *changeAggregator) makeKVFeedCfg(
 		SchemaChangeEvents:  schemaChange.EventClass,
 		SchemaChangePolicy:  schemaChange.Policy,
 		SchemaFeed:          sf,
+		EmitSchemaChanges:   schemaChange.Topic != ``,
 		Knobs:               ca.knobs.FeedKnobs,
 		MonitoringCfg:       monitoringCfg,
 	}, nil
@@ -579,6 +601,20 @@ func (ca *changeAggregator) setupSpansAndFrontier() (spans []roachpb.Span, err e
 	return spans, nil
 }
 
+// sinkInstanceID identifies the sink of the aggregator by the spans it watches,
+// which are the same when the changefeed resumes with the same plan.
+func (ca *changeAggregator) sinkInstanceID() string {
+	h := fnv.New64a()
+	for i := range ca.spec.Watches {
+		b, err := protoutil.Marshal(&ca.spec.Watches[i].Span)
+		if err != nil {
+			return ``
+		}
+		_, _ = h.Write(b)
+	}
+	return fmt.Sprintf(`%016x`, h.Sum64())
+}
+
 // close has two purposes: to synchronize on the completion of the helper
 // goroutines created by the Start method, and to clean up any resources used by
 // the processor.
@@ -613,6 +649,9 @@ func (ca *changeAggregator) close() {
 		// Best effort: context is often cancel by now, so we expect to see an error
 		_ = ca.sink.Close()
 	}
+	if ca.deadLetters != nil {
+		_ = ca.deadLetters.Close()
+	}
 
 	// The sliMetrics registry may hold on to some state for each aggregator
 	// (ex. last known resolved timestamp). De-register the aggregator so this
@@ -736,6 +775,9 @@ func (ca *changeAggregator) computeTrailingMetadata(meta *execinfrapb.Changefeed
 		// which in this case is nothing.
 		return
 	}
+	if err := ca.commitSinkTransaction(); err != nil {
+		return
+	}
 
 	// Build out the list of frontier spans.
 	ca.frontier.Entries(func(r roachpb.Span, ts hlc.Timestamp) (done span.OpResult) {
@@ -782,6 +824,14 @@ func (ca *changeAggregator) tick() error {
 			}
 		}
 		return ca.noteResolvedSpan(resolved)
+	case kvevent.TypeSchemaChange:
+		// The rows consumed so far predate the schema change, so they are
+		// emitted before the message describing it.
+		if err := ca.eventConsumer.Flush(ca.Ctx()); err != nil {
+			return err
+		}
+		return emitSchemaChange(ca.Ctx(), ca.sink,
+			ca.spec.Feed.Opts[changefeedbase.OptSchemaChangeTopic], AllTargets(ca.spec.Feed), event)
 	case kvevent.TypeFlush:
 		return ca.flushBufferedEvents()
 	}
@@ -869,6 +919,9 @@ func (ca *changeAggregator) flushFrontier() error {
 	if err := ca.flushBufferedEvents(); err != nil {
 		return err
 	}
+	if err := ca.commitSinkTransaction(); err != nil {
+		return err
+	}
 
 	// Iterate frontier spans and build a list of spans to emit.
 	var batch jobspb.ResolvedSpans
@@ -889,6 +942,23 @@ func (ca *changeAggregator) flushFrontier() error {
 	return ca.emitResolved(batch)
 }
 
+// commitSinkTransaction commits the transaction of the rows flushed to a
+// transactional sink. It is called once the sink is flushed and before the
+// resolved spans are forwarded to changeFrontier, which checkpoints them, so
+// that the rows of a transaction are those emitted between two checkpoints of
+// the aggregator.
+func (ca *changeAggregator) commitSinkTransaction() error {
+	if ca.txnSink == nil {
+		return nil
+	}
+	// Like all the errors of the sink once it is set up, see errorWrapperSink,
+	// failures to commit are retryable.
+	if err := ca.txnSink.CommitTransaction(ca.Ctx()); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 	progressUpdate := jobspb.ResolvedSpans{
 		ResolvedSpans: batch.ResolvedSpans,
@@ -927,6 +997,10 @@ const (
 	emitNoResolved  = -1
 )
 
+// changeFrontierSinkInstanceID identifies the sink of changeFrontier, which
+// only emits resolved timestamps, among the sinks of the job.
+const changeFrontierSinkInstanceID = `frontier`
+
 type changeFrontier struct {
 	execinfra.ProcessorBase
 
@@ -1239,7 +1313,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 	}
 	cf.sliMetrics = sli
 	cf.sink, err = getResolvedTimestampSink(ctx, cf.FlowCtx.Cfg, cf.spec.Feed, nilOracle,
-		cf.spec.User(), cf.spec.JobID, sli)
+		cf.spec.User(), cf.spec.JobID, changeFrontierSinkInstanceID, sli)
 
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
@@ -1656,6 +1730,7 @@ func (cf *changeFrontier) checkpointJobProgress(
 		defer func() { cf.js.lastRunStatusUpdate = timeutil.Now() }()
 	}
 	cf.metrics.FrontierUpdates.Inc(1)
+	var rescanned bool
 	if cf.js.job != nil {
 		if err := cf.js.job.NoTxn().Update(cf.Ctx(), func(
 			txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
@@ -1673,6 +1748,16 @@ func (cf *changeFrontier) checkpointJobProgress(
 			changefeedProgress := progress.Details.(*jobspb.Progress_Changefeed).Changefeed
 			changefeedProgress.Checkpoint = &checkpoint
 
+			// Apply the rescan requested by ALTER CHANGEFEED ... RESCAN, if any,
+			// once every change up to the frontier has been emitted and no
+			// backfill is in progress.
+			rescanned = changefeedProgress.RescanRequest != nil &&
+				!frontier.IsEmpty() && len(checkpoint.Spans) == 0
+			if rescanned {
+				payload := applyRescanRequest(*md.Payload, progress, frontier)
+				ju.UpdatePayload(&payload)
+			}
+
 			if err := cf.manageProtectedTimestamps(cf.Ctx(), txn, changefeedProgress); err != nil {
 				log.Warningf(cf.Ctx(), "error managing protected timestamp record: %v", err)
 				return err
@@ -1701,6 +1786,11 @@ func (cf *changeFrontier) checkpointJobProgress(
 		}
 	}
 
+	if rescanned {
+		log.Infof(cf.Ctx(), "change frontier applied rescan at %s, restarting changefeed", frontier)
+		return false, errChangefeedRescanned
+	}
+
 	cf.localState.SetHighwater(frontier)
 	cf.localState.SetCheckpoint(checkpoint.Spans, checkpoint.Timestamp)
 
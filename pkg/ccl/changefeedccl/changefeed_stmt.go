@@ -500,7 +500,14 @@ func createChangefeedJobRecord(
 		}
 	}
 	if checkPrivs {
-		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry()); err != nil {
+		dlqURI, err := opts.GetDeadLetterQueue()
+		if err != nil {
+			return nil, err
+		}
+		if dlqURI == changefeedbase.DeadLetterQueueTable {
+			dlqURI = ``
+		}
+		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry(), dlqURI); err != nil {
 			return nil, err
 		}
 	}
@@ -517,12 +524,13 @@ func createChangefeedJobRecord(
 			opts.ForceDiff()
 		} else if opts.IsSet(changefeedbase.OptDiff) {
 			// Expression didn't reference cdc_prev, but the diff option was specified.
-			// This only makes sense if we have wrapped envelope.
+			// This only makes sense if we have wrapped or debezium envelope.
 			encopts, err := opts.GetEncodingOptions()
 			if err != nil {
 				return nil, err
 			}
-			if encopts.Envelope != changefeedbase.OptEnvelopeWrapped {
+			if encopts.Envelope != changefeedbase.OptEnvelopeWrapped &&
+				encopts.Envelope != changefeedbase.OptEnvelopeDebezium {
 				opts.ClearDiff()
 				p.BufferClientNotice(ctx, pgnotice.Newf(
 					"turning off unused %s option (expression <%s> does not use cdc_prev)",
@@ -916,13 +924,25 @@ func validateSink(
 
 	var nilOracle timestampLowerBoundOracle
 	canarySink, err := getAndDialSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig, details,
-		nilOracle, p.User(), jobID, sli)
+		nilOracle, p.User(), jobID, `` /* instanceID */, sli)
 	if err != nil {
 		return err
 	}
 	if err := canarySink.Close(); err != nil {
 		return err
 	}
+	if dest, err := opts.GetDeadLetterQueue(); err != nil {
+		return err
+	} else if dest != `` && dest != changefeedbase.DeadLetterQueueTable {
+		dlqSink, err := getAndDialSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig,
+			deadLetterQueueSinkDetails(details, dest), nilOracle, p.User(), jobID, `` /* instanceID */, sli)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", changefeedbase.OptDeadLetterQueue)
+		}
+		if err := dlqSink.Close(); err != nil {
+			return err
+		}
+	}
 	// If there's no projection we may need to force some options to ensure messages
 	// have enough information.
 	if details.Select == `` {
@@ -1290,6 +1310,20 @@ func (b *changefeedResumer) resumeWithRetries(
 			return err
 		}
 
+		// The change frontier stops the flow once it has applied a rescan
+		// requested by ALTER CHANGEFEED ... RESCAN. The flow then restarts
+		// from the new details and progress of the job, rather than from the
+		// local state of the previous flow.
+		if errors.Is(flowErr, errChangefeedRescanned) {
+			reloadedJob, err := execCfg.JobRegistry.LoadClaimedJob(ctx, jobID)
+			if err != nil {
+				return jobs.MarkAsRetryJobError(err)
+			}
+			details = reloadedJob.Details().(jobspb.ChangefeedDetails)
+			localState.progress = reloadedJob.Progress()
+			continue
+		}
+
 		// All other errors retry.
 		log.Warningf(ctx, `Changefeed job %d encountered transient error: %v (attempt %d)`,
 			jobID, flowErr, 1+r.CurrentAttempt())
@@ -2174,6 +2174,93 @@ func TestChangefeedLaggingSpanCheckpointing(t *testing.T) {
 	require.NoError(t, incorrectCheckpointErr)
 }
 
+func TestChangefeedSchemaChangeTopic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1)`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo `+
+			`WITH schema_change_topic = 'ddl', schema_change_policy = 'nobackfill'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN b STRING NOT NULL DEFAULT 'b'`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, '2')`)
+
+		msgs, err := readNextMessages(context.Background(), foo, 2)
+		require.NoError(t, err)
+		var schemaChange *cdctest.TestFeedMessage
+		for i := range msgs {
+			if msgs[i].Topic == `ddl` {
+				schemaChange = &msgs[i]
+			} else {
+				require.Equal(t, `foo: [2]->{"after": {"a": 2, "b": "2"}}`, msgs[i].String())
+			}
+		}
+		require.NotNil(t, schemaChange, "no schema change message in %v", msgs)
+		require.Equal(t, `["foo"]`, string(schemaChange.Key))
+
+		var msg schemaChangeMessage
+		require.NoError(t, json.Unmarshal(schemaChange.Value, &msg))
+		require.Equal(t, "foo", msg.Table)
+		require.Less(t, msg.Before.Version, msg.After.Version)
+		require.Equal(t, []schemaChangeColumn{
+			{Name: "a", Type: "INT8"},
+		}, msg.Before.Columns)
+		require.Equal(t, []schemaChangeColumn{
+			{Name: "a", Type: "INT8"},
+			{Name: "b", Type: "STRING"},
+		}, msg.After.Columns)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestChangefeedDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		// The schema registry rejects every schema, so no row can be encoded.
+		reg := cdctest.StartErrorTestSchemaRegistry(http.StatusConflict)
+		defer reg.Close()
+
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+		var tableID int
+		sqlDB.QueryRow(t, `SELECT 'foo'::regclass::int`).Scan(&tableID)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo `+
+			`WITH format=avro, confluent_schema_registry=$1, dead_letter_queue='table'`, reg.URL())
+		defer closeFeed(t, foo)
+		jobFeed := foo.(cdctest.EnterpriseTestFeed)
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+
+		// The changefeed makes progress past the rows which it cannot emit.
+		waitForHighwater(t, jobFeed, registry)
+
+		sqlDB.CheckQueryResults(t, fmt.Sprintf(
+			`SELECT job_id, table_id, changed_row->>'a', changed_row->>'b', dlq_reason LIKE '%%409 Conflict%%'
+FROM d.crdb_changefeed.dlq_%d_public_foo ORDER BY 3`, tableID),
+			[][]string{
+				{fmt.Sprint(jobFeed.JobID()), fmt.Sprint(tableID), `1`, `a`, `true`},
+				{fmt.Sprint(jobFeed.JobID()), fmt.Sprint(tableID), `2`, `b`, `true`},
+			})
+		metrics := registry.MetricsStruct().Changefeed.(*Metrics)
+		require.EqualValues(t, 2, metrics.AggMetrics.DeadLetterMessages.Count())
+	}
+
+	// The kafka test feed uses its own schema registry with avro.
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
 // Test checkpointing during schema change backfills that can be paused and
 // resumed multiple times during execution
 func TestChangefeedSchemaChangeBackfillCheckpoint(t *testing.T) {
@@ -10,3 +10,8 @@ package changefeedbase
 
 // ConfluentAvroWireFormatMagic is the "magic" header bytes for kafka messages.
 const ConfluentAvroWireFormatMagic = byte(0)
+
+// ConfluentProtobufWireFormatMagic is the "magic" header byte for kafka
+// messages encoded with format=protobuf. Protobuf messages use the same
+// confluent wire format header as avro ones.
+const ConfluentProtobufWireFormatMagic = ConfluentAvroWireFormatMagic
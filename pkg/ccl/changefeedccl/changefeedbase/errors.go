@@ -101,6 +101,29 @@ func MarkRetryableError(cause error) error {
 	return errors.Mark(cause, &retryableError{})
 }
 
+type permanentRowError struct{}
+
+func (e *permanentRowError) Error() string {
+	return "permanent changefeed row error"
+}
+
+// MarkPermanentRowError wraps the given error, marking it as caused by the
+// contents of the rows being encoded or emitted: retrying those rows would
+// fail again. Such rows may be routed to the dead letter queue of the
+// changefeed instead of failing it.
+func MarkPermanentRowError(cause error) error {
+	if cause == nil {
+		return nil
+	}
+	return errors.Mark(cause, &permanentRowError{})
+}
+
+// IsPermanentRowError returns true if the error was marked with
+// MarkPermanentRowError.
+func IsPermanentRowError(err error) bool {
+	return errors.Is(err, &permanentRowError{})
+}
+
 type drainHelper interface {
 	IsDraining() bool
 }
@@ -93,6 +93,7 @@ const (
 	OptCompression                        = `compression`
 	OptSchemaChangeEvents                 = `schema_change_events`
 	OptSchemaChangePolicy                 = `schema_change_policy`
+	OptSchemaChangeTopic                  = `schema_change_topic`
 	OptSplitColumnFamilies                = `split_column_families`
 	OptExpirePTSAfter                     = `gc_protect_expires_after`
 	OptWebhookAuthHeader                  = `webhook_auth_header`
@@ -106,6 +107,7 @@ const (
 	OptLaggingRangesPollingInterval       = `lagging_ranges_polling_interval`
 	OptIgnoreDisableChangefeedReplication = `ignore_disable_changefeed_replication`
 	OptEncodeJSONValueNullAsObject        = `encode_json_value_null_as_object`
+	OptDeadLetterQueue                    = `dead_letter_queue`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
@@ -152,11 +154,13 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 	OptEnvelopeBare          EnvelopeType = `bare`
+	OptEnvelopeDebezium      EnvelopeType = `debezium`
 
-	OptFormatJSON    FormatType = `json`
-	OptFormatAvro    FormatType = `avro`
-	OptFormatCSV     FormatType = `csv`
-	OptFormatParquet FormatType = `parquet`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `avro`
+	OptFormatCSV      FormatType = `csv`
+	OptFormatParquet  FormatType = `parquet`
+	OptFormatProtobuf FormatType = `protobuf`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
@@ -178,6 +182,9 @@ const (
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptPubsubSinkConfig  = `pubsub_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	OptNATSSinkConfig    = `nats_sink_config`
+	OptMQTTSinkConfig    = `mqtt_sink_config`
+	OptRedisSinkConfig   = `redis_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -202,11 +209,16 @@ const (
 	SinkSchemeCloudStorageHTTPS     = `file-https`
 	SinkSchemeCloudStorageNodelocal = `nodelocal`
 	SinkSchemeCloudStorageS3        = `s3`
+	SinkSchemeCloudStorageSFTP      = `sftp`
 	SinkSchemeExperimentalSQL       = `experimental-sql`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
+	SinkSchemeMQTT                  = `mqtt`
+	SinkSchemeMQTTS                 = `mqtts`
+	SinkSchemeRedis                 = `redis`
+	SinkSchemeRedisTLS              = `rediss`
 	SinkSchemePulsar                = `pulsar`
 	SinkSchemeExternalConnection    = `external`
 	SinkParamSASLEnabled            = `sasl_enabled`
@@ -232,6 +244,21 @@ const (
 	SinkParamAzureAccessKeyName = `shared_access_key_name`
 	SinkParamAzureAccessKey     = `shared_access_key`
 
+	SinkSchemeNATS        = `nats`
+	SinkParamNATSSubject  = `subject`
+	SinkParamNATSToken    = `token`
+	SinkParamNATSJWT      = `jwt`
+	SinkParamNATSNKeySeed = `nkey_seed`
+
+	SinkParamMQTTTopicTemplate = `topic_template`
+	SinkParamMQTTQoS           = `qos`
+	SinkParamMQTTRetain        = `retain`
+
+	SinkParamRedisMode              = `mode`
+	SinkParamRedisMaxLen            = `max_len`
+	SinkParamRedisApproximateMaxLen = `approximate_max_len`
+	SinkParamRedisKeyTemplate       = `key_template`
+
 	RegistryParamCACert     = `ca_cert`
 	RegistryParamClientCert = `client_cert`
 	RegistryParamClientKey  = `client_key`
@@ -342,8 +369,8 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptCursor:                             timestampOption,
 	OptCustomKeyColumn:                    stringOption,
 	OptEndTime:                            timestampOption,
-	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare"),
-	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet"),
+	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare", "debezium"),
+	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet", "protobuf"),
 	OptFullTableName:                      flagOption,
 	OptKeyInValue:                         flagOption,
 	OptTopicInValue:                       flagOption,
@@ -355,6 +382,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptCompression:                        enum("gzip", "zstd"),
 	OptSchemaChangeEvents:                 enum("column_changes", "default"),
 	OptSchemaChangePolicy:                 enum("backfill", "nobackfill", "stop", "ignore"),
+	OptSchemaChangeTopic:                  stringOption,
 	OptSplitColumnFamilies:                flagOption,
 	OptInitialScan:                        enum("yes", "no", "only").orEmptyMeans("yes"),
 	OptNoInitialScan:                      flagOption,
@@ -364,6 +392,9 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptKafkaSinkConfig:                    jsonOption,
 	OptPubsubSinkConfig:                   jsonOption,
 	OptWebhookSinkConfig:                  jsonOption,
+	OptNATSSinkConfig:                     jsonOption,
+	OptMQTTSinkConfig:                     jsonOption,
+	OptRedisSinkConfig:                    jsonOption,
 	OptWebhookAuthHeader:                  stringOption,
 	OptWebhookClientTimeout:               durationOption,
 	OptOnError:                            enum("pause", "fail"),
@@ -375,6 +406,7 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptLaggingRangesPollingInterval:       durationOption,
 	OptIgnoreDisableChangefeedReplication: flagOption,
 	OptEncodeJSONValueNullAsObject:        flagOption,
+	OptDeadLetterQueue:                    stringOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -383,12 +415,12 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptKeyInValue, OptTopicInValue,
 	OptResolvedTimestamps, OptUpdatedTimestamps,
 	OptMVCCTimestamps, OptDiff, OptSplitColumnFamilies,
-	OptSchemaChangeEvents, OptSchemaChangePolicy,
+	OptSchemaChangeEvents, OptSchemaChangePolicy, OptSchemaChangeTopic,
 	OptOnError,
 	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptUnordered, OptCustomKeyColumn,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
-	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject,
+	OptIgnoreDisableChangefeedReplication, OptEncodeJSONValueNullAsObject, OptDeadLetterQueue,
 )
 
 // SQLValidOptions is options exclusive to SQL sink
@@ -406,6 +438,15 @@ var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTi
 // PubsubValidOptions is options exclusive to pubsub sink
 var PubsubValidOptions = makeStringSet(OptPubsubSinkConfig)
 
+// NATSValidOptions is options exclusive to the NATS sink
+var NATSValidOptions = makeStringSet(OptNATSSinkConfig)
+
+// MQTTValidOptions is options exclusive to the MQTT sink
+var MQTTValidOptions = makeStringSet(OptMQTTSinkConfig)
+
+// RedisValidOptions is options exclusive to the Redis sink
+var RedisValidOptions = makeStringSet(OptRedisSinkConfig)
+
 // ExternalConnectionValidOptions is options exclusive to the external
 // connection sink.
 //
@@ -446,6 +487,16 @@ var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptDeadLetterQueue:         redactDeadLetterQueue,
+}
+
+// redactDeadLetterQueue redacts the value of the dead_letter_queue option
+// unless it is a table, since a sink URI may contain credentials.
+var redactDeadLetterQueue = func(v string) (string, error) {
+	if v == DeadLetterQueueTable {
+		return v, nil
+	}
+	return redactSimple(v)
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -465,11 +516,12 @@ type OptionsSet map[string]struct{}
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions OptionsSet = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptSchemaChangeTopic)
 
 // ParquetFormatUnsupportedOptions is options that are not supported with the
 // parquet format.
-var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
+var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue, OptSchemaChangeTopic,
+	OptDeadLetterQueue)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -848,6 +900,9 @@ func (e EncodingOptions) Validate() error {
 	if e.Format != OptFormatJSON && e.EncodeJSONValueNullAsObject {
 		return errors.Errorf(`%s is only usable with %s=%s`, OptEncodeJSONValueNullAsObject, OptFormat, OptFormatJSON)
 	}
+	if e.Envelope == OptEnvelopeDebezium {
+		return e.validateDebezium()
+	}
 	if e.Envelope != OptEnvelopeWrapped && e.Format != OptFormatJSON && e.Format != OptFormatParquet {
 		requiresWrap := []struct {
 			k string
@@ -869,11 +924,44 @@ func (e EncodingOptions) Validate() error {
 	return nil
 }
 
+// validateDebezium validates the options of a changefeed using the debezium
+// envelope. The envelope has a fixed shape, so none of the options adding
+// fields to the wrapped envelope apply to it. It needs the previous value of
+// a row to populate its `before` field and to tell updates from inserts.
+func (e EncodingOptions) validateDebezium() error {
+	if e.Format != OptFormatJSON && e.Format != OptFormatAvro {
+		return errors.Errorf(`%s=%s is only usable with %s=%s or %s=%s`,
+			OptEnvelope, OptEnvelopeDebezium, OptFormat, OptFormatJSON, OptFormat, OptFormatAvro)
+	}
+	if !e.Diff {
+		return errors.Errorf(`%s=%s requires the %s option`, OptEnvelope, OptEnvelopeDebezium, OptDiff)
+	}
+	notSupported := []struct {
+		k string
+		b bool
+	}{
+		{OptKeyInValue, e.KeyInValue},
+		{OptTopicInValue, e.TopicInValue},
+		{OptUpdatedTimestamps, e.UpdatedTimestamps},
+		{OptMVCCTimestamps, e.MVCCTimestamps},
+	}
+	for _, v := range notSupported {
+		if v.b {
+			return errors.Errorf(`%s is not supported with %s=%s`,
+				v.k, OptEnvelope, OptEnvelopeDebezium)
+		}
+	}
+	return nil
+}
+
 // SchemaChangeHandlingOptions specify how the feed should
 // behave when a target is affected by a schema change.
 type SchemaChangeHandlingOptions struct {
 	EventClass SchemaChangeEventClass
 	Policy     SchemaChangePolicy
+	// Topic, if set, is the topic (or file prefix) to which a message
+	// describing each schema change is emitted.
+	Topic string
 }
 
 // GetSchemaChangeHandlingOptions populates and validates a SchemaChangeHandlingOptions.
@@ -899,10 +987,46 @@ func (s StatementOptions) GetSchemaChangeHandlingOptions() (SchemaChangeHandling
 		o.Policy = SchemaChangePolicy(p)
 	}
 
+	o.Topic = s.m[OptSchemaChangeTopic]
+	if _, ok := s.m[OptSchemaChangeTopic]; ok && o.Topic == `` {
+		return o, errors.Newf(`%s cannot be empty`, OptSchemaChangeTopic)
+	}
+	if o.Topic != `` && o.Policy == OptSchemaChangePolicyIgnore {
+		return o, errors.Newf(`%s is not usable with %s='%s' because schema changes are not observed`,
+			OptSchemaChangeTopic, OptSchemaChangePolicy, OptSchemaChangePolicyIgnore)
+	}
+
 	return o, nil
 
 }
 
+// DeadLetterQueueTable is the value of the dead_letter_queue option which
+// records rows to tables in the crdb_changefeed schema of the database of each
+// target rather than to a sink.
+const DeadLetterQueueTable = `table`
+
+// GetDeadLetterQueue returns the value of the dead_letter_queue option, which
+// is either DeadLetterQueueTable or the URI of a sink. It is empty if rows
+// which cannot be emitted fail the changefeed.
+func (s StatementOptions) GetDeadLetterQueue() (string, error) {
+	v, ok := s.m[OptDeadLetterQueue]
+	if !ok || v == DeadLetterQueueTable {
+		return v, nil
+	}
+	if v == `` {
+		return ``, errors.Newf(`%s cannot be empty`, OptDeadLetterQueue)
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return ``, errors.Wrapf(err, `problem parsing option %s`, OptDeadLetterQueue)
+	}
+	if u.Scheme == `` {
+		return ``, errors.Newf(`%s must be '%s' or a sink URI, found %q`,
+			OptDeadLetterQueue, DeadLetterQueueTable, v)
+	}
+	return v, nil
+}
+
 // Filters are aspects of the feed that the backing
 // kvfeed or rangefeed want to know about.
 type Filters struct {
@@ -954,6 +1078,24 @@ func (s StatementOptions) GetPubsubConfigJSON() SinkSpecificJSONConfig {
 	return s.getJSONValue(OptPubsubSinkConfig)
 }
 
+// GetNATSConfigJSON returns arbitrary json to be interpreted
+// by the NATS sink.
+func (s StatementOptions) GetNATSConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptNATSSinkConfig)
+}
+
+// GetMQTTConfigJSON returns arbitrary json to be interpreted
+// by the MQTT sink.
+func (s StatementOptions) GetMQTTConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptMQTTSinkConfig)
+}
+
+// GetRedisConfigJSON returns arbitrary json to be interpreted
+// by the Redis sink.
+func (s StatementOptions) GetRedisConfigJSON() SinkSpecificJSONConfig {
+	return s.getJSONValue(OptRedisSinkConfig)
+}
+
 // GetResolvedTimestampInterval gets the best-effort interval at which resolved timestamps
 // should be emitted. Nil or 0 means emit as often as possible. False means do not emit at all.
 // Returns an error for negative or invalid duration value.
@@ -1151,6 +1293,12 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 			return err
 		}
 	}
+	if _, err := s.GetSchemaChangeHandlingOptions(); err != nil {
+		return err
+	}
+	if _, err := s.GetDeadLetterQueue(); err != nil {
+		return err
+	}
 	for o := range s.m {
 		for _, pair := range incompatibleOptionsMap[o] {
 			if s.IsSet(pair.opt1) && s.IsSet(pair.opt2) {
@@ -40,6 +40,16 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"schema_change_topic": "ddl"}, false, ""},
+		{map[string]string{"schema_change_topic": ""}, false, "schema_change_topic cannot be empty"},
+		{map[string]string{"schema_change_topic": "ddl", "schema_change_policy": "ignore"}, false,
+			"is not usable with schema_change_policy='ignore'"},
+		{map[string]string{"schema_change_topic": "ddl", "format": "parquet"}, false, "cannot specify both"},
+		{map[string]string{"dead_letter_queue": "table"}, false, ""},
+		{map[string]string{"dead_letter_queue": "kafka://dlq:9092"}, false, ""},
+		{map[string]string{"dead_letter_queue": ""}, false, "dead_letter_queue cannot be empty"},
+		{map[string]string{"dead_letter_queue": "dlq"}, false, "must be 'table' or a sink URI"},
+		{map[string]string{"dead_letter_queue": "table", "format": "parquet"}, false, "cannot specify both"},
 	}
 
 	for _, test := range tests {
@@ -69,6 +79,13 @@ func TestEncodingOptionsValidations(t *testing.T) {
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, UpdatedTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, MVCCTimestamps: true}, "is only usable with envelope=wrapped"},
 		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeBare, Diff: true}, "is only usable with envelope=wrapped"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeDebezium, Diff: true}, ""},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeDebezium, Diff: true}, ""},
+		{EncodingOptions{Format: OptFormatCSV, Envelope: OptEnvelopeDebezium, Diff: true}, "envelope=debezium is only usable with format=json or format=avro"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeDebezium}, "envelope=debezium requires the diff option"},
+		{EncodingOptions{Format: OptFormatAvro, Envelope: OptEnvelopeDebezium, Diff: true, KeyInValue: true}, "key_in_value is not supported with envelope=debezium"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeDebezium, Diff: true, UpdatedTimestamps: true}, "updated is not supported with envelope=debezium"},
+		{EncodingOptions{Format: OptFormatJSON, Envelope: OptEnvelopeDebezium, Diff: true, MVCCTimestamps: true}, "mvcc_timestamp is not supported with envelope=debezium"},
 	}
 
 	for _, c := range cases {
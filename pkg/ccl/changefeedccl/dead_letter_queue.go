@@ -0,0 +1,381 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	dlqSchemaName = "crdb_changefeed"
+	// dlqBaseTableName is defined as: "<dbName>.<dlqSchemaName>.dlq_<tableID>_<schemaName>_<tableName>"
+	dlqBaseTableName        = "%s.%s.%s"
+	createDLQSchemaBaseStmt = `CREATE SCHEMA IF NOT EXISTS %s.%s`
+	createDLQTableBaseStmt  = `CREATE TABLE IF NOT EXISTS %s (
+			id              INT8 DEFAULT unique_rowid(),
+			job_id          INT8 NOT NULL,
+			table_id        INT8 NOT NULL,
+			dlq_timestamp   TIMESTAMPTZ NOT NULL DEFAULT now():::TIMESTAMPTZ,
+			dlq_reason      STRING NOT NULL,
+			updated         DECIMAL NOT NULL,
+			mvcc_timestamp  DECIMAL NOT NULL,
+			-- The encoded key and value are only known for rows which the sink
+			-- rejected; the row itself only for rows which failed to be encoded.
+			encoded_key     BYTES,
+			encoded_value   BYTES,
+			changed_row     JSONB,
+			PRIMARY KEY (job_id, dlq_timestamp, id) USING HASH
+		)`
+	insertDLQBaseStmt = `INSERT INTO %s (
+			job_id,
+			table_id,
+			dlq_reason,
+			updated,
+			mvcc_timestamp,
+			encoded_key,
+			encoded_value,
+			changed_row
+		) VALUES %s`
+	// insertDLQColumns is the number of columns set by insertDLQBaseStmt.
+	insertDLQColumns = 8
+	// insertDLQMaxRows is the maximum number of rows inserted by a single
+	// statement.
+	insertDLQMaxRows = 256
+)
+
+// deadLetter is a row which the changefeed permanently failed to encode or
+// emit.
+type deadLetter struct {
+	topic         TopicDescriptor
+	updated, mvcc hlc.Timestamp
+	// row is the row which failed to be encoded. It is not initialized for
+	// rows which the sink rejected.
+	row cdcevent.Row
+	// key and value are the encoded row. They are only set for rows which the
+	// sink rejected.
+	key, value []byte
+	reason     error
+}
+
+// deadLetterQueue records the rows which a changefeed permanently fails to
+// encode or emit, so that it can keep making progress past them. It is
+// configured with the dead_letter_queue option.
+type deadLetterQueue interface {
+	// Log records the rows. Once it returns without error, the changefeed no
+	// longer needs to emit the rows.
+	Log(ctx context.Context, rows ...deadLetter) error
+	Close() error
+}
+
+// deadLetterSink is implemented by sinks which can record the rows they
+// permanently fail to emit to a dead letter queue.
+type deadLetterSink interface {
+	setDeadLetterQueue(dlq deadLetterQueue)
+}
+
+// makeDeadLetterQueue returns the dead letter queue of the changefeed, or nil
+// if it does not have one.
+func makeDeadLetterQueue(
+	ctx context.Context,
+	cfg *execinfra.ServerConfig,
+	spec execinfrapb.ChangeAggregatorSpec,
+	timestampOracle timestampLowerBoundOracle,
+	metrics *sliMetrics,
+) (deadLetterQueue, error) {
+	opts := changefeedbase.MakeStatementOptions(spec.Feed.Opts)
+	dest, err := opts.GetDeadLetterQueue()
+	if err != nil {
+		return nil, err
+	}
+	switch dest {
+	case ``:
+		return nil, nil
+	case changefeedbase.DeadLetterQueueTable:
+		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
+		dlq := &tableDeadLetterQueue{
+			ie:       execCfg.InternalDB.Executor(),
+			jobID:    spec.JobID,
+			resolver: newDebeziumSourceResolver(execCfg.LeaseManager),
+			metrics:  metrics,
+		}
+		dlq.mu.tables = make(map[descpb.ID]string)
+		return dlq, nil
+	default:
+		// Messages emitted to the dead letter queue are not counted as emitted
+		// by the changefeed.
+		sink, err := getEventSink(ctx, cfg, deadLetterQueueSinkDetails(spec.Feed, dest),
+			timestampOracle, spec.User(), spec.JobID, `` /* instanceID */, (*sliMetrics)(nil))
+		if err != nil {
+			return nil, err
+		}
+		return &sinkDeadLetterQueue{sink: sink, jobID: spec.JobID, metrics: metrics}, nil
+	}
+}
+
+// deadLetterQueueSinkDetails returns the details with which to make the sink
+// of a dead letter queue with the given URI. The options of the changefeed are
+// specific to its own sink, and messages recorded to the dead letter queue are
+// always JSON.
+func deadLetterQueueSinkDetails(
+	details jobspb.ChangefeedDetails, sinkURI string,
+) jobspb.ChangefeedDetails {
+	details.SinkURI = sinkURI
+	details.Opts = map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatJSON)}
+	return details
+}
+
+// tableDeadLetterQueue records rows to a table in the crdb_changefeed schema
+// of the database of their table, which it creates when it first records a
+// row of the table.
+type tableDeadLetterQueue struct {
+	ie       isql.Executor
+	jobID    jobspb.JobID
+	metrics  *sliMetrics
+	resolver *debeziumSourceResolver
+
+	mu struct {
+		syncutil.Mutex
+		// tables are the names of the dead letter queue tables created so far,
+		// by the ID of the table whose rows they record.
+		tables map[descpb.ID]string
+	}
+}
+
+var _ deadLetterQueue = (*tableDeadLetterQueue)(nil)
+
+// Log implements the deadLetterQueue interface. The rows of each table are
+// inserted in batches.
+func (dlq *tableDeadLetterQueue) Log(ctx context.Context, rows ...deadLetter) error {
+	// tables are the names of the dead letter queue tables of the rows, in the
+	// order in which they are first seen.
+	var tables []string
+	args := make(map[string][]interface{})
+	for _, dl := range rows {
+		dlqTableName, err := dlq.getOrCreateTable(ctx, dl)
+		if err != nil {
+			return err
+		}
+		if _, ok := args[dlqTableName]; !ok {
+			tables = append(tables, dlqTableName)
+		}
+
+		changedRow := tree.Datum(tree.DNull)
+		if dl.row.IsInitialized() {
+			if jsonRow, err := dl.row.ToJSON(); err != nil {
+				log.Warningf(ctx, "failed to convert cdc event row to json: %v", err)
+			} else {
+				changedRow = jsonRow
+			}
+		}
+		args[dlqTableName] = append(args[dlqTableName],
+			int64(dlq.jobID),
+			int64(dl.topic.GetTopicIdentifier().TableID),
+			dl.reason.Error(),
+			eval.TimestampToDecimalDatum(dl.updated),
+			eval.TimestampToDecimalDatum(dl.mvcc),
+			bytesOrNull(dl.key),
+			bytesOrNull(dl.value),
+			changedRow,
+		)
+	}
+
+	for _, dlqTableName := range tables {
+		tableArgs := args[dlqTableName]
+		for len(tableArgs) > 0 {
+			n := len(tableArgs)
+			if n > insertDLQMaxRows*insertDLQColumns {
+				n = insertDLQMaxRows * insertDLQColumns
+			}
+			if _, err := dlq.ie.Exec(
+				ctx,
+				"insert-rows-into-changefeed-dlq-table",
+				nil, /* txn */
+				fmt.Sprintf(insertDLQBaseStmt, dlqTableName, insertDLQPlaceholders(n/insertDLQColumns)),
+				tableArgs[:n]...,
+			); err != nil {
+				return errors.Wrapf(err, "failed to insert rows for table %s", dlqTableName)
+			}
+			tableArgs = tableArgs[n:]
+		}
+	}
+	dlq.metrics.DeadLetterMessages.Inc(int64(len(rows)))
+	return nil
+}
+
+// insertDLQPlaceholders returns the VALUES clause of an insertDLQBaseStmt
+// which inserts the given number of rows.
+func insertDLQPlaceholders(numRows int) string {
+	var b strings.Builder
+	for i := 0; i < numRows; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := 0; j < insertDLQColumns; j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", i*insertDLQColumns+j+1)
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+func bytesOrNull(b []byte) tree.Datum {
+	if b == nil {
+		return tree.DNull
+	}
+	return tree.NewDBytes(tree.DBytes(b))
+}
+
+func (dlq *tableDeadLetterQueue) getOrCreateTable(
+	ctx context.Context, dl deadLetter,
+) (string, error) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	tableID := dl.topic.GetTopicIdentifier().TableID
+	if name, ok := dlq.mu.tables[tableID]; ok {
+		return name, nil
+	}
+
+	database, schema, err := dlq.resolver.resolve(ctx, cdcevent.Metadata{
+		TableID:  tableID,
+		Version:  dl.topic.GetVersion(),
+		SchemaTS: dl.updated,
+	})
+	if err != nil {
+		return "", err
+	}
+	database = lexbase.EscapeSQLIdent(database)
+	name := fmt.Sprintf(dlqBaseTableName, database, dlqSchemaName,
+		lexbase.EscapeSQLIdent(fmt.Sprintf("dlq_%d_%s_%s", tableID, schema, dl.topic.GetTableName())))
+
+	createSchemaStmt := fmt.Sprintf(createDLQSchemaBaseStmt, database, dlqSchemaName)
+	if _, err := dlq.ie.Exec(ctx, "create-changefeed-dlq-schema", nil, createSchemaStmt); err != nil {
+		return "", errors.Wrapf(err, "failed to create %s schema in database %s", dlqSchemaName, database)
+	}
+	createTableStmt := fmt.Sprintf(createDLQTableBaseStmt, name)
+	if _, err := dlq.ie.Exec(ctx, "create-changefeed-dlq-table", nil, createTableStmt); err != nil {
+		return "", errors.Wrapf(err, "failed to create dlq for table %d", tableID)
+	}
+	dlq.mu.tables[tableID] = name
+	return name, nil
+}
+
+// Close implements the deadLetterQueue interface.
+func (dlq *tableDeadLetterQueue) Close() error {
+	return nil
+}
+
+// sinkDeadLetterQueue emits rows as JSON messages to a sink other than the one
+// of the changefeed, under the topic of their table.
+type sinkDeadLetterQueue struct {
+	jobID   jobspb.JobID
+	metrics *sliMetrics
+
+	// mu serializes the emission and flush of the rows of each call to Log.
+	mu   syncutil.Mutex
+	sink EventSink
+}
+
+var _ deadLetterQueue = (*sinkDeadLetterQueue)(nil)
+
+// deadLetterMessage is the value of the message emitted for a row recorded to
+// a sinkDeadLetterQueue.
+type deadLetterMessage struct {
+	JobID         jobspb.JobID `json:"job_id"`
+	Table         string       `json:"table"`
+	TableID       descpb.ID    `json:"table_id"`
+	Reason        string       `json:"reason"`
+	Updated       string       `json:"updated"`
+	MVCCTimestamp string       `json:"mvcc_timestamp"`
+	// EncodedKey and EncodedValue are only set for rows which the sink
+	// rejected, and Row only for rows which failed to be encoded.
+	EncodedKey   []byte            `json:"encoded_key,omitempty"`
+	EncodedValue []byte            `json:"encoded_value,omitempty"`
+	Row          gojson.RawMessage `json:"row,omitempty"`
+}
+
+// Log implements the deadLetterQueue interface.
+func (dlq *sinkDeadLetterQueue) Log(ctx context.Context, rows ...deadLetter) error {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+	for _, dl := range rows {
+		if err := dlq.emit(ctx, dl); err != nil {
+			return err
+		}
+	}
+	// The rows are flushed right away since the changefeed may checkpoint past
+	// them as soon as Log returns.
+	if err := dlq.sink.Flush(ctx); err != nil {
+		return errors.Wrap(err, "failed to flush dead letter queue")
+	}
+	dlq.metrics.DeadLetterMessages.Inc(int64(len(rows)))
+	return nil
+}
+
+func (dlq *sinkDeadLetterQueue) emit(ctx context.Context, dl deadLetter) error {
+	msg := deadLetterMessage{
+		JobID:         dlq.jobID,
+		Table:         dl.topic.GetTableName(),
+		TableID:       dl.topic.GetTopicIdentifier().TableID,
+		Reason:        dl.reason.Error(),
+		Updated:       dl.updated.AsOfSystemTime(),
+		MVCCTimestamp: dl.mvcc.AsOfSystemTime(),
+		EncodedKey:    dl.key,
+		EncodedValue:  dl.value,
+	}
+	if dl.row.IsInitialized() {
+		if jsonRow, err := dl.row.ToJSON(); err != nil {
+			log.Warningf(ctx, "failed to convert cdc event row to json: %v", err)
+		} else {
+			msg.Row = gojson.RawMessage(jsonRow.JSON.String())
+		}
+	}
+	key, err := gojson.Marshal([]string{msg.Table})
+	if err != nil {
+		return err
+	}
+	value, err := gojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := dlq.sink.EmitRow(ctx, dl.topic, key, value, dl.updated, dl.mvcc, kvevent.Alloc{}); err != nil {
+		return errors.Wrap(err, "failed to emit row to dead letter queue")
+	}
+	return nil
+}
+
+// Close implements the deadLetterQueue interface.
+func (dlq *sinkDeadLetterQueue) Close() error {
+	return dlq.sink.Close()
+}
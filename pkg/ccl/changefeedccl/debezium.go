@@ -0,0 +1,191 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// The debezium envelope mirrors the change event value produced by Debezium
+// connectors, so that consumers built for Debezium can read changefeeds
+// without any translation:
+//
+//	{
+//	  "before": {...},
+//	  "after": {...},
+//	  "op": "u",
+//	  "source": {"connector": "cockroachdb", "db": ..., "schema": ..., ...},
+//	  "ts_ms": 1700000000000
+//	}
+//
+// See https://debezium.io/documentation/reference/stable/connectors/postgresql.html#postgresql-change-events-value.
+
+// Values of the `op` field of the debezium envelope.
+const (
+	debeziumOpCreate = `c`
+	debeziumOpUpdate = `u`
+	debeziumOpDelete = `d`
+	debeziumOpRead   = `r`
+)
+
+// debeziumConnector is the value of the `connector` field of the `source`
+// block of the debezium envelope.
+const debeziumConnector = `cockroachdb`
+
+// Names of the fields of the `source` block of the debezium envelope.
+const (
+	debeziumSourceConnectorField = `connector`
+	debeziumSourceDBField        = `db`
+	debeziumSourceSchemaField    = `schema`
+	debeziumSourceTableField     = `table`
+	debeziumSourceSnapshotField  = `snapshot`
+	debeziumSourceTsMsField      = `ts_ms`
+	debeziumSourceMVCCField      = `mvcc_timestamp`
+)
+
+// debeziumSourceFields lists the fields of the `source` block in the order in
+// which they are encoded.
+var debeziumSourceFields = []string{
+	debeziumSourceConnectorField,
+	debeziumSourceDBField,
+	debeziumSourceSchemaField,
+	debeziumSourceTableField,
+	debeziumSourceSnapshotField,
+	debeziumSourceTsMsField,
+	debeziumSourceMVCCField,
+}
+
+// debeziumOp returns the operation of a change. Distinguishing updates from
+// inserts relies on the previous value of the row, which is why the envelope
+// requires the diff option.
+func debeziumOp(evCtx eventContext, updated, prev cdcevent.Row) string {
+	switch {
+	case updated.IsDeleted():
+		return debeziumOpDelete
+	case evCtx.snapshot:
+		return debeziumOpRead
+	case prev.IsInitialized() && prev.HasValues() && !prev.IsDeleted():
+		return debeziumOpUpdate
+	default:
+		return debeziumOpCreate
+	}
+}
+
+// debeziumSource is the content of the `source` block of a debezium envelope.
+type debeziumSource struct {
+	database, schema, table string
+	snapshot                bool
+	mvcc                    hlc.Timestamp
+}
+
+func makeDebeziumSource(evCtx eventContext, updated cdcevent.Row) debeziumSource {
+	return debeziumSource{
+		database: evCtx.database,
+		schema:   evCtx.schema,
+		table:    updated.TableName,
+		snapshot: evCtx.snapshot,
+		mvcc:     evCtx.mvcc,
+	}
+}
+
+// snapshotString returns the value of the `snapshot` field, which Debezium
+// encodes as a string.
+func (s debeziumSource) snapshotString() string {
+	return strconv.FormatBool(s.snapshot)
+}
+
+// debeziumTsMs converts a timestamp to the milliseconds since the epoch used by
+// the `ts_ms` fields of the envelope.
+func debeziumTsMs(ts hlc.Timestamp) int64 {
+	return ts.WallTime / 1e6
+}
+
+// debeziumSourceResolver resolves the names of the database and schema
+// containing the tables of a changefeed, which the debezium envelope includes
+// in its `source` block. Names are resolved at the schema timestamp of an
+// event and cached per table descriptor version.
+type debeziumSourceResolver struct {
+	leaseMgr *lease.Manager
+	names    *cache.UnorderedCache // [tableIDAndVersion]debeziumParentNames
+}
+
+type debeziumParentNames struct {
+	database, schema string
+}
+
+func newDebeziumSourceResolver(leaseMgr *lease.Manager) *debeziumSourceResolver {
+	return &debeziumSourceResolver{
+		leaseMgr: leaseMgr,
+		names:    cache.NewUnorderedCache(encoderCacheConfig),
+	}
+}
+
+// resolve returns the database and schema names of the table of the event.
+func (r *debeziumSourceResolver) resolve(
+	ctx context.Context, meta cdcevent.Metadata,
+) (database string, schema string, _ error) {
+	key := tableIDAndVersion{tableID: meta.TableID, version: meta.Version}
+	if v, ok := r.names.Get(key); ok {
+		names := v.(debeziumParentNames)
+		return names.database, names.schema, nil
+	}
+
+	desc, err := r.acquire(ctx, meta.SchemaTS, meta.TableID)
+	if err != nil {
+		return "", "", err
+	}
+	table, ok := desc.(catalog.TableDescriptor)
+	if !ok {
+		return "", "", errors.AssertionFailedf("descriptor %d is a %s, not a table",
+			meta.TableID, desc.DescriptorType())
+	}
+	db, err := r.acquire(ctx, meta.SchemaTS, table.GetParentID())
+	if err != nil {
+		return "", "", err
+	}
+	names := debeziumParentNames{database: db.GetName()}
+	// The public schema of the system database has no descriptor.
+	if id := table.GetParentSchemaID(); id == keys.PublicSchemaID {
+		names.schema = catconstants.PublicSchemaName
+	} else {
+		sc, err := r.acquire(ctx, meta.SchemaTS, id)
+		if err != nil {
+			return "", "", err
+		}
+		names.schema = sc.GetName()
+	}
+	r.names.Add(key, names)
+	return names.database, names.schema, nil
+}
+
+func (r *debeziumSourceResolver) acquire(
+	ctx context.Context, ts hlc.Timestamp, id descpb.ID,
+) (catalog.Descriptor, error) {
+	desc, err := r.leaseMgr.Acquire(ctx, ts, id)
+	if err != nil {
+		// As in the row fetcher, errors from the lease manager are never
+		// considered terminal.
+		return nil, changefeedbase.MarkRetryableError(err)
+	}
+	// We only need the descriptor at the exact timestamp requested.
+	defer desc.Release(ctx)
+	return desc.Underlying(), nil
+}
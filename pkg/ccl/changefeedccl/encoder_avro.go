@@ -256,10 +256,16 @@ func (e *confluentAvroEncoder) EncodeValue(
 		// it goes in the "record" field. In the "key_only" envelope it's omitted.
 		// This means metadata can safely go at the top level as there are never arbitrary column names
 		// for it to conflict with.
-		if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		switch e.envelopeType {
+		case changefeedbase.OptEnvelopeWrapped:
 			opts = avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
 			afterDataSchema = currentSchema
-		} else {
+		case changefeedbase.OptEnvelopeDebezium:
+			opts = avroEnvelopeOpts{
+				beforeField: e.beforeField, afterField: true, opField: true, sourceField: true, tsMsField: true,
+			}
+			afterDataSchema = currentSchema
+		default:
 			opts = avroEnvelopeOpts{recordField: true, updatedField: e.updatedField}
 			recordDataSchema = currentSchema
 		}
@@ -290,6 +296,13 @@ func (e *confluentAvroEncoder) EncodeValue(
 			`updated`: evCtx.updated,
 		}
 	}
+	if e.envelopeType == changefeedbase.OptEnvelopeDebezium {
+		meta = map[string]interface{}{
+			`op`:     debeziumOp(evCtx, updatedRow, prevRow),
+			`source`: makeDebeziumSource(evCtx, updatedRow),
+			`ts_ms`:  evCtx.updated,
+		}
+	}
 
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
 	header := []byte{
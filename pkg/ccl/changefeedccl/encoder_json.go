@@ -134,11 +134,16 @@ func makeJSONEncoder(ctx context.Context, opts jsonEncoderOptions) (*jsonEncoder
 		}
 	}
 
-	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+	switch e.envelopeType {
+	case changefeedbase.OptEnvelopeWrapped:
 		if err := e.initWrappedEnvelope(ctx); err != nil {
 			return nil, err
 		}
-	} else {
+	case changefeedbase.OptEnvelopeDebezium:
+		if err := e.initDebeziumEnvelope(ctx); err != nil {
+			return nil, err
+		}
+	default:
 		if err := e.initRawEnvelope(ctx); err != nil {
 			return nil, err
 		}
@@ -437,6 +442,85 @@ func (e *jsonEncoder) initWrappedEnvelope(ctx context.Context) error {
 	return nil
 }
 
+func (e *jsonEncoder) initDebeziumEnvelope(ctx context.Context) error {
+	b, err := json.NewFixedKeysObjectBuilder([]string{"before", "after", "op", "source", "ts_ms"})
+	if err != nil {
+		return err
+	}
+	sourceBuilder, err := json.NewFixedKeysObjectBuilder(debeziumSourceFields)
+	if err != nil {
+		return err
+	}
+
+	const emitDeletedRowAsNull = true
+	e.envelopeEncoder = func(evCtx eventContext, updated, prev cdcevent.Row) (json.JSON, error) {
+		var before json.JSON = json.NullJSONValue
+		if prev.IsInitialized() && !prev.IsDeleted() {
+			var err error
+			before, err = e.versionEncoder(prev.EventDescriptor, true).rowAsGoNative(ctx, prev, emitDeletedRowAsNull, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := b.Set("before", before); err != nil {
+			return nil, err
+		}
+
+		after, err := e.versionEncoder(updated.EventDescriptor, false).rowAsGoNative(ctx, updated, emitDeletedRowAsNull, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Set("after", after); err != nil {
+			return nil, err
+		}
+
+		if err := b.Set("op", json.FromString(debeziumOp(evCtx, updated, prev))); err != nil {
+			return nil, err
+		}
+
+		source, err := debeziumSourceAsJSON(sourceBuilder, makeDebeziumSource(evCtx, updated))
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Set("source", source); err != nil {
+			return nil, err
+		}
+
+		if err := b.Set("ts_ms", json.FromInt64(debeziumTsMs(evCtx.updated))); err != nil {
+			return nil, err
+		}
+
+		return b.Build()
+	}
+	return nil
+}
+
+// debeziumSourceAsJSON builds the `source` block of a debezium envelope.
+func debeziumSourceAsJSON(b *json.FixedKeysObjectBuilder, s debeziumSource) (json.JSON, error) {
+	if err := b.Set(debeziumSourceConnectorField, json.FromString(debeziumConnector)); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceDBField, json.FromString(s.database)); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceSchemaField, json.FromString(s.schema)); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceTableField, json.FromString(s.table)); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceSnapshotField, json.FromString(s.snapshotString())); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceTsMsField, json.FromInt64(debeziumTsMs(s.mvcc))); err != nil {
+		return nil, err
+	}
+	if err := b.Set(debeziumSourceMVCCField, json.FromString(s.mvcc.AsOfSystemTime())); err != nil {
+		return nil, err
+	}
+	return b.Build()
+}
+
 // EncodeValue implements the Encoder interface.
 func (e *jsonEncoder) EncodeValue(
 	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
@@ -445,7 +529,11 @@ func (e *jsonEncoder) EncodeValue(
 		return nil, nil
 	}
 
-	if updatedRow.IsDeleted() && !canJSONEncodeMetadata(e.envelopeType) {
+	// Deletes are only emitted by envelopes with room for metadata alongside
+	// the (empty) row, and by the debezium envelope, whose `op` field tells
+	// them apart.
+	if updatedRow.IsDeleted() && !canJSONEncodeMetadata(e.envelopeType) &&
+		e.envelopeType != changefeedbase.OptEnvelopeDebezium {
 		return nil, nil
 	}
 
@@ -466,7 +554,8 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
 	var jsonEntries interface{}
-	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped ||
+		e.envelopeType == changefeedbase.OptEnvelopeDebezium {
 		jsonEntries = meta
 	} else {
 		jsonEntries = map[string]interface{}{
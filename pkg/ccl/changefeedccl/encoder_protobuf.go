@@ -0,0 +1,428 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Names and numbers of the fields in the generated envelope messages. Field
+// numbers are fixed regardless of which fields are enabled so that consumers
+// can rely on them.
+const (
+	protobufFieldAfter         = `after`
+	protobufFieldBefore        = `before`
+	protobufFieldKey           = `key`
+	protobufFieldTopic         = `topic`
+	protobufFieldUpdated       = `updated`
+	protobufFieldMVCCTimestamp = `mvcc_timestamp`
+	protobufFieldResolved      = `resolved`
+
+	protobufFieldNumberAfter         = 1
+	protobufFieldNumberBefore        = 2
+	protobufFieldNumberKey           = 3
+	protobufFieldNumberTopic         = 4
+	protobufFieldNumberUpdated       = 5
+	protobufFieldNumberMVCCTimestamp = 6
+	protobufFieldNumberResolved      = 1
+)
+
+// confluentProtobufEncoder encodes changefeed entries as proto3 messages whose
+// descriptors are derived from the table schema. Keys are messages with one
+// field per primary key column. Values are either a message with one field per
+// column or, in the wrapped envelope, a message wrapping it together with the
+// requested metadata.
+//
+// Every message is prefixed with the confluent wire format header, which
+// carries the schema registry ID of the file describing it, followed by the
+// message indexes identifying the message within that file. The message is
+// always the first message declared in the registered file.
+type confluentProtobufEncoder struct {
+	schemaRegistry schemaRegistry
+	targets        changefeedbase.Targets
+	envelopeType   changefeedbase.EnvelopeType
+
+	updatedField, mvccTimestampField, beforeField bool
+	keyInValue, topicInValue                      bool
+	customKeyColumn                               string
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]*protobufSchema
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]*protobufSchema
+
+	// resolvedCache doesn't need to be bounded like the other caches because
+	// the number of topics is fixed per changefeed.
+	resolvedCache map[string]*protobufSchema
+
+	fmtCtx *tree.FmtCtx
+	buf    []byte
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{
+		targets:            targets,
+		envelopeType:       opts.Envelope,
+		updatedField:       opts.UpdatedTimestamps,
+		mvccTimestampField: opts.MVCCTimestamps,
+		beforeField:        opts.Diff,
+		keyInValue:         opts.KeyInValue,
+		topicInValue:       opts.TopicInValue,
+		customKeyColumn:    opts.CustomKeyColumn,
+		fmtCtx:             tree.NewFmtCtx(tree.FmtExport),
+	}
+
+	if len(opts.SchemaRegistryURI) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	reg, err := newConfluentSchemaRegistry(opts.SchemaRegistryURI, p, sliMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	e.schemaRegistry = reg
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]*protobufSchema)
+	return e, nil
+}
+
+// rawTableName returns the SQL-formatted name used to derive the subject and
+// message names of the given table.
+func (e *confluentProtobufEncoder) rawTableName(eventMeta cdcevent.Metadata) (string, error) {
+	target, found := e.targets.FindByTableIDAndFamilyName(eventMeta.TableID, eventMeta.FamilyName)
+	if !found {
+		return eventMeta.TableName, errors.Newf("Could not find Target for %s", eventMeta)
+	}
+	switch target.Type {
+	case jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY:
+		return string(target.StatementTimeName), nil
+	case jobspb.ChangefeedTargetSpecification_EACH_FAMILY:
+		return fmt.Sprintf("%s.%s", target.StatementTimeName, eventMeta.FamilyName), nil
+	case jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY:
+		return fmt.Sprintf("%s.%s", target.StatementTimeName, target.FamilyName), nil
+	default:
+		return "", errors.AssertionFailedf("Found a matching target with unimplemented type %s", target.Type)
+	}
+}
+
+// keyIterator returns the columns making up the key of the given row.
+func (e *confluentProtobufEncoder) keyIterator(row cdcevent.Row) (cdcevent.Iterator, error) {
+	if e.customKeyColumn == "" {
+		return row.ForEachKeyColumn(), nil
+	}
+	return row.DatumNamed(e.customKeyColumn)
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(
+	ctx context.Context, row cdcevent.Row,
+) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+
+	keys, err := e.keyIterator(row)
+	if err != nil {
+		return nil, err
+	}
+
+	var registered *protobufSchema
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		registered = v.(*protobufSchema)
+	} else {
+		tableName, err := e.rawTableName(row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		keyMsg, err := protobufMessageForRow(keys, SQLNameToAvroName(tableName)+`_key`)
+		if err != nil {
+			return nil, err
+		}
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		registered, err = e.register(ctx, subject, keyMsg)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+
+	msg := dynamicpb.NewMessage(registered.message)
+	if err := setProtobufFieldsFromRow(msg, keys, e.fmtCtx); err != nil {
+		return nil, err
+	}
+	return e.frame(registered, msg)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.envelopeType == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, nil
+	}
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped && updatedRow.IsDeleted() {
+		// Without an envelope there is nothing to say about a deleted row,
+		// so it is emitted as a tombstone.
+		return nil, nil
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && prevRow.IsInitialized() {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registered *protobufSchema
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		registered = v.(*protobufSchema)
+	} else {
+		var err error
+		registered, err = e.registerValueSchema(ctx, updatedRow, prevRow)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	msg := dynamicpb.NewMessage(registered.message)
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped {
+		if err := setProtobufFieldsFromRow(msg, updatedRow.ForEachColumn(), e.fmtCtx); err != nil {
+			return nil, err
+		}
+		return e.frame(registered, msg)
+	}
+
+	fields := registered.message.Fields()
+	if !updatedRow.IsDeleted() {
+		if err := e.setNestedRow(msg, fields.ByNumber(protobufFieldNumberAfter), updatedRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	if e.beforeField && prevRow.IsInitialized() && !prevRow.IsDeleted() {
+		if err := e.setNestedRow(msg, fields.ByNumber(protobufFieldNumberBefore), prevRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	if e.keyInValue {
+		keys, err := e.keyIterator(updatedRow)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.setNestedRow(msg, fields.ByNumber(protobufFieldNumberKey), keys); err != nil {
+			return nil, err
+		}
+	}
+	if e.topicInValue {
+		msg.Set(fields.ByNumber(protobufFieldNumberTopic), protoreflect.ValueOfString(evCtx.topic))
+	}
+	if e.updatedField {
+		msg.Set(fields.ByNumber(protobufFieldNumberUpdated),
+			protoreflect.ValueOfString(evCtx.updated.AsOfSystemTime()))
+	}
+	if e.mvccTimestampField {
+		msg.Set(fields.ByNumber(protobufFieldNumberMVCCTimestamp),
+			protoreflect.ValueOfString(evCtx.mvcc.AsOfSystemTime()))
+	}
+	return e.frame(registered, msg)
+}
+
+// registerValueSchema generates and registers the value schema for rows of
+// the given table version.
+func (e *confluentProtobufEncoder) registerValueSchema(
+	ctx context.Context, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) (*protobufSchema, error) {
+	tableName, err := e.rawTableName(updatedRow.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	// NB: This uses the kafka name escaper because it has to match the name
+	// of the kafka topic.
+	subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixValue
+
+	rowName := SQLNameToAvroName(tableName)
+	rowMsg, err := protobufMessageForRow(updatedRow.ForEachColumn(), rowName)
+	if err != nil {
+		return nil, err
+	}
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped {
+		return e.register(ctx, subject, rowMsg)
+	}
+
+	envelope := &descriptorpb.DescriptorProto{Name: protobufString(rowName + `_envelope`)}
+	// The envelope must be the first message of the file.
+	msgs := []*descriptorpb.DescriptorProto{envelope, rowMsg}
+	envelope.Field = append(envelope.Field,
+		protobufMessageField(protobufFieldAfter, protobufFieldNumberAfter, rowName))
+	if e.beforeField {
+		beforeName := rowName
+		if prevRow.IsInitialized() {
+			beforeName = rowName + `_before`
+			beforeMsg, err := protobufMessageForRow(prevRow.ForEachColumn(), beforeName)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, beforeMsg)
+		}
+		envelope.Field = append(envelope.Field,
+			protobufMessageField(protobufFieldBefore, protobufFieldNumberBefore, beforeName))
+	}
+	if e.keyInValue {
+		keys, err := e.keyIterator(updatedRow)
+		if err != nil {
+			return nil, err
+		}
+		keyName := rowName + `_key`
+		keyMsg, err := protobufMessageForRow(keys, keyName)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, keyMsg)
+		envelope.Field = append(envelope.Field,
+			protobufMessageField(protobufFieldKey, protobufFieldNumberKey, keyName))
+	}
+	if e.topicInValue {
+		envelope.Field = append(envelope.Field,
+			protobufStringField(protobufFieldTopic, protobufFieldNumberTopic))
+	}
+	if e.updatedField {
+		envelope.Field = append(envelope.Field,
+			protobufStringField(protobufFieldUpdated, protobufFieldNumberUpdated))
+	}
+	if e.mvccTimestampField {
+		envelope.Field = append(envelope.Field,
+			protobufStringField(protobufFieldMVCCTimestamp, protobufFieldNumberMVCCTimestamp))
+	}
+	return e.register(ctx, subject, msgs...)
+}
+
+// setNestedRow populates the message typed field fd of msg from the iterator.
+func (e *confluentProtobufEncoder) setNestedRow(
+	msg *dynamicpb.Message, fd protoreflect.FieldDescriptor, it cdcevent.Iterator,
+) error {
+	nested := dynamicpb.NewMessage(fd.Message())
+	if err := setProtobufFieldsFromRow(nested, it, e.fmtCtx); err != nil {
+		return err
+	}
+	msg.Set(fd, protoreflect.ValueOfMessage(nested))
+	return nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		resolvedMsg := &descriptorpb.DescriptorProto{
+			Name: protobufString(SQLNameToAvroName(topic) + `_resolved`),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				protobufStringField(protobufFieldResolved, protobufFieldNumberResolved),
+			},
+		}
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		registered, err = e.register(ctx, subject, resolvedMsg)
+		if err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = registered
+	}
+	msg := dynamicpb.NewMessage(registered.message)
+	msg.Set(registered.message.Fields().ByNumber(protobufFieldNumberResolved),
+		protoreflect.ValueOfString(eval.TimestampToDecimalDatum(resolved).Decimal.String()))
+	return e.frame(registered, msg)
+}
+
+// register generates a schema declaring the given messages and registers it
+// for the subject. A new table version yields a new descriptor and thus a new
+// version of the subject.
+func (e *confluentProtobufEncoder) register(
+	ctx context.Context, subject string, msgs ...*descriptorpb.DescriptorProto,
+) (*protobufSchema, error) {
+	schema, err := newProtobufSchema(subject, msgs...)
+	if err != nil {
+		return nil, err
+	}
+	schema.registryID, err = e.schemaRegistry.RegisterSchemaForSubjectWithType(
+		ctx, subject, schemaTypeProtobuf, schema.registrySchema())
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// frame marshals the message and prepends the wire format header to it. The
+// returned bytes are only valid until the next call to frame.
+func (e *confluentProtobufEncoder) frame(
+	schema *protobufSchema, msg *dynamicpb.Message,
+) ([]byte, error) {
+	encoded, err := protoutil.TODOMarshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+	e.buf = append(e.buf[:0],
+		changefeedbase.ConfluentProtobufWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+	)
+	binary.BigEndian.PutUint32(e.buf[1:5], uint32(schema.registryID))
+	e.buf = appendConfluentProtobufMessageIndexes(e.buf, protobufTopLevelMessageIndexes)
+	e.buf = append(e.buf, encoded...)
+	return e.buf, nil
+}
+
+// protobufTopLevelMessageIndexes are the message indexes of the first message
+// declared in a file.
+var protobufTopLevelMessageIndexes = []int{0}
+
+// appendConfluentProtobufMessageIndexes appends the message indexes of the
+// confluent protobuf wire format, which locate a message within the registered
+// file: the number of indexes followed by the index of the message at each
+// level of nesting, all encoded as zigzag varints. The indexes of the first
+// top-level message are encoded as a single 0 instead.
+func appendConfluentProtobufMessageIndexes(buf []byte, indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return binary.AppendVarint(buf, 0)
+	}
+	buf = binary.AppendVarint(buf, int64(len(indexes)))
+	for _, idx := range indexes {
+		buf = binary.AppendVarint(buf, int64(idx))
+	}
+	return buf
+}
@@ -12,19 +12,26 @@ import (
 	"context"
 	gosql "database/sql"
 	"encoding/base64"
+	"encoding/binary"
+	gojson "encoding/json"
 	"fmt"
 	"math/rand"
 	"net/url"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/apd/v3"
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -36,10 +43,18 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func TestEncoders(t *testing.T) {
@@ -1315,3 +1330,467 @@ func TestAvroWithRegionalTable(t *testing.T) {
 		})
 	}
 }
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c FLOAT, d DECIMAL)`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: &tree.DDecimal{Decimal: *apd.New(125, -2)}},
+	}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+
+	for _, tc := range []struct {
+		opts     changefeedbase.EncodingOptions
+		insert   string
+		delete   string
+		resolved string
+	}{
+		{
+			opts:     changefeedbase.EncodingOptions{Envelope: changefeedbase.OptEnvelopeKeyOnly},
+			insert:   `{"a":"1"}->`,
+			delete:   `{"a":"1"}->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts:     changefeedbase.EncodingOptions{Envelope: changefeedbase.OptEnvelopeBare},
+			insert:   `{"a":"1"}->{"a":"1","b":"bar","d":"1.25"}`,
+			delete:   `{"a":"1"}->`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts:     changefeedbase.EncodingOptions{Envelope: changefeedbase.OptEnvelopeWrapped},
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar","d":"1.25"}}`,
+			delete:   `{"a":"1"}->{}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts: changefeedbase.EncodingOptions{
+				Envelope: changefeedbase.OptEnvelopeWrapped, Diff: true, UpdatedTimestamps: true,
+			},
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar","d":"1.25"},"updated":"1.0000000002"}`,
+			delete:   `{"a":"1"}->{"before":{"a":"1","b":"bar","d":"1.25"},"updated":"1.0000000002"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+		{
+			opts: changefeedbase.EncodingOptions{
+				Envelope: changefeedbase.OptEnvelopeWrapped, KeyInValue: true, TopicInValue: true,
+			},
+			insert:   `{"a":"1"}->{"after":{"a":"1","b":"bar","d":"1.25"},"key":{"a":"1"},"topic":"foo"}`,
+			delete:   `{"a":"1"}->{"key":{"a":"1"},"topic":"foo"}`,
+			resolved: `{"resolved":"1.0000000002"}`,
+		},
+	} {
+		name := fmt.Sprintf("envelope=%s,diff=%t,updated=%t,key_in_value=%t",
+			tc.opts.Envelope, tc.opts.Diff, tc.opts.UpdatedTimestamps, tc.opts.KeyInValue)
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			reg := cdctest.StartTestSchemaRegistry()
+			defer reg.Close()
+
+			o := tc.opts
+			o.Format = changefeedbase.OptFormatProtobuf
+			o.SchemaRegistryURI = reg.URL()
+			require.NoError(t, o.Validate())
+			e, err := getEncoder(ctx, o, targets, false, nil, nil)
+			require.NoError(t, err)
+
+			toJSON := func(b []byte) string {
+				j, err := reg.ProtobufToJSON(b)
+				require.NoError(t, err)
+				return string(j)
+			}
+			evCtx := eventContext{updated: ts, topic: `foo`}
+
+			rowInsert := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+			prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false)
+			keyInsert, err := e.EncodeKey(ctx, rowInsert)
+			require.NoError(t, err)
+			keyInsert = append([]byte(nil), keyInsert...)
+			valueInsert, err := e.EncodeValue(ctx, evCtx, rowInsert, prevRow)
+			require.NoError(t, err)
+			require.Equal(t, tc.insert, toJSON(keyInsert)+`->`+toJSON(valueInsert))
+
+			rowDelete := cdcevent.TestingMakeEventRow(tableDesc, 0, row, true)
+			prevRow = cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+			keyDelete, err := e.EncodeKey(ctx, rowDelete)
+			require.NoError(t, err)
+			keyDelete = append([]byte(nil), keyDelete...)
+			valueDelete, err := e.EncodeValue(ctx, evCtx, rowDelete, prevRow)
+			require.NoError(t, err)
+			require.Equal(t, tc.delete, toJSON(keyDelete)+`->`+toJSON(valueDelete))
+
+			resolved, err := e.EncodeResolvedTimestamp(ctx, tableDesc.GetName(), ts)
+			require.NoError(t, err)
+			require.Equal(t, tc.resolved, toJSON(resolved))
+
+			for _, subject := range reg.Subjects() {
+				require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(subject))
+			}
+		})
+	}
+}
+
+// TestProtobufEncoderSchemaChange verifies that a new table version results in
+// a new descriptor being registered and that field numbers of existing columns
+// are preserved.
+func TestProtobufEncoderSchemaChange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	v1, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	v2Mut, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+	require.NoError(t, err)
+	v2Desc := v2Mut.(*tabledesc.Mutable).TableDescriptor
+	v2Desc.Version = v1.GetVersion() + 1
+	v2 := tabledesc.NewBuilder(&v2Desc).BuildImmutableTable()
+
+	reg := cdctest.StartTestSchemaRegistry()
+	defer reg.Close()
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           v1.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(v1.GetName()),
+	})
+	opts := changefeedbase.EncodingOptions{
+		Format:            changefeedbase.OptFormatProtobuf,
+		Envelope:          changefeedbase.OptEnvelopeWrapped,
+		SchemaRegistryURI: reg.URL(),
+	}
+	e, err := getEncoder(ctx, opts, targets, false, nil, nil)
+	require.NoError(t, err)
+
+	encode := func(desc catalog.TableDescriptor, datums rowenc.EncDatumRow) []byte {
+		row := cdcevent.TestingMakeEventRow(desc, 0, datums, false)
+		value, err := e.EncodeValue(ctx, eventContext{}, row, cdcevent.Row{})
+		require.NoError(t, err)
+		return append([]byte(nil), value...)
+	}
+	value1 := encode(v1, rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	})
+	require.Equal(t, 1, reg.RegistrationCount())
+	value2 := encode(v2, rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(2)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+		rowenc.EncDatum{Datum: tree.NewDInt(3)},
+	})
+	require.Equal(t, 2, reg.RegistrationCount())
+	require.Equal(t, []string{`foo-value`}, reg.Subjects())
+
+	j, err := reg.ProtobufToJSON(value2)
+	require.NoError(t, err)
+	require.Equal(t, `{"after":{"a":"2","b":"baz","c":"3"}}`, string(j))
+
+	fieldNumber := func(value []byte, column string) protoreflect.FieldNumber {
+		msg, err := reg.EncodedProtobufToNative(value)
+		require.NoError(t, err)
+		after := msg.Descriptor().Fields().ByName(`after`).Message()
+		return after.Fields().ByName(protoreflect.Name(column)).Number()
+	}
+	require.Equal(t, fieldNumber(value1, `a`), fieldNumber(value2, `a`))
+	require.Equal(t, fieldNumber(value1, `b`), fieldNumber(value2, `b`))
+}
+
+// TestProtobufEncoderWireFormat checks the encoded bytes against the confluent
+// protobuf wire format: a zero magic byte, the big-endian schema ID, the
+// message indexes of the top-level message (a single zero byte), and the
+// serialized message. The registered schema must be a base64 encoded
+// FileDescriptorProto.
+func TestProtobufEncoderWireFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	desc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+
+	reg := cdctest.StartTestSchemaRegistry()
+	defer reg.Close()
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           desc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(desc.GetName()),
+	})
+	opts := changefeedbase.EncodingOptions{
+		Format:            changefeedbase.OptFormatProtobuf,
+		Envelope:          changefeedbase.OptEnvelopeBare,
+		SchemaRegistryURI: reg.URL(),
+	}
+	e, err := getEncoder(ctx, opts, targets, false, nil, nil)
+	require.NoError(t, err)
+
+	// Register a throwaway schema first so that the schema ID is not zero.
+	_, err = e.EncodeKey(ctx, cdcevent.TestingMakeEventRow(desc, 0, rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}, false))
+	require.NoError(t, err)
+	row := cdcevent.TestingMakeEventRow(desc, 0, rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}, false)
+	value, err := e.EncodeValue(ctx, eventContext{}, row, cdcevent.Row{})
+	require.NoError(t, err)
+
+	require.Greater(t, len(value), 6)
+	require.Equal(t, byte(0), value[0], `magic byte`)
+	require.Equal(t, uint32(1), binary.BigEndian.Uint32(value[1:5]), `schema ID`)
+	require.Equal(t, byte(0), value[5], `message indexes`)
+
+	encodedFile, err := base64.StdEncoding.DecodeString(reg.SchemaForSubject(`foo-value`))
+	require.NoError(t, err)
+	var fdp descriptorpb.FileDescriptorProto
+	require.NoError(t, protoutil.TODOUnmarshal(encodedFile, &fdp))
+	fd, err := protodesc.NewFile(&fdp, new(protoregistry.Files))
+	require.NoError(t, err)
+	msg := dynamicpb.NewMessage(fd.Messages().Get(0))
+	require.NoError(t, protoutil.TODOUnmarshal(value[6:], msg))
+	j, err := protojson.Marshal(msg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":"1","b":"bar"}`, string(j))
+
+	// Nested messages are located by a count followed by their indexes, all
+	// zigzag varints.
+	require.Equal(t, []byte{0}, appendConfluentProtobufMessageIndexes(nil, []int{0}))
+	require.Equal(t, []byte{2, 2}, appendConfluentProtobufMessageIndexes(nil, []int{1}))
+	require.Equal(t, []byte{4, 2, 0}, appendConfluentProtobufMessageIndexes(nil, []int{1, 0}))
+}
+
+func TestProtobufEncoderKafka(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar'), (2, NULL)`)
+
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo WITH format=%s, diff`,
+			changefeedbase.OptFormatProtobuf))
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: {"a":"1"}->{"after":{"a":"1","b":"bar"}}`,
+			`foo: {"a":"2"}->{"after":{"a":"2"}}`,
+		})
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'baz' WHERE a = 1`)
+		assertPayloads(t, foo, []string{
+			`foo: {"a":"1"}->{"after":{"a":"1","b":"baz"},"before":{"a":"1","b":"bar"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	oldRow := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}
+	newRow := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+	}
+	mvcc := hlc.Timestamp{WallTime: 1700000000000000000}
+	updated := hlc.Timestamp{WallTime: 1700000000001000000}
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+
+	type change struct {
+		name     string
+		snapshot bool
+		updated  rowenc.EncDatumRow
+		deleted  bool
+		prev     rowenc.EncDatumRow
+	}
+	changes := []change{
+		{name: `insert`, updated: newRow},
+		{name: `update`, updated: newRow, prev: oldRow},
+		{name: `delete`, updated: oldRow, deleted: true, prev: oldRow},
+		{name: `scan`, snapshot: true, updated: oldRow},
+	}
+
+	jsonSource := func(snapshot bool) string {
+		return fmt.Sprintf(`"source": {"connector": "cockroachdb", "db": "d", `+
+			`"mvcc_timestamp": "1700000000000000000.0000000000", "schema": "public", `+
+			`"snapshot": "%t", "table": "foo", "ts_ms": 1700000000000}, "ts_ms": 1700000000001}`, snapshot)
+	}
+	avroSource := func(snapshot bool) string {
+		return fmt.Sprintf(`"source":{"foo_source":{"connector":{"string":"cockroachdb"},"db":{"string":"d"},`+
+			`"mvcc_timestamp":{"string":"1700000000000000000.0000000000"},"schema":{"string":"public"},`+
+			`"snapshot":{"string":"%t"},"table":{"string":"foo"},"ts_ms":{"long":1700000000000}}},`+
+			`"ts_ms":{"long":1700000000001}}`, snapshot)
+	}
+
+	for _, tc := range []struct {
+		format   changefeedbase.FormatType
+		expected map[string]string
+		resolved string
+	}{
+		{
+			format: changefeedbase.OptFormatJSON,
+			expected: map[string]string{
+				`insert`: `[1]->{"after": {"a": 1, "b": "baz"}, "before": null, "op": "c", ` + jsonSource(false),
+				`update`: `[1]->{"after": {"a": 1, "b": "baz"}, "before": {"a": 1, "b": "bar"}, "op": "u", ` + jsonSource(false),
+				`delete`: `[1]->{"after": null, "before": {"a": 1, "b": "bar"}, "op": "d", ` + jsonSource(false),
+				`scan`:   `[1]->{"after": {"a": 1, "b": "bar"}, "before": null, "op": "r", ` + jsonSource(true),
+			},
+			resolved: `{"resolved":"1700000000001000000.0000000000"}`,
+		},
+		{
+			format: changefeedbase.OptFormatAvro,
+			expected: map[string]string{
+				`insert`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"baz"}}},"before":null,` +
+					`"op":{"string":"c"},` + avroSource(false),
+				`update`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"baz"}}},` +
+					`"before":{"foo_before":{"a":{"long":1},"b":{"string":"bar"}}},"op":{"string":"u"},` + avroSource(false),
+				`delete`: `{"a":{"long":1}}->{"after":null,` +
+					`"before":{"foo_before":{"a":{"long":1},"b":{"string":"bar"}}},"op":{"string":"d"},` + avroSource(false),
+				`scan`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},"before":null,` +
+					`"op":{"string":"r"},` + avroSource(true),
+			},
+			resolved: `{"resolved":{"string":"1700000000001000000.0000000000"}}`,
+		},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			ctx := context.Background()
+			o := changefeedbase.EncodingOptions{
+				Format: tc.format, Envelope: changefeedbase.OptEnvelopeDebezium, Diff: true,
+			}
+			rowStringFn := func(k, v []byte) string { return fmt.Sprintf(`%s->%s`, k, v) }
+			resolvedStringFn := func(r []byte) string { return string(r) }
+			if tc.format == changefeedbase.OptFormatAvro {
+				reg := cdctest.StartTestSchemaRegistry()
+				defer reg.Close()
+				o.SchemaRegistryURI = reg.URL()
+				rowStringFn = func(k, v []byte) string {
+					return fmt.Sprintf(`%s->%s`, avroToJSON(t, reg, k), avroToJSON(t, reg, v))
+				}
+				resolvedStringFn = func(r []byte) string { return string(avroToJSON(t, reg, r)) }
+			}
+			require.NoError(t, o.Validate())
+			e, err := getEncoder(ctx, o, targets, false, nil, nil)
+			require.NoError(t, err)
+
+			for _, c := range changes {
+				updatedRow := cdcevent.TestingMakeEventRow(tableDesc, 0, c.updated, c.deleted)
+				prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, c.prev, false)
+				evCtx := eventContext{
+					updated:  updated,
+					mvcc:     mvcc,
+					snapshot: c.snapshot,
+					database: `d`,
+					schema:   `public`,
+				}
+				key, err := e.EncodeKey(ctx, updatedRow)
+				require.NoError(t, err)
+				key = append([]byte(nil), key...)
+				value, err := e.EncodeValue(ctx, evCtx, updatedRow, prevRow)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected[c.name], rowStringFn(key, value), c.name)
+			}
+
+			resolved, err := e.EncodeResolvedTimestamp(ctx, tableDesc.GetName(), updated)
+			require.NoError(t, err)
+			require.Equal(t, tc.resolved, resolvedStringFn(resolved))
+		})
+	}
+}
+
+// TestDebeziumEnvelopeChangefeed verifies the op and source of the debezium
+// envelope end to end. Timestamps are stripped from the payloads.
+func TestDebeziumEnvelopeChangefeed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	assertDebeziumPayloads := func(t *testing.T, f cdctest.TestFeed, expected []string) {
+		t.Helper()
+		require.NoError(t, withTimeout(f, assertPayloadsTimeout(), func(ctx context.Context) error {
+			msgs, err := readNextMessages(ctx, f, len(expected))
+			if err != nil {
+				return err
+			}
+			var actual []string
+			for _, m := range msgs {
+				var value map[string]interface{}
+				if err := gojson.Unmarshal(m.Value, &value); err != nil {
+					return err
+				}
+				delete(value, `ts_ms`)
+				if source, ok := value[`source`].(map[string]interface{}); ok {
+					delete(source, `ts_ms`)
+					delete(source, `mvcc_timestamp`)
+				}
+				formatted, err := reformatJSON(value)
+				if err != nil {
+					return err
+				}
+				actual = append(actual, fmt.Sprintf(`%s: %s->%s`, m.Topic, m.Key, formatted))
+			}
+			sort.Strings(expected)
+			sort.Strings(actual)
+			if !reflect.DeepEqual(expected, actual) {
+				return errors.Newf("expected\n  %s\ngot\n  %s",
+					strings.Join(expected, "\n  "), strings.Join(actual, "\n  "))
+			}
+			return nil
+		}))
+	}
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope=debezium, diff`)
+		defer closeFeed(t, foo)
+
+		const source = `"source": {"connector": "cockroachdb", "db": "d", "schema": "public", "snapshot": "%t", "table": "foo"}`
+		assertDebeziumPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "bar"}, "before": null, "op": "r", ` +
+				fmt.Sprintf(source, true) + `}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'baz')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'qux' WHERE a = 1`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertDebeziumPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "baz"}, "before": null, "op": "c", ` +
+				fmt.Sprintf(source, false) + `}`,
+			`foo: [1]->{"after": {"a": 1, "b": "qux"}, "before": {"a": 1, "b": "bar"}, "op": "u", ` +
+				fmt.Sprintf(source, false) + `}`,
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "baz"}, "op": "d", ` +
+				fmt.Sprintf(source, false) + `}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
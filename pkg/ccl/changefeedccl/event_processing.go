@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -41,6 +42,12 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// snapshot is set if the event was produced by a table scan (an initial
+	// scan or a schema change backfill) rather than by the rangefeed.
+	snapshot bool
+	// database and schema are the names of the database and schema of the
+	// table of the event. They are only resolved for envelope=debezium.
+	database, schema string
 }
 
 type eventConsumer interface {
@@ -62,9 +69,18 @@ type kvEventToRowConsumer struct {
 	details      ChangefeedConfig
 	evaluator    *cdceval.Evaluator
 	encodingOpts changefeedbase.EncodingOptions
+	// rescanFilter is set when the changefeed rescans the rows of a table
+	// which match a predicate. It filters the rows of that table emitted by
+	// the rescan.
+	rescanFilter *rescanFilter
 
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
+	// sourceResolver is set when the debezium envelope is used.
+	sourceResolver *debeziumSourceResolver
+	// deadLetters is set when the changefeed has a dead letter queue. Rows
+	// which permanently fail to be encoded are recorded to it.
+	deadLetters deadLetterQueue
 
 	metrics *sliMetrics
 	sv      *settings.Values
@@ -89,6 +105,7 @@ func newEventConsumer(
 	spanFrontier frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	deadLetters deadLetterQueue,
 	metrics *Metrics,
 	sliMetrics *sliMetrics,
 	knobs TestingKnobs,
@@ -138,7 +155,7 @@ func newEventConsumer(
 		}
 
 		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
-		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s,
+		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s, deadLetters,
 			encoder, feed, spec, knobs, topicNamer, sliMetrics, pacer)
 	}
 
@@ -213,6 +230,7 @@ func newKVEventToRowConsumer(
 	frontier frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	deadLetters deadLetterQueue,
 	encoder Encoder,
 	details ChangefeedConfig,
 	spec execinfrapb.ChangeAggregatorSpec,
@@ -236,11 +254,24 @@ func newKVEventToRowConsumer(
 		}
 	}
 
+	var rf *rescanFilter
+	if f := spec.Feed.RescanFilter; f != nil {
+		rf, err = newRescanFilter(ctx, cfg, spec, *f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	encodingOpts, err := details.Opts.GetEncodingOptions()
 	if err != nil {
 		return nil, err
 	}
 
+	var sourceResolver *debeziumSourceResolver
+	if encodingOpts.Envelope == changefeedbase.OptEnvelopeDebezium {
+		sourceResolver = newDebeziumSourceResolver(cfg.LeaseManager)
+	}
+
 	return &kvEventToRowConsumer{
 		frontier:             frontier,
 		encoder:              encoder,
@@ -251,7 +282,10 @@ func newKVEventToRowConsumer(
 		knobs:                knobs,
 		topicDescriptorCache: make(map[TopicIdentifier]TopicDescriptor),
 		topicNamer:           topicNamer,
+		sourceResolver:       sourceResolver,
+		deadLetters:          deadLetters,
 		evaluator:            evaluator,
+		rescanFilter:         rf,
 		encodingOpts:         encodingOpts,
 		metrics:              metrics,
 		pacer:                pacer,
@@ -294,6 +328,46 @@ func newEvaluator(
 	return cdceval.NewEvaluator(sc, cfg, spec.User(), sd, spec.Feed.StatementTime, withDiff), nil
 }
 
+// rescanFilter filters the rows emitted by a rescan of a table requested with
+// ALTER CHANGEFEED ... RESCAN TABLE ... WHERE.
+type rescanFilter struct {
+	filter    jobspb.ChangefeedRescanFilter
+	evaluator *cdceval.Evaluator
+}
+
+func newRescanFilter(
+	ctx context.Context,
+	cfg *sql.ExecutorConfig,
+	spec execinfrapb.ChangeAggregatorSpec,
+	filter jobspb.ChangefeedRescanFilter,
+) (*rescanFilter, error) {
+	spec.Select = execinfrapb.Expression{Expr: filter.Select}
+	evaluator, err := newEvaluator(ctx, cfg, spec, false /* withDiff */)
+	if err != nil {
+		return nil, err
+	}
+	return &rescanFilter{filter: filter, evaluator: evaluator}, nil
+}
+
+// matches returns false if the row was produced by the rescan but does not
+// match the predicate of the rescan.
+func (f *rescanFilter) matches(
+	ctx context.Context, row cdcevent.Row, backfillTs hlc.Timestamp,
+) (bool, error) {
+	if row.TableID != f.filter.TableID || !backfillTs.Equal(f.filter.Timestamp) {
+		return true, nil
+	}
+	projection, err := f.evaluator.Eval(ctx, row, cdcevent.Row{})
+	if err != nil {
+		return false, err
+	}
+	return projection.IsInitialized(), nil
+}
+
+func (f *rescanFilter) close() {
+	f.evaluator.Close()
+}
+
 func (c *kvEventToRowConsumer) topicForEvent(eventMeta cdcevent.Metadata) (TopicDescriptor, error) {
 	if topic, ok := c.topicDescriptorCache[TopicIdentifier{TableID: eventMeta.TableID, FamilyID: eventMeta.FamilyID}]; ok {
 		if topic.GetVersion() == eventMeta.Version {
@@ -330,7 +404,8 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	prevSchemaTimestamp := schemaTimestamp
 	keyOnly := c.details.Opts.KeyOnly()
 
-	if backfillTs := ev.BackfillTimestamp(); !backfillTs.IsEmpty() {
+	backfillTs := ev.BackfillTimestamp()
+	if !backfillTs.IsEmpty() {
 		schemaTimestamp = backfillTs
 		prevSchemaTimestamp = schemaTimestamp.Prev()
 	}
@@ -361,6 +436,19 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return err
 	}
 
+	if c.rescanFilter != nil {
+		matches, err := c.rescanFilter.matches(ctx, updatedRow, backfillTs)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			c.metrics.FilteredMessages.Inc(1)
+			a := ev.DetachAlloc()
+			a.Release(ctx)
+			return nil
+		}
+	}
+
 	if c.evaluator != nil {
 		updatedRow, err = c.evaluator.Eval(ctx, updatedRow, prevRow)
 		if err != nil {
@@ -376,7 +464,8 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		}
 	}
 
-	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, ev.DetachAlloc())
+	snapshot := !backfillTs.IsEmpty()
+	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, snapshot, ev.DetachAlloc())
 }
 
 func (c *kvEventToRowConsumer) encodeAndEmit(
@@ -384,6 +473,7 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	updatedRow cdcevent.Row,
 	prevRow cdcevent.Row,
 	schemaTS hlc.Timestamp,
+	snapshot bool,
 	alloc kvevent.Alloc,
 ) error {
 	topic, err := c.topicForEvent(updatedRow.Metadata)
@@ -403,8 +493,16 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	}
 
 	evCtx := eventContext{
-		updated: schemaTS,
-		mvcc:    updatedRow.MvccTimestamp,
+		updated:  schemaTS,
+		mvcc:     updatedRow.MvccTimestamp,
+		snapshot: snapshot,
+	}
+
+	if c.sourceResolver != nil {
+		evCtx.database, evCtx.schema, err = c.sourceResolver.resolve(ctx, updatedRow.Metadata)
+		if err != nil {
+			return err
+		}
 	}
 
 	if c.topicNamer != nil {
@@ -430,14 +528,14 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
-		return err
+		return c.handleEncodingError(ctx, topic, updatedRow, schemaTS, alloc, err)
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
 	// might not be available at all when working with changefeed expressions.
 	encodedValue, err := c.encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
 	if err != nil {
-		return err
+		return c.handleEncodingError(ctx, topic, updatedRow, schemaTS, alloc, err)
 	}
 	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 
@@ -456,12 +554,42 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	return nil
 }
 
+// handleEncodingError records the row to the dead letter queue if the error
+// encoding it is permanent, in which case the row is not emitted and the
+// changefeed carries on. Otherwise, it returns the error.
+func (c *kvEventToRowConsumer) handleEncodingError(
+	ctx context.Context,
+	topic TopicDescriptor,
+	row cdcevent.Row,
+	schemaTS hlc.Timestamp,
+	alloc kvevent.Alloc,
+	err error,
+) error {
+	if c.deadLetters == nil || !changefeedbase.IsPermanentRowError(err) {
+		return err
+	}
+	if err := c.deadLetters.Log(ctx, deadLetter{
+		topic:   topic,
+		updated: schemaTS,
+		mvcc:    row.MvccTimestamp,
+		row:     row,
+		reason:  err,
+	}); err != nil {
+		return err
+	}
+	alloc.Release(ctx)
+	return nil
+}
+
 // Close closes this consumer.
 func (c *kvEventToRowConsumer) Close() error {
 	c.pacer.Close()
 	if c.evaluator != nil {
 		c.evaluator.Close()
 	}
+	if c.rescanFilter != nil {
+		c.rescanFilter.close()
+	}
 	return nil
 }
 
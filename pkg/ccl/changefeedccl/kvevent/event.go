@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -86,6 +87,10 @@ const (
 	// on the Event meaningful.
 	TypeKV
 
+	// TypeSchemaChange indicates that the SchemaChange method on the Event will
+	// be meaningful.
+	TypeSchemaChange
+
 	// Private fields indicating the type of the resolved event.
 	resolvedNone
 	resolvedBackfill
@@ -100,11 +105,12 @@ const (
 	numEventTypes = TypeResolved + 1
 )
 
-// Event represents an event emitted by a kvfeed. It is either a KV or a
-// resolved timestamp.
+// Event represents an event emitted by a kvfeed. It is either a KV, a
+// resolved timestamp or a schema change.
 type Event struct {
 	ev                 *kvpb.RangeFeedEvent
 	et                 Type
+	schemaChange       *SchemaChange
 	backfillTimestamp  hlc.Timestamp
 	bufferAddTimestamp time.Time
 	alloc              Alloc
@@ -127,6 +133,8 @@ func (t Type) Index() int {
 		return int(TypeFlush)
 	case TypeKV:
 		return int(TypeKV)
+	case TypeSchemaChange:
+		return int(TypeSchemaChange)
 	case TypeResolved, resolvedBackfill, resolvedRestart, resolvedExit:
 		return int(TypeResolved)
 	default:
@@ -146,6 +154,9 @@ func (e *Event) ApproximateSize() int {
 	if e.et == TypeFlush {
 		return 0
 	}
+	if e.et == TypeSchemaChange {
+		return int(unsafe.Sizeof(Event{}) + unsafe.Sizeof(SchemaChange{}))
+	}
 	return e.ev.Size() + int(unsafe.Sizeof(Event{}))
 }
 
@@ -200,6 +211,11 @@ func (e *Event) BackfillTimestamp() hlc.Timestamp {
 	return e.backfillTimestamp
 }
 
+// SchemaChange is populated if this event returns TypeSchemaChange for Type().
+func (e *Event) SchemaChange() SchemaChange {
+	return *e.schemaChange
+}
+
 // BufferAddTimestamp is the time this event came into  the buffer.
 func (e *Event) BufferAddTimestamp() time.Time {
 	return e.bufferAddTimestamp
@@ -229,6 +245,8 @@ func (e *Event) getTimestamp(backfillTS hlc.Timestamp) hlc.Timestamp {
 			return backfillTS
 		}
 		return e.ev.Val.Value.Timestamp
+	case TypeSchemaChange:
+		return e.schemaChange.After.GetModificationTime()
 	case TypeFlush:
 		return hlc.Timestamp{}
 	default:
@@ -253,6 +271,8 @@ func (e *Event) String() string {
 	case e.et == TypeKV:
 		kv := e.KV()
 		return fmt.Sprintf("%s@%s", roachpb.PrettyPrintKey(nil, kv.Key), kv.Value.Timestamp)
+	case e.et == TypeSchemaChange:
+		return fmt.Sprintf("schema change %d@%s", e.schemaChange.After.GetID(), e.Timestamp())
 	default:
 		r := e.Resolved()
 		return fmt.Sprintf("resolved %s@%s (bt=%s)", r.Span, r.Timestamp, r.BoundaryType)
@@ -333,3 +353,17 @@ func NewBackfillKVEvent(
 		backfillTimestamp: backfillTS,
 	}
 }
+
+// SchemaChange describes a change to the descriptor of a watched table.
+type SchemaChange struct {
+	Before, After catalog.TableDescriptor
+}
+
+// NewSchemaChangeEvent returns a new event describing a change to the schema
+// of a watched table, which takes effect at the modification time of after.
+func NewSchemaChangeEvent(before, after catalog.TableDescriptor) Event {
+	return Event{
+		et:           TypeSchemaChange,
+		schemaChange: &SchemaChange{Before: before, After: after},
+	}
+}
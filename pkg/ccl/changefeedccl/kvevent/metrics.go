@@ -81,6 +81,8 @@ func MakeMetrics(histogramWindow time.Duration) Metrics {
 				return "flush"
 			case TypeKV:
 				return "kv"
+			case TypeSchemaChange:
+				return "schema_change"
 			default:
 				return "resolved"
 			}
@@ -103,6 +105,7 @@ func MakeMetrics(histogramWindow time.Duration) Metrics {
 		BufferEntriesByType: [numEventTypes]*metric.Counter{
 			metric.NewCounter(eventTypeMeta(TypeFlush)),
 			metric.NewCounter(eventTypeMeta(TypeKV)),
+			metric.NewCounter(eventTypeMeta(TypeSchemaChange)),
 			metric.NewCounter(eventTypeMeta(TypeResolved)),
 		},
 	}
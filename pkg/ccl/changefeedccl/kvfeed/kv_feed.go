@@ -71,6 +71,11 @@ type Config struct {
 	SchemaChangePolicy  changefeedbase.SchemaChangePolicy
 	SchemaFeed          schemafeed.SchemaFeed
 
+	// If true, the feed writes a schema change event to the writer for each
+	// change to the schema of a target, ahead of the events at or after the
+	// timestamp of the change.
+	EmitSchemaChanges bool
+
 	// If true, the feed will begin with a dump of data at exactly the
 	// InitialHighWater. This is a peculiar behavior. In general the
 	// InitialHighWater is a point in time at which all data is known to have
@@ -128,6 +133,7 @@ func Run(ctx context.Context, cfg Config) error {
 		cfg.SchemaFeed,
 		sc, pff, bf, cfg.Targets, cfg.Knobs)
 	f.onBackfillCallback = cfg.MonitoringCfg.OnBackfillCallback
+	f.emitSchemaChanges = cfg.EmitSchemaChanges
 	f.rangeObserver = startLaggingRangesObserver(g, cfg.MonitoringCfg.LaggingRangesCallback,
 		cfg.MonitoringCfg.LaggingRangesPollingInterval, cfg.MonitoringCfg.LaggingRangesThreshold)
 
@@ -254,6 +260,7 @@ type kvFeed struct {
 	rangeObserver      func(fn kvcoord.ForEachRangeFn)
 	schemaChangeEvents changefeedbase.SchemaChangeEventClass
 	schemaChangePolicy changefeedbase.SchemaChangePolicy
+	emitSchemaChanges  bool
 
 	targets changefeedbase.Targets
 
@@ -392,6 +399,13 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		} else if f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop {
 			boundaryType = jobspb.ResolvedSpan_EXIT
 		}
+		// The schema changes are otherwise written when they are consumed by
+		// scanIfShould, which never happens if the feed exits.
+		if boundaryType == jobspb.ResolvedSpan_EXIT {
+			if err := f.writeSchemaChanges(ctx, events); err != nil {
+				return err
+			}
+		}
 		// Resolve all of the spans as a boundary if the policy indicates that
 		// we should do so.
 		if f.schemaChangePolicy != changefeedbase.OptSchemaChangePolicyNoBackfill ||
@@ -491,6 +505,10 @@ func (f *kvFeed) scanIfShould(
 		return nil, hlc.Timestamp{}, nil
 	}
 
+	if err := f.writeSchemaChanges(ctx, events); err != nil {
+		return nil, hlc.Timestamp{}, err
+	}
+
 	// Consume the events up to scanTime.
 	if _, err := f.tableFeed.Pop(ctx, scanTime); err != nil {
 		return nil, hlc.Timestamp{}, err
@@ -528,6 +546,30 @@ func (f *kvFeed) scanIfShould(
 	return spansToScan, scanTime, nil
 }
 
+// writeSchemaChanges writes a schema change event for each of the table
+// events, if the feed emits schema changes. Every kvfeed of a changefeed
+// observes the schema changes of all of its targets, so the event for a table
+// is only written by the feed which watches the start of the primary index of
+// the table; this ensures that each schema change is emitted once.
+func (f *kvFeed) writeSchemaChanges(ctx context.Context, events []schemafeed.TableEvent) error {
+	if !f.emitSchemaChanges {
+		return nil
+	}
+	for _, ev := range events {
+		start := ev.Before.PrimaryIndexSpan(f.codec).Key
+		for _, sp := range f.spans {
+			if !sp.ContainsKey(start) {
+				continue
+			}
+			if err := f.writer.Add(ctx, kvevent.NewSchemaChangeEvent(ev.Before, ev.After)); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
 func (f *kvFeed) runUntilTableEvent(ctx context.Context, resumeFrontier span.Frontier) (err error) {
 	startFrom := resumeFrontier.Frontier()
 
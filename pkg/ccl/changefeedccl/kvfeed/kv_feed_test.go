@@ -110,9 +110,12 @@ func TestKVFeed(t *testing.T) {
 
 		descs []catalog.TableDescriptor
 
-		expScans  []hlc.Timestamp
-		expEvents int
-		expErrRE  string
+		emitSchemaChanges bool
+
+		expScans         []hlc.Timestamp
+		expEvents        int
+		expSchemaChanges int
+		expErrRE         string
 	}
 	st := cluster.MakeTestingClusterSettings()
 	runTest := func(t *testing.T, tc testCase) {
@@ -150,6 +153,7 @@ func TestKVFeed(t *testing.T) {
 			tf, sf, rangefeedFactory(ref.run), bufferFactory,
 			changefeedbase.Targets{},
 			TestingKnobs{})
+		f.emitSchemaChanges = tc.emitSchemaChanges
 		ctx, cancel := context.WithCancel(context.Background())
 		g := ctxgroup.WithContext(ctx)
 		g.GoCtx(func(ctx context.Context) error {
@@ -173,10 +177,15 @@ func TestKVFeed(t *testing.T) {
 		// Assert that number of events emitted from the kvfeed matches what we
 		// specified in the testcase.
 		testG.GoCtx(func(ctx context.Context) error {
+			var schemaChanges int
 			for events := 0; events < tc.expEvents; events++ {
-				_, err := buf.Get(ctx)
+				ev, err := buf.Get(ctx)
 				assert.NoError(t, err)
+				if ev.Type() == kvevent.TypeSchemaChange {
+					schemaChanges++
+				}
 			}
+			assert.Equal(t, tc.expSchemaChanges, schemaChanges)
 			return nil
 		})
 
@@ -346,6 +355,62 @@ func TestKVFeed(t *testing.T) {
 			expEvents: 2,
 			expErrRE:  "schema change ...",
 		},
+		{
+			name:               "one table event - backfill - emit schema changes",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyBackfill,
+			needsInitialScan:   true,
+			initialHighWater:   ts(2),
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			events: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "b", ts(3)),
+				checkpointEvent(tableSpan(codec, 42), ts(4)),
+				kvEvent(codec, 42, "a", "b", ts(5)),
+				checkpointEvent(tableSpan(codec, 42), ts(2)), // ensure that events are filtered
+				checkpointEvent(tableSpan(codec, 42), ts(5)),
+			},
+			expScans: []hlc.Timestamp{
+				ts(2),
+				ts(3),
+			},
+			descs: []catalog.TableDescriptor{
+				makeTableDesc(42, 1, ts(1), 2, 1),
+				addColumnDropBackfillMutation(makeTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			emitSchemaChanges: true,
+			expEvents:         6,
+			expSchemaChanges:  1,
+		},
+		{
+			name:               "one table event - stop - emit schema changes",
+			schemaChangeEvents: changefeedbase.OptSchemaChangeEventClassDefault,
+			schemaChangePolicy: changefeedbase.OptSchemaChangePolicyStop,
+			needsInitialScan:   true,
+			initialHighWater:   ts(2),
+			spans: []roachpb.Span{
+				tableSpan(codec, 42),
+			},
+			events: []kvpb.RangeFeedEvent{
+				kvEvent(codec, 42, "a", "b", ts(3)),
+				checkpointEvent(tableSpan(codec, 42), ts(4)),
+				kvEvent(codec, 42, "a", "b", ts(5)),
+				checkpointEvent(tableSpan(codec, 42), ts(2)), // ensure that events are filtered
+				checkpointEvent(tableSpan(codec, 42), ts(5)),
+			},
+			expScans: []hlc.Timestamp{
+				ts(2),
+			},
+			descs: []catalog.TableDescriptor{
+				makeTableDesc(42, 1, ts(1), 2, 1),
+				addColumnDropBackfillMutation(makeTableDesc(42, 2, ts(4), 1, 1)),
+			},
+			emitSchemaChanges: true,
+			expEvents:         3,
+			expSchemaChanges:  1,
+			expErrRE:          "schema change ...",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			runTest(t, tc)
@@ -353,6 +418,77 @@ func TestKVFeed(t *testing.T) {
 	}
 }
 
+// TestKVFeedWriteSchemaChanges verifies that the schema changes of a table
+// are only written by the feed watching the start of its primary index.
+func TestKVFeedWriteSchemaChanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	codec := keys.SystemSQLCodec
+	ts := func(seconds int) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: (time.Duration(seconds) * time.Second).Nanoseconds()}
+	}
+	before := schematestutils.MakeTableDesc(42, 1, ts(1), 2, 1)
+	after := schematestutils.AddColumnDropBackfillMutation(
+		schematestutils.MakeTableDesc(42, 2, ts(3), 1, 1))
+	events := []schemafeed.TableEvent{{Before: before, After: after}}
+	primaryIndexSpan := before.PrimaryIndexSpan(codec)
+
+	for _, tc := range []struct {
+		name              string
+		emitSchemaChanges bool
+		spans             []roachpb.Span
+		expWritten        bool
+	}{
+		{
+			name:              "disabled",
+			emitSchemaChanges: false,
+			spans:             []roachpb.Span{tableSpan(codec, 42)},
+		},
+		{
+			name:              "watches table",
+			emitSchemaChanges: true,
+			spans:             []roachpb.Span{tableSpan(codec, 42)},
+			expWritten:        true,
+		},
+		{
+			name:              "watches start of primary index",
+			emitSchemaChanges: true,
+			spans: []roachpb.Span{
+				tableSpan(codec, 41),
+				{Key: primaryIndexSpan.Key, EndKey: primaryIndexSpan.Key.Next()},
+			},
+			expWritten: true,
+		},
+		{
+			name:              "watches rest of primary index",
+			emitSchemaChanges: true,
+			spans: []roachpb.Span{
+				{Key: primaryIndexSpan.Key.Next(), EndKey: primaryIndexSpan.EndKey},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &testKVEventWriter{}
+			f := &kvFeed{
+				writer:            w,
+				spans:             tc.spans,
+				codec:             codec,
+				emitSchemaChanges: tc.emitSchemaChanges,
+			}
+			require.NoError(t, f.writeSchemaChanges(context.Background(), events))
+			if !tc.expWritten {
+				require.Empty(t, w.events)
+				return
+			}
+			require.Len(t, w.events, 1)
+			require.Equal(t, kvevent.TypeSchemaChange, w.events[0].Type())
+			require.Equal(t, ts(3), w.events[0].Timestamp())
+			require.Equal(t, before, w.events[0].SchemaChange().Before)
+			require.Equal(t, after, w.events[0].SchemaChange().After)
+		})
+	}
+}
+
 type scannerFunc func(ctx context.Context, sink kvevent.Writer, cfg scanConfig) error
 
 func (s scannerFunc) Scan(ctx context.Context, sink kvevent.Writer, cfg scanConfig) error {
@@ -55,6 +55,7 @@ type AggMetrics struct {
 	EmittedMessages             *aggmetric.AggCounter
 	EmittedBatchSizes           *aggmetric.AggHistogram
 	FilteredMessages            *aggmetric.AggCounter
+	DeadLetterMessages          *aggmetric.AggCounter
 	MessageSize                 *aggmetric.AggHistogram
 	EmittedBytes                *aggmetric.AggCounter
 	FlushedBytes                *aggmetric.AggCounter
@@ -127,6 +128,7 @@ type sliMetrics struct {
 	EmittedResolvedMessages     *aggmetric.Counter
 	EmittedBatchSizes           *aggmetric.Histogram
 	FilteredMessages            *aggmetric.Counter
+	DeadLetterMessages          *aggmetric.Counter
 	MessageSize                 *aggmetric.Histogram
 	EmittedBytes                *aggmetric.Counter
 	FlushedBytes                *aggmetric.Counter
@@ -759,6 +761,13 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		Measurement: "Messages",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaChangefeedDeadLetterMessages := metric.Metadata{
+		Name: "changefeed.dead_letter_queue_messages",
+		Help: "Messages which could not be emitted by all feeds and were recorded " +
+			"to their dead letter queue instead",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedEmittedBytes := metric.Metadata{
 		Name:        "changefeed.emitted_bytes",
 		Help:        "Bytes emitted by all feeds",
@@ -949,7 +958,8 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 			SigFigs:      1,
 			BucketConfig: metric.DataCount16MBuckets,
 		}),
-		FilteredMessages: b.Counter(metaChangefeedFilteredMessages),
+		FilteredMessages:   b.Counter(metaChangefeedFilteredMessages),
+		DeadLetterMessages: b.Counter(metaChangefeedDeadLetterMessages),
 		MessageSize: b.Histogram(metric.HistogramOptions{
 			Metadata:     metaMessageSize,
 			Duration:     histogramWindow,
@@ -1066,6 +1076,7 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		EmittedResolvedMessages:     a.EmittedMessages.AddChild(scope, "resolved"),
 		EmittedBatchSizes:           a.EmittedBatchSizes.AddChild(scope),
 		FilteredMessages:            a.FilteredMessages.AddChild(scope),
+		DeadLetterMessages:          a.DeadLetterMessages.AddChild(scope),
 		MessageSize:                 a.MessageSize.AddChild(scope),
 		EmittedBytes:                a.EmittedBytes.AddChild(scope),
 		FlushedBytes:                a.FlushedBytes.AddChild(scope),
@@ -0,0 +1,269 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"encoding/base64"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// This file maps SQL table schemas onto proto3 message descriptors. As with
+// the avro mapping in avro.go, it is not intended to be a general purpose
+// protobuf utility.
+//
+// Every column becomes an `optional` proto3 field so that SQL NULLs can be
+// told apart from zero values. Field numbers are taken from the column IDs of
+// the table whenever possible, which keeps the field number of a column stable
+// across schema changes: adding or dropping a column produces a new descriptor
+// that is wire compatible with the previous one, mirroring the backward and
+// forward compatibility we aim for with avro.
+//
+// Only a handful of SQL types have a natural proto3 scalar counterpart. All
+// other types are encoded as their textual SQL representation.
+
+// protobufPackage is the proto package every generated message is declared
+// in.
+const protobufPackage = `cockroach.changefeed`
+
+// Protobuf reserves field numbers 19000 through 19999 for its own use and
+// does not allow numbers above protobufMaxFieldNumber.
+const (
+	protobufFirstReservedFieldNumber = 19000
+	protobufLastReservedFieldNumber  = 19999
+	protobufMaxFieldNumber           = 1<<29 - 1
+)
+
+// protobufSchema is a proto3 schema generated for the key, value or resolved
+// timestamp messages of a table version or topic.
+type protobufSchema struct {
+	// message is the top-level message of the schema. It is always the first
+	// message declared in the generated file.
+	message protoreflect.MessageDescriptor
+	// fileDescriptor is the serialized FileDescriptorProto of the generated
+	// file.
+	fileDescriptor []byte
+	// registryID is the ID assigned to the descriptor set by the schema
+	// registry.
+	registryID int32
+}
+
+// registrySchema returns the representation of this schema that is published
+// to the schema registry: the base64 encoded FileDescriptorProto, which the
+// confluent schema registry accepts in place of a .proto file.
+func (s *protobufSchema) registrySchema() string {
+	return base64.StdEncoding.EncodeToString(s.fileDescriptor)
+}
+
+// newProtobufSchema builds a proto3 file named after the given subject that
+// declares the given messages. The first message is the top-level message.
+func newProtobufSchema(
+	subject string, msgs ...*descriptorpb.DescriptorProto,
+) (*protobufSchema, error) {
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:        protobufString(subject + `.proto`),
+		Package:     protobufString(protobufPackage),
+		Syntax:      protobufString(`proto3`),
+		MessageType: msgs,
+	}
+	fd, err := protodesc.NewFile(fdp, new(protoregistry.Files))
+	if err != nil {
+		return nil, errors.Wrapf(err, `generating protobuf descriptor for %s`, subject)
+	}
+	fileDescriptor, err := protoutil.TODOMarshal(fdp)
+	if err != nil {
+		return nil, err
+	}
+	return &protobufSchema{
+		message:        fd.Messages().Get(0),
+		fileDescriptor: fileDescriptor,
+	}, nil
+}
+
+// protobufMessageForRow returns a message descriptor with one optional field
+// per column of the iterator.
+func protobufMessageForRow(
+	it cdcevent.Iterator, name string,
+) (*descriptorpb.DescriptorProto, error) {
+	var cols []cdcevent.ResultColumn
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		cols = append(cols, col)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	fieldNumbers := protobufFieldNumbers(cols)
+	msg := &descriptorpb.DescriptorProto{Name: protobufString(name)}
+	for i, col := range cols {
+		fieldName := SQLNameToAvroName(col.Name)
+		oneofIdx := int32(len(msg.OneofDecl))
+		// A proto3 optional field is represented as a field that is the only
+		// member of a synthetic oneof, whose name is the field name prefixed
+		// with an underscore.
+		msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: protobufString(`_` + fieldName),
+		})
+		msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+			Name:           protobufString(fieldName),
+			Number:         protobufInt32(fieldNumbers[i]),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           columnToProtobufType(col.Typ).Enum(),
+			OneofIndex:     protobufInt32(oneofIdx),
+			Proto3Optional: protobufBool(true),
+		})
+	}
+	return msg, nil
+}
+
+// protobufFieldNumbers assigns field numbers to columns. Column IDs are used
+// if every column has a distinct, valid one. Otherwise, for example when the
+// columns are the projection of a changefeed expression, columns are numbered
+// by their position.
+func protobufFieldNumbers(cols []cdcevent.ResultColumn) []int32 {
+	numbers := make([]int32, len(cols))
+	seen := make(map[uint32]struct{}, len(cols))
+	useColumnIDs := true
+	for _, col := range cols {
+		id := col.PGAttributeNum
+		if _, dup := seen[id]; dup || id == 0 || id > protobufMaxFieldNumber ||
+			(id >= protobufFirstReservedFieldNumber && id <= protobufLastReservedFieldNumber) {
+			useColumnIDs = false
+			break
+		}
+		seen[id] = struct{}{}
+	}
+	for i, col := range cols {
+		if useColumnIDs {
+			numbers[i] = int32(col.PGAttributeNum)
+		} else {
+			numbers[i] = int32(i + 1)
+		}
+	}
+	return numbers
+}
+
+// protobufMessageField returns a singular field of the given message type.
+func protobufMessageField(
+	name string, number int32, typeName string,
+) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     protobufString(name),
+		Number:   protobufInt32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: protobufString(`.` + protobufPackage + `.` + typeName),
+	}
+}
+
+// protobufStringField returns a singular string field.
+func protobufStringField(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   protobufString(name),
+		Number: protobufInt32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+}
+
+// columnToProtobufType returns the proto3 scalar type used to encode values of
+// the given SQL type.
+func columnToProtobufType(typ *types.T) descriptorpb.FieldDescriptorProto_Type {
+	switch typ.Family() {
+	case types.IntFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_INT64
+	case types.FloatFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+	case types.BoolFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	case types.BytesFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BYTES
+	default:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING
+	}
+}
+
+// setProtobufFieldsFromRow populates the fields of msg, which must have been
+// generated by protobufMessageForRow from the same columns, with the datums of
+// the iterator. NULL datums leave the corresponding field unset.
+func setProtobufFieldsFromRow(
+	msg *dynamicpb.Message, it cdcevent.Iterator, fmtCtx *tree.FmtCtx,
+) error {
+	fields := msg.Descriptor().Fields()
+	return it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		if d == tree.DNull {
+			return nil
+		}
+		fd := fields.ByName(protoreflect.Name(SQLNameToAvroName(col.Name)))
+		if fd == nil {
+			return errors.AssertionFailedf(`column %s not found in protobuf message %s`,
+				col.Name, msg.Descriptor().FullName())
+		}
+		v, err := datumToProtobufValue(d, fd.Kind(), fmtCtx)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, v)
+		return nil
+	})
+}
+
+// datumToProtobufValue converts a datum to a value of the given kind, which
+// must be the kind columnToProtobufType picked for the datum's type.
+func datumToProtobufValue(
+	d tree.Datum, kind protoreflect.Kind, fmtCtx *tree.FmtCtx,
+) (protoreflect.Value, error) {
+	d = tree.UnwrapDOidWrapper(d)
+	switch kind {
+	case protoreflect.Int64Kind:
+		if i, ok := d.(*tree.DInt); ok {
+			return protoreflect.ValueOfInt64(int64(*i)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := d.(*tree.DFloat); ok {
+			return protoreflect.ValueOfFloat64(float64(*f)), nil
+		}
+	case protoreflect.BoolKind:
+		if b, ok := d.(*tree.DBool); ok {
+			return protoreflect.ValueOfBool(bool(*b)), nil
+		}
+	case protoreflect.BytesKind:
+		if b, ok := d.(*tree.DBytes); ok {
+			return protoreflect.ValueOfBytes([]byte(*b)), nil
+		}
+	case protoreflect.StringKind:
+		switch s := d.(type) {
+		case *tree.DString:
+			return protoreflect.ValueOfString(string(*s)), nil
+		case *tree.DCollatedString:
+			return protoreflect.ValueOfString(s.Contents), nil
+		default:
+			fmtCtx.Reset()
+			fmtCtx.FormatNode(d)
+			return protoreflect.ValueOfString(fmtCtx.String()), nil
+		}
+	}
+	return protoreflect.Value{}, errors.AssertionFailedf(
+		`cannot encode %s datum as protobuf %s`, d.ResolvedType(), kind)
+}
+
+func protobufString(s string) *string { return &s }
+
+func protobufInt32(i int32) *int32 { return &i }
+
+func protobufBool(b bool) *bool { return &b }
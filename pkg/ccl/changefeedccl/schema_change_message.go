@@ -0,0 +1,166 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+)
+
+// schemaChangeTopic is the topic, or file prefix, to which the messages
+// describing the schema changes of the targets of a changefeed are emitted
+// when the schema_change_topic option is set.
+type schemaChangeTopic struct {
+	name changefeedbase.StatementTimeName
+}
+
+var _ TopicDescriptor = (*schemaChangeTopic)(nil)
+
+// GetNameComponents implements the TopicDescriptor interface.
+func (t *schemaChangeTopic) GetNameComponents() (changefeedbase.StatementTimeName, []string) {
+	return t.name, nil
+}
+
+// GetTopicIdentifier implements the TopicDescriptor interface. No table has
+// the zero ID, so the identifier does not collide with the one of a target.
+func (t *schemaChangeTopic) GetTopicIdentifier() TopicIdentifier {
+	return TopicIdentifier{}
+}
+
+// GetVersion implements the TopicDescriptor interface.
+func (t *schemaChangeTopic) GetVersion() descpb.DescriptorVersion {
+	return 0
+}
+
+// GetTargetSpecification implements the TopicDescriptor interface.
+func (t *schemaChangeTopic) GetTargetSpecification() changefeedbase.Target {
+	return changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		StatementTimeName: t.name,
+	}
+}
+
+// GetTableName implements the TopicDescriptor interface.
+func (t *schemaChangeTopic) GetTableName() string {
+	return string(t.name)
+}
+
+// schemaChangeMessage is the value of the message emitted for a schema change.
+type schemaChangeMessage struct {
+	Table     string    `json:"table"`
+	TableID   descpb.ID `json:"table_id"`
+	Timestamp string    `json:"timestamp"`
+	// Statements are the statements which caused the schema change. They are
+	// only known for schema changes run by the declarative schema changer.
+	Statements []string           `json:"statements,omitempty"`
+	Before     schemaChangeSchema `json:"before"`
+	After      schemaChangeSchema `json:"after"`
+}
+
+// schemaChangeSchema describes a version of the schema of a table.
+type schemaChangeSchema struct {
+	Version descpb.DescriptorVersion `json:"version"`
+	Columns []schemaChangeColumn     `json:"columns"`
+}
+
+type schemaChangeColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Hidden   bool   `json:"hidden,omitempty"`
+}
+
+// emitSchemaChange emits a message describing the schema change of the event
+// to the schema change topic. The message is keyed by the name of the table so
+// that the schema changes of a table are ordered.
+func emitSchemaChange(
+	ctx context.Context,
+	sink EventSink,
+	topic string,
+	targets changefeedbase.Targets,
+	ev kvevent.Event,
+) error {
+	key, value, err := encodeSchemaChange(targets, ev.SchemaChange())
+	if err != nil {
+		return err
+	}
+	ts := ev.Timestamp()
+	return sink.EmitRow(ctx, &schemaChangeTopic{name: changefeedbase.StatementTimeName(topic)},
+		key, value, ts, ts, ev.DetachAlloc())
+}
+
+func encodeSchemaChange(
+	targets changefeedbase.Targets, sc kvevent.SchemaChange,
+) (key, value []byte, _ error) {
+	msg := schemaChangeMessage{
+		Table:      sc.After.GetName(),
+		TableID:    sc.After.GetID(),
+		Timestamp:  sc.After.GetModificationTime().AsOfSystemTime(),
+		Statements: schemaChangeStatements(sc.After, sc.Before),
+		Before:     makeSchemaChangeSchema(sc.Before),
+		After:      makeSchemaChangeSchema(sc.After),
+	}
+	// Use the name under which the table is emitted rather than its current
+	// name so that messages can be matched with the topic of the table.
+	if _, err := targets.EachHavingTableID(msg.TableID, func(t changefeedbase.Target) error {
+		msg.Table = string(t.StatementTimeName)
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	key, err := gojson.Marshal([]string{msg.Table})
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err = gojson.Marshal(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+func makeSchemaChangeSchema(desc catalog.TableDescriptor) schemaChangeSchema {
+	s := schemaChangeSchema{
+		Version: desc.GetVersion(),
+		Columns: make([]schemaChangeColumn, 0, len(desc.PublicColumns())),
+	}
+	for _, col := range desc.PublicColumns() {
+		s.Columns = append(s.Columns, schemaChangeColumn{
+			Name:     col.GetName(),
+			Type:     col.GetType().SQLString(),
+			Nullable: col.IsNullable(),
+			Hidden:   col.IsHidden(),
+		})
+	}
+	return s
+}
+
+// schemaChangeStatements returns the statements of the declarative schema
+// change in progress on the first of the descriptors which has one.
+func schemaChangeStatements(descs ...catalog.TableDescriptor) []string {
+	for _, desc := range descs {
+		state := desc.GetDeclarativeSchemaChangerState()
+		if state == nil || len(state.RelevantStatements) == 0 {
+			continue
+		}
+		stmts := make([]string, 0, len(state.RelevantStatements))
+		for _, stmt := range state.RelevantStatements {
+			stmts = append(stmts, stmt.Statement.Statement)
+		}
+		return stmts
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"path"
 	"time"
@@ -30,6 +31,18 @@ import (
 
 const confluentSchemaContentType = `application/vnd.schemaregistry.v1+json`
 
+// schemaType is the type of a schema registered with the schema registry.
+type schemaType string
+
+const (
+	// schemaTypeAvro is the default schema type of the confluent schema
+	// registry.
+	schemaTypeAvro schemaType = `AVRO`
+	// schemaTypeProtobuf is the schema type of the FileDescriptorProtos
+	// registered by the protobuf encoder.
+	schemaTypeProtobuf schemaType = `PROTOBUF`
+)
+
 type schemaRegistry interface {
 	// Ping tests the connectivity to the schema registry. A nil
 	// error is returned if the schema registry appears to be
@@ -41,10 +54,19 @@ type schemaRegistry interface {
 	// be used in Avro wire messages or in other calls to the
 	// schema registry.
 	RegisterSchemaForSubject(ctx context.Context, subject string, schema string) (int32, error)
+
+	// RegisterSchemaForSubjectWithType is like RegisterSchemaForSubject
+	// but registers a schema of the given type.
+	RegisterSchemaForSubjectWithType(
+		ctx context.Context, subject string, typ schemaType, schema string,
+	) (int32, error)
 }
 
 type confluentSchemaVersionRequest struct {
 	Schema string `json:"schema"`
+	// SchemaType is omitted for avro schemas, which is the type the registry
+	// assumes by default.
+	SchemaType string `json:"schemaType,omitempty"`
 }
 
 type confluentSchemaVersionResponse struct {
@@ -228,13 +250,24 @@ func (r *confluentSchemaRegistry) Ping(ctx context.Context) error {
 //	https://docs.confluent.io/platform/current/schema-registry/develop/api.html#post--subjects-(string-%20subject)-versions
 func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 	ctx context.Context, subject string, schema string,
+) (int32, error) {
+	return r.RegisterSchemaForSubjectWithType(ctx, subject, schemaTypeAvro, schema)
+}
+
+// RegisterSchemaForSubjectWithType registers the given schema of the given
+// type for the given subject.
+func (r *confluentSchemaRegistry) RegisterSchemaForSubjectWithType(
+	ctx context.Context, subject string, typ schemaType, schema string,
 ) (int32, error) {
 	u := r.urlForPath(fmt.Sprintf("subjects/%s/versions", subject))
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		log.Infof(ctx, "registering %s schema %s %s", typ, u, schema)
 	}
 
 	req := confluentSchemaVersionRequest{Schema: schema}
+	if typ != schemaTypeAvro {
+		req.SchemaType = string(typ)
+	}
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -249,7 +282,11 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 		defer gracefulClose(ctx, resp.Body)
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			body, _ := io.ReadAll(resp.Body)
-			return errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body)
+			err := errors.Errorf("registering schema to %s %s: %s", u, resp.Status, body)
+			if isSchemaRejection(resp.StatusCode) {
+				err = changefeedbase.MarkPermanentRowError(err)
+			}
+			return err
 		}
 		var res confluentSchemaVersionResponse
 		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
@@ -267,6 +304,14 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 	return id, nil
 }
 
+// isSchemaRejection returns true if the status code of a response to a schema
+// registration indicates that the registry rejected the schema itself, either
+// because it is invalid or because it is incompatible with the schemas
+// previously registered for its subject.
+func isSchemaRejection(statusCode int) bool {
+	return statusCode == http.StatusConflict || statusCode == http.StatusUnprocessableEntity
+}
+
 func (r *confluentSchemaRegistry) doWithRetry(ctx context.Context, fn func() error) error {
 	// Since network services are often a source of flakes, add a few retries here
 	// before we give up and return an error that will bubble up and tear down the
@@ -317,6 +362,7 @@ func (r *confluentSchemaRegistry) urlForPath(relPath string) string {
 
 type schemaRegistryCacheKey struct {
 	subject string
+	typ     schemaType
 	schema  string
 }
 
@@ -354,9 +400,16 @@ func (csr *schemaRegistryWithCache) Ping(ctx context.Context) error {
 // RegisterSchemaForSubject implements the schemaRegistry interface.
 func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
 	ctx context.Context, subject string, schema string,
+) (int32, error) {
+	return csr.RegisterSchemaForSubjectWithType(ctx, subject, schemaTypeAvro, schema)
+}
+
+// RegisterSchemaForSubjectWithType implements the schemaRegistry interface.
+func (csr *schemaRegistryWithCache) RegisterSchemaForSubjectWithType(
+	ctx context.Context, subject string, typ schemaType, schema string,
 ) (int32, error) {
 	cacheKey := schemaRegistryCacheKey{
-		subject: subject, schema: schema,
+		subject: subject, typ: typ, schema: schema,
 	}
 	csr.cache.mu.Lock()
 	defer csr.cache.mu.Unlock()
@@ -364,7 +417,7 @@ func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
 	if ok {
 		return id, nil
 	}
-	id, err := csr.base.RegisterSchemaForSubject(ctx, subject, schema)
+	id, err := csr.base.RegisterSchemaForSubjectWithType(ctx, subject, typ, schema)
 	if err == nil {
 		csr.cache.Add(cacheKey, id)
 	}
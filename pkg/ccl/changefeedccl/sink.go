@@ -60,6 +60,9 @@ const (
 	sinkTypeCloudstorage
 	sinkTypeSQL
 	sinkTypePulsar
+	sinkTypeNATS
+	sinkTypeMQTT
+	sinkTypeRedis
 )
 
 // externalResource is the interface common to both EventSink and
@@ -117,6 +120,16 @@ type SinkWithTopics interface {
 	Topics() []string
 }
 
+// transactionalSink is implemented by sinks which may write rows in
+// transactions. The rows flushed to the sink become visible to readers of the
+// transactional destination once the transaction is committed.
+type transactionalSink interface {
+	// CommitTransaction commits the rows flushed since the last commit. It must
+	// be called after Flush, and before rows are emitted again. It is a noop if
+	// the sink does not write rows in transactions.
+	CommitTransaction(ctx context.Context) error
+}
+
 func getEventSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -124,9 +137,10 @@ func getEventSink(
 	timestampOracle timestampLowerBoundOracle,
 	user username.SQLUsername,
 	jobID jobspb.JobID,
+	instanceID string,
 	m metricsRecorder,
 ) (EventSink, error) {
-	return getAndDialSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	return getAndDialSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, instanceID, m)
 }
 
 func getResolvedTimestampSink(
@@ -136,9 +150,10 @@ func getResolvedTimestampSink(
 	timestampOracle timestampLowerBoundOracle,
 	user username.SQLUsername,
 	jobID jobspb.JobID,
+	instanceID string,
 	m metricsRecorder,
 ) (ResolvedTimestampSink, error) {
-	return getAndDialSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	return getAndDialSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, instanceID, m)
 }
 
 func getAndDialSink(
@@ -148,9 +163,10 @@ func getAndDialSink(
 	timestampOracle timestampLowerBoundOracle,
 	user username.SQLUsername,
 	jobID jobspb.JobID,
+	instanceID string,
 	m metricsRecorder,
 ) (Sink, error) {
-	sink, err := getSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	sink, err := getSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, instanceID, m)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +214,10 @@ var KafkaV2Enabled = settings.RegisterBoolSetting(
 	settings.WithName("changefeed.new_kafka_sink.enabled"),
 )
 
+// getSink makes the sink described by the details of the changefeed. The
+// instanceID identifies the sink among the sinks of the job across its
+// resumptions, and is empty for sinks which are not resumed, such as the
+// canary sink made when the changefeed is created.
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -205,6 +225,7 @@ func getSink(
 	timestampOracle timestampLowerBoundOracle,
 	user username.SQLUsername,
 	jobID jobspb.JobID,
+	instanceID string,
 	m metricsRecorder,
 ) (Sink, error) {
 	u, err := url.Parse(feedCfg.SinkURI)
@@ -255,7 +276,7 @@ func getSink(
 				if KafkaV2Enabled.Get(&serverCfg.Settings.SV) {
 					return makeKafkaSinkV2(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
 						numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
-						serverCfg.Settings, metricsBuilder, kafkaSinkV2Knobs{})
+						serverCfg.Settings, metricsBuilder, jobID, instanceID, kafkaSinkV2Knobs{})
 				} else {
 					return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(), serverCfg.Settings, metricsBuilder)
 				}
@@ -297,6 +318,24 @@ func getSink(
 			} else {
 				return makeDeprecatedPubsubSink(ctx, u, encodingOpts, AllTargets(feedCfg), opts.IsSet(changefeedbase.OptUnordered), metricsBuilder, testingKnobs)
 			}
+		case isNATSSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.NATSValidOptions, func() (Sink, error) {
+				return makeNATSSink(ctx, sinkURL{URL: u}, encodingOpts, opts.GetNATSConfigJSON(), AllTargets(feedCfg),
+					numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
+					metricsBuilder, serverCfg.Settings, natsSinkKnobs{})
+			})
+		case isMQTTSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.MQTTValidOptions, func() (Sink, error) {
+				return makeMQTTSink(ctx, sinkURL{URL: u}, encodingOpts, opts.GetMQTTConfigJSON(), AllTargets(feedCfg),
+					numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
+					metricsBuilder, serverCfg.Settings, mqttSinkKnobs{})
+			})
+		case isRedisSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.RedisValidOptions, func() (Sink, error) {
+				return makeRedisSink(ctx, sinkURL{URL: u}, encodingOpts, opts.GetRedisConfigJSON(), AllTargets(feedCfg),
+					numSinkIOWorkers(serverCfg), newCPUPacerFactory(ctx, serverCfg), timeutil.DefaultTimeSource{},
+					metricsBuilder, serverCfg.Settings, redisSinkKnobs{})
+			})
 		case isCloudStorageSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.CloudStorageValidOptions, func() (Sink, error) {
 				var testingKnobs *TestingKnobs
@@ -322,7 +361,7 @@ func getSink(
 			return validateOptionsAndMakeSink(changefeedbase.ExternalConnectionValidOptions, func() (Sink, error) {
 				return makeExternalConnectionSink(
 					ctx, sinkURL{URL: u}, user, makeExternalConnectionProvider(ctx, serverCfg.DB),
-					serverCfg, feedCfg, timestampOracle, jobID, m,
+					serverCfg, feedCfg, timestampOracle, jobID, instanceID, m,
 				)
 			})
 		case u.Scheme == "":
@@ -47,7 +47,8 @@ func isCloudStorageSink(u *url.URL) bool {
 	switch u.Scheme {
 	case changefeedbase.SinkSchemeCloudStorageS3, changefeedbase.SinkSchemeCloudStorageGCS,
 		changefeedbase.SinkSchemeCloudStorageNodelocal, changefeedbase.SinkSchemeCloudStorageHTTP,
-		changefeedbase.SinkSchemeCloudStorageHTTPS, changefeedbase.SinkSchemeCloudStorageAzure:
+		changefeedbase.SinkSchemeCloudStorageHTTPS, changefeedbase.SinkSchemeCloudStorageAzure,
+		changefeedbase.SinkSchemeCloudStorageSFTP:
 		return true
 	// During the deprecation period, we need to keep parsing these as cloudstorage for backwards
 	// compatibility. Afterwards we'll either remove them or move them to webhook.
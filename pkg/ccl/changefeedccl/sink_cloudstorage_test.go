@@ -34,11 +34,16 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -233,6 +238,45 @@ func TestCloudStorageSink(t *testing.T) {
 		require.Equal(t, `{"resolved":"5.0000000000"}`, string(resolvedFile))
 	})
 
+	t.Run(`schema-change-topic`, func(t *testing.T) {
+		t1 := makeTopic(`t1`)
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf, err := span.MakeFrontier(testSpan)
+		require.NoError(t, err)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+
+		s, err := makeCloudStorageSink(
+			ctx, sinkURI(t, unlimitedFileSize), 1, settings, opts,
+			timestampOracle, externalStorageFromURI, user, nil, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+
+		// The messages of the schema change topic are written to files of their
+		// own, prefixed with the name of the topic.
+		ddl := &schemaChangeTopic{name: `ddl`}
+		require.NoError(t, s.EmitRow(ctx, ddl, noKey, []byte(`d1`), ts(1), ts(1), zeroAlloc))
+		require.NoError(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+		require.NoError(t, s.Flush(ctx))
+
+		topics := make(map[string]string)
+		absRoot := filepath.Join(externalIODir, testDir(t))
+		require.NoError(t, filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			subs := cloudFeedFileRE.FindStringSubmatch(filepath.Base(path))
+			require.NotNil(t, subs, "unexpected file %s", path)
+			file, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			topics[subs[5]] = string(file)
+			return nil
+		}))
+		require.Equal(t, map[string]string{`ddl`: "d1\n", `t1`: "v1\n"}, topics)
+	})
+
 	forwardFrontier := func(f span.Frontier, s roachpb.Span, wall int64) bool {
 		forwarded, err := f.Forward(s, ts(wall))
 		require.NoError(t, err)
@@ -836,6 +880,85 @@ func TestCloudStorageSink(t *testing.T) {
 	})
 }
 
+// envelopeTestKMS "encrypts" data keys by prefixing them with a constant.
+type envelopeTestKMS struct{}
+
+var _ cloud.KMS = envelopeTestKMS{}
+
+const envelopeTestKMSPrefix = "cdctestkms:"
+
+func (envelopeTestKMS) MasterKeyID() string { return "key" }
+
+func (envelopeTestKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	return append([]byte(envelopeTestKMSPrefix), data...), nil
+}
+
+func (envelopeTestKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	return bytes.TrimPrefix(data, []byte(envelopeTestKMSPrefix)), nil
+}
+
+func (envelopeTestKMS) Close() error { return nil }
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(func(context.Context, string, cloud.KMSEnv) (cloud.KMS, error) {
+		return envelopeTestKMS{}, nil
+	}, "cdctestkms")
+}
+
+// TestCloudStorageSinkEnvelopeEncryption tests that a changefeed into a cloud
+// storage URI with the ENCRYPTION_KMS parameter writes encrypted files.
+func TestCloudStorageSinkEnvelopeEncryption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	externalIODir, dirCleanupFn := testutils.TempDir(t)
+	defer dirCleanupFn()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TODOTestTenantDisabled,
+		ExternalIODir:     externalIODir,
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'secret-one'), (2, 'secret-two')`)
+
+	uri := "nodelocal://1/feed?" + cloud.EnvelopeEncryptionKMSParam + "=" + url.QueryEscape("cdctestkms:///key")
+	var jobID jobspb.JobID
+	sqlDB.QueryRow(t, `CREATE CHANGEFEED FOR foo INTO $1 WITH initial_scan = 'only'`, uri).Scan(&jobID)
+	jobutils.WaitForJobToSucceed(t, sqlDB, jobID)
+
+	execCfg := s.ApplicationLayer().ExecutorConfig().(sql.ExecutorConfig)
+	store, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri, username.RootUserName())
+	require.NoError(t, err)
+	defer store.Close()
+	var rows []string
+	require.NoError(t, store.List(ctx, "", "", func(f string) error {
+		raw, err := os.ReadFile(filepath.Join(externalIODir, "feed", f))
+		if err != nil {
+			return err
+		}
+		require.NotContains(t, string(raw), "secret")
+
+		r, _, err := store.ReadFile(ctx, f, cloud.ReadOptions{NoFileSize: true})
+		if err != nil {
+			return err
+		}
+		defer r.Close(ctx)
+		data, err := ioctx.ReadAll(ctx, r)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, strings.Split(strings.TrimSpace(string(data)), "\n")...)
+		return nil
+	}))
+	sort.Strings(rows)
+	require.Len(t, rows, 2)
+	require.Contains(t, rows[0], `"secret-one"`)
+	require.Contains(t, rows[1], `"secret-two"`)
+}
+
 type explicitTimestampOracle hlc.Timestamp
 
 func (o explicitTimestampOracle) inclusiveLowerBoundTS() hlc.Timestamp {
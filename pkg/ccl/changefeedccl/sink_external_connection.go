@@ -32,6 +32,7 @@ func makeExternalConnectionSink(
 	feedCfg jobspb.ChangefeedDetails,
 	timestampOracle timestampLowerBoundOracle,
 	jobID jobspb.JobID,
+	instanceID string,
 	m metricsRecorder,
 ) (Sink, error) {
 	if u.Host == "" {
@@ -48,7 +49,7 @@ func makeExternalConnectionSink(
 	// Replace the external connection URI in the `feedCfg` with the URI of the
 	// underlying resource.
 	feedCfg.SinkURI = uri
-	return getSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	return getSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, instanceID, m)
 }
 
 func validateExternalConnectionSinkURI(
@@ -78,7 +79,7 @@ func validateExternalConnectionSinkURI(
 	// TODO(adityamaru): When we add `CREATE EXTERNAL CONNECTION ... WITH` support
 	// to accept JSONConfig we should validate that here too.
 	s, err := getSink(ctx, serverCfg, jobspb.ChangefeedDetails{SinkURI: uri}, nil, env.Username,
-		jobspb.JobID(0), (*sliMetrics)(nil))
+		jobspb.JobID(0), `` /* instanceID */, (*sliMetrics)(nil))
 	if err != nil {
 		return errors.Wrap(err, "invalid changefeed sink URI")
 	}
@@ -99,6 +100,7 @@ var supportedExternalConnectionTypes = map[string]connectionpb.ConnectionProvide
 	changefeedbase.DeprecatedSinkSchemeHTTPS:       connectionpb.ConnectionProvider_https,
 	changefeedbase.SinkSchemeCloudStorageNodelocal: connectionpb.ConnectionProvider_nodelocal,
 	changefeedbase.SinkSchemeCloudStorageS3:        connectionpb.ConnectionProvider_s3,
+	changefeedbase.SinkSchemeCloudStorageSFTP:      connectionpb.ConnectionProvider_sftp,
 	changefeedbase.SinkSchemeKafka:                 connectionpb.ConnectionProvider_kafka,
 	changefeedbase.SinkSchemeWebhookHTTP:           connectionpb.ConnectionProvider_webhookhttp,
 	changefeedbase.SinkSchemeWebhookHTTPS:          connectionpb.ConnectionProvider_webhookhttps,
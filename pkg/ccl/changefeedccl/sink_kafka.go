@@ -207,6 +207,21 @@ type saramaConfig struct {
 	RequiredAcks string `json:",omitempty"`
 
 	Version string `json:",omitempty"`
+
+	// Transaction configures the producer transactions of the v2 sink, see
+	// kafkaSinkClientV2. Transactions commit the messages of a change
+	// aggregator at its checkpoints, so that consumers reading with the
+	// read_committed isolation level never see the messages of a failed
+	// aggregator. Delivery remains at least once: messages committed before
+	// a failure may be emitted again once the changefeed resumes, and
+	// consumers still have to deduplicate them.
+	Transaction struct {
+		Enabled bool `json:",omitempty"`
+		// IDPrefix prefixes the transactional ID of every producer, which
+		// brokers with ACLs typically restrict.
+		IDPrefix string       `json:",omitempty"`
+		Timeout  jsonDuration `json:",omitempty"`
+	}
 }
 
 func (c saramaConfig) Validate() error {
@@ -1318,6 +1333,10 @@ func buildKafkaConfig(
 	if err := saramaCfg.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid sarama configuration")
 	}
+	if saramaCfg.Transaction.Enabled {
+		return nil, errors.Newf("Transaction.Enabled requires the %s cluster setting",
+			KafkaV2Enabled.Name())
+	}
 
 	// Apply configures config based on saramaCfg.
 	if err := saramaCfg.Apply(config); err != nil {
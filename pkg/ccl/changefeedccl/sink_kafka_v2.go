@@ -12,6 +12,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"hash/fnv"
 	"io"
 	"net/url"
@@ -21,12 +22,17 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/aws/aws-msk-iam-sasl-signer-go/signer"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 	"github.com/klauspost/compress/gzip"
@@ -60,8 +66,48 @@ type kafkaSinkClientV2 struct {
 		allTopicPartitions  map[string][]int32
 		lastMetadataRefresh time.Time
 	}
+
+	// transactional is set if the Transaction.Enabled option of the sink config
+	// is set, in which case messages are produced in Kafka transactions. A
+	// transaction begins with the first Flush after the previous one ended, and
+	// is committed by CommitTransaction, which the change aggregator calls when
+	// it forwards its resolved spans to the change frontier. A transaction thus
+	// holds the messages emitted by an aggregator between two of its
+	// checkpoints, and consumers reading with the read_committed isolation
+	// level never see the messages of an aggregator which failed before its
+	// next checkpoint, as its transaction is aborted.
+	//
+	// This does not make the changefeed emit messages exactly once: a
+	// transaction may hold messages newer than the resolved spans forwarded
+	// along with its commit, and the change frontier persists those spans only
+	// after the commit, so the changefeed may emit committed messages again
+	// once it resumes after a failure.
+	transactional bool
+	txn           struct {
+		// RWMutex is held for reading while producing messages, and for writing
+		// while ending a transaction.
+		syncutil.RWMutex
+		mu struct {
+			syncutil.Mutex
+			// open is set once a transaction began and until it ends.
+			open bool
+			// err is the first error producing the messages of the open
+			// transaction, which can then only be aborted.
+			err error
+		}
+	}
 }
 
+const (
+	// kafkaDefaultTransactionalIDPrefix prefixes the transactional ID of the
+	// producers of the sink unless the Transaction.IDPrefix option is set.
+	kafkaDefaultTransactionalIDPrefix = `crdb-changefeed`
+
+	// kafkaAbortTransactionTimeout bounds how long closing the sink waits for
+	// the open transaction to be aborted.
+	kafkaAbortTransactionTimeout = 10 * time.Second
+)
+
 // newKafkaSinkClientV2 creates a new kafka sink client. It is a thin wrapper
 // around the kgo client for use by the batching sink. It's not meant to be
 // invoked on its own, but rather through makeKafkaSinkV2.
@@ -69,6 +115,7 @@ func newKafkaSinkClientV2(
 	ctx context.Context,
 	clientOpts []kgo.Opt,
 	batchCfg sinkBatchConfig,
+	transactional bool,
 	bootstrapAddrs string,
 	settings *cluster.Settings,
 	knobs kafkaSinkV2Knobs,
@@ -77,9 +124,6 @@ func newKafkaSinkClientV2(
 ) (*kafkaSinkClientV2, error) {
 
 	baseOpts := []kgo.Opt{
-		// Disable idempotency to maintain parity with the v1 sink and not add surface area for unknowns.
-		kgo.DisableIdempotentWrite(),
-
 		kgo.SeedBrokers(bootstrapAddrs),
 		kgo.WithLogger(kgoLogAdapter{ctx: ctx}),
 		kgo.RecordPartitioner(newKgoChangefeedPartitioner()),
@@ -100,6 +144,12 @@ func newKafkaSinkClientV2(
 		}),
 	}
 
+	if !transactional {
+		// Disable idempotency to maintain parity with the v1 sink and not add
+		// surface area for unknowns. Transactions require idempotent writes.
+		baseOpts = append(baseOpts, kgo.DisableIdempotentWrite())
+	}
+
 	recordResize := func(numRecords int64) {}
 	if m := mb(requiresResourceAccounting); m != nil { // `m` can be nil in tests.
 		baseOpts = append(baseOpts, kgo.WithHooks(&kgoMetricsAdapter{throttling: m.getKafkaThrottlingMetrics(settings)}))
@@ -124,22 +174,36 @@ func newKafkaSinkClientV2(
 		adminClient = kadm.NewClient(client.(*kgo.Client))
 	}
 
+	// A message failing to be produced fails its transaction, so resizing is
+	// pointless with transactions.
+	canTryResizing := !transactional && changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV)
+
 	c := &kafkaSinkClientV2{
 		client:                   client,
 		adminClient:              adminClient,
 		knobs:                    knobs,
 		batchCfg:                 batchCfg,
-		canTryResizing:           changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV),
+		canTryResizing:           canTryResizing,
 		recordResize:             recordResize,
 		topicsForConnectionCheck: topicsForConnectionCheck,
+		transactional:            transactional,
 	}
 	c.metadataMu.allTopicPartitions = make(map[string][]int32)
 
 	return c, nil
 }
 
-// Close implements SinkClient.
+// Close implements SinkClient. The open transaction, if any, is aborted, as its
+// messages will be emitted again when the changefeed resumes from its last
+// checkpoint.
 func (k *kafkaSinkClientV2) Close() error {
+	if k.transactional {
+		ctx, cancel := context.WithTimeout(context.Background(), kafkaAbortTransactionTimeout)
+		defer cancel()
+		if err := k.abortTransaction(ctx); err != nil {
+			log.Warningf(ctx, `failed to abort kafka transaction: %v`, err)
+		}
+	}
 	k.client.Close()
 	return nil
 }
@@ -147,6 +211,9 @@ func (k *kafkaSinkClientV2) Close() error {
 // Flush implements SinkClient. Does not retry -- retries will be handled either by kafka or ParallelIO.
 func (k *kafkaSinkClientV2) Flush(ctx context.Context, payload SinkPayload) (retErr error) {
 	msgs := payload.([]*kgo.Record)
+	if k.transactional {
+		return k.flushInTransaction(ctx, msgs)
+	}
 
 	var flushMsgs func(msgs []*kgo.Record) error
 	flushMsgs = func(msgs []*kgo.Record) error {
@@ -214,10 +281,106 @@ func (k *kafkaSinkClientV2) FlushResolvedPayload(
 		if err != nil {
 			return err
 		}
+		if k.transactional {
+			return k.flushResolvedInTransaction(ctx, msgs)
+		}
 		return k.Flush(ctx, msgs)
 	})
 }
 
+// flushInTransaction produces messages in the open transaction, beginning one
+// if necessary.
+func (k *kafkaSinkClientV2) flushInTransaction(ctx context.Context, msgs []*kgo.Record) error {
+	k.txn.RLock()
+	defer k.txn.RUnlock()
+	if err := k.maybeBeginTransaction(); err != nil {
+		return err
+	}
+	if err := k.client.ProduceSync(ctx, msgs...).FirstErr(); err != nil {
+		k.txn.mu.Lock()
+		defer k.txn.mu.Unlock()
+		if k.txn.mu.err == nil {
+			k.txn.mu.err = err
+		}
+		return err
+	}
+	return nil
+}
+
+func (k *kafkaSinkClientV2) maybeBeginTransaction() error {
+	k.txn.mu.Lock()
+	defer k.txn.mu.Unlock()
+	// Retrying to produce messages in a failed transaction would lose the
+	// messages produced before the failure, which are aborted with it.
+	if k.txn.mu.err != nil {
+		return errors.Wrap(k.txn.mu.err, `kafka transaction failed`)
+	}
+	if !k.txn.mu.open {
+		if err := k.client.BeginTransaction(); err != nil {
+			return errors.Wrap(err, `beginning kafka transaction`)
+		}
+		k.txn.mu.open = true
+	}
+	return nil
+}
+
+// flushResolvedInTransaction produces resolved messages in a transaction of
+// their own, which the batching sink guarantees by committing the transaction
+// of the rows before flushing resolved messages. A failed transaction can thus
+// be aborted and retried.
+func (k *kafkaSinkClientV2) flushResolvedInTransaction(
+	ctx context.Context, msgs []*kgo.Record,
+) error {
+	err := k.flushInTransaction(ctx, msgs)
+	if err == nil {
+		err = k.CommitTransaction(ctx)
+	}
+	if err != nil {
+		return errors.CombineErrors(err, k.abortTransaction(ctx))
+	}
+	return nil
+}
+
+// CommitTransaction implements the transactionalSinkClient interface. It
+// commits the open transaction, if any.
+func (k *kafkaSinkClientV2) CommitTransaction(ctx context.Context) error {
+	if !k.transactional {
+		return nil
+	}
+	k.txn.Lock()
+	defer k.txn.Unlock()
+	k.txn.mu.Lock()
+	defer k.txn.mu.Unlock()
+	if !k.txn.mu.open {
+		return nil
+	}
+	if k.txn.mu.err != nil {
+		return errors.Wrap(k.txn.mu.err, `cannot commit failed kafka transaction`)
+	}
+	k.txn.mu.open = false
+	if err := k.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return errors.Wrap(err, `committing kafka transaction`)
+	}
+	return nil
+}
+
+// abortTransaction aborts the open transaction, if any.
+func (k *kafkaSinkClientV2) abortTransaction(ctx context.Context) error {
+	k.txn.Lock()
+	defer k.txn.Unlock()
+	k.txn.mu.Lock()
+	defer k.txn.mu.Unlock()
+	if !k.txn.mu.open {
+		return nil
+	}
+	k.txn.mu.open = false
+	k.txn.mu.err = nil
+	if err := k.client.AbortBufferedRecords(ctx); err != nil {
+		return errors.Wrap(err, `aborting buffered kafka records`)
+	}
+	return errors.Wrap(k.client.EndTransaction(ctx, kgo.TryAbort), `aborting kafka transaction`)
+}
+
 func (k *kafkaSinkClientV2) CheckConnection(ctx context.Context) error {
 	return k.maybeUpdateTopicPartitions(ctx, func(cb func(topic string) error) error {
 		for _, topic := range k.topicsForConnectionCheck {
@@ -283,6 +446,9 @@ func (k *kafkaSinkClientV2) shouldTryResizing(err error, msgs []*kgo.Record) boo
 // KafkaClientV2 is a small interface restricting the functionality in *kgo.Client
 type KafkaClientV2 interface {
 	ProduceSync(ctx context.Context, msgs ...*kgo.Record) kgo.ProduceResults
+	BeginTransaction() error
+	EndTransaction(ctx context.Context, commit kgo.TransactionEndTry) error
+	AbortBufferedRecords(ctx context.Context) error
 	Close()
 }
 
@@ -298,6 +464,7 @@ type kafkaSinkV2Knobs struct {
 }
 
 var _ SinkClient = (*kafkaSinkClientV2)(nil)
+var _ transactionalSinkClient = (*kafkaSinkClientV2)(nil)
 var _ SinkPayload = ([]*kgo.Record)(nil) // NOTE: This doesn't actually assert anything, but it's good documentation.
 
 type kafkaBuffer struct {
@@ -339,6 +506,8 @@ func makeKafkaSinkV2(
 	timeSource timeutil.TimeSource,
 	settings *cluster.Settings,
 	mb metricsRecorderBuilder,
+	jobID jobspb.JobID,
+	instanceID string,
 	knobs kafkaSinkV2Knobs,
 ) (Sink, error) {
 	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
@@ -360,10 +529,14 @@ func makeKafkaSinkV2(
 		return nil, errors.Errorf(`%s is not yet supported`, changefeedbase.SinkParamSchemaTopic)
 	}
 
-	clientOpts, err := buildKgoConfig(ctx, u, jsonConfig)
+	clientOpts, transactional, err := buildKgoConfig(ctx, u, jsonConfig, jobID, instanceID)
 	if err != nil {
 		return nil, err
 	}
+	if transactional && !settings.Version.IsActive(ctx, clusterversion.V24_2_ChangefeedKafkaTransactions) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			`Transaction.Enabled is only supported after v24.2 upgrade is finalized`)
+	}
 
 	topicNamer, err := MakeTopicNamer(
 		targets,
@@ -379,7 +552,7 @@ func makeKafkaSinkV2(
 	}
 
 	topicsForConnectionCheck := topicNamer.DisplayNamesSlice()
-	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, u.Host, settings, knobs, mb, topicsForConnectionCheck)
+	client, err := newKafkaSinkClientV2(ctx, clientOpts, batchCfg, transactional, u.Host, settings, knobs, mb, topicsForConnectionCheck)
 	if err != nil {
 		return nil, err
 	}
@@ -388,14 +561,20 @@ func makeKafkaSinkV2(
 		parallelism, topicNamer, pacerFactory, timeSource, mb(true), settings), nil
 }
 
+// buildKgoConfig returns the client options described by the sink URI and
+// config, and whether the config enables transactions.
 func buildKgoConfig(
-	ctx context.Context, u sinkURL, jsonStr changefeedbase.SinkSpecificJSONConfig,
-) ([]kgo.Opt, error) {
+	ctx context.Context,
+	u sinkURL,
+	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	jobID jobspb.JobID,
+	instanceID string,
+) ([]kgo.Opt, bool, error) {
 	var opts []kgo.Opt
 
 	dialConfig, err := buildDialConfig(u)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if dialConfig.tlsEnabled {
@@ -407,25 +586,25 @@ func buildKgoConfig(
 		}
 
 		if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
-			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+			return nil, false, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
 		} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
-			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+			return nil, false, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
 		}
 
 		if dialConfig.clientCert != nil && dialConfig.clientKey != nil {
 			cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
 			if err != nil {
-				return nil, errors.Wrap(err, `invalid client certificate data provided`)
+				return nil, false, errors.Wrap(err, `invalid client certificate data provided`)
 			}
 			tlsCfg.Certificates = []tls.Certificate{cert}
 		}
 		opts = append(opts, kgo.DialTLSConfig(tlsCfg))
 	} else {
 		if dialConfig.caCert != nil {
-			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+			return nil, false, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
 		}
 		if dialConfig.clientCert != nil {
-			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+			return nil, false, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
 		}
 	}
 
@@ -437,14 +616,14 @@ func buildKgoConfig(
 		case changefeedbase.SASLTypeAWSMSKIAM:
 			tp, err := newKgoAWSIAMRoleOauthTokenProvider(dialConfig)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			s = sasloauth.Oauth(tp)
 		// TODO(#126991): Remove this sarama dependency.
 		case sarama.SASLTypeOAuth:
 			tp, err := newKgoOauthTokenProvider(ctx, dialConfig)
 			if err != nil {
-				return nil, err
+				return nil, false, err
 			}
 			s = sasloauth.Oauth(tp)
 		case sarama.SASLTypePlaintext, "":
@@ -469,7 +648,7 @@ func buildKgoConfig(
 				}, nil
 			})
 		default:
-			return nil, errors.Errorf(`unsupported SASL mechanism: %s`, dialConfig.saslMechanism)
+			return nil, false, errors.Errorf(`unsupported SASL mechanism: %s`, dialConfig.saslMechanism)
 		}
 		opts = append(opts, kgo.SASL(s))
 	}
@@ -478,7 +657,7 @@ func buildKgoConfig(
 	// TODO(#126991): Remove this sarama dependency.
 	sinkCfg, err := getSaramaConfig(jsonStr)
 	if err != nil {
-		return nil, errors.Wrapf(err,
+		return nil, false, errors.Wrapf(err,
 			"failed to parse sink config; check %s option", changefeedbase.OptKafkaSinkConfig)
 	}
 
@@ -496,7 +675,27 @@ func buildKgoConfig(
 		opts = append(opts, kgo.ClientID(sinkCfg.ClientID))
 	}
 
-	switch strings.ToUpper(sinkCfg.RequiredAcks) {
+	requiredAcks := strings.ToUpper(sinkCfg.RequiredAcks)
+	if sinkCfg.Transaction.Enabled {
+		// Transactions require idempotent writes, which require the
+		// acknowledgement of all in-sync replicas.
+		switch requiredAcks {
+		case ``, `ALL`, `-1`:
+			requiredAcks = `ALL`
+		default:
+			return nil, false, errors.New(`Transaction.Enabled requires RequiredAcks=ALL`)
+		}
+		prefix := sinkCfg.Transaction.IDPrefix
+		if prefix == `` {
+			prefix = kafkaDefaultTransactionalIDPrefix
+		}
+		opts = append(opts, kgo.TransactionalID(kafkaTransactionalID(prefix, jobID, instanceID)))
+		if timeout := time.Duration(sinkCfg.Transaction.Timeout); timeout > 0 {
+			opts = append(opts, kgo.TransactionTimeout(timeout))
+		}
+	}
+
+	switch requiredAcks {
 	case ``, `ONE`, `1`: // This is our default.
 		opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()))
 	case `ALL`, `-1`:
@@ -504,7 +703,7 @@ func buildKgoConfig(
 	case `NONE`, `0`:
 		opts = append(opts, kgo.RequiredAcks(kgo.NoAck()))
 	default:
-		return nil, errors.Errorf(`unknown required acks value: %s`, sinkCfg.RequiredAcks)
+		return nil, false, errors.Errorf(`unknown required acks value: %s`, sinkCfg.RequiredAcks)
 	}
 
 	// TODO(#126991): Remove this sarama dependency.
@@ -521,12 +720,12 @@ func buildKgoConfig(
 	case sarama.CompressionZSTD:
 		comp = kgo.ZstdCompression()
 	default:
-		return nil, errors.Errorf(`unknown compression codec: %v`, sinkCfg.Compression)
+		return nil, false, errors.Errorf(`unknown compression codec: %v`, sinkCfg.Compression)
 	}
 
 	if level := sinkCfg.CompressionLevel; level != sarama.CompressionLevelDefault {
 		if err := validateCompressionLevel(sinkCfg.Compression, level); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		comp = comp.WithLevel(level)
 	}
@@ -539,7 +738,7 @@ func buildKgoConfig(
 		}
 		v := kversion.FromString(version)
 		if v == nil {
-			return nil, errors.Errorf(`unknown kafka version: %s`, version)
+			return nil, false, errors.Errorf(`unknown kafka version: %s`, version)
 		}
 		// NOTE: This version of kgo doesn't support specifying max versions
 		// >3.6.0 (released Oct 10 2023). This option is only really needed for
@@ -547,7 +746,22 @@ func buildKgoConfig(
 		opts = append(opts, kgo.MaxVersions(v))
 	}
 
-	return opts, nil
+	return opts, sinkCfg.Transaction.Enabled, nil
+}
+
+// kafkaTransactionalID returns the transactional ID of the producer of a sink.
+// Every producer needs a distinct transactional ID. The ID of the sinks of a
+// job is derived from the job and the sink instance, so that it is the same
+// when the job resumes: the producer of the resumed sink then fences off the
+// producer it replaces, and the brokers abort the transaction that producer
+// left open at once. Transactions left open by producers with a random ID are
+// only aborted once they time out, and hold back read_committed consumers
+// until then.
+func kafkaTransactionalID(prefix string, jobID jobspb.JobID, instanceID string) string {
+	if jobID == 0 || instanceID == `` {
+		return prefix + `-` + uuid.MakeV4().String()
+	}
+	return fmt.Sprintf(`%s-%d-%s`, prefix, jobID, instanceID)
 }
 
 // NOTE: kgo will ignore invalid compression levels, but the v1 sinks will fail validations. So we have to validate these ourselves.
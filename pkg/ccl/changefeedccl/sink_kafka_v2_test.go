@@ -21,6 +21,8 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/mocks"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
@@ -356,6 +358,20 @@ func TestKafkaSinkClientV2_Opts(t *testing.T) {
 			},
 			expectedBatchingSinkMinFreq: 2 * time.Second,
 		},
+		{
+			name: "transactions",
+			jsonConfig: map[string]any{
+				"Transaction": map[string]any{
+					"Enabled": true,
+					"Timeout": "30s",
+				},
+			},
+			expectedOpts: map[string]any{
+				"RequiredAcks":           kgo.AllISRAcks(),
+				"DisableIdempotentWrite": false,
+				"TransactionTimeout":     30 * time.Second,
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -518,6 +534,111 @@ func TestKafkaSinkClientV2_CompressionOpts(t *testing.T) {
 
 }
 
+func TestKafkaSinkClientV2_Transactions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	t.Run("committing the sink commits", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withTransactions())
+		defer fx.close()
+
+		gomock.InOrder(
+			fx.kc.EXPECT().BeginTransaction().Times(1).Return(nil),
+			fx.kc.EXPECT().ProduceSync(gomock.Any(), gomock.Any()).Times(2).Return(nil),
+			fx.kc.EXPECT().EndTransaction(gomock.Any(), kgo.TryCommit).Times(1).Return(nil),
+		)
+		// Flushing the sink produces the messages in the open transaction,
+		// which is only committed along with the progress of the changefeed.
+		require.NoError(t, fx.bs.EmitRow(fx.ctx, topic(`t`), []byte(`k1`), []byte(`v1`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, fx.bs.Flush(fx.ctx))
+		require.NoError(t, fx.bs.EmitRow(fx.ctx, topic(`t`), []byte(`k2`), []byte(`v2`), zeroTS, zeroTS, zeroAlloc))
+		require.NoError(t, fx.bs.Flush(fx.ctx))
+		require.NoError(t, fx.bs.CommitTransaction(fx.ctx))
+		// There is no transaction to commit without new messages.
+		require.NoError(t, fx.bs.CommitTransaction(fx.ctx))
+	})
+
+	t.Run("failed transactions are aborted", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withTransactions())
+		defer fx.close()
+
+		buf := fx.sink.MakeBatchBuffer("t")
+		buf.Append([]byte("k1"), []byte("v1"), attributes{})
+		payload, err := buf.Close()
+		require.NoError(t, err)
+
+		pr := kgo.ProduceResults{kgo.ProduceResult{Err: fmt.Errorf("..: %w", kerr.NotEnoughReplicas)}}
+		gomock.InOrder(
+			fx.kc.EXPECT().BeginTransaction().Times(1).Return(nil),
+			fx.kc.EXPECT().ProduceSync(fx.ctx, payload.([]*kgo.Record)).Times(1).Return(pr),
+			// Closing the sink aborts the transaction.
+			fx.kc.EXPECT().AbortBufferedRecords(gomock.Any()).Times(1).Return(nil),
+			fx.kc.EXPECT().EndTransaction(gomock.Any(), kgo.TryAbort).Times(1).Return(nil),
+		)
+		require.ErrorIs(t, fx.sink.Flush(fx.ctx, payload), kerr.NotEnoughReplicas)
+		// Retrying would lose the messages produced before the failure.
+		require.ErrorContains(t, fx.sink.Flush(fx.ctx, payload), `kafka transaction failed`)
+		require.ErrorContains(t, fx.sink.CommitTransaction(fx.ctx), `cannot commit failed kafka transaction`)
+	})
+
+	t.Run("resolved messages commit", func(t *testing.T) {
+		fx := newKafkaSinkV2Fx(t, withTransactions())
+		defer fx.close()
+
+		forEachTopic := func(cb func(topic string) error) error {
+			return cb("t")
+		}
+		topicDetails := kadm.TopicDetails{
+			"t": kadm.TopicDetail{
+				Topic:      "t",
+				Partitions: map[int32]kadm.PartitionDetail{0: {Topic: "t", Partition: 0}},
+			},
+		}
+		fx.ac.EXPECT().ListTopics(fx.ctx, "t").Times(1).Return(topicDetails, nil)
+		gomock.InOrder(
+			fx.kc.EXPECT().BeginTransaction().Times(1).Return(nil),
+			fx.kc.EXPECT().ProduceSync(fx.ctx, gomock.Any()).Times(1).Return(nil),
+			fx.kc.EXPECT().EndTransaction(fx.ctx, kgo.TryCommit).Times(1).Return(nil),
+		)
+		require.NoError(t, fx.sink.FlushResolvedPayload(fx.ctx, []byte(`{"resolved" 42}`), forEachTopic, retry.Options{}))
+	})
+
+	t.Run("transactional id", func(t *testing.T) {
+		transactionalID := func(opts ...fxOpt) string {
+			fx := newKafkaSinkV2Fx(t, append(opts, withRealClient(),
+				withJSONConfig(`{"Transaction": {"Enabled": true, "IDPrefix": "cdc"}}`))...)
+			defer fx.close()
+			client := fx.bs.client.(*kafkaSinkClientV2).client.(*kgo.Client)
+			return *client.OptValue("TransactionalID").(*string)
+		}
+
+		// Sinks which are not resumed get a random ID.
+		require.Regexp(t, `^cdc-[0-9a-f-]{36}$`, transactionalID())
+		require.Regexp(t, `^cdc-[0-9a-f-]{36}$`, transactionalID(withSinkInstance(42, ``)))
+		// The sinks of a job get the same ID whenever the job resumes.
+		require.Equal(t, `cdc-42-frontier`, transactionalID(withSinkInstance(42, `frontier`)))
+		require.Equal(t, `cdc-42-frontier`, transactionalID(withSinkInstance(42, `frontier`)))
+	})
+
+	t.Run("requires the cluster version", func(t *testing.T) {
+		var createErr error
+		fx := newKafkaSinkV2Fx(t, withTransactions(),
+			withClusterVersion(clusterversion.V24_2_ChangefeedKafkaTransactions-1),
+			withCreateClientErrorCb(func(err error) { createErr = err }))
+		defer fx.close()
+		require.ErrorContains(t, createErr, `Transaction.Enabled is only supported after v24.2 upgrade is finalized`)
+	})
+
+	t.Run("requires acks from all replicas", func(t *testing.T) {
+		var createErr error
+		fx := newKafkaSinkV2Fx(t, withRealClient(),
+			withJSONConfig(`{"Transaction": {"Enabled": true}, "RequiredAcks": "ONE"}`),
+			withCreateClientErrorCb(func(err error) { createErr = err }))
+		defer fx.close()
+		require.ErrorContains(t, createErr, `Transaction.Enabled requires RequiredAcks=ALL`)
+	})
+}
+
 func TestKafkaSinkClientV2_ErrorsEventually(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -583,6 +704,9 @@ type kafkaSinkV2Fx struct {
 	sinkJSONConfig      changefeedbase.SinkSpecificJSONConfig
 	batchConfig         sinkBatchConfig
 	realClient          bool
+	transactional       bool
+	jobID               jobspb.JobID
+	instanceID          string
 	additionalKOpts     []kgo.Opt
 	createClientErrorCb func(error)
 
@@ -622,6 +746,31 @@ func withJSONConfig(cfg string) fxOpt {
 	}
 }
 
+func withTransactions() fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.transactional = true
+		fx.sinkJSONConfig = `{"Transaction": {"Enabled": true}}`
+	}
+}
+
+func withSinkInstance(jobID jobspb.JobID, instanceID string) fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.jobID = jobID
+		fx.instanceID = instanceID
+	}
+}
+
+func withClusterVersion(key clusterversion.Key) fxOpt {
+	return func(fx *kafkaSinkV2Fx) {
+		fx.settings = cluster.MakeTestingClusterSettingsWithVersions(
+			clusterversion.Latest.Version(),
+			clusterversion.MinSupported.Version(),
+			false, /* initializeVersion */
+		)
+		require.NoError(fx.t, clusterversion.Initialize(fx.ctx, key.Version(), &fx.settings.SV))
+	}
+}
+
 func withRealClient() fxOpt {
 	return func(fx *kafkaSinkV2Fx) {
 		fx.realClient = true
@@ -661,6 +810,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	for _, opt := range opts {
 		opt(fx)
 	}
+	settings = fx.settings
 
 	var knobs kafkaSinkV2Knobs
 
@@ -671,7 +821,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	}
 
 	var err error
-	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, fx.batchConfig, "no addrs", settings, knobs, nilMetricsRecorderBuilder, nil)
+	fx.sink, err = newKafkaSinkClientV2(ctx, fx.additionalKOpts, fx.batchConfig, fx.transactional, "no addrs", settings, knobs, nilMetricsRecorderBuilder, nil)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -692,7 +842,7 @@ func newKafkaSinkV2Fx(t *testing.T, opts ...fxOpt) *kafkaSinkV2Fx {
 	}
 	u.RawQuery = q.Encode()
 
-	bs, err := makeKafkaSinkV2(ctx, sinkURL{URL: u}, targets, fx.sinkJSONConfig, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, knobs)
+	bs, err := makeKafkaSinkV2(ctx, sinkURL{URL: u}, targets, fx.sinkJSONConfig, 1, nilPacerFactory, timeutil.DefaultTimeSource{}, settings, nilMetricsRecorderBuilder, fx.jobID, fx.instanceID, knobs)
 	if err != nil && fx.createClientErrorCb != nil {
 		fx.createClientErrorCb(err)
 		return fx
@@ -0,0 +1,408 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// The MQTT sink publishes every message to the MQTT topic derived from the
+// topic_template param of the sink URI, a topicTemplate whose key tokens are
+// separated by slashes, for example:
+//
+//	mqtts://broker:8883?topic_template=cdc/{topic}/{key}&qos=1&retain=true
+//
+// With the retain param, the broker keeps the last message published to each
+// topic, so that clients subscribing to a row topic immediately receive the
+// latest state of the row.
+
+const (
+	// mqttDefaultTopicTemplate is the topic template used when the sink URI
+	// does not specify one.
+	mqttDefaultTopicTemplate = topicTemplateTopicPlaceholder
+
+	// mqttDefaultQoS is the quality of service used when the sink URI does
+	// not specify one. At least once delivery matches the guarantees of
+	// changefeeds.
+	mqttDefaultQoS = 1
+
+	mqttDefaultPort       = `1883`
+	mqttDefaultSecurePort = `8883`
+
+	// mqttTimeout bounds how long connecting to the broker and waiting for
+	// a flush to complete can take.
+	mqttTimeout = 30 * time.Second
+
+	// mqttDisconnectQuiesce is the number of milliseconds the client waits
+	// for in-flight work to complete when closing.
+	mqttDisconnectQuiesce = 250
+)
+
+// isMQTTSink returns true if url contains scheme with valid MQTT sink
+func isMQTTSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemeMQTT, changefeedbase.SinkSchemeMQTTS:
+		return true
+	default:
+		return false
+	}
+}
+
+// mqttClient is the subset of mqtt.Client used by the MQTT sink.
+type mqttClient interface {
+	Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token
+	IsConnectionOpen() bool
+	Disconnect(quiesce uint)
+}
+
+var _ mqttClient = (mqtt.Client)(nil)
+
+type mqttSinkKnobs struct {
+	// OverrideClient, if set, is used instead of connecting to a broker.
+	OverrideClient func() mqttClient
+}
+
+type mqttSinkClient struct {
+	client        mqttClient
+	topicTemplate topicTemplate
+	qos           byte
+	retain        bool
+	batchCfg      sinkBatchConfig
+}
+
+// mqttMessage is a message to publish.
+type mqttMessage struct {
+	topic   string
+	payload []byte
+}
+
+var _ SinkClient = (*mqttSinkClient)(nil)
+var _ SinkPayload = ([]mqttMessage)(nil)
+
+func makeMQTTSinkClient(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+	knobs mqttSinkKnobs,
+) (*mqttSinkClient, error) {
+	templateParam := u.consumeParam(changefeedbase.SinkParamMQTTTopicTemplate)
+	if templateParam == `` {
+		templateParam = mqttDefaultTopicTemplate
+	}
+	tmpl, err := parseTopicTemplate(templateParam, `/`, isMQTTReserved)
+	if err != nil {
+		return nil, errors.Wrapf(err, `invalid %s param`, changefeedbase.SinkParamMQTTTopicTemplate)
+	}
+	// Topic levels are derived from the key by decoding it, which is only
+	// possible with JSON encoded keys.
+	if tmpl.hasKey() && encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return nil, errors.Errorf(`%s in the %s param requires %s=%s`, topicTemplateKeyPlaceholder,
+			changefeedbase.SinkParamMQTTTopicTemplate, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+
+	sc := &mqttSinkClient{topicTemplate: tmpl, qos: mqttDefaultQoS, batchCfg: batchCfg}
+	if qos := u.consumeParam(changefeedbase.SinkParamMQTTQoS); qos != `` {
+		switch qos {
+		case `0`:
+			sc.qos = 0
+		case `1`:
+			sc.qos = 1
+		default:
+			return nil, errors.Errorf(`param %s must be 0 or 1`, changefeedbase.SinkParamMQTTQoS)
+		}
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamMQTTRetain, &sc.retain); err != nil {
+		return nil, err
+	}
+	// A topic retains a single message, so retaining the state of each row
+	// requires a topic per row.
+	if sc.retain && !tmpl.hasKey() {
+		return nil, errors.WithHintf(
+			errors.Errorf(`param %s requires %s in the %s param`, changefeedbase.SinkParamMQTTRetain,
+				topicTemplateKeyPlaceholder, changefeedbase.SinkParamMQTTTopicTemplate),
+			`For example, %s=cdc/%s/%s`, changefeedbase.SinkParamMQTTTopicTemplate,
+			topicTemplateTopicPlaceholder, topicTemplateKeyPlaceholder)
+	}
+
+	clientOpts, err := buildMQTTClientOptions(u)
+	if err != nil {
+		return nil, err
+	}
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown mqtt sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	if knobs.OverrideClient != nil {
+		sc.client = knobs.OverrideClient()
+		return sc, nil
+	}
+	client := mqtt.NewClient(clientOpts)
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+	if err := waitForMQTTToken(ctx, client.Connect()); err != nil {
+		return nil, errors.Wrap(err, `connecting to mqtt broker`)
+	}
+	sc.client = client
+	return sc, nil
+}
+
+// buildMQTTClientOptions returns the client options described by the sink URI.
+func buildMQTTClientOptions(u sinkURL) (*mqtt.ClientOptions, error) {
+	secure := u.Scheme == changefeedbase.SinkSchemeMQTTS
+
+	var tlsSkipVerify bool
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	var caCert, clientCert, clientKey []byte
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return nil, err
+	}
+	if !secure && (len(caCert) != 0 || len(clientCert) != 0 || tlsSkipVerify) {
+		return nil, errors.Errorf(`%s, %s and %s require the %s scheme`,
+			changefeedbase.SinkParamCACert, changefeedbase.SinkParamClientCert,
+			changefeedbase.SinkParamSkipTLSVerify, changefeedbase.SinkSchemeMQTTS)
+	}
+
+	if u.Host == `` {
+		return nil, errors.New(`missing mqtt broker address`)
+	}
+	host, port := u.Hostname(), u.Port()
+	if port == `` {
+		port = mqttDefaultPort
+		if secure {
+			port = mqttDefaultSecurePort
+		}
+	}
+	broker := url.URL{Scheme: `tcp`, Host: net.JoinHostPort(host, port)}
+
+	opts := mqtt.NewClientOptions().
+		SetClientID(`cockroachdb-changefeed-` + uuid.MakeV4().Short()).
+		SetConnectTimeout(mqttTimeout).
+		SetWriteTimeout(mqttTimeout).
+		SetAutoReconnect(true)
+	if secure {
+		broker.Scheme = `ssl`
+		tlsConfig, err := newTLSConfigFromKeyPair(caCert, clientCert, clientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.InsecureSkipVerify = tlsSkipVerify
+		opts.SetTLSConfig(tlsConfig)
+	}
+	opts.AddBroker(broker.String())
+	if u.User != nil {
+		opts.SetUsername(u.User.Username())
+		if password, ok := u.User.Password(); ok {
+			opts.SetPassword(password)
+		}
+	}
+	return opts, nil
+}
+
+// waitForMQTTToken waits for the operation associated with a token to
+// complete.
+func waitForMQTTToken(ctx context.Context, tok mqtt.Token) error {
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), `waiting for the mqtt broker`)
+	case <-tok.Done():
+		return tok.Error()
+	}
+}
+
+// MakeBatchBuffer implements the SinkClient interface
+func (sc *mqttSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &mqttBuffer{
+		sc:    sc,
+		topic: topic,
+		msgs:  make([]mqttMessage, 0, sc.batchCfg.Messages),
+	}
+}
+
+// Flush implements the SinkClient interface. It publishes every message of the
+// payload and waits for all of them to complete, which for QoS 1 means that
+// the broker acknowledged them. Retries are handled by the batching sink.
+func (sc *mqttSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	msgs := payload.([]mqttMessage)
+	toks := make([]mqtt.Token, len(msgs))
+	for i, msg := range msgs {
+		toks[i] = sc.client.Publish(msg.topic, sc.qos, sc.retain, msg.payload)
+	}
+	ctx, cancel := context.WithTimeout(ctx, mqttTimeout)
+	defer cancel()
+	for i, tok := range toks {
+		if err := waitForMQTTToken(ctx, tok); err != nil {
+			return errors.Wrapf(err, `publishing to mqtt topic %s`, msgs[i].topic)
+		}
+	}
+	return nil
+}
+
+// FlushResolvedPayload implements the SinkClient interface
+func (sc *mqttSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	return forEachTopic(func(topic string) error {
+		resolvedTopic := sc.topicTemplate.renderResolved(topic)
+		return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+			ctx, cancel := context.WithTimeout(ctx, mqttTimeout)
+			defer cancel()
+			// Resolved timestamps are never retained, as they would otherwise be
+			// redelivered to every new subscriber.
+			err := waitForMQTTToken(ctx, sc.client.Publish(resolvedTopic, sc.qos, false /* retained */, body))
+			return errors.Wrapf(err, `publishing to mqtt topic %s`, resolvedTopic)
+		})
+	})
+}
+
+// CheckConnection implements the SinkClient interface
+func (sc *mqttSinkClient) CheckConnection(ctx context.Context) error {
+	if !sc.client.IsConnectionOpen() {
+		return errors.New(`not connected to the mqtt broker`)
+	}
+	return nil
+}
+
+// Close implements the SinkClient interface
+func (sc *mqttSinkClient) Close() error {
+	sc.client.Disconnect(mqttDisconnectQuiesce)
+	return nil
+}
+
+type mqttBuffer struct {
+	sc       *mqttSinkClient
+	topic    string
+	msgs     []mqttMessage
+	numBytes int
+	// err is the first error encountered rendering the topic of a message,
+	// which is returned by Close.
+	err error
+}
+
+var _ BatchBuffer = (*mqttBuffer)(nil)
+
+// Append implements the BatchBuffer interface
+func (b *mqttBuffer) Append(key []byte, value []byte, _ attributes) {
+	if b.err != nil {
+		return
+	}
+	topic, err := b.sc.topicTemplate.render(b.topic, key)
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.msgs = append(b.msgs, mqttMessage{topic: topic, payload: value})
+	b.numBytes += len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface
+func (b *mqttBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(b.numBytes, len(b.msgs), b.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface
+func (b *mqttBuffer) Close() (SinkPayload, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.msgs, nil
+}
+
+// isMQTTReserved returns whether a character cannot appear in a topic name.
+// The wildcards are only valid in subscriptions.
+func isMQTTReserved(r rune) bool {
+	return r == '+' || r == '#' || r == 0
+}
+
+// sanitizeMQTTTopic replaces the characters that cannot appear in a topic
+// name.
+func sanitizeMQTTTopic(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isMQTTReserved(r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func makeMQTTSink(
+	ctx context.Context,
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+	knobs mqttSinkKnobs,
+) (Sink, error) {
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  100,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	topicNamer, err := MakeTopicNamer(targets,
+		WithPrefix(u.consumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.consumeParam(changefeedbase.SinkParamTopicName)),
+		WithSanitizeFn(sanitizeMQTTTopic))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeMQTTSinkClient(u, encodingOpts, batchCfg, knobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeMQTT,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		mb(requiresResourceAccounting),
+		settings,
+	), nil
+}
@@ -0,0 +1,388 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// The NATS sink publishes every message to a JetStream stream, on a subject
+// derived from the subject param of the sink URI, a topicTemplate whose key
+// tokens are separated by dots, for example:
+//
+//	nats://localhost:4222?subject=cdc.{topic}.{key}
+//
+// Messages are only considered delivered, and resolved timestamps are only
+// emitted, once the stream has acknowledged them.
+
+const (
+	// natsDefaultSubject is the subject template used when the sink URI does
+	// not specify one.
+	natsDefaultSubject = topicTemplateTopicPlaceholder
+
+	// natsKeyHeader is the header carrying the encoded key of a row.
+	natsKeyHeader = `Cockroach-Changefeed-Key`
+
+	// natsAckTimeout bounds how long a flush waits for the stream to
+	// acknowledge its messages.
+	natsAckTimeout = 30 * time.Second
+)
+
+// isNATSSink returns true if url contains scheme with valid NATS sink
+func isNATSSink(u *url.URL) bool {
+	return u.Scheme == changefeedbase.SinkSchemeNATS
+}
+
+// natsPublisher is the subset of jetstream.JetStream used by the NATS sink.
+type natsPublisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+	PublishMsgAsync(msg *nats.Msg, opts ...jetstream.PublishOpt) (jetstream.PubAckFuture, error)
+}
+
+var _ natsPublisher = (jetstream.JetStream)(nil)
+
+type natsSinkKnobs struct {
+	// OverridePublisher, if set, is used instead of connecting to a NATS
+	// server.
+	OverridePublisher func() natsPublisher
+}
+
+type natsSinkClient struct {
+	// conn is nil when the publisher is overridden by testing knobs.
+	conn      *nats.Conn
+	publisher natsPublisher
+	subject   topicTemplate
+	batchCfg  sinkBatchConfig
+}
+
+var _ SinkClient = (*natsSinkClient)(nil)
+var _ SinkPayload = ([]*nats.Msg)(nil)
+
+func makeNATSSinkClient(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+	knobs natsSinkKnobs,
+) (*natsSinkClient, error) {
+	subjectTemplate := u.consumeParam(changefeedbase.SinkParamNATSSubject)
+	if subjectTemplate == `` {
+		subjectTemplate = natsDefaultSubject
+	}
+	subject, err := parseTopicTemplate(subjectTemplate, `.`, isNATSReserved)
+	if err != nil {
+		return nil, errors.Wrapf(err, `invalid %s param`, changefeedbase.SinkParamNATSSubject)
+	}
+	// Subject tokens are derived from the key by decoding it, which is only
+	// possible with JSON encoded keys.
+	if subject.hasKey() && encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return nil, errors.Errorf(`%s in the %s param requires %s=%s`, topicTemplateKeyPlaceholder,
+			changefeedbase.SinkParamNATSSubject, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+
+	servers, connOpts, err := buildNATSConnOpts(u)
+	if err != nil {
+		return nil, err
+	}
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown nats sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	sc := &natsSinkClient{subject: subject, batchCfg: batchCfg}
+	if knobs.OverridePublisher != nil {
+		sc.publisher = knobs.OverridePublisher()
+		return sc, nil
+	}
+	sc.conn, err = nats.Connect(servers, connOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, `connecting to nats`)
+	}
+	sc.publisher, err = jetstream.New(sc.conn)
+	if err != nil {
+		sc.conn.Close()
+		return nil, errors.Wrap(err, `creating jetstream context`)
+	}
+	return sc, nil
+}
+
+// buildNATSConnOpts returns the comma separated server URLs and the connection
+// options described by the sink URI.
+func buildNATSConnOpts(u sinkURL) (string, []nats.Option, error) {
+	opts := []nats.Option{nats.Name(`cockroachdb-changefeed`)}
+
+	var tlsEnabled, tlsSkipVerify bool
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &tlsEnabled); err != nil {
+		return ``, nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return ``, nil, err
+	}
+	var caCert, clientCert, clientKey []byte
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return ``, nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return ``, nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return ``, nil, err
+	}
+	if !tlsEnabled && (len(caCert) != 0 || len(clientCert) != 0 || tlsSkipVerify) {
+		return ``, nil, errors.Errorf(`%s, %s and %s require the %s=true param`,
+			changefeedbase.SinkParamCACert, changefeedbase.SinkParamClientCert,
+			changefeedbase.SinkParamSkipTLSVerify, changefeedbase.SinkParamTLSEnabled)
+	}
+	if tlsEnabled {
+		tlsConfig, err := newTLSConfigFromKeyPair(caCert, clientCert, clientKey)
+		if err != nil {
+			return ``, nil, err
+		}
+		tlsConfig.InsecureSkipVerify = tlsSkipVerify
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	token := u.consumeParam(changefeedbase.SinkParamNATSToken)
+	jwt := u.consumeParam(changefeedbase.SinkParamNATSJWT)
+	seed := u.consumeParam(changefeedbase.SinkParamNATSNKeySeed)
+	password, hasPassword := ``, false
+	if u.User != nil {
+		password, hasPassword = u.User.Password()
+	}
+	numAuthMethods := 0
+	if hasPassword {
+		numAuthMethods++
+		opts = append(opts, nats.UserInfo(u.User.Username(), password))
+	}
+	if token != `` {
+		numAuthMethods++
+		opts = append(opts, nats.Token(token))
+	}
+	if jwt != `` || seed != `` {
+		numAuthMethods++
+		if jwt == `` || seed == `` {
+			return ``, nil, errors.Errorf(`%s and %s must be provided together`,
+				changefeedbase.SinkParamNATSJWT, changefeedbase.SinkParamNATSNKeySeed)
+		}
+		opts = append(opts, nats.UserJWTAndSeed(jwt, seed))
+	}
+	if numAuthMethods > 1 {
+		return ``, nil, errors.Errorf(
+			`only one of a user password, the %s param or the %s and %s params can be provided`,
+			changefeedbase.SinkParamNATSToken, changefeedbase.SinkParamNATSJWT, changefeedbase.SinkParamNATSNKeySeed)
+	}
+
+	if u.Host == `` {
+		return ``, nil, errors.New(`missing nats server address`)
+	}
+	var servers []string
+	for _, host := range strings.Split(u.Host, `,`) {
+		servers = append(servers, (&url.URL{Scheme: `nats`, Host: host}).String())
+	}
+	return strings.Join(servers, `,`), opts, nil
+}
+
+// MakeBatchBuffer implements the SinkClient interface
+func (sc *natsSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &natsBuffer{
+		sc:    sc,
+		topic: topic,
+		msgs:  make([]*nats.Msg, 0, sc.batchCfg.Messages),
+	}
+}
+
+// Flush implements the SinkClient interface. It publishes every message of the
+// payload and waits for all of them to be acknowledged, so that the batching
+// sink only considers the payload delivered once it is persisted in the
+// stream. Retries are handled by the batching sink.
+func (sc *natsSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	msgs := payload.([]*nats.Msg)
+	acks := make([]jetstream.PubAckFuture, 0, len(msgs))
+	for _, msg := range msgs {
+		ack, err := sc.publisher.PublishMsgAsync(msg)
+		if err != nil {
+			return errors.Wrapf(err, `publishing to nats subject %s`, msg.Subject)
+		}
+		acks = append(acks, ack)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, natsAckTimeout)
+	defer cancel()
+	for _, ack := range acks {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), `waiting for nats to acknowledge messages`)
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return errors.Wrapf(err, `publishing to nats subject %s`, ack.Msg().Subject)
+		}
+	}
+	return nil
+}
+
+// FlushResolvedPayload implements the SinkClient interface
+func (sc *natsSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	return forEachTopic(func(topic string) error {
+		msg := &nats.Msg{
+			Subject: sc.subject.renderResolved(topic),
+			Data:    body,
+		}
+		return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+			_, err := sc.publisher.PublishMsg(ctx, msg)
+			return errors.Wrapf(err, `publishing to nats subject %s`, msg.Subject)
+		})
+	})
+}
+
+// CheckConnection implements the SinkClient interface
+func (sc *natsSinkClient) CheckConnection(ctx context.Context) error {
+	if sc.conn == nil {
+		return nil
+	}
+	// A round trip to the server.
+	return sc.conn.FlushWithContext(ctx)
+}
+
+// Close implements the SinkClient interface
+func (sc *natsSinkClient) Close() error {
+	if sc.conn != nil {
+		sc.conn.Close()
+	}
+	return nil
+}
+
+type natsBuffer struct {
+	sc       *natsSinkClient
+	topic    string
+	msgs     []*nats.Msg
+	numBytes int
+	// err is the first error encountered rendering the subject of a message,
+	// which is returned by Close.
+	err error
+}
+
+var _ BatchBuffer = (*natsBuffer)(nil)
+
+// Append implements the BatchBuffer interface
+func (b *natsBuffer) Append(key []byte, value []byte, _ attributes) {
+	if b.err != nil {
+		return
+	}
+	subject, err := b.sc.subject.render(b.topic, key)
+	if err != nil {
+		b.err = err
+		return
+	}
+	msg := &nats.Msg{
+		Subject: subject,
+		Header:  nats.Header{natsKeyHeader: []string{string(key)}},
+		Data:    value,
+	}
+	b.msgs = append(b.msgs, msg)
+	b.numBytes += len(key) + len(value)
+}
+
+// ShouldFlush implements the BatchBuffer interface
+func (b *natsBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(b.numBytes, len(b.msgs), b.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface
+func (b *natsBuffer) Close() (SinkPayload, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.msgs, nil
+}
+
+// isNATSReserved returns whether a character cannot appear in a subject.
+func isNATSReserved(r rune) bool {
+	return r == '*' || r == '>' || unicode.IsSpace(r)
+}
+
+// sanitizeNATSTopic replaces the characters that cannot appear in a subject.
+// Unlike in key tokens, dots are preserved so that full table names map onto
+// a subject hierarchy.
+func sanitizeNATSTopic(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isNATSReserved(r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func makeNATSSink(
+	ctx context.Context,
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+	knobs natsSinkKnobs,
+) (Sink, error) {
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  1000,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	topicNamer, err := MakeTopicNamer(targets,
+		WithPrefix(u.consumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.consumeParam(changefeedbase.SinkParamTopicName)),
+		WithSanitizeFn(sanitizeNATSTopic))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeNATSSinkClient(u, encodingOpts, batchCfg, knobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeNATS,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		mb(requiresResourceAccounting),
+		settings,
+	), nil
+}
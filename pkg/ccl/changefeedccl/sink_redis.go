@@ -0,0 +1,432 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// The Redis sink writes changefeed messages to Redis in one of two modes,
+// selected by the mode param of the sink URI:
+//
+//   - stream (the default) appends every message to a Redis stream named after
+//     its topic with XADD. The entries have a key and a value field, and
+//     resolved timestamps are appended as entries with a resolved field. The
+//     max_len param trims the streams as they grow.
+//
+//	redis://localhost:6379?mode=stream&max_len=100000
+//
+//   - state maintains a Redis key per row with SET, and removes it with DEL
+//     when the row is deleted, so that Redis holds a copy of the watched
+//     tables. The key of each row is derived from the key_template param, a
+//     topicTemplate whose key tokens are separated by colons. The resolved
+//     timestamps of a table are SET at the key for which the template renders
+//     `{key}` as `_resolved`: once it holds a timestamp, the keys of the table
+//     reflect its rows as of that timestamp. This mode requires envelope=row,
+//     so that the value of each key is the row itself.
+//
+//	rediss://localhost:6380/0?mode=state&key_template=cdc:{topic}:{key}
+//
+// The commands of a batch are sent to Redis in a single pipeline. Query params
+// not used by the sink are interpreted by redis.ParseURL, which supports
+// options such as db, dial_timeout and pool_size.
+
+const (
+	redisModeStream = `stream`
+	redisModeState  = `state`
+
+	// redisDefaultKeyTemplate is the key template of the state mode used when
+	// the sink URI does not specify one.
+	redisDefaultKeyTemplate = topicTemplateTopicPlaceholder + `:` + topicTemplateKeyPlaceholder
+
+	// Field names of the stream entries.
+	redisKeyField      = `key`
+	redisValueField    = `value`
+	redisResolvedField = `resolved`
+)
+
+// isRedisSink returns true if url contains scheme with valid Redis sink
+func isRedisSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemeRedis, changefeedbase.SinkSchemeRedisTLS:
+		return true
+	default:
+		return false
+	}
+}
+
+// redisCommand is a Redis command and its arguments.
+type redisCommand []interface{}
+
+// redisClient is the interface through which the Redis sink sends commands.
+type redisClient interface {
+	// pipeline sends the commands in a single round trip and returns the error
+	// of the first command that failed, if any.
+	pipeline(ctx context.Context, cmds []redisCommand) error
+	ping(ctx context.Context) error
+	close() error
+}
+
+// goRedisClient is the redisClient backed by a connection pool.
+type goRedisClient struct {
+	*redis.Client
+}
+
+var _ redisClient = goRedisClient{}
+
+func (c goRedisClient) pipeline(ctx context.Context, cmds []redisCommand) error {
+	pipe := c.Pipeline()
+	for _, cmd := range cmds {
+		pipe.Do(ctx, cmd...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c goRedisClient) ping(ctx context.Context) error {
+	return c.Ping(ctx).Err()
+}
+
+func (c goRedisClient) close() error {
+	return c.Close()
+}
+
+type redisSinkKnobs struct {
+	// OverrideClient, if set, is used instead of connecting to a server.
+	OverrideClient func() redisClient
+}
+
+type redisSinkClient struct {
+	client      redisClient
+	stateMode   bool
+	keyTemplate topicTemplate
+	// maxLen, if positive, is the MAXLEN of XADD commands, approximate unless
+	// exactMaxLen is set.
+	maxLen      int64
+	exactMaxLen bool
+	batchCfg    sinkBatchConfig
+}
+
+var _ SinkClient = (*redisSinkClient)(nil)
+var _ SinkPayload = ([]redisCommand)(nil)
+
+func makeRedisSinkClient(
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	batchCfg sinkBatchConfig,
+	knobs redisSinkKnobs,
+) (*redisSinkClient, error) {
+	sc := &redisSinkClient{batchCfg: batchCfg}
+	switch mode := u.consumeParam(changefeedbase.SinkParamRedisMode); mode {
+	case ``, redisModeStream:
+	case redisModeState:
+		sc.stateMode = true
+	default:
+		return nil, errors.Errorf(`param %s must be %s or %s`,
+			changefeedbase.SinkParamRedisMode, redisModeStream, redisModeState)
+	}
+
+	if maxLen := u.consumeParam(changefeedbase.SinkParamRedisMaxLen); maxLen != `` {
+		var err error
+		if sc.maxLen, err = strconv.ParseInt(maxLen, 10, 64); err != nil || sc.maxLen <= 0 {
+			return nil, errors.Errorf(`param %s must be a positive integer`, changefeedbase.SinkParamRedisMaxLen)
+		}
+	}
+	approximate := true
+	approximateSet, err := u.consumeBool(changefeedbase.SinkParamRedisApproximateMaxLen, &approximate)
+	if err != nil {
+		return nil, err
+	}
+	sc.exactMaxLen = !approximate
+	if approximateSet && sc.maxLen == 0 {
+		return nil, errors.Errorf(`param %s requires the %s param`,
+			changefeedbase.SinkParamRedisApproximateMaxLen, changefeedbase.SinkParamRedisMaxLen)
+	}
+
+	keyTemplate := u.consumeParam(changefeedbase.SinkParamRedisKeyTemplate)
+	if sc.stateMode {
+		if err := sc.initStateMode(keyTemplate, encodingOpts); err != nil {
+			return nil, err
+		}
+	} else {
+		if keyTemplate != `` {
+			return nil, errors.Errorf(`param %s requires %s=%s`, changefeedbase.SinkParamRedisKeyTemplate,
+				changefeedbase.SinkParamRedisMode, redisModeState)
+		}
+	}
+
+	opts, err := buildRedisOptions(u)
+	if err != nil {
+		return nil, err
+	}
+
+	if knobs.OverrideClient != nil {
+		sc.client = knobs.OverrideClient()
+		return sc, nil
+	}
+	sc.client = goRedisClient{redis.NewClient(opts)}
+	return sc, nil
+}
+
+// initStateMode validates the options of the state mode.
+func (sc *redisSinkClient) initStateMode(
+	keyTemplate string, encodingOpts changefeedbase.EncodingOptions,
+) error {
+	if sc.maxLen != 0 {
+		return errors.Errorf(`param %s requires %s=%s`, changefeedbase.SinkParamRedisMaxLen,
+			changefeedbase.SinkParamRedisMode, redisModeStream)
+	}
+	// Deletes are only distinguishable from other changes with the row
+	// envelope, whose value is empty for deletes.
+	if encodingOpts.Envelope != changefeedbase.OptEnvelopeRow {
+		return errors.Errorf(`%s=%s requires %s=%s`, changefeedbase.SinkParamRedisMode, redisModeState,
+			changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeRow)
+	}
+
+	if keyTemplate == `` {
+		keyTemplate = redisDefaultKeyTemplate
+	}
+	tmpl, err := parseTopicTemplate(keyTemplate, `:`, isRedisReserved)
+	if err != nil {
+		return errors.Wrapf(err, `invalid %s param`, changefeedbase.SinkParamRedisKeyTemplate)
+	}
+	if !tmpl.hasKey() {
+		return errors.Errorf(`param %s must contain %s`,
+			changefeedbase.SinkParamRedisKeyTemplate, topicTemplateKeyPlaceholder)
+	}
+	if encodingOpts.Format != changefeedbase.OptFormatJSON {
+		return errors.Errorf(`%s=%s requires %s=%s`, changefeedbase.SinkParamRedisMode, redisModeState,
+			changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+	// Distinct rows must map to distinct keys, so key tokens are escaped
+	// rather than sanitized.
+	tmpl.escapeTokens = true
+	sc.keyTemplate = tmpl
+	return nil
+}
+
+// buildRedisOptions returns the client options described by the sink URI.
+func buildRedisOptions(u sinkURL) (*redis.Options, error) {
+	secure := u.Scheme == changefeedbase.SinkSchemeRedisTLS
+
+	var tlsSkipVerify bool
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	var caCert, clientCert, clientKey []byte
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &clientKey); err != nil {
+		return nil, err
+	}
+	if !secure && (len(caCert) != 0 || len(clientCert) != 0 || tlsSkipVerify) {
+		return nil, errors.Errorf(`%s, %s and %s require the %s scheme`,
+			changefeedbase.SinkParamCACert, changefeedbase.SinkParamClientCert,
+			changefeedbase.SinkParamSkipTLSVerify, changefeedbase.SinkSchemeRedisTLS)
+	}
+
+	// The remaining query params are options of the client, which are
+	// validated by ParseURL.
+	opts, err := redis.ParseURL(u.String())
+	if err != nil {
+		return nil, errors.Wrap(err, `invalid redis sink URI`)
+	}
+	if secure {
+		tlsConfig, err := newTLSConfigFromKeyPair(caCert, clientCert, clientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ServerName = opts.TLSConfig.ServerName
+		tlsConfig.InsecureSkipVerify = tlsSkipVerify
+		opts.TLSConfig = tlsConfig
+	}
+	return opts, nil
+}
+
+// MakeBatchBuffer implements the SinkClient interface
+func (sc *redisSinkClient) MakeBatchBuffer(topic string) BatchBuffer {
+	return &redisBuffer{
+		sc:    sc,
+		topic: topic,
+		cmds:  make([]redisCommand, 0, sc.batchCfg.Messages),
+	}
+}
+
+// Flush implements the SinkClient interface
+func (sc *redisSinkClient) Flush(ctx context.Context, payload SinkPayload) error {
+	return errors.Wrap(sc.client.pipeline(ctx, payload.([]redisCommand)), `writing to redis`)
+}
+
+// FlushResolvedPayload implements the SinkClient interface
+func (sc *redisSinkClient) FlushResolvedPayload(
+	ctx context.Context,
+	body []byte,
+	forEachTopic func(func(topic string) error) error,
+	retryOpts retry.Options,
+) error {
+	var cmds []redisCommand
+	if err := forEachTopic(func(topic string) error {
+		if sc.stateMode {
+			cmds = append(cmds, redisCommand{`SET`, sc.keyTemplate.renderResolved(topic), body})
+		} else {
+			cmds = append(cmds, sc.xadd(topic, redisResolvedField, body))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return retry.WithMaxAttempts(ctx, retryOpts, retryOpts.MaxRetries+1, func() error {
+		return sc.Flush(ctx, cmds)
+	})
+}
+
+// xadd returns the XADD command appending an entry with the given field-value
+// pairs to a stream.
+func (sc *redisSinkClient) xadd(stream string, fieldsAndValues ...interface{}) redisCommand {
+	cmd := make(redisCommand, 0, 6+len(fieldsAndValues))
+	cmd = append(cmd, `XADD`, stream)
+	if sc.maxLen > 0 {
+		cmd = append(cmd, `MAXLEN`)
+		if !sc.exactMaxLen {
+			cmd = append(cmd, `~`)
+		}
+		cmd = append(cmd, sc.maxLen)
+	}
+	cmd = append(cmd, `*`)
+	return append(cmd, fieldsAndValues...)
+}
+
+// CheckConnection implements the SinkClient interface
+func (sc *redisSinkClient) CheckConnection(ctx context.Context) error {
+	return errors.Wrap(sc.client.ping(ctx), `connecting to redis`)
+}
+
+// Close implements the SinkClient interface
+func (sc *redisSinkClient) Close() error {
+	return sc.client.close()
+}
+
+type redisBuffer struct {
+	sc       *redisSinkClient
+	topic    string
+	cmds     []redisCommand
+	numBytes int
+	// err is the first error encountered rendering the key of a row, which is
+	// returned by Close.
+	err error
+}
+
+var _ BatchBuffer = (*redisBuffer)(nil)
+
+// Append implements the BatchBuffer interface
+func (b *redisBuffer) Append(key []byte, value []byte, _ attributes) {
+	if b.err != nil {
+		return
+	}
+	b.numBytes += len(key) + len(value)
+	if !b.sc.stateMode {
+		b.cmds = append(b.cmds, b.sc.xadd(b.topic, redisKeyField, key, redisValueField, value))
+		return
+	}
+	redisKey, err := b.sc.keyTemplate.render(b.topic, key)
+	if err != nil {
+		b.err = err
+		return
+	}
+	if len(value) == 0 {
+		b.cmds = append(b.cmds, redisCommand{`DEL`, redisKey})
+	} else {
+		b.cmds = append(b.cmds, redisCommand{`SET`, redisKey, value})
+	}
+}
+
+// ShouldFlush implements the BatchBuffer interface
+func (b *redisBuffer) ShouldFlush() bool {
+	return shouldFlushBatch(b.numBytes, len(b.cmds), b.sc.batchCfg)
+}
+
+// Close implements the BatchBuffer interface
+func (b *redisBuffer) Close() (SinkPayload, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.cmds, nil
+}
+
+// isRedisReserved returns whether a character cannot appear in a key. Redis
+// keys are binary safe.
+func isRedisReserved(rune) bool {
+	return false
+}
+
+func makeRedisSink(
+	ctx context.Context,
+	u sinkURL,
+	encodingOpts changefeedbase.EncodingOptions,
+	jsonConfig changefeedbase.SinkSpecificJSONConfig,
+	targets changefeedbase.Targets,
+	parallelism int,
+	pacerFactory func() *admission.Pacer,
+	source timeutil.TimeSource,
+	mb metricsRecorderBuilder,
+	settings *cluster.Settings,
+	knobs redisSinkKnobs,
+) (Sink, error) {
+	batchCfg, retryOpts, err := getSinkConfigFromJson(jsonConfig, sinkJSONConfig{
+		Flush: sinkBatchConfig{
+			Frequency: jsonDuration(10 * time.Millisecond),
+			Messages:  1000,
+			Bytes:     1 << 20,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	topicNamer, err := MakeTopicNamer(targets,
+		WithPrefix(u.consumeParam(changefeedbase.SinkParamTopicPrefix)),
+		WithSingleName(u.consumeParam(changefeedbase.SinkParamTopicName)))
+	if err != nil {
+		return nil, err
+	}
+
+	sinkClient, err := makeRedisSinkClient(u, encodingOpts, batchCfg, knobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeBatchingSink(
+		ctx,
+		sinkTypeRedis,
+		sinkClient,
+		time.Duration(batchCfg.Frequency),
+		retryOpts,
+		parallelism,
+		topicNamer,
+		pacerFactory,
+		source,
+		mb(requiresResourceAccounting),
+		settings,
+	), nil
+}
@@ -417,6 +417,25 @@ func TestKafkaTopicNameWithPrefix(t *testing.T) {
 	require.Equal(t, `prefix-_u2603_`, m.Topic)
 }
 
+func TestKafkaTopicNameProvidedSchemaChangeTopic(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	p := newAsyncProducerMock(2)
+	sink, cleanup := makeTestKafkaSink(t, "prefix-", "general", p, "particular0")
+	defer cleanup()
+
+	// The schema change topic is not replaced by the topic name of the targets.
+	ddl := &schemaChangeTopic{name: "ddl"}
+	require.NoError(t, sink.EmitRow(ctx, ddl, []byte(`k`), []byte(`v`), zeroTS, zeroTS, zeroAlloc))
+	m := <-p.inputCh
+	require.Equal(t, `prefix-ddl`, m.Topic)
+	require.NoError(t, sink.EmitRow(ctx, topic("particular0"), []byte(`k`), []byte(`v`), zeroTS, zeroTS, zeroAlloc))
+	m = <-p.inputCh
+	require.Equal(t, `prefix-general`, m.Topic)
+}
+
 // goos: darwin
 // goarch: amd64
 // pkg: github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl
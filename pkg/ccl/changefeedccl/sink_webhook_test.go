@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
@@ -696,6 +697,134 @@ func TestWebhookSinkShutsDownOnError(t *testing.T) {
 	}
 }
 
+type recordingDeadLetterQueue struct {
+	syncutil.Mutex
+	rows []deadLetter
+}
+
+func (dlq *recordingDeadLetterQueue) Log(_ context.Context, rows ...deadLetter) error {
+	dlq.Lock()
+	defer dlq.Unlock()
+	dlq.rows = append(dlq.rows, rows...)
+	return nil
+}
+
+func (dlq *recordingDeadLetterQueue) Close() error {
+	return nil
+}
+
+func TestWebhookSinkDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+	require.NoError(t, err)
+	sinkDest, err := cdctest.StartMockWebhookSink(cert)
+	require.NoError(t, err)
+	defer sinkDest.Close()
+
+	// Reject the first payload, which is not retried, then accept the next one.
+	sinkDest.SetStatusCodes([]int{http.StatusBadRequest, http.StatusOK})
+	sinkDestHost, err := url.Parse(sinkDest.URL())
+	require.NoError(t, err)
+
+	params := sinkDestHost.Query()
+	params.Set(changefeedbase.SinkParamCACert, certEncoded)
+	sinkDestHost.RawQuery = params.Encode()
+
+	details := jobspb.ChangefeedDetails{
+		SinkURI: fmt.Sprintf("webhook-%s", sinkDestHost.String()),
+		Opts:    getGenericWebhookSinkOptions().AsMap(),
+	}
+
+	sinkSrc, err := setupWebhookSinkWithDetails(ctx, details, 1 /* parallelism */, timeutil.DefaultTimeSource{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sinkSrc.Close()) }()
+	var dlq recordingDeadLetterQueue
+	sinkSrc.(deadLetterSink).setDeadLetterQueue(&dlq)
+
+	require.NoError(t, sinkSrc.EmitRow(ctx, noTopic{}, []byte("[1001]"), []byte("{\"after\":{\"col1\":\"val1\",\"rowid\":1000},\"key\":[1001],\"topic:\":\"foo\"}"), zeroTS, zeroTS, zeroAlloc))
+	require.NoError(t, sinkSrc.Flush(ctx))
+	require.NoError(t, sinkSrc.EmitRow(ctx, noTopic{}, []byte("[1002]"), []byte("{\"after\":{\"col1\":\"val2\",\"rowid\":1002},\"key\":[1002],\"topic:\":\"foo\"}"), zeroTS, zeroTS, zeroAlloc))
+	require.NoError(t, sinkSrc.Flush(ctx))
+
+	// The rejected row was recorded to the dead letter queue rather than
+	// failing the sink, and the next row was delivered.
+	require.Len(t, dlq.rows, 1)
+	require.Equal(t, "[1001]", string(dlq.rows[0].key))
+	require.True(t, changefeedbase.IsPermanentRowError(dlq.rows[0].reason))
+	require.Contains(t, dlq.rows[0].reason.Error(), "400 Bad Request")
+	require.Equal(t, "{\"payload\":[{\"after\":{\"col1\":\"val2\",\"rowid\":1002},\"key\":[1002],\"topic:\":\"foo\"}],\"length\":1}", sinkDest.Pop())
+}
+
+// startRejectingWebhookSink starts a webhook sink which responds to its
+// requests with the given status codes, and a sink which emits to it batches
+// of four rows.
+func startRejectingWebhookSink(
+	t *testing.T, statusCodes []int,
+) (sinkDest *cdctest.MockWebhookSink, sinkSrc Sink) {
+	cert, certEncoded, err := cdctest.NewCACertBase64Encoded()
+	require.NoError(t, err)
+	sinkDest, err = cdctest.StartMockWebhookSink(cert)
+	require.NoError(t, err)
+	sinkDest.SetStatusCodes(statusCodes)
+	sinkDestHost, err := url.Parse(sinkDest.URL())
+	require.NoError(t, err)
+
+	params := sinkDestHost.Query()
+	params.Set(changefeedbase.SinkParamCACert, certEncoded)
+	sinkDestHost.RawQuery = params.Encode()
+
+	opts := getGenericWebhookSinkOptions(struct {
+		key   string
+		value string
+	}{
+		key:   changefeedbase.OptWebhookSinkConfig,
+		value: `{"Retry":{"Backoff": "5ms"},"Flush":{"Messages": 4, "Frequency": "1h"}}`,
+	})
+	details := jobspb.ChangefeedDetails{
+		SinkURI: fmt.Sprintf("webhook-%s", sinkDestHost.String()),
+		Opts:    opts.AsMap(),
+	}
+	sinkSrc, err = setupWebhookSinkWithDetails(context.Background(), details, 1 /* parallelism */, timeutil.DefaultTimeSource{})
+	require.NoError(t, err)
+	return sinkDest, sinkSrc
+}
+
+func webhookTestRow(i int) (key, value []byte) {
+	return []byte(fmt.Sprintf("[%d]", i)),
+		[]byte(fmt.Sprintf(`{"after":{"rowid":%d},"key":[%d]}`, i, i))
+}
+
+func TestWebhookSinkDeadLetterQueueBisectsBatches(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	// The batch and its second half are rejected, and so is the third row on
+	// its own.
+	sinkDest, sinkSrc := startRejectingWebhookSink(t, []int{
+		http.StatusBadRequest, http.StatusOK, http.StatusBadRequest, http.StatusBadRequest, http.StatusOK,
+	})
+	defer sinkDest.Close()
+	defer func() { require.NoError(t, sinkSrc.Close()) }()
+	var dlq recordingDeadLetterQueue
+	sinkSrc.(deadLetterSink).setDeadLetterQueue(&dlq)
+
+	for i := 1; i <= 4; i++ {
+		key, value := webhookTestRow(i)
+		require.NoError(t, sinkSrc.EmitRow(ctx, noTopic{}, key, value, zeroTS, zeroTS, zeroAlloc))
+	}
+	require.NoError(t, sinkSrc.Flush(ctx))
+
+	// Only the row which was rejected on its own was recorded to the dead
+	// letter queue.
+	require.Len(t, dlq.rows, 1)
+	require.Equal(t, "[3]", string(dlq.rows[0].key))
+	require.True(t, changefeedbase.IsPermanentRowError(dlq.rows[0].reason))
+	require.Equal(t, `{"payload":[{"after":{"rowid":1},"key":[1]},{"after":{"rowid":2},"key":[2]}],"length":2}`, sinkDest.Pop())
+	require.Equal(t, `{"payload":[{"after":{"rowid":4},"key":[4]}],"length":1}`, sinkDest.Pop())
+}
+
 // Regression test for https://github.com/cockroachdb/cockroach/issues/102467.
 // Ensure that we do not use the default retry config which is capped at
 // 4000ms.
@@ -229,11 +229,27 @@ func (sc *webhookSinkClient) Flush(ctx context.Context, batch SinkPayload) error
 		if err != nil {
 			return errors.Wrapf(err, "failed to read body for HTTP response with status: %d", res.StatusCode)
 		}
-		return fmt.Errorf("%s: %s", res.Status, string(resBody))
+		err = fmt.Errorf("%s: %s", res.Status, string(resBody))
+		if isPayloadRejection(res.StatusCode) {
+			err = changefeedbase.MarkPermanentRowError(err)
+		}
+		return err
 	}
 	return nil
 }
 
+// isPayloadRejection returns true if the status code of a response indicates
+// that the endpoint rejected the contents of the request, which would be
+// rejected again if it were retried.
+func isPayloadRejection(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return true
+	default:
+		return false
+	}
+}
+
 // Close implements the SinkClient interface
 func (sc *webhookSinkClient) Close() error {
 	sc.client.CloseIdleConnections()
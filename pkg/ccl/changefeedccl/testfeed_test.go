@@ -1872,13 +1872,14 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 	}
 
 	var registry *cdctest.SchemaRegistry
+	var registryFormat changefeedbase.FormatType
 	for _, opt := range createStmt.Options {
 		if opt.Key == changefeedbase.OptFormat {
 			format, err := exprAsString(opt.Value)
 			if err != nil {
 				return nil, err
 			}
-			if format == string(changefeedbase.OptFormatAvro) {
+			if format == string(changefeedbase.OptFormatAvro) || format == string(changefeedbase.OptFormatProtobuf) {
 				// Must use confluent schema registry so that we register our schema
 				// in order to be able to decode kafka messages.
 				registry = cdctest.StartTestSchemaRegistry()
@@ -1887,6 +1888,7 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 					Value: tree.NewStrVal(registry.URL()),
 				}
 				createStmt.Options = append(createStmt.Options, registryOption)
+				registryFormat = changefeedbase.FormatType(format)
 				break
 			}
 		}
@@ -1922,6 +1924,7 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 		source:         feedCh,
 		tg:             tg,
 		registry:       registry,
+		registryFormat: registryFormat,
 	}
 
 	if err := k.startFeedJob(c.jobFeed, tree.AsStringWithFlags(createStmt, tree.FmtShowPasswords), args...); err != nil {
@@ -1942,8 +1945,9 @@ type kafkaFeed struct {
 	source chan *sarama.ProducerMessage
 	tg     *teeGroup
 
-	// Registry is set if we're emitting avro.
-	registry *cdctest.SchemaRegistry
+	// Registry is set if we're emitting avro or protobuf.
+	registry       *cdctest.SchemaRegistry
+	registryFormat changefeedbase.FormatType
 }
 
 var _ cdctest.TestFeed = (*kafkaFeed)(nil)
@@ -1989,9 +1993,17 @@ func (k *kafkaFeed) Next() (*cdctest.TestFeedMessage, error) {
 			if err != nil {
 				return err
 			}
-			if k.registry == nil {
+			switch {
+			case k.registry == nil:
 				*dest = decoded
-			} else {
+			case k.registryFormat == changefeedbase.OptFormatProtobuf:
+				// Convert protobuf message to json.
+				jsonBytes, err := k.registry.ProtobufToJSON(decoded)
+				if err != nil {
+					return err
+				}
+				*dest = jsonBytes
+			default:
 				// Convert avro record to json.
 				jsonBytes, err := k.registry.AvroToJSON(decoded)
 				if err != nil {
@@ -42,6 +42,23 @@ func decodeBase64FromString(src string, dest *[]byte) error {
 }
 
 func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Client, error) {
+	tlsConfig, err := newTLSConfigFromKeyPair(caCert, clientCert, clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client := httputil.NewClientWithTimeout(httputil.StandardHTTPTimeout)
+	transport := client.Transport.(*http.Transport)
+	transport.TLSClientConfig = tlsConfig
+	client.Client.Transport = transport
+
+	return client, nil
+}
+
+// newTLSConfigFromKeyPair returns a TLS configuration trusting the system root
+// CAs as well as caCert, if provided, and presenting the client certificate, if
+// provided.
+func newTLSConfigFromKeyPair(caCert, clientCert, clientKey []byte) (*tls.Config, error) {
 	rootCAs, err := x509.SystemCertPool()
 	if err != nil {
 		return nil, errors.Wrap(err, "could not load system root CA pool")
@@ -65,9 +82,6 @@ func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Cl
 			changefeedbase.RegistryParamClientCert, changefeedbase.RegistryParamClientKey)
 	}
 
-	client := httputil.NewClientWithTimeout(httputil.StandardHTTPTimeout)
-	transport := client.Transport.(*http.Transport)
-
 	if clientCertProvided {
 		cert, err := tls.X509KeyPair(clientCert, clientKey)
 		if err != nil {
@@ -76,8 +90,5 @@ func newClientFromTLSKeyPair(caCert, clientCert, clientKey []byte) (*httputil.Cl
 		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	transport.TLSClientConfig = tlsConfig
-	client.Client.Transport = transport
-
-	return client, nil
+	return tlsConfig, nil
 }
@@ -145,6 +145,13 @@ func (tn *TopicNamer) Name(td TopicDescriptor) (string, error) {
 	if name, ok := tn.FullNames[td.GetTopicIdentifier()]; ok {
 		return name, nil
 	}
+	if sc, ok := td.(*schemaChangeTopic); ok {
+		// The schema change topic is not a target of the changefeed, so the
+		// single name shared by the targets does not apply to it.
+		name := tn.sanitizeName(tn.prefix + string(sc.name))
+		tn.FullNames[td.GetTopicIdentifier()] = name
+		return name, nil
+	}
 	name, err := tn.makeName(td.GetTargetSpecification(), td)
 	tn.FullNames[td.GetTopicIdentifier()] = name
 	return name, err
@@ -217,12 +224,13 @@ func (tn *TopicNamer) nameFromComponents(
 			b.WriteString(c)
 		}
 	}
-	str := b.String()
+	return tn.sanitizeName(b.String())
+}
 
+func (tn *TopicNamer) sanitizeName(str string) string {
 	if tn.sanitize != nil {
 		return tn.sanitize(str)
 	}
-
 	return str
 }
 
@@ -0,0 +1,195 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+// A topicTemplate describes the destination of each message for sinks that
+// route messages per row rather than per table, such as the subjects of the
+// NATS sink and the topics of the MQTT sink. The `{topic}` placeholder expands
+// to the topic name produced by the TopicNamer and the `{key}` placeholder to
+// the primary key of the row, one token per key column, joined by the
+// separator of the sink. For example, with the `.` separator,
+// `cdc.{topic}.{key}` expands to `cdc.users.us-east.42`.
+//
+// Resolved timestamps have no key, so the `{key}` placeholder expands to
+// topicTemplateResolvedToken for them.
+type topicTemplate struct {
+	parts     []string
+	separator string
+	// isReserved returns whether a character cannot appear in tokens. Reserved
+	// characters are rejected in the literal parts of a template and replaced
+	// in key tokens.
+	isReserved func(rune) bool
+	// escapeTokens, if set, escapes the separator in key tokens instead of
+	// replacing it, so that distinct keys always render to distinct
+	// destinations. See escapeToken.
+	escapeTokens bool
+}
+
+const (
+	topicTemplateTopicPlaceholder = `{topic}`
+	topicTemplateKeyPlaceholder   = `{key}`
+
+	// topicTemplateResolvedToken replaces the `{key}` placeholder for resolved
+	// timestamp messages.
+	topicTemplateResolvedToken = `_resolved`
+)
+
+// parseTopicTemplate parses a template. Key tokens are joined by separator,
+// which, like the characters for which isReserved returns true, is replaced by
+// an underscore in key tokens.
+func parseTopicTemplate(
+	s string, separator string, isReserved func(rune) bool,
+) (topicTemplate, error) {
+	t := topicTemplate{separator: separator, isReserved: isReserved}
+	for rest := s; rest != ``; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.parts = append(t.parts, rest)
+			break
+		}
+		if start > 0 {
+			t.parts = append(t.parts, rest[:start])
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return topicTemplate{}, errors.Errorf(`unterminated placeholder in %q`, s)
+		}
+		switch placeholder := rest[start : start+end+1]; placeholder {
+		case topicTemplateTopicPlaceholder, topicTemplateKeyPlaceholder:
+			t.parts = append(t.parts, placeholder)
+		default:
+			return topicTemplate{}, errors.Errorf(`unknown placeholder %s in %q, expected %s or %s`,
+				placeholder, s, topicTemplateTopicPlaceholder, topicTemplateKeyPlaceholder)
+		}
+		rest = rest[start+end+1:]
+	}
+	if len(t.parts) == 0 {
+		return topicTemplate{}, errors.New(`empty topic template`)
+	}
+	for _, part := range t.parts {
+		if i := strings.IndexFunc(part, isReserved); i >= 0 {
+			return topicTemplate{}, errors.Errorf(`%q cannot contain %q`, s, part[i:i+1])
+		}
+	}
+	return t, nil
+}
+
+// hasKey returns whether the template contains the `{key}` placeholder.
+func (t topicTemplate) hasKey() bool {
+	for _, part := range t.parts {
+		if part == topicTemplateKeyPlaceholder {
+			return true
+		}
+	}
+	return false
+}
+
+// render returns the destination of a row of the given topic. The key must be
+// JSON encoded if the template contains the `{key}` placeholder.
+func (t topicTemplate) render(topic string, key []byte) (string, error) {
+	if !t.hasKey() {
+		return t.renderTokens(topic, nil), nil
+	}
+	tokens, err := t.keyTokens(key)
+	if err != nil {
+		return ``, err
+	}
+	return t.renderTokens(topic, tokens), nil
+}
+
+// renderResolved returns the destination of resolved timestamps of the given
+// topic.
+func (t topicTemplate) renderResolved(topic string) string {
+	return t.renderTokens(topic, []string{topicTemplateResolvedToken})
+}
+
+func (t topicTemplate) renderTokens(topic string, keyTokens []string) string {
+	var b strings.Builder
+	for _, part := range t.parts {
+		switch part {
+		case topicTemplateTopicPlaceholder:
+			b.WriteString(topic)
+		case topicTemplateKeyPlaceholder:
+			for i, tok := range keyTokens {
+				if i > 0 {
+					b.WriteString(t.separator)
+				}
+				b.WriteString(tok)
+			}
+		default:
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+// keyTokens decodes a JSON encoded key into one token per key column.
+func (t topicTemplate) keyTokens(key []byte) ([]string, error) {
+	j, err := json.ParseJSON(string(key))
+	if err != nil {
+		return nil, errors.Wrap(err, `decoding key`)
+	}
+	if j.Type() != json.ArrayJSONType {
+		return nil, errors.AssertionFailedf(`expected key to be an array, got %s`, j)
+	}
+	tokens := make([]string, 0, j.Len())
+	for i := 0; i < j.Len(); i++ {
+		elem, err := j.FetchValIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		var tok string
+		if text, err := elem.AsText(); err == nil && text != nil {
+			tok = *text
+		} else {
+			tok = elem.String()
+		}
+		if t.escapeTokens {
+			tok = t.escapeToken(tok)
+		} else {
+			tok = t.sanitizeToken(tok)
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens, nil
+}
+
+// sanitizeToken replaces the reserved characters and the separator in a key
+// token with underscores. Empty tokens are replaced by an underscore.
+func (t topicTemplate) sanitizeToken(s string) string {
+	if s == `` {
+		return `_`
+	}
+	s = strings.ReplaceAll(s, t.separator, `_`)
+	return strings.Map(func(r rune) rune {
+		if t.isReserved(r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// escapeToken prefixes the separator and backslashes in a key token with a
+// backslash. A leading underscore is escaped as well, so that no key token
+// renders as topicTemplateResolvedToken.
+func (t topicTemplate) escapeToken(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, t.separator, `\`+t.separator)
+	if strings.HasPrefix(s, `_`) {
+		s = `\` + s
+	}
+	return s
+}
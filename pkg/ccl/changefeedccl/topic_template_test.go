@@ -0,0 +1,79 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicTemplate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		template  string
+		separator string
+		reserved  func(rune) bool
+		topic     string
+		key       string
+		expected  string
+		resolved  string
+		escape    bool
+		err       string
+	}{
+		{template: `{topic}`, separator: `.`, reserved: isNATSReserved,
+			topic: `t`, key: `[1]`, expected: `t`, resolved: `t`},
+		{template: `cdc.{topic}`, separator: `.`, reserved: isNATSReserved,
+			topic: `d.public.t`, key: `[1]`, expected: `cdc.d.public.t`, resolved: `cdc.d.public.t`},
+		{template: `cdc.{topic}.{key}`, separator: `.`, reserved: isNATSReserved,
+			topic: `t`, key: `[1, "a"]`, expected: `cdc.t.1.a`, resolved: `cdc.t._resolved`},
+		{template: `{key}.rows.{topic}`, separator: `.`, reserved: isNATSReserved,
+			topic: `t`, key: `[1]`, expected: `1.rows.t`, resolved: `_resolved.rows.t`},
+		{template: `cdc.{topic}.{key}`, separator: `.`, reserved: isNATSReserved,
+			topic: `t`, key: `[1, "a.b", "c d", "", true, null, 1.5, "*>"]`,
+			expected: `cdc.t.1.a_b.c_d._.true.null.1_5.__`, resolved: `cdc.t._resolved`},
+		{template: `cdc/{topic}/{key}`, separator: `/`, reserved: isMQTTReserved,
+			topic: `t`, key: `[1, "a/b", "c.d", "+#"]`, expected: `cdc/t/1/a_b/c.d/__`, resolved: `cdc/t/_resolved`},
+		{template: `{topic}:{key}`, separator: `:`, reserved: isRedisReserved, escape: true,
+			topic: `t`, key: `[1, "a:b", "c\\d", "", "_resolved", "x_"]`,
+			expected: `t:1:a\:b:c\\d::\_resolved:x_`, resolved: `t:_resolved`},
+		{template: `cdc.{table}`, separator: `.`, reserved: isNATSReserved, err: `unknown placeholder {table}`},
+		{template: `cdc.{topic`, separator: `.`, reserved: isNATSReserved, err: `unterminated placeholder`},
+		{template: `cdc.*`, separator: `.`, reserved: isNATSReserved, err: `cannot contain "*"`},
+		{template: `cdc.>`, separator: `.`, reserved: isNATSReserved, err: `cannot contain ">"`},
+		{template: `cdc. {topic}`, separator: `.`, reserved: isNATSReserved, err: `cannot contain " "`},
+		{template: `cdc/#`, separator: `/`, reserved: isMQTTReserved, err: `cannot contain "#"`},
+		{template: ``, separator: `/`, reserved: isMQTTReserved, err: `empty topic template`},
+	} {
+		t.Run(tc.template, func(t *testing.T) {
+			tmpl, err := parseTopicTemplate(tc.template, tc.separator, tc.reserved)
+			if tc.err != `` {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			tmpl.escapeTokens = tc.escape
+			rendered, err := tmpl.render(tc.topic, []byte(tc.key))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, rendered)
+			require.Equal(t, tc.resolved, tmpl.renderResolved(tc.topic))
+		})
+	}
+
+	tmpl, err := parseTopicTemplate(`{key}`, `.`, isNATSReserved)
+	require.NoError(t, err)
+	_, err = tmpl.render(`t`, []byte(`not json`))
+	require.ErrorContains(t, err, `decoding key`)
+	_, err = tmpl.render(`t`, []byte(`{"a": 1}`))
+	require.ErrorContains(t, err, `expected key to be an array`)
+}
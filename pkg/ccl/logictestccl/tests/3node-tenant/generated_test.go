@@ -2846,6 +2846,13 @@ func TestTenantLogicCCL_vector(
 	runCCLLogicTest(t, "vector")
 }
 
+func TestTenantLogicCCL_vector_index(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "vector_index")
+}
+
 func TestTenantLogicCCL_zone_config_secondary_tenants(
 	t *testing.T,
 ) {
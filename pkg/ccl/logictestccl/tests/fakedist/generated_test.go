@@ -294,3 +294,10 @@ func TestCCLLogic_vector(
 	defer leaktest.AfterTest(t)()
 	runCCLLogicTest(t, "vector")
 }
+
+func TestCCLLogic_vector_index(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "vector_index")
+}
@@ -315,6 +315,13 @@ func TestReadCommittedLogicCCL_vector(
 	runCCLLogicTest(t, "vector")
 }
 
+func TestReadCommittedLogicCCL_vector_index(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runCCLLogicTest(t, "vector_index")
+}
+
 func TestReadCommittedExecBuild_explain_analyze_read_committed(
 	t *testing.T,
 ) {
@@ -13,6 +13,7 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	crypto_rand "crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
@@ -29,8 +30,9 @@ import (
 
 // The following helpers are intended for use in creating and reading encrypted
 // files in BACKUPs. Encryption is done using AES-GCM with a key derived from
-// the provided passphrase. Individual files are always written with a random IV
-// which is prefixed to the ciphertext for retrieval and use by decrypt. Helpers
+// the provided passphrase. Individual files are written with a random IV, or
+// one derived from their plaintext if they are encrypted convergently, which is
+// prefixed to the ciphertext for retrieval and use by decrypt. Helpers
 // are included for deriving a key from a salt and passphrase though the caller
 // is responsible for remembering the salt to rederive that key later.
 
@@ -115,9 +117,46 @@ func EncryptFile(plaintext, key []byte) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// EncryptFileConvergently encrypts a file with the supplied key like
+// EncryptFile, but with an IV derived from the key and the plaintext instead of
+// a random one, so that a plaintext is always encrypted to the same ciphertext
+// under a key. An IV is thus only reused to encrypt the same plaintext, which
+// reveals nothing but the fact that the plaintexts are equal. It is used by
+// backups that are deduplicated by the hash of the ciphertext of their files.
+func EncryptFileConvergently(plaintext, key []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, key)
+	// Writes to a hash never fail.
+	_, _ = mac.Write(plaintext)
+	iv := mac.Sum(nil)[:nonceSize]
+
+	b := &bytes.Buffer{}
+	w, err := encryptingWriterWithIV(NopCloser{b}, key, iv)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 // EncryptingWriter returns a writer that wraps an underlying sink writer but
 // which encrypts bytes written to it before flushing them to the wrapped sink.
 func EncryptingWriter(ciphertext io.WriteCloser, key []byte) (io.WriteCloser, error) {
+	// Pick a unique IV for this file.
+	iv := make([]byte, nonceSize)
+	if _, err := crypto_rand.Read(iv); err != nil {
+		return nil, err
+	}
+	return encryptingWriterWithIV(ciphertext, key, iv)
+}
+
+func encryptingWriterWithIV(
+	ciphertext io.WriteCloser, key []byte, iv []byte,
+) (io.WriteCloser, error) {
 	gcm, err := aesgcm(key)
 	if err != nil {
 		return nil, err
@@ -127,12 +166,8 @@ func EncryptingWriter(ciphertext io.WriteCloser, key []byte) (io.WriteCloser, er
 	copy(header, encryptionPreamble)
 	header[len(encryptionPreamble)] = encryptionVersionChunk
 
-	// Pick a unique IV for this file and write it in the header.
+	// Write the IV for this file in the header.
 	ivStart := len(encryptionPreamble) + 1
-	iv := make([]byte, nonceSize)
-	if _, err := crypto_rand.Read(iv); err != nil {
-		return nil, err
-	}
 	copy(header[ivStart:], iv)
 
 	// Write our header (preamble+version+IV) to the ciphertext sink.
@@ -53,6 +53,32 @@ func TestEncryptDecrypt(t *testing.T) {
 		}
 	})
 
+	t.Run("EncryptFileConvergently", func(t *testing.T) {
+		plaintext := bytes.Repeat([]byte("hello world\n"), 100)
+		ciphertext, err := EncryptFileConvergently(plaintext, key)
+		require.NoError(t, err)
+		require.True(t, AppearsEncrypted(ciphertext), "cipher text should appear encrypted")
+
+		decrypted, err := DecryptFile(context.Background(), ciphertext, key, mon.NewStandaloneUnlimitedAccount())
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+
+		// The same plaintext is encrypted to the same ciphertext under a key, but
+		// not under another key, and other plaintexts use other IVs.
+		again, err := EncryptFileConvergently(plaintext, key)
+		require.NoError(t, err)
+		require.Equal(t, ciphertext, again)
+
+		otherKey := GenerateKey([]byte("another key"), salt)
+		other, err := EncryptFileConvergently(plaintext, otherKey)
+		require.NoError(t, err)
+		require.NotEqual(t, ciphertext, other)
+
+		other, err = EncryptFileConvergently(append(plaintext, '!'), key)
+		require.NoError(t, err)
+		require.NotEqual(t, ciphertext[:headerSize], other[:headerSize])
+	})
+
 	t.Run("helpful error on bad input", func(t *testing.T) {
 		_, err := DecryptFile(context.Background(), []byte("a"), key, mon.NewStandaloneUnlimitedAccount())
 		require.EqualError(t, err, "file does not appear to be encrypted")
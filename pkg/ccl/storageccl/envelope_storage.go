@@ -0,0 +1,322 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"context"
+	"crypto/cipher"
+	crypto_rand "crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// Files written by an envelope encrypted storage start with an envelope that
+// holds the data key of the file encrypted by the KMS of the storage, followed
+// by the contents of the file encrypted with the data key in the chunked format
+// of EncryptingWriter:
+//
+//	envelopePreamble | envelopeVersion | len(wrapped key) (uint32) | wrapped key | ciphertext
+//
+// Since the ciphertext is made of chunks of a fixed size, a file can be read
+// from an offset by reading its envelope, and then only the ciphertext from the
+// chunk that contains the offset.
+var envelopePreamble = []byte("envelope")
+
+const envelopeVersion = 1
+
+// envelopeDataKeySize is the size of the data keys, which are AES-256 keys.
+const envelopeDataKeySize = 32
+
+// maxWrappedKeySize bounds the size of a wrapped key read from an envelope, to
+// avoid allocating an arbitrary amount of memory for a corrupt file.
+const maxWrappedKeySize = 64 << 10
+
+// envelopeReadHint is the length of the reads of the envelope alone, which is
+// larger than the envelopes written with the wrapped keys of the supported
+// KMSes.
+const envelopeReadHint = 4 << 10
+
+// envelopeStorage is an ExternalStorage that encrypts every file written to
+// the storage it wraps with a data key of its own, and decrypts files when they
+// are read.
+type envelopeStorage struct {
+	cloud.ExternalStorage
+
+	conf *cloudpb.ExternalStorage_EnvelopeEncryption
+	kms  cloud.KMS
+
+	mu struct {
+		syncutil.Mutex
+		// dataKeys caches the data keys decrypted by the KMS, by their encrypted
+		// value, since the same file is often read several times, at different
+		// offsets.
+		dataKeys map[string][]byte
+	}
+}
+
+var _ cloud.ExternalStorage = &envelopeStorage{}
+
+func makeEnvelopeStorage(
+	_ context.Context,
+	es cloud.ExternalStorage,
+	conf *cloudpb.ExternalStorage_EnvelopeEncryption,
+	kms cloud.KMS,
+) (cloud.ExternalStorage, error) {
+	s := &envelopeStorage{ExternalStorage: es, conf: conf, kms: kms}
+	s.mu.dataKeys = make(map[string][]byte)
+	return s, nil
+}
+
+// Conf implements cloud.ExternalStorage.
+func (s *envelopeStorage) Conf() cloudpb.ExternalStorage {
+	conf := s.ExternalStorage.Conf()
+	conf.EnvelopeEncryption = s.conf
+	return conf
+}
+
+// Writer implements cloud.ExternalStorage.
+func (s *envelopeStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	key := make([]byte, envelopeDataKeySize)
+	if _, err := crypto_rand.Read(key); err != nil {
+		return nil, err
+	}
+	wrappedKey, err := s.kms.Encrypt(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting data key")
+	}
+
+	envelope := make([]byte, 0, len(envelopePreamble)+1+4+len(wrappedKey))
+	envelope = append(envelope, envelopePreamble...)
+	envelope = append(envelope, envelopeVersion)
+	envelope = binary.BigEndian.AppendUint32(envelope, uint32(len(wrappedKey)))
+	envelope = append(envelope, wrappedKey...)
+
+	w, err := s.ExternalStorage.Writer(ctx, basename, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(envelope); err != nil {
+		return nil, errors.CombineErrors(err, w.Close())
+	}
+	ew, err := EncryptingWriter(w, key)
+	if err != nil {
+		return nil, errors.CombineErrors(err, w.Close())
+	}
+	return ew, nil
+}
+
+// ReadFile implements cloud.ExternalStorage.
+func (s *envelopeStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, _ error) {
+	chunkSize := int64(encryptionChunkSizeV2)
+	ciphertextChunkSize := chunkSize + tagSize
+	chunk := opts.Offset / chunkSize
+
+	readOpts := cloud.ReadOptions{NoFileSize: opts.NoFileSize}
+	if chunk > 0 {
+		// Only the envelope is read from this reader.
+		readOpts.LengthHint = envelopeReadHint
+	}
+	r, size, err := s.ExternalStorage.ReadFile(ctx, basename, readOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	gcm, iv, dataOffset, err := s.readEnvelope(ctx, r)
+	if err != nil {
+		return nil, 0, errors.CombineErrors(
+			errors.Wrapf(err, "reading encryption envelope of %s", basename), r.Close(ctx))
+	}
+	if !opts.NoFileSize {
+		size -= dataOffset
+		fileSize = size - tagSize*((size/ciphertextChunkSize)+1)
+	}
+
+	if chunk > 0 {
+		if err := r.Close(ctx); err != nil {
+			return nil, 0, err
+		}
+		readOpts := cloud.ReadOptions{Offset: dataOffset + chunk*ciphertextChunkSize, NoFileSize: true}
+		if opts.LengthHint > 0 {
+			chunks := (opts.Offset%chunkSize+opts.LengthHint)/chunkSize + 1
+			readOpts.LengthHint = chunks * ciphertextChunkSize
+		}
+		r, _, err = s.ExternalStorage.ReadFile(ctx, basename, readOpts)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	dr := &chunkDecryptingReader{
+		ciphertext: r,
+		gcm:        gcm,
+		iv:         iv,
+		buf:        make([]byte, ciphertextChunkSize),
+	}
+	binary.BigEndian.PutUint64(dr.iv[4:], binary.BigEndian.Uint64(dr.iv[4:])+uint64(chunk))
+	if skip := opts.Offset % chunkSize; skip > 0 {
+		if _, err := io.CopyN(io.Discard, ioctx.ReaderCtxAdapter(ctx, dr), skip); err != nil {
+			return nil, 0, errors.CombineErrors(err, dr.Close(ctx))
+		}
+	}
+	return dr, fileSize, nil
+}
+
+// readEnvelope reads the envelope and the encryption header at the start of a
+// file, and returns the cipher and the IV of the first chunk of the file, as
+// well as the offset of that chunk.
+func (s *envelopeStorage) readEnvelope(
+	ctx context.Context, r ioctx.ReaderCtx,
+) (_ cipher.AEAD, iv []byte, dataOffset int64, _ error) {
+	reader := ioctx.ReaderCtxAdapter(ctx, r)
+	prefix := make([]byte, len(envelopePreamble)+1+4)
+	if _, err := io.ReadFull(reader, prefix); err != nil {
+		return nil, nil, 0, errors.Wrap(err, "file does not appear to be encrypted")
+	}
+	if string(prefix[:len(envelopePreamble)]) != string(envelopePreamble) {
+		return nil, nil, 0, errors.New("file does not appear to be encrypted")
+	}
+	if version := prefix[len(envelopePreamble)]; version != envelopeVersion {
+		return nil, nil, 0, errors.Errorf("unexpected encryption envelope version %d", version)
+	}
+	keyLen := binary.BigEndian.Uint32(prefix[len(envelopePreamble)+1:])
+	if keyLen > maxWrappedKeySize {
+		return nil, nil, 0, errors.Errorf("invalid encrypted data key length %d", keyLen)
+	}
+	wrappedKey := make([]byte, keyLen)
+	if _, err := io.ReadFull(reader, wrappedKey); err != nil {
+		return nil, nil, 0, errors.Wrap(err, "reading encrypted data key")
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, 0, errors.Wrap(err, "invalid encryption header")
+	}
+	if !AppearsEncrypted(header) || header[len(encryptionPreamble)] != encryptionVersionChunk {
+		return nil, nil, 0, errors.New("invalid encryption header")
+	}
+
+	key, err := s.dataKey(ctx, wrappedKey)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	gcm, err := aesgcm(key)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	iv = append([]byte(nil), header[len(encryptionPreamble)+1:]...)
+	return gcm, iv, int64(len(prefix)) + int64(keyLen) + headerSize, nil
+}
+
+// dataKey decrypts a wrapped data key with the KMS of the storage.
+func (s *envelopeStorage) dataKey(ctx context.Context, wrappedKey []byte) ([]byte, error) {
+	s.mu.Lock()
+	key, ok := s.mu.dataKeys[string(wrappedKey)]
+	s.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	key, err := s.kms.Decrypt(ctx, wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data key")
+	}
+	s.mu.Lock()
+	s.mu.dataKeys[string(wrappedKey)] = key
+	s.mu.Unlock()
+	return key, nil
+}
+
+// Size implements cloud.ExternalStorage.
+func (s *envelopeStorage) Size(ctx context.Context, basename string) (int64, error) {
+	r, size, err := s.ReadFile(ctx, basename, cloud.ReadOptions{LengthHint: 1})
+	if err != nil {
+		return 0, err
+	}
+	return size, r.Close(ctx)
+}
+
+// ObjectLockConfiguration implements cloud.ObjectLockingStorage.
+func (s *envelopeStorage) ObjectLockConfiguration(
+	ctx context.Context,
+) (cloud.ObjectLockConfiguration, error) {
+	return cloud.GetObjectLockConfiguration(ctx, s.ExternalStorage)
+}
+
+// Close implements cloud.ExternalStorage.
+func (s *envelopeStorage) Close() error {
+	return errors.CombineErrors(s.ExternalStorage.Close(), s.kms.Close())
+}
+
+// chunkDecryptingReader decrypts the chunks of a file written by
+// EncryptingWriter as it reads them from a stream of ciphertext.
+type chunkDecryptingReader struct {
+	ciphertext ioctx.ReadCloserCtx
+	gcm        cipher.AEAD
+	// iv is the IV of the next chunk.
+	iv  []byte
+	buf []byte
+	// plaintext is the part of the current chunk that has not been read yet.
+	plaintext []byte
+	// eof is set once the last chunk, which is shorter than a full chunk, has
+	// been decrypted.
+	eof bool
+}
+
+var _ ioctx.ReadCloserCtx = &chunkDecryptingReader{}
+
+// Read implements ioctx.ReaderCtx.
+func (r *chunkDecryptingReader) Read(ctx context.Context, p []byte) (int, error) {
+	for len(r.plaintext) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		if err := r.nextChunk(ctx); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.plaintext)
+	r.plaintext = r.plaintext[n:]
+	return n, nil
+}
+
+func (r *chunkDecryptingReader) nextChunk(ctx context.Context) error {
+	n, err := io.ReadFull(ioctx.ReaderCtxAdapter(ctx, r.ciphertext), r.buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	// Every file ends with a sealed chunk shorter than a full chunk, which may be
+	// empty but always has a tag, so a file that ends without one was truncated.
+	if n < tagSize {
+		return errors.New("encrypted file is truncated")
+	}
+	plaintext, err := r.gcm.Open(r.buf[:0], r.iv, r.buf[:n], nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt — maybe incorrect key")
+	}
+	binary.BigEndian.PutUint64(r.iv[4:], binary.BigEndian.Uint64(r.iv[4:])+1)
+	r.plaintext = plaintext
+	r.eof = len(plaintext) < encryptionChunkSizeV2
+	return nil
+}
+
+// Close implements ioctx.ReadCloserCtx.
+func (r *chunkDecryptingReader) Close(ctx context.Context) error {
+	return r.ciphertext.Close(ctx)
+}
+
+func init() {
+	cloud.RegisterEnvelopeEncryptionWrapper(makeEnvelopeStorage)
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package storageccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/blobs"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// envelopeTestKMS "encrypts" data keys by prefixing them with its URI.
+type envelopeTestKMS struct {
+	uri string
+}
+
+var _ cloud.KMS = &envelopeTestKMS{}
+
+func (k *envelopeTestKMS) MasterKeyID() string { return k.uri }
+
+func (k *envelopeTestKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	return append([]byte(k.uri), data...), nil
+}
+
+func (k *envelopeTestKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(k.uri)) {
+		return nil, errors.New("data key was not encrypted by this KMS")
+	}
+	return data[len(k.uri):], nil
+}
+
+func (k *envelopeTestKMS) Close() error { return nil }
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(func(_ context.Context, uri string, _ cloud.KMSEnv) (cloud.KMS, error) {
+		return &envelopeTestKMS{uri: uri}, nil
+	}, "envelopetestkms")
+}
+
+func TestEnvelopeStorage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	rng, _ := randutil.NewTestRand()
+
+	defer func(chunkSize int) { encryptionChunkSizeV2 = chunkSize }(encryptionChunkSizeV2)
+	encryptionChunkSizeV2 = 64
+
+	settings := cluster.MakeTestingClusterSettings()
+	settings.ExternalIODir = t.TempDir()
+	openStore := func(uri string) cloud.ExternalStorage {
+		s, err := cloud.ExternalStorageFromURI(ctx, uri, base.ExternalIODirConfig{}, settings,
+			blobs.TestBlobServiceClient(settings.ExternalIODir), username.RootUserName(),
+			nil /* db */, nil /* limiters */, cloud.NilMetrics)
+		require.NoError(t, err)
+		return s
+	}
+	withKMS := func(uri, kmsURI string) string {
+		return fmt.Sprintf("%s?%s=%s", uri, cloud.EnvelopeEncryptionKMSParam, url.QueryEscape(kmsURI))
+	}
+
+	const dir = "nodelocal://1/envelope"
+	store := openStore(withKMS(dir, "envelopetestkms:///key1"))
+	defer store.Close()
+	raw := openStore(dir)
+	defer raw.Close()
+
+	require.NotNil(t, store.Conf().EnvelopeEncryption)
+	require.Equal(t, "envelopetestkms:///key1", store.Conf().EnvelopeEncryption.KMSURI)
+
+	readAll := func(s cloud.ExternalStorage, name string, opts cloud.ReadOptions) ([]byte, int64, error) {
+		r, size, err := s.ReadFile(ctx, name, opts)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer r.Close(ctx)
+		data, err := ioctx.ReadAll(ctx, r)
+		return data, size, err
+	}
+
+	for _, size := range []int{0, 1, 63, 64, 65, 128, 1000} {
+		t.Run(fmt.Sprintf("size=%d", size), func(t *testing.T) {
+			name := fmt.Sprintf("file-%d", size)
+			plaintext := randutil.RandBytes(rng, size)
+			require.NoError(t, cloud.WriteFile(ctx, store, name, bytes.NewReader(plaintext)))
+
+			// The stored file is encrypted.
+			ciphertext, _, err := readAll(raw, name, cloud.ReadOptions{NoFileSize: true})
+			require.NoError(t, err)
+			require.True(t, bytes.HasPrefix(ciphertext, envelopePreamble))
+			if size > 0 {
+				require.False(t, bytes.Contains(ciphertext, plaintext))
+			}
+
+			sz, err := store.Size(ctx, name)
+			require.NoError(t, err)
+			require.Equal(t, int64(size), sz)
+
+			for _, offset := range []int{0, 1, 63, 64, 65, 500, size - 1, size} {
+				if offset < 0 || offset > size {
+					continue
+				}
+				data, fileSize, err := readAll(store, name, cloud.ReadOptions{Offset: int64(offset)})
+				require.NoError(t, err)
+				require.Equal(t, int64(size), fileSize)
+				require.Equal(t, plaintext[offset:], data, "offset %d", offset)
+			}
+		})
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		plaintext := randutil.RandBytes(rng, 200)
+		require.NoError(t, cloud.WriteFile(ctx, store, "truncated", bytes.NewReader(plaintext)))
+		ciphertext, _, err := readAll(raw, "truncated", cloud.ReadOptions{NoFileSize: true})
+		require.NoError(t, err)
+		// Remove the final chunk of the file.
+		truncated := ciphertext[:len(ciphertext)-(200%64+tagSize)]
+		require.NoError(t, cloud.WriteFile(ctx, raw, "truncated", bytes.NewReader(truncated)))
+		_, _, err = readAll(store, "truncated", cloud.ReadOptions{NoFileSize: true})
+		require.ErrorContains(t, err, "truncated")
+	})
+
+	t.Run("wrong-key", func(t *testing.T) {
+		require.NoError(t, cloud.WriteFile(ctx, store, "wrong-key", bytes.NewReader([]byte("hello"))))
+		other := openStore(withKMS(dir, "envelopetestkms:///key2"))
+		defer other.Close()
+		_, _, err := readAll(other, "wrong-key", cloud.ReadOptions{NoFileSize: true})
+		require.ErrorContains(t, err, "decrypting data key")
+	})
+
+	t.Run("unencrypted", func(t *testing.T) {
+		require.NoError(t, cloud.WriteFile(ctx, raw, "unencrypted", bytes.NewReader([]byte("hello"))))
+		_, _, err := readAll(store, "unencrypted", cloud.ReadOptions{NoFileSize: true})
+		require.ErrorContains(t, err, "does not appear to be encrypted")
+	})
+
+	t.Run("mixed-version", func(t *testing.T) {
+		st := cluster.MakeTestingClusterSettingsWithVersions(
+			clusterversion.Latest.Version(), clusterversion.MinSupported.Version(), false, /* initializeVersion */
+		)
+		require.NoError(t, clusterversion.Initialize(ctx, clusterversion.MinSupported.Version(), &st.SV))
+		st.ExternalIODir = settings.ExternalIODir
+		_, err := cloud.ExternalStorageFromURI(ctx, withKMS(dir, "envelopetestkms:///key1"),
+			base.ExternalIODirConfig{}, st, blobs.TestBlobServiceClient(st.ExternalIODir),
+			username.RootUserName(), nil /* db */, nil /* limiters */, cloud.NilMetrics)
+		require.ErrorContains(t, err, "only supported after v24.2 upgrade is finalized")
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		redacted, err := cloud.SanitizeExternalStorageURI(
+			withKMS(dir, "aws-kms:///key?AWS_SECRET_ACCESS_KEY=secret"), nil)
+		require.NoError(t, err)
+		require.NotContains(t, redacted, "secret")
+	})
+}
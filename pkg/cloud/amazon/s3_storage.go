@@ -70,6 +70,11 @@ const (
 	// storage class for written objects.
 	S3StorageClassParam = "S3_STORAGE_CLASS"
 
+	// S3ObjectLockModeParam is the query parameter used in S3 URIs to configure
+	// the object lock mode of the objects written with a retention. It can
+	// either be GOVERNANCE or COMPLIANCE, which is the default.
+	S3ObjectLockModeParam = "S3_OBJECT_LOCK_MODE"
+
 	// S3RegionParam is the query parameter for the 'endpoint' in an S3 URI.
 	S3RegionParam = "AWS_REGION"
 
@@ -247,6 +252,7 @@ var s3ClientCache struct {
 }
 
 var _ cloud.ExternalStorage = &s3Storage{}
+var _ cloud.ObjectLockingStorage = &s3Storage{}
 
 type serverSideEncMode string
 
@@ -272,6 +278,7 @@ func S3URI(bucket, path string, conf *cloudpb.ExternalStorage_S3) string {
 	setIf(AWSServerSideEncryptionMode, conf.ServerEncMode)
 	setIf(AWSServerSideEncryptionKMSID, conf.ServerKMSID)
 	setIf(S3StorageClassParam, conf.StorageClass)
+	setIf(S3ObjectLockModeParam, conf.ObjectLockMode)
 	if conf.AssumeRoleProvider.Role != "" {
 		roleProviderStrings := make([]string, 0, len(conf.DelegateRoleProviders)+1)
 		for _, p := range conf.DelegateRoleProviders {
@@ -320,6 +327,7 @@ func parseS3URL(uri *url.URL) (cloudpb.ExternalStorage, error) {
 		ServerEncMode:         s3URL.ConsumeParam(AWSServerSideEncryptionMode),
 		ServerKMSID:           s3URL.ConsumeParam(AWSServerSideEncryptionKMSID),
 		StorageClass:          s3URL.ConsumeParam(S3StorageClassParam),
+		ObjectLockMode:        s3URL.ConsumeParam(S3ObjectLockModeParam),
 		RoleARN:               assumeRole,
 		DelegateRoleARNs:      delegateRoles,
 		AssumeRoleProvider:    assumeRoleProvider,
@@ -383,6 +391,14 @@ func parseS3URL(uri *url.URL) (cloudpb.ExternalStorage, error) {
 		}
 	}
 
+	switch conf.S3Config.ObjectLockMode {
+	case "", s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance:
+	default:
+		return cloudpb.ExternalStorage{}, errors.Newf("unsupported object lock mode %s. "+
+			"Supported values are `%s` and `%s`.", conf.S3Config.ObjectLockMode,
+			s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance)
+	}
+
 	return conf, nil
 }
 
@@ -661,7 +677,9 @@ func (u *putUploader) Close() error {
 	return err
 }
 
-func (s *s3Storage) putUploader(ctx context.Context, basename string) (io.WriteCloser, error) {
+func (s *s3Storage) putUploader(
+	ctx context.Context, basename string, opts cloud.WriteOptions,
+) (io.WriteCloser, error) {
 	client, err := s.getClient(ctx)
 	if err != nil {
 		return nil, err
@@ -669,22 +687,48 @@ func (s *s3Storage) putUploader(ctx context.Context, basename string) (io.WriteC
 
 	buf := bytes.NewBuffer(make([]byte, 0, 4<<20))
 
+	lockMode, retainUntil := s.objectLockHeaders(opts)
 	return &putUploader{
 		b: buf,
 		input: &s3.PutObjectInput{
-			Bucket:               s.bucket,
-			Key:                  aws.String(path.Join(s.prefix, basename)),
-			ServerSideEncryption: nilIfEmpty(s.conf.ServerEncMode),
-			SSEKMSKeyId:          nilIfEmpty(s.conf.ServerKMSID),
-			StorageClass:         nilIfEmpty(s.conf.StorageClass),
+			Bucket:                    s.bucket,
+			Key:                       aws.String(path.Join(s.prefix, basename)),
+			ServerSideEncryption:      nilIfEmpty(s.conf.ServerEncMode),
+			SSEKMSKeyId:               nilIfEmpty(s.conf.ServerKMSID),
+			StorageClass:              nilIfEmpty(s.conf.StorageClass),
+			ObjectLockMode:            lockMode,
+			ObjectLockRetainUntilDate: retainUntil,
 		},
 		client: client,
 	}, nil
 }
 
-func (s *s3Storage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+// objectLockHeaders returns the object lock mode and retain-until date to set
+// on an uploaded object, if the options request a retention. Objects are locked
+// in compliance mode, so that no user can shorten or remove their retention,
+// unless the URI selects governance mode, which lets users with the
+// s3:BypassGovernanceRetention permission do so. S3 requires a Content-MD5
+// header on the uploads of locked objects, which the SDK computes for both
+// PutObject and the parts of multipart uploads.
+func (s *s3Storage) objectLockHeaders(
+	opts cloud.WriteOptions,
+) (mode *string, retainUntil *time.Time) {
+	if opts.RetainUntil.IsZero() {
+		return nil, nil
+	}
+	lockMode := s.conf.ObjectLockMode
+	if lockMode == "" {
+		lockMode = s3.ObjectLockModeCompliance
+	}
+	return aws.String(lockMode), aws.Time(opts.RetainUntil)
+}
+
+func (s *s3Storage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	writeOpts := cloud.MakeWriteOptions(opts...)
 	if usePutObject.Get(&s.settings.SV) {
-		return s.putUploader(ctx, basename)
+		return s.putUploader(ctx, basename, writeOpts)
 	}
 
 	uploader, err := s.getUploader(ctx)
@@ -698,13 +742,16 @@ func (s *s3Storage) Writer(ctx context.Context, basename string) (io.WriteCloser
 		defer sp.Finish()
 		// Upload the file to S3.
 		// TODO(dt): test and tune the uploader parameters.
+		lockMode, retainUntil := s.objectLockHeaders(writeOpts)
 		_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
-			Bucket:               s.bucket,
-			Key:                  aws.String(path.Join(s.prefix, basename)),
-			Body:                 r,
-			ServerSideEncryption: nilIfEmpty(s.conf.ServerEncMode),
-			SSEKMSKeyId:          nilIfEmpty(s.conf.ServerKMSID),
-			StorageClass:         nilIfEmpty(s.conf.StorageClass),
+			Bucket:                    s.bucket,
+			Key:                       aws.String(path.Join(s.prefix, basename)),
+			Body:                      r,
+			ServerSideEncryption:      nilIfEmpty(s.conf.ServerEncMode),
+			SSEKMSKeyId:               nilIfEmpty(s.conf.ServerKMSID),
+			StorageClass:              nilIfEmpty(s.conf.StorageClass),
+			ObjectLockMode:            lockMode,
+			ObjectLockRetainUntilDate: retainUntil,
 		})
 		err = interpretAWSError(err)
 		err = errors.Wrap(err, "upload failed")
@@ -960,6 +1007,44 @@ func (s *s3Storage) Size(ctx context.Context, basename string) (int64, error) {
 	return *out.ContentLength, nil
 }
 
+// ObjectLockConfiguration implements the cloud.ObjectLockingStorage interface.
+func (s *s3Storage) ObjectLockConfiguration(
+	ctx context.Context,
+) (cloud.ObjectLockConfiguration, error) {
+	client, err := s.getClient(ctx)
+	if err != nil {
+		return cloud.ObjectLockConfiguration{}, err
+	}
+	var out *s3.GetObjectLockConfigurationOutput
+	err = timeutil.RunWithTimeout(ctx, "get s3 object lock configuration",
+		cloud.Timeout.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			var err error
+			out, err = client.GetObjectLockConfigurationWithContext(ctx,
+				&s3.GetObjectLockConfigurationInput{Bucket: s.bucket})
+			return err
+		})
+	if err != nil {
+		if aerr := (awserr.Error)(nil); errors.As(err, &aerr) &&
+			aerr.Code() == "ObjectLockConfigurationNotFoundError" {
+			return cloud.ObjectLockConfiguration{}, nil
+		}
+		return cloud.ObjectLockConfiguration{}, errors.Wrap(interpretAWSError(err),
+			"failed to get s3 object lock configuration")
+	}
+	lockConf := out.ObjectLockConfiguration
+	if lockConf == nil || aws.StringValue(lockConf.ObjectLockEnabled) != s3.ObjectLockEnabledEnabled {
+		return cloud.ObjectLockConfiguration{}, nil
+	}
+	conf := cloud.ObjectLockConfiguration{Enabled: true, PerObjectRetention: true}
+	if lockConf.Rule != nil && lockConf.Rule.DefaultRetention != nil {
+		retention := lockConf.Rule.DefaultRetention
+		conf.DefaultRetention = time.Duration(aws.Int64Value(retention.Days))*24*time.Hour +
+			time.Duration(aws.Int64Value(retention.Years))*365*24*time.Hour
+	}
+	return conf, nil
+}
+
 func (s *s3Storage) Close() error {
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -32,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
@@ -209,6 +211,45 @@ func TestPutS3(t *testing.T) {
 				_, err = makeS3Storage(ctx, invalidKMSURI, user)
 				require.True(t, testutils.IsError(err, "AWS_SERVER_KMS_ID param must be set when using aws:kms server side encryption mode."))
 			})
+
+			t.Run("object-lock", func(t *testing.T) {
+				uri := S3URI(bucket, fmt.Sprintf("backup-test-object-lock-%d", testID),
+					&cloudpb.ExternalStorage_S3{AccessKey: creds.AccessKeyID, Secret: creds.SecretAccessKey, Region: "us-east-1"},
+				)
+				s, err := makeS3Storage(ctx, uri, user)
+				require.NoError(t, err)
+				defer s.Close()
+
+				lockConf, err := cloud.GetObjectLockConfiguration(ctx, s)
+				require.NoError(t, err)
+				require.Equal(t, locked, lockConf.Enabled)
+				require.Equal(t, locked, lockConf.PerObjectRetention)
+
+				// Objects locked in compliance mode cannot be deleted by anyone until
+				// their retention expires, so only lock the test object briefly.
+				err = cloud.WriteFile(ctx, s, "locked", strings.NewReader("data"),
+					cloud.WithRetainUntil(timeutil.Now().Add(time.Minute)))
+				if locked {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+
+				governanceURI := S3URI(bucket, fmt.Sprintf("backup-test-object-lock-%d", testID),
+					&cloudpb.ExternalStorage_S3{AccessKey: creds.AccessKeyID, Secret: creds.SecretAccessKey,
+						Region: "us-east-1", ObjectLockMode: s3.ObjectLockModeGovernance},
+				)
+				governance, err := makeS3Storage(ctx, governanceURI, user)
+				require.NoError(t, err)
+				defer governance.Close()
+				err = cloud.WriteFile(ctx, governance, "locked-governance", strings.NewReader("data"),
+					cloud.WithRetainUntil(timeutil.Now().Add(time.Minute)))
+				if locked {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+			})
 		})
 
 	}
@@ -382,6 +423,33 @@ func TestPutS3Endpoint(t *testing.T) {
 	)
 }
 
+func TestParseS3ObjectLockMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		mode string
+		err  string
+	}{
+		{mode: ""},
+		{mode: s3.ObjectLockModeGovernance},
+		{mode: s3.ObjectLockModeCompliance},
+		{mode: "legal-hold", err: "unsupported object lock mode legal-hold"},
+	} {
+		uri := S3URI("bucket", "backup", &cloudpb.ExternalStorage_S3{
+			Auth: cloud.AuthParamImplicit, ObjectLockMode: tc.mode,
+		})
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		conf, err := parseS3URL(u)
+		if tc.err != "" {
+			require.ErrorContains(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.mode, conf.S3Config.ObjectLockMode)
+	}
+}
+
 func TestS3DisallowCustomEndpoints(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -207,6 +207,7 @@ type azureStorage struct {
 }
 
 var _ cloud.ExternalStorage = &azureStorage{}
+var _ cloud.ObjectLockingStorage = &azureStorage{}
 
 func makeAzureStorage(
 	_ context.Context, args cloud.EarlyBootExternalStorageContext, dest cloudpb.ExternalStorage,
@@ -305,17 +306,30 @@ func (s *azureStorage) Settings() *cluster.Settings {
 	return s.settings
 }
 
-func (s *azureStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+// Writer implements the cloud.ExternalStorage interface. The streaming upload
+// API cannot set an immutability policy, so a requested retention is set on the
+// blob once it has been uploaded, which requires the container to have
+// version-level immutability support enabled.
+func (s *azureStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	writeOpts := cloud.MakeWriteOptions(opts...)
 	ctx, sp := tracing.ChildSpan(ctx, "azure.Writer")
 	sp.SetTag("path", attribute.StringValue(path.Join(s.prefix, basename)))
-	blob := s.getBlob(basename)
+	blobClient := s.getBlob(basename)
 	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
 		defer sp.Finish()
-		_, err := blob.UploadStream(ctx, r, &azblob.UploadStreamOptions{
+		_, err := blobClient.UploadStream(ctx, r, &azblob.UploadStreamOptions{
 			BlockSize:   cloud.WriteChunkSize.Get(&s.settings.SV),
 			Concurrency: int(maxConcurrentUploadBuffers.Get(&s.settings.SV)),
 		})
-		return err
+		if err != nil || writeOpts.RetainUntil.IsZero() {
+			return err
+		}
+		mode := blob.ImmutabilityPolicySettingLocked
+		_, err = blobClient.SetImmutabilityPolicy(ctx, writeOpts.RetainUntil,
+			&blob.SetImmutabilityPolicyOptions{Mode: &mode})
+		return errors.Wrap(err, "setting immutability policy of azure blob")
 	}), nil
 }
 
@@ -407,6 +421,32 @@ func (s *azureStorage) Size(ctx context.Context, basename string) (int64, error)
 }
 
 // Close is part of the cloud.ExternalStorage interface.
+// ObjectLockConfiguration implements the cloud.ObjectLockingStorage interface.
+// Only containers with version-level immutability support enabled allow
+// setting the immutability policies of individual blobs. The period of a
+// container-level policy cannot be read through the blob service, so the
+// objects of containers that only have such a policy are not considered to be
+// locked.
+func (s *azureStorage) ObjectLockConfiguration(
+	ctx context.Context,
+) (cloud.ObjectLockConfiguration, error) {
+	var props container.GetPropertiesResponse
+	err := timeutil.RunWithTimeout(ctx, "get azure container properties",
+		cloud.Timeout.Get(&s.settings.SV),
+		func(ctx context.Context) error {
+			var err error
+			props, err = s.container.GetProperties(ctx, nil)
+			return err
+		})
+	if err != nil {
+		return cloud.ObjectLockConfiguration{}, errors.Wrap(err, "failed to get azure container properties")
+	}
+	if props.IsImmutableStorageWithVersioningEnabled == nil || !*props.IsImmutableStorageWithVersioningEnabled {
+		return cloud.ObjectLockConfiguration{}, nil
+	}
+	return cloud.ObjectLockConfiguration{Enabled: true, PerObjectRetention: true}, nil
+}
+
 func (s *azureStorage) Close() error {
 	return nil
 }
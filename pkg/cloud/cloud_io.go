@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
@@ -413,7 +414,9 @@ func (s *backgroundPipe) Close() error {
 
 // WriteFile is a helper for writing the content of a Reader to the given path
 // of an ExternalStorage.
-func WriteFile(ctx context.Context, dest ExternalStorage, basename string, src io.Reader) error {
+func WriteFile(
+	ctx context.Context, dest ExternalStorage, basename string, src io.Reader, opts ...WriteOption,
+) error {
 	var span *tracing.Span
 	ctx, span = tracing.ChildSpan(ctx, fmt.Sprintf("%s.WriteFile", dest.Conf().Provider.String()))
 	defer span.Finish()
@@ -422,7 +425,7 @@ func WriteFile(ctx context.Context, dest ExternalStorage, basename string, src i
 	ctx, cancel = context.WithCancel(ctx)
 	defer cancel()
 
-	w, err := dest.Writer(ctx, basename)
+	w, err := dest.Writer(ctx, basename, opts...)
 	if err != nil {
 		return errors.Wrap(err, "opening object for writing")
 	}
@@ -433,3 +436,34 @@ func WriteFile(ctx context.Context, dest ExternalStorage, basename string, src i
 	}
 	return errors.Wrap(w.Close(), "closing object")
 }
+
+// MakeWriteOptions returns the WriteOptions set by the given options.
+func MakeWriteOptions(opts ...WriteOption) WriteOptions {
+	var o WriteOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// CheckRetentionUnsupported returns an error if the given options of a call to
+// Writer request a retention, for the ExternalStorages that cannot lock the
+// objects they write.
+func CheckRetentionUnsupported(provider cloudpb.ExternalStorageProvider, opts []WriteOption) error {
+	if o := MakeWriteOptions(opts...); !o.RetainUntil.IsZero() {
+		return errors.Newf("%s storage does not support locking objects for a retention period", provider)
+	}
+	return nil
+}
+
+// GetObjectLockConfiguration returns the ObjectLockConfiguration of the given
+// ExternalStorage. Storages which do not implement ObjectLockingStorage cannot
+// lock objects.
+func GetObjectLockConfiguration(
+	ctx context.Context, es ExternalStorage,
+) (ObjectLockConfiguration, error) {
+	if l, ok := es.(ObjectLockingStorage); ok {
+		return l.ObjectLockConfiguration(ctx)
+	}
+	return ObjectLockConfiguration{}, nil
+}
@@ -48,7 +48,7 @@ func (m *ExternalStorage) AccessIsWithExplicitAuth() bool {
 		return true
 	case ExternalStorageProvider_null:
 		return true
-	case ExternalStorageProvider_http:
+	case ExternalStorageProvider_http, ExternalStorageProvider_sftp:
 		// Arbitrary network endpoints may be accessible only via the node and thus
 		// make use of its implicit access to them.
 		return false
@@ -12,6 +12,7 @@ package cloudprivilege
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
@@ -22,6 +23,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
 )
 
+// externalConnectionScheme is the scheme of the URIs of External Connections.
+const externalConnectionScheme = "external"
+
 // CheckDestinationPrivileges iterates over the External Storage URIs and
 // ensures the user has adequate privileges to use each of them.
 func CheckDestinationPrivileges(ctx context.Context, p sql.PlanHookState, to []string) error {
@@ -43,6 +47,19 @@ func CheckDestinationPrivileges(ctx context.Context, p sql.PlanHookState, to []s
 		// Check if the destination requires the user to be an admin or have the
 		// `EXTERNALIOIMPLICITACCESS` privilege.
 		requiresImplicitAccess := !conf.AccessIsWithExplicitAuth()
+		var kmsConnection string
+		if conf.EnvelopeEncryption != nil {
+			kmsURI, err := url.Parse(conf.EnvelopeEncryption.KMSURI)
+			if err != nil {
+				return err
+			}
+			if kmsURI.Query().Get(cloud.AuthParam) == cloud.AuthParamImplicit {
+				requiresImplicitAccess = true
+			}
+			if kmsURI.Scheme == externalConnectionScheme {
+				kmsConnection = kmsURI.Host
+			}
+		}
 		hasImplicitAccessPrivilege, privErr :=
 			p.HasPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.EXTERNALIOIMPLICITACCESS, p.User())
 		if privErr != nil {
@@ -65,6 +82,14 @@ func CheckDestinationPrivileges(ctx context.Context, p sql.PlanHookState, to []s
 				return err
 			}
 		}
+		if kmsConnection != "" {
+			ecPrivilege := &syntheticprivilege.ExternalConnectionPrivilege{
+				ConnectionName: kmsConnection,
+			}
+			if err := p.CheckPrivilege(ctx, ecPrivilege, privilege.USAGE); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
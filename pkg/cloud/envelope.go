@@ -0,0 +1,122 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// EnvelopeEncryptionKMSParam is the query parameter, accepted by every
+// external storage URI, for the URI of a KMS. If it is set, every file written
+// to the storage is encrypted with a data key of its own, which is stored in
+// the file after being encrypted by the KMS, and files are decrypted when they
+// are read.
+const EnvelopeEncryptionKMSParam = "ENCRYPTION_KMS"
+
+// EnvelopeEncryptionWrapper wraps an ExternalStorage so that the files written
+// to it are encrypted with data keys encrypted by the passed KMS. The returned
+// storage takes ownership of the KMS.
+type EnvelopeEncryptionWrapper func(
+	ctx context.Context, es ExternalStorage, conf *cloudpb.ExternalStorage_EnvelopeEncryption, kms KMS,
+) (ExternalStorage, error)
+
+var envelopeEncryptionWrapper EnvelopeEncryptionWrapper
+
+// RegisterEnvelopeEncryptionWrapper registers the implementation of the
+// envelope encryption of external storage.
+func RegisterEnvelopeEncryptionWrapper(fn EnvelopeEncryptionWrapper) {
+	envelopeEncryptionWrapper = fn
+}
+
+// consumeEnvelopeEncryptionParam removes EnvelopeEncryptionKMSParam from the
+// URI, so that the URI can be parsed by the parser of its scheme, and returns
+// the envelope encryption configuration it specifies, if any.
+func consumeEnvelopeEncryptionParam(
+	uri *url.URL, user username.SQLUsername,
+) *cloudpb.ExternalStorage_EnvelopeEncryption {
+	params := uri.Query()
+	kmsURI := params.Get(EnvelopeEncryptionKMSParam)
+	if kmsURI == "" {
+		return nil
+	}
+	params.Del(EnvelopeEncryptionKMSParam)
+	uri.RawQuery = params.Encode()
+	return &cloudpb.ExternalStorage_EnvelopeEncryption{KMSURI: kmsURI, User: user.Normalized()}
+}
+
+// envelopeKMSEnv is the KMSEnv in which the KMS of an envelope encrypted
+// storage is opened.
+type envelopeKMSEnv struct {
+	settings *cluster.Settings
+	conf     *base.ExternalIODirConfig
+	db       isql.DB
+	user     username.SQLUsername
+}
+
+var _ KMSEnv = &envelopeKMSEnv{}
+
+func (e *envelopeKMSEnv) ClusterSettings() *cluster.Settings   { return e.settings }
+func (e *envelopeKMSEnv) KMSConfig() *base.ExternalIODirConfig { return e.conf }
+func (e *envelopeKMSEnv) DBHandle() isql.DB                    { return e.db }
+func (e *envelopeKMSEnv) User() username.SQLUsername           { return e.user }
+
+// wrapWithEnvelopeEncryption wraps es with envelope encryption if conf is set.
+func wrapWithEnvelopeEncryption(
+	ctx context.Context,
+	es ExternalStorage,
+	conf *cloudpb.ExternalStorage_EnvelopeEncryption,
+	ioConf base.ExternalIODirConfig,
+	settings *cluster.Settings,
+	db isql.DB,
+) (ExternalStorage, error) {
+	if conf == nil {
+		return es, nil
+	}
+	// Nodes running an older binary ignore the envelope encryption of the
+	// storage, and would write its files in plaintext.
+	if !settings.Version.ActiveVersionOrEmpty(ctx).IsActive(clusterversion.V24_2_EnvelopeEncryption) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"the %s parameter is only supported after v24.2 upgrade is finalized",
+			EnvelopeEncryptionKMSParam)
+	}
+	if envelopeEncryptionWrapper == nil {
+		return nil, errors.Errorf("the %s parameter requires a CCL binary", EnvelopeEncryptionKMSParam)
+	}
+	kms, err := KMSFromURI(ctx, conf.KMSURI, &envelopeKMSEnv{
+		settings: settings,
+		conf:     &ioConf,
+		db:       db,
+		user:     username.MakeSQLUsernameFromPreNormalizedString(conf.User),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening the KMS of %s", EnvelopeEncryptionKMSParam)
+	}
+	wrapped, err := envelopeEncryptionWrapper(ctx, es, conf, kms)
+	if err != nil {
+		return nil, errors.CombineErrors(err, kms.Close())
+	}
+	return wrapped, nil
+}
+
+func init() {
+	RegisterRedactedParams(RedactedParams(EnvelopeEncryptionKMSParam))
+}
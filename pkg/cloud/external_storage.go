@@ -15,6 +15,7 @@ import (
 	"database/sql/driver"
 	"io"
 	"net/url"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/blobs"
@@ -75,7 +76,10 @@ type ExternalStorage interface {
 	// implementation may buffer written data until Close and only then return
 	// an error, or Write may return an opaque io.EOF with the underlying cause
 	// returned by the subsequent Close().
-	Writer(ctx context.Context, basename string) (io.WriteCloser, error)
+	//
+	// Implementations must return an error, rather than ignore the option, if
+	// they are passed a WriteOption that they cannot honor.
+	Writer(ctx context.Context, basename string, opts ...WriteOption) (io.WriteCloser, error)
 
 	// List enumerates files within the supplied prefix, calling the passed
 	// function with the name of each file found, relative to the external storage
@@ -109,6 +113,40 @@ type ReadOptions struct {
 	NoFileSize bool
 }
 
+// WriteOptions are the options of a call to ExternalStorage.Writer, which are
+// set by the WriteOption values passed to it.
+type WriteOptions struct {
+	// RetainUntil, if non-zero, is the time until which the written object must
+	// be protected against being deleted or overwritten. Storages without
+	// PerObjectRetention in their ObjectLockConfiguration can only honor it
+	// through their DefaultRetention, which callers must check covers it.
+	RetainUntil time.Time
+}
+
+// ObjectLockConfiguration describes whether, and for how long, the objects
+// written to an ExternalStorage are protected against being deleted or
+// overwritten.
+type ObjectLockConfiguration struct {
+	// Enabled is true if the objects written to the storage can be locked.
+	Enabled bool
+	// PerObjectRetention is true if the storage honors WithRetainUntil for
+	// arbitrary retention periods, as opposed to only the DefaultRetention.
+	PerObjectRetention bool
+	// DefaultRetention is the period for which objects written without an
+	// explicit retention are locked, or zero if they are not locked.
+	DefaultRetention time.Duration
+}
+
+// ObjectLockingStorage is implemented by the ExternalStorages which can report
+// their ObjectLockConfiguration.
+type ObjectLockingStorage interface {
+	ExternalStorage
+
+	// ObjectLockConfiguration returns the configuration of the locks of the
+	// objects written to the storage.
+	ObjectLockConfiguration(ctx context.Context) (ObjectLockConfiguration, error)
+}
+
 // ListingFn describes functions passed to ExternalStorage.ListFiles.
 type ListingFn func(string) error
 
@@ -113,6 +113,7 @@ type gcsStorage struct {
 }
 
 var _ cloud.ExternalStorage = &gcsStorage{}
+var _ cloud.ObjectLockingStorage = &gcsStorage{}
 
 func (g *gcsStorage) Conf() cloudpb.ExternalStorage {
 	return cloudpb.ExternalStorage{
@@ -269,7 +270,16 @@ func createImpersonateCredentials(
 	return option.WithTokenSource(source), nil
 }
 
-func (g *gcsStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+// Writer implements the cloud.ExternalStorage interface. GCS does not support
+// setting the retention of individual objects, so a requested retention is
+// honored by the retention policy of the bucket, which locks every object for
+// a period from its creation. Checking that the policy covers the retention
+// needs a bucket metadata request, so it is left to the callers, which check
+// the ObjectLockConfiguration once before writing their files, rather than
+// repeated for every object written.
+func (g *gcsStorage) Writer(
+	ctx context.Context, basename string, _ ...cloud.WriteOption,
+) (io.WriteCloser, error) {
 	_, sp := tracing.ChildSpan(ctx, "gcs.Writer")
 	defer sp.Finish()
 	sp.SetTag("path", attribute.StringValue(path.Join(g.prefix, basename)))
@@ -387,6 +397,30 @@ func (g *gcsStorage) Size(ctx context.Context, basename string) (int64, error) {
 	return sz, nil
 }
 
+// ObjectLockConfiguration implements the cloud.ObjectLockingStorage interface.
+func (g *gcsStorage) ObjectLockConfiguration(
+	ctx context.Context,
+) (cloud.ObjectLockConfiguration, error) {
+	var attrs *gcs.BucketAttrs
+	err := timeutil.RunWithTimeout(ctx, "get gcs bucket attributes",
+		cloud.Timeout.Get(&g.settings.SV),
+		func(ctx context.Context) error {
+			var err error
+			attrs, err = g.bucket.Attrs(ctx)
+			return err
+		})
+	if err != nil {
+		return cloud.ObjectLockConfiguration{}, errors.Wrap(err, "failed to get gcs bucket attributes")
+	}
+	if attrs.RetentionPolicy == nil || attrs.RetentionPolicy.RetentionPeriod == 0 {
+		return cloud.ObjectLockConfiguration{}, nil
+	}
+	return cloud.ObjectLockConfiguration{
+		Enabled:          true,
+		DefaultRetention: attrs.RetentionPolicy.RetentionPeriod,
+	}, nil
+}
+
 func (g *gcsStorage) Close() error {
 	return g.client.Close()
 }
@@ -167,7 +167,12 @@ func (h *httpStorage) ReadFile(
 	return ioctx.ReadCloserAdapter(stream.Body), size, nil
 }
 
-func (h *httpStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+func (h *httpStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	if err := cloud.CheckRetentionUnsupported(cloudpb.ExternalStorageProvider_http, opts); err != nil {
+		return nil, err
+	}
 	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
 		_, err := h.reqNoBody(ctx, "PUT", basename, r)
 		return err
@@ -197,7 +197,13 @@ func ExternalStorageConfFromURI(
 		return cloudpb.ExternalStorage{}, err
 	}
 	if fn, ok := confParsers[uri.Scheme]; ok {
-		return fn(ExternalStorageURIContext{CurrentUser: user}, uri)
+		envelope := consumeEnvelopeEncryptionParam(uri, user)
+		conf, err := fn(ExternalStorageURIContext{CurrentUser: user}, uri)
+		if err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		conf.EnvelopeEncryption = envelope
+		return conf, nil
 	}
 	// TODO(adityamaru): Link dedicated ExternalStorage scheme docs once ready.
 	return cloudpb.ExternalStorage{}, errors.Errorf("unsupported storage scheme: %q - refer to docs to find supported"+
@@ -272,7 +278,13 @@ func EarlyBootExternalStorageConfFromURI(path string) (cloudpb.ExternalStorage,
 		return cloudpb.ExternalStorage{}, err
 	}
 	if fn, ok := earlyBootConfParsers[uri.Scheme]; ok {
-		return fn(uri)
+		envelope := consumeEnvelopeEncryptionParam(uri, username.RootUserName())
+		conf, err := fn(uri)
+		if err != nil {
+			return cloudpb.ExternalStorage{}, err
+		}
+		conf.EnvelopeEncryption = envelope
+		return conf, nil
 	}
 	return cloudpb.ExternalStorage{}, errors.Errorf("unsupported storage scheme: %q - refer to docs to find supported storage schemes",
 		uri.Scheme)
@@ -425,12 +437,14 @@ func (e *esWrapper) List(ctx context.Context, prefix, delimiter string, fn Listi
 	return e.ExternalStorage.List(ctx, prefix, delimiter, countingFn)
 }
 
-func (e *esWrapper) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+func (e *esWrapper) Writer(
+	ctx context.Context, basename string, opts ...WriteOption,
+) (io.WriteCloser, error) {
 	if e.httpTracer != nil {
 		ctx = httptrace.WithClientTrace(ctx, e.httpTracer)
 	}
 
-	w, err := e.ExternalStorage.Writer(ctx, basename)
+	w, err := e.ExternalStorage.Writer(ctx, basename, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -438,6 +452,11 @@ func (e *esWrapper) Writer(ctx context.Context, basename string) (io.WriteCloser
 	return e.wrapWriter(ctx, w), nil
 }
 
+// ObjectLockConfiguration implements the ObjectLockingStorage interface.
+func (e *esWrapper) ObjectLockConfiguration(ctx context.Context) (ObjectLockConfiguration, error) {
+	return GetObjectLockConfiguration(ctx, e.ExternalStorage)
+}
+
 type limitedReader struct {
 	r    ioctx.ReadCloserCtx
 	lim  *quotapool.RateLimiter
@@ -537,6 +556,17 @@ func makeExternalStorage[T interface {
 		if err != nil {
 			return nil, err
 		}
+		if dest.EnvelopeEncryption != nil {
+			var db isql.DB
+			if args, ok := any(args).(ExternalStorageContext); ok {
+				db = args.DB
+			}
+			wrapped, err := wrapWithEnvelopeEncryption(ctx, e, dest.EnvelopeEncryption, conf, settings, db)
+			if err != nil {
+				return nil, errors.CombineErrors(err, e.Close())
+			}
+			e = wrapped
+		}
 
 		// We do not wrap the ExternalStorage for the `external` provider. An
 		// external connection object represents an underlying external resource
@@ -151,7 +151,12 @@ func joinRelativePath(filePath string, file string) string {
 	return path.Join(".", filePath, file)
 }
 
-func (l *localFileStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+func (l *localFileStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	if err := cloud.CheckRetentionUnsupported(cloudpb.ExternalStorageProvider_nodelocal, opts); err != nil {
+		return nil, err
+	}
 	return l.blobClient.Writer(ctx, joinRelativePath(l.base, basename))
 }
 
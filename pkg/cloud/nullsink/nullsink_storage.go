@@ -80,7 +80,12 @@ type nullWriter struct{}
 func (nullWriter) Write(p []byte) (int, error) { return len(p), nil }
 func (nullWriter) Close() error                { return nil }
 
-func (n *nullSinkStorage) Writer(_ context.Context, _ string) (io.WriteCloser, error) {
+func (n *nullSinkStorage) Writer(
+	_ context.Context, _ string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	if err := cloud.CheckRetentionUnsupported(cloudpb.ExternalStorageProvider_null, opts); err != nil {
+		return nil, err
+	}
 	return nullWriter{}, nil
 }
 
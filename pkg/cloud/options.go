@@ -10,7 +10,11 @@
 
 package cloud
 
-import "github.com/cockroachdb/cockroach/pkg/base"
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+)
 
 // ExternalStorageOption is an option passed during the construction
 // of an external storage.
@@ -28,3 +32,14 @@ func WithAzureStorageTestingKnobs(knobs base.ModuleTestingKnobs) ExternalStorage
 		opts.AzureStorageTestingKnobs = knobs
 	}
 }
+
+// WriteOption is an option passed to ExternalStorage.Writer.
+type WriteOption func(opts *WriteOptions)
+
+// WithRetainUntil requests that the written object be protected against being
+// deleted or overwritten until the given time.
+func WithRetainUntil(t time.Time) WriteOption {
+	return func(opts *WriteOptions) {
+		opts.RetainUntil = t
+	}
+}
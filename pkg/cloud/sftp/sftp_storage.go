@@ -0,0 +1,418 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sftp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/ioctx"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	sftpclient "github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	scheme = "sftp"
+
+	// SFTPPrivateKeyParam is the query parameter for the base64 encoded, PEM
+	// encoded private key used to authenticate the user. A password in the
+	// user info of the URI can be used instead.
+	SFTPPrivateKeyParam = "SFTP_PRIVATE_KEY"
+	// SFTPHostKeyParam is the query parameter for the public key of the server,
+	// in authorized_keys format (e.g. "ssh-ed25519 AAAA...").
+	SFTPHostKeyParam = "SFTP_HOST_KEY"
+	// SFTPInsecureIgnoreHostKeyParam is the query parameter that, if set to
+	// true, disables the verification of the key of the server.
+	SFTPInsecureIgnoreHostKeyParam = "SFTP_INSECURE_IGNORE_HOST_KEY"
+
+	defaultPort = "22"
+)
+
+func parseSFTPURL(uri *url.URL) (cloudpb.ExternalStorage, error) {
+	sftpURL := cloud.ConsumeURL{URL: uri}
+	conf := cloudpb.ExternalStorage{}
+	if uri.Host == "" {
+		return conf, errors.New("empty host component; sftp URI must specify a server")
+	}
+	if uri.User == nil || uri.User.Username() == "" {
+		return conf, errors.New("sftp URI must specify a user")
+	}
+
+	conf.Provider = cloudpb.ExternalStorageProvider_sftp
+	password, _ := uri.User.Password()
+	conf.SFTPConfig = &cloudpb.ExternalStorage_SFTP{
+		Host:     uri.Host,
+		Prefix:   uri.Path,
+		User:     uri.User.Username(),
+		Password: password,
+		HostKey:  sftpURL.ConsumeParam(SFTPHostKeyParam),
+	}
+	if encodedKey := sftpURL.ConsumeParam(SFTPPrivateKeyParam); encodedKey != "" {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return conf, errors.Wrapf(err, "decoding value of %s", SFTPPrivateKeyParam)
+		}
+		conf.SFTPConfig.PrivateKey = string(key)
+	}
+	if insecure := sftpURL.ConsumeParam(SFTPInsecureIgnoreHostKeyParam); insecure != "" {
+		var err error
+		conf.SFTPConfig.InsecureIgnoreHostKey, err = strconv.ParseBool(insecure)
+		if err != nil {
+			return conf, errors.Wrapf(err, "parsing value of %s", SFTPInsecureIgnoreHostKeyParam)
+		}
+	}
+
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := sftpURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return cloudpb.ExternalStorage{}, errors.Errorf(
+			`unknown sftp query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	return conf, nil
+}
+
+// sftpStorage is an ExternalStorage backed by a directory on an SFTP server.
+// It connects to the server the first time it is used and reuses the
+// connection until it is closed.
+type sftpStorage struct {
+	conf     *cloudpb.ExternalStorage_SFTP
+	config   *ssh.ClientConfig
+	prefix   string
+	ioConf   base.ExternalIODirConfig
+	settings *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		conn   *ssh.Client
+		client *sftpclient.Client
+	}
+}
+
+var _ cloud.ExternalStorage = &sftpStorage{}
+
+// MakeSFTPStorage returns an instance of sftpStorage ExternalStorage.
+func MakeSFTPStorage(
+	ctx context.Context, args cloud.EarlyBootExternalStorageContext, dest cloudpb.ExternalStorage,
+) (cloud.ExternalStorage, error) {
+	telemetry.Count("external-io.sftp")
+	conf := dest.SFTPConfig
+	if conf == nil {
+		return nil, errors.Errorf("sftp upload requested but info missing")
+	}
+
+	var auth []ssh.AuthMethod
+	if conf.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(conf.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing value of %s", SFTPPrivateKeyParam)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if conf.Password != "" {
+		auth = append(auth, ssh.Password(conf.Password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.Errorf(
+			"sftp URI must specify a password or the %s parameter", SFTPPrivateKeyParam)
+	}
+
+	var hostKeyCallback ssh.HostKeyCallback
+	switch {
+	case conf.HostKey != "" && conf.InsecureIgnoreHostKey:
+		return nil, errors.Errorf(
+			"only one of %s and %s can be specified", SFTPHostKeyParam, SFTPInsecureIgnoreHostKeyParam)
+	case conf.HostKey != "":
+		hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(conf.HostKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing value of %s", SFTPHostKeyParam)
+		}
+		hostKeyCallback = ssh.FixedHostKey(hostKey)
+	case conf.InsecureIgnoreHostKey:
+		// nolint:gosec
+		hostKeyCallback = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, errors.Errorf(
+			"sftp URI must specify the %s parameter, or set %s to true",
+			SFTPHostKeyParam, SFTPInsecureIgnoreHostKeyParam)
+	}
+
+	return &sftpStorage{
+		conf: conf,
+		config: &ssh.ClientConfig{
+			User:            conf.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         cloud.Timeout.Get(&args.Settings.SV),
+		},
+		prefix:   conf.Prefix,
+		ioConf:   args.IOConf,
+		settings: args.Settings,
+	}, nil
+}
+
+// client returns the SFTP client of the storage, connecting to the server if
+// the storage is not connected yet, or if its connection was lost.
+func (s *sftpStorage) client(ctx context.Context) (*sftpclient.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.client != nil {
+		return s.mu.client, nil
+	}
+
+	addr := s.conf.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultPort)
+	}
+	d := net.Dialer{Timeout: s.config.Timeout}
+	netConn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to sftp server %s", addr)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, addr, s.config)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, errors.Wrapf(err, "connecting to sftp server %s", addr)
+	}
+	conn := ssh.NewClient(sshConn, chans, reqs)
+	client, err := sftpclient.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrapf(err, "starting sftp session with %s", addr)
+	}
+	s.mu.conn, s.mu.client = conn, client
+
+	// Forget the connection once it is closed, be it by the server, by a
+	// network failure or by Close, so that the next operation reconnects.
+	go func() {
+		_ = conn.Wait()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.mu.conn == conn {
+			_ = client.Close()
+			s.mu.conn, s.mu.client = nil, nil
+		}
+	}()
+	return client, nil
+}
+
+func (s *sftpStorage) Conf() cloudpb.ExternalStorage {
+	return cloudpb.ExternalStorage{
+		Provider:   cloudpb.ExternalStorageProvider_sftp,
+		SFTPConfig: s.conf,
+	}
+}
+
+func (s *sftpStorage) ExternalIOConf() base.ExternalIODirConfig {
+	return s.ioConf
+}
+
+func (s *sftpStorage) RequiresExternalIOAccounting() bool { return true }
+
+func (s *sftpStorage) Settings() *cluster.Settings {
+	return s.settings
+}
+
+func (s *sftpStorage) ReadFile(
+	ctx context.Context, basename string, opts cloud.ReadOptions,
+) (_ ioctx.ReadCloserCtx, fileSize int64, _ error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	name := path.Join(s.prefix, basename)
+	f, err := client.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			// nolint:errwrap
+			err = errors.Wrapf(
+				errors.Wrapf(cloud.ErrFileDoesNotExist, "sftp file %q does not exist", name),
+				"%v",
+				err.Error(),
+			)
+		}
+		return nil, 0, err
+	}
+	if !opts.NoFileSize {
+		stat, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, 0, errors.Wrapf(err, "stat %q", name)
+		}
+		fileSize = stat.Size()
+	}
+	if opts.Offset != 0 {
+		if _, err := f.Seek(opts.Offset, io.SeekStart); err != nil {
+			_ = f.Close()
+			return nil, 0, errors.Wrapf(err, "seeking to offset %d in %q", opts.Offset, name)
+		}
+	}
+	return ioctx.ReadCloserAdapter(f), fileSize, nil
+}
+
+// Writer writes the file to a temporary name in its directory, and renames it
+// to the requested name once it is complete so that readers never observe a
+// partially written file.
+func (s *sftpStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	if err := cloud.CheckRetentionUnsupported(cloudpb.ExternalStorageProvider_sftp, opts); err != nil {
+		return nil, err
+	}
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(s.prefix, basename)
+	return cloud.BackgroundPipe(ctx, func(ctx context.Context, r io.Reader) error {
+		if err := client.MkdirAll(path.Dir(name)); err != nil {
+			return errors.Wrapf(err, "creating directory of %q", name)
+		}
+		tmpName := fmt.Sprintf("%s.%s.tmp", name, uuid.MakeV4())
+		f, err := client.Create(tmpName)
+		if err != nil {
+			return errors.Wrapf(err, "creating %q", tmpName)
+		}
+		if _, err := f.ReadFrom(r); err != nil {
+			_ = f.Close()
+			_ = client.Remove(tmpName)
+			return errors.Wrapf(err, "writing %q", tmpName)
+		}
+		if err := f.Close(); err != nil {
+			_ = client.Remove(tmpName)
+			return errors.Wrapf(err, "writing %q", tmpName)
+		}
+		if err := rename(client, tmpName, name); err != nil {
+			_ = client.Remove(tmpName)
+			return errors.Wrapf(err, "renaming %q to %q", tmpName, name)
+		}
+		return nil
+	}), nil
+}
+
+// rename renames the file oldname to newname, replacing newname if it exists.
+// Servers that do not support the posix-rename@openssh.com extension refuse to
+// rename a file to a name that is taken, so newname is removed first, which
+// leaves a short window in which neither file exists.
+func rename(client *sftpclient.Client, oldname, newname string) error {
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		return client.PosixRename(oldname, newname)
+	}
+	if err := client.Remove(newname); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return client.Rename(oldname, newname)
+}
+
+func (s *sftpStorage) List(ctx context.Context, prefix, delim string, fn cloud.ListingFn) error {
+	client, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	dest := cloud.JoinPathPreservingTrailingSlash(s.prefix, prefix)
+
+	// Like a cloud storage listing, the prefix need not be a directory: if it
+	// is not, walk its parent and only keep the files that match it.
+	walkRoot := dest
+	if stat, err := client.Stat(dest); err != nil || !stat.IsDir() {
+		walkRoot = path.Dir(dest)
+	}
+	var res []string
+	walker := client.Walk(walkRoot)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return errors.Wrap(err, "unable to list files")
+		}
+		if walker.Stat().IsDir() || !strings.HasPrefix(walker.Path(), dest) {
+			continue
+		}
+		res = append(res, walker.Path())
+	}
+
+	// Sort results so that we can group as we go.
+	sort.Strings(res)
+	var prevPrefix string
+	for _, f := range res {
+		f = strings.TrimPrefix(f, dest)
+		if delim != "" {
+			if i := strings.Index(f, delim); i >= 0 {
+				f = f[:i+len(delim)]
+			}
+			if f == prevPrefix {
+				continue
+			}
+			prevPrefix = f
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sftpStorage) Delete(ctx context.Context, basename string) error {
+	client, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.Remove(path.Join(s.prefix, basename))
+}
+
+func (s *sftpStorage) Size(ctx context.Context, basename string) (int64, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return 0, err
+	}
+	stat, err := client.Stat(path.Join(s.prefix, basename))
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+func (s *sftpStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.client == nil {
+		return nil
+	}
+	err := errors.CombineErrors(s.mu.client.Close(), s.mu.conn.Close())
+	s.mu.client, s.mu.conn = nil, nil
+	return err
+}
+
+func init() {
+	cloud.RegisterExternalStorageProvider(cloudpb.ExternalStorageProvider_sftp,
+		cloud.RegisteredProvider{
+			EarlyBootParseFn:     parseSFTPURL,
+			EarlyBootConstructFn: MakeSFTPStorage,
+			RedactedParams:       cloud.RedactedParams(SFTPPrivateKeyParam),
+			Schemes:              []string{scheme},
+		})
+}
@@ -233,7 +233,12 @@ func (f *fileTableStorage) ReadFile(
 
 // Writer implements the ExternalStorage interface and writes the file to the
 // user scoped FileToTableSystem.
-func (f *fileTableStorage) Writer(ctx context.Context, basename string) (io.WriteCloser, error) {
+func (f *fileTableStorage) Writer(
+	ctx context.Context, basename string, opts ...cloud.WriteOption,
+) (io.WriteCloser, error) {
+	if err := cloud.CheckRetentionUnsupported(cloudpb.ExternalStorageProvider_userfile, opts); err != nil {
+		return nil, err
+	}
 	filepath, err := checkBaseAndJoinFilePath(f.prefix, basename)
 	if err != nil {
 		return nil, err
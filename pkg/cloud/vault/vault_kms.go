@@ -0,0 +1,235 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// vaultScheme is the scheme of the URIs of keys of the transit secrets
+	// engine of a HashiCorp Vault server:
+	//
+	//   vault://<host>[:<port>]/[<mount>/]<key>?VAULT_TOKEN=<token>
+	//
+	// The mount defaults to "transit".
+	vaultScheme = "vault"
+	// kmsScheme is accepted as an alias of vaultScheme, in line with the
+	// schemes of the other KMSes.
+	kmsScheme = "vault-kms"
+
+	// VaultTokenParam is the query parameter for the token used to authenticate
+	// to Vault.
+	VaultTokenParam = "VAULT_TOKEN"
+	// VaultNamespaceParam is the query parameter for the Vault Enterprise
+	// namespace of the key.
+	VaultNamespaceParam = "VAULT_NAMESPACE"
+	// VaultDisableTLSParam is the query parameter that, if set to true, makes
+	// requests to Vault over plain HTTP, e.g. to a Vault dev server.
+	VaultDisableTLSParam = "VAULT_DISABLE_TLS"
+
+	defaultTransitMount = "transit"
+)
+
+// vaultKMS is a KMS backed by a named key of the transit secrets engine of a
+// Vault server. Vault prefixes the ciphertexts it returns with the version of
+// the key that produced them, so the key can be rotated in Vault: data is
+// always encrypted with the latest version of the key, and data encrypted with
+// older versions can be decrypted as long as they are not trimmed or disabled
+// in Vault.
+type vaultKMS struct {
+	client    *http.Client
+	baseURL   url.URL
+	mount     string
+	key       string
+	token     string
+	namespace string
+}
+
+var _ cloud.KMS = &vaultKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeVaultKMS, vaultScheme, kmsScheme)
+	cloud.RegisterRedactedParams(cloud.RedactedParams(VaultTokenParam))
+}
+
+// MakeVaultKMS is the factory method which returns a configured, ready-to-use
+// Vault KMS object.
+func MakeVaultKMS(ctx context.Context, uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use Vault KMS")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if kmsURI.Host == "" {
+		return nil, errors.New("host component of the KMS cannot be empty; must contain the address of the Vault server")
+	}
+	keyPath := strings.Trim(kmsURI.Path, "/")
+	if keyPath == "" {
+		return nil, errors.New("path component of the KMS cannot be empty; must contain the name of the transit key")
+	}
+	mount, key := defaultTransitMount, keyPath
+	if i := strings.LastIndex(keyPath, "/"); i >= 0 {
+		mount, key = keyPath[:i], keyPath[i+1:]
+	}
+
+	kmsConsumeURL := cloud.ConsumeURL{URL: kmsURI}
+	token := kmsConsumeURL.ConsumeParam(VaultTokenParam)
+	namespace := kmsConsumeURL.ConsumeParam(VaultNamespaceParam)
+	disableTLS := false
+	if v := kmsConsumeURL.ConsumeParam(VaultDisableTLSParam); v != "" {
+		if disableTLS, err = strconv.ParseBool(v); err != nil {
+			return nil, errors.Wrapf(err, "parsing value of %s", VaultDisableTLSParam)
+		}
+	}
+	// Validate that all the passed in parameters are supported.
+	if unknownParams := kmsConsumeURL.RemainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown KMS query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+	if token == "" {
+		return nil, errors.Errorf("%s must be set to use Vault KMS", VaultTokenParam)
+	}
+
+	client, err := cloud.MakeHTTPClient(env.ClusterSettings())
+	if err != nil {
+		return nil, err
+	}
+	baseURL := url.URL{Scheme: "https", Host: kmsURI.Host}
+	if disableTLS {
+		baseURL.Scheme = "http"
+	}
+	return &vaultKMS{
+		client:    client,
+		baseURL:   baseURL,
+		mount:     mount,
+		key:       key,
+		token:     token,
+		namespace: namespace,
+	}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+func (k *vaultKMS) MasterKeyID() string {
+	return path.Join(k.mount, k.key)
+}
+
+// Encrypt implements the KMS interface.
+func (k *vaultKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	req := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(data)}
+	if err := k.do(ctx, "encrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, errors.New("Vault returned an empty ciphertext")
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *vaultKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	req := map[string]string{"ciphertext": string(data)}
+	if err := k.do(ctx, "decrypt", req, &resp); err != nil {
+		return nil, err
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding plaintext returned by Vault")
+	}
+	return plaintext, nil
+}
+
+// do sends a request for the given operation on the key of the KMS to the
+// transit secrets engine, and decodes the response into resp.
+func (k *vaultKMS) do(ctx context.Context, op string, req interface{}, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	u := k.baseURL
+	u.Path = path.Join("/v1", k.mount, op, k.key)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("X-Vault-Token", k.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	if k.namespace != "" {
+		httpReq.Header.Set("X-Vault-Namespace", k.namespace)
+	}
+
+	httpResp, err := k.client.Do(httpReq)
+	if err != nil {
+		return cloud.KMSInaccessible(errors.Wrapf(err, "Vault %s request failed", op))
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return cloud.KMSInaccessible(errors.Wrapf(err, "reading Vault %s response", op))
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		msg := string(respBody)
+		if json.Unmarshal(respBody, &vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			msg = strings.Join(vaultErr.Errors, "; ")
+		}
+		err := errors.Newf("Vault %s request failed: %s: %s", op, httpResp.Status, msg)
+		// Errors that are not caused by the request itself mean that the key cannot
+		// be used at all.
+		switch {
+		case httpResp.StatusCode == http.StatusForbidden, httpResp.StatusCode == http.StatusNotFound,
+			httpResp.StatusCode >= http.StatusInternalServerError:
+			err = cloud.KMSInaccessible(err)
+		}
+		return err
+	}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return errors.Wrapf(err, "decoding Vault %s response", op)
+	}
+	return nil
+}
+
+// Close implements the KMS interface.
+func (k *vaultKMS) Close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
+
+func (k *vaultKMS) String() string {
+	return fmt.Sprintf("%s://%s/%s", vaultScheme, k.baseURL.Host, k.MasterKeyID())
+}
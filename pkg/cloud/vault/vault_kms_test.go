@@ -0,0 +1,275 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/cloud/cloudtestutils"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn"
+	"github.com/cockroachdb/cockroach/pkg/cloud/externalconn/connectionpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "s.testtoken"
+
+// fakeTransit is an HTTP server that implements the encrypt and decrypt
+// endpoints of the transit secrets engine of Vault, including the versioning of
+// keys.
+type fakeTransit struct {
+	mount string
+
+	mu sync.Mutex
+	// keys holds the versions of every key, the latest last.
+	keys map[string][]cipher.AEAD
+}
+
+func newFakeTransit(mount string) *fakeTransit {
+	return &fakeTransit{mount: mount, keys: make(map[string][]cipher.AEAD)}
+}
+
+// rotate adds a new version to the named key, creating it if needed.
+func (f *fakeTransit) rotate(t *testing.T, name string) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[name] = append(f.keys[name], gcm)
+}
+
+func (f *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fail := func(code int, msg string) {
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {msg}})
+	}
+	if r.Header.Get("X-Vault-Token") != testToken {
+		fail(http.StatusForbidden, "permission denied")
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/"+f.mount+"/"), "/")
+	if r.Method != http.MethodPost || len(parts) != 2 {
+		fail(http.StatusNotFound, "no handler for route")
+		return
+	}
+	op, name := parts[0], parts[1]
+	var req map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	f.mu.Lock()
+	versions := f.keys[name]
+	f.mu.Unlock()
+	if len(versions) == 0 {
+		fail(http.StatusBadRequest, "encryption key not found")
+		return
+	}
+
+	var resp map[string]string
+	switch op {
+	case "encrypt":
+		plaintext, err := base64.StdEncoding.DecodeString(req["plaintext"])
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		gcm := versions[len(versions)-1]
+		nonce := make([]byte, gcm.NonceSize())
+		_, _ = rand.Read(nonce)
+		sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+		resp = map[string]string{"ciphertext": fmt.Sprintf(
+			"vault:v%d:%s", len(versions), base64.StdEncoding.EncodeToString(sealed))}
+	case "decrypt":
+		fields := strings.SplitN(req["ciphertext"], ":", 3)
+		if len(fields) != 3 || fields[0] != "vault" || !strings.HasPrefix(fields[1], "v") {
+			fail(http.StatusBadRequest, "invalid ciphertext: no prefix")
+			return
+		}
+		version, err := strconv.Atoi(fields[1][1:])
+		if err != nil || version < 1 || version > len(versions) {
+			fail(http.StatusBadRequest, "invalid key version")
+			return
+		}
+		sealed, err := base64.StdEncoding.DecodeString(fields[2])
+		gcm := versions[version-1]
+		if err != nil || len(sealed) < gcm.NonceSize() {
+			fail(http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+		if err != nil {
+			fail(http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		resp = map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}
+	default:
+		fail(http.StatusNotFound, "no handler for route")
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]map[string]string{"data": resp})
+}
+
+func testKMSEnv() cloud.KMSEnv {
+	return &cloud.TestKMSEnv{
+		Settings:         cluster.MakeTestingClusterSettings(),
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+}
+
+func TestVaultKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	transit := newFakeTransit("secret/transit")
+	transit.rotate(t, "backup")
+	srv := httptest.NewServer(transit)
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	params := url.Values{VaultTokenParam: {testToken}, VaultDisableTLSParam: {"true"}}
+	uri := fmt.Sprintf("vault://%s/secret/transit/backup?%s", host, params.Encode())
+
+	t.Run("encrypt-decrypt", func(t *testing.T) {
+		cloud.KMSEncryptDecrypt(t, uri, testKMSEnv())
+		cloud.KMSEncryptDecrypt(t, strings.Replace(uri, "vault://", "vault-kms://", 1), testKMSEnv())
+	})
+
+	t.Run("key-id", func(t *testing.T) {
+		k, err := cloud.KMSFromURI(ctx, uri, testKMSEnv())
+		require.NoError(t, err)
+		defer k.Close()
+		require.Equal(t, "secret/transit/backup", k.MasterKeyID())
+
+		k, err = cloud.KMSFromURI(ctx, fmt.Sprintf("vault://%s/backup?%s", host, params.Encode()), testKMSEnv())
+		require.NoError(t, err)
+		defer k.Close()
+		require.Equal(t, "transit/backup", k.MasterKeyID())
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		k, err := cloud.KMSFromURI(ctx, uri, testKMSEnv())
+		require.NoError(t, err)
+		defer k.Close()
+
+		before, err := k.Encrypt(ctx, []byte("before"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(before), "vault:v1:"))
+
+		transit.rotate(t, "backup")
+
+		after, err := k.Encrypt(ctx, []byte("after"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(after), "vault:v2:"))
+
+		// Data encrypted before the rotation can still be decrypted.
+		plaintext, err := k.Decrypt(ctx, before)
+		require.NoError(t, err)
+		require.Equal(t, "before", string(plaintext))
+		plaintext, err = k.Decrypt(ctx, after)
+		require.NoError(t, err)
+		require.Equal(t, "after", string(plaintext))
+	})
+
+	t.Run("inaccessible", func(t *testing.T) {
+		badToken := url.Values{VaultTokenParam: {"wrong"}, VaultDisableTLSParam: {"true"}}
+		cloudtestutils.RequireKMSInaccessibleErrorContaining(ctx, t,
+			fmt.Sprintf("vault://%s/secret/transit/backup?%s", host, badToken.Encode()),
+			"permission denied")
+	})
+
+	t.Run("invalid-uri", func(t *testing.T) {
+		for _, tc := range []struct {
+			uri string
+			err string
+		}{
+			{fmt.Sprintf("vault://%s/backup", host), "VAULT_TOKEN must be set"},
+			{fmt.Sprintf("vault://%s/?%s", host, params.Encode()), "must contain the name of the transit key"},
+			{fmt.Sprintf("vault:///backup?%s", params.Encode()), "must contain the address of the Vault server"},
+			{fmt.Sprintf("vault://%s/backup?%s&foo=bar", host, params.Encode()), "unknown KMS query parameters: foo"},
+			{fmt.Sprintf("vault://%s/backup?%s=maybe&%s=t", host, VaultDisableTLSParam, VaultTokenParam),
+				"parsing value of VAULT_DISABLE_TLS"},
+		} {
+			_, err := cloud.KMSFromURI(ctx, tc.uri, testKMSEnv())
+			require.ErrorContains(t, err, tc.err)
+		}
+	})
+
+	t.Run("disable-outbound", func(t *testing.T) {
+		_, err := cloud.KMSFromURI(ctx, uri, &cloud.TestKMSEnv{
+			Settings:         cluster.MakeTestingClusterSettings(),
+			ExternalIOConfig: &base.ExternalIODirConfig{DisableOutbound: true},
+		})
+		require.ErrorContains(t, err, "external IO must be enabled")
+	})
+}
+
+// TestVaultKMSDevServer runs against a real Vault server, such as one started
+// with `vault server -dev`, with the transit secrets engine enabled and a key
+// named by VAULT_TRANSIT_KEY.
+func TestVaultKMSDevServer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	addr, token, key := os.Getenv("VAULT_ADDR"), os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_TRANSIT_KEY")
+	if addr == "" || token == "" || key == "" {
+		skip.IgnoreLint(t, "VAULT_ADDR, VAULT_TOKEN and VAULT_TRANSIT_KEY env vars must be set")
+	}
+	vaultAddr, err := url.Parse(addr)
+	require.NoError(t, err)
+
+	params := url.Values{VaultTokenParam: {token}}
+	if vaultAddr.Scheme == "http" {
+		params.Set(VaultDisableTLSParam, "true")
+	}
+	uri := fmt.Sprintf("vault://%s/%s?%s", vaultAddr.Host, key, params.Encode())
+	cloud.KMSEncryptDecrypt(t, uri, testKMSEnv())
+}
+
+func TestVaultKMSConnection(t *testing.T) {
+	require.Equal(t, connectionpb.ConnectionProvider_vault_kms, externalconn.ProviderForURI("vault://test"))
+	require.Equal(t, connectionpb.ConnectionProvider_vault_kms, externalconn.ProviderForURI("vault-kms://test"))
+}
+
+func TestVaultKMSRedaction(t *testing.T) {
+	uri := "vault://vault.example.com:8200/transit/backup?VAULT_TOKEN=secret&VAULT_NAMESPACE=team"
+	redacted, err := cloud.SanitizeExternalStorageURI(uri, nil)
+	require.NoError(t, err)
+	require.NotContains(t, redacted, "secret")
+	require.Contains(t, redacted, "VAULT_NAMESPACE=team")
+
+	redacted, err = cloud.RedactKMSURI(uri)
+	require.NoError(t, err)
+	require.NotContains(t, redacted, "secret")
+}
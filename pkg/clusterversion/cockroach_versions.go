@@ -268,6 +268,34 @@ const (
 	// minimum timestamp field.
 	V24_2_LeaseMinTimestamp
 
+	// V24_2_ChangefeedKafkaTransactions is the version from which changefeeds
+	// may write to Kafka in transactions, which nodes running earlier versions
+	// would silently ignore.
+	V24_2_ChangefeedKafkaTransactions
+
+	// V24_2_RestoreRowFilter is the version from which RESTORE accepts the
+	// row_filter option, which restore processors running earlier versions would
+	// ignore.
+	V24_2_RestoreRowFilter
+
+	// V24_2_EnvelopeEncryption is the version from which external storage URIs
+	// accept the ENCRYPTION_KMS parameter.
+	V24_2_EnvelopeEncryption
+
+	// V24_2_ListenNotify is the version from which the SQL instances deliver the
+	// notifications raised by NOTIFY through the Notify RPC.
+	V24_2_ListenNotify
+
+	// V24_2_RowLevelSecurity is the version from which tables can have
+	// row-level security policies, which nodes running earlier versions would
+	// not enforce.
+	V24_2_RowLevelSecurity
+
+	// V24_2_ChangefeedRescanRunning is the version from which ALTER CHANGEFEED
+	// ... RESCAN applies to running changefeeds, whose change frontiers on nodes
+	// running earlier versions would not pick up the request.
+	V24_2_ChangefeedRescanRunning
+
 	// *************************************************
 	// Step (1) Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -332,6 +360,12 @@ var versionTable = [numKeys]roachpb.Version{
 	V24_2_TenantRates:                 {Major: 24, Minor: 1, Internal: 8},
 	V24_2_DeleteTenantSettingsVersion: {Major: 24, Minor: 1, Internal: 10},
 	V24_2_LeaseMinTimestamp:           {Major: 24, Minor: 1, Internal: 12},
+	V24_2_ChangefeedKafkaTransactions: {Major: 24, Minor: 1, Internal: 14},
+	V24_2_RestoreRowFilter:            {Major: 24, Minor: 1, Internal: 16},
+	V24_2_EnvelopeEncryption:          {Major: 24, Minor: 1, Internal: 18},
+	V24_2_ListenNotify:                {Major: 24, Minor: 1, Internal: 20},
+	V24_2_RowLevelSecurity:            {Major: 24, Minor: 1, Internal: 22},
+	V24_2_ChangefeedRescanRunning:     {Major: 24, Minor: 1, Internal: 24},
 
 	// *************************************************
 	// Step (2): Add new versions above this comment.
@@ -381,7 +381,7 @@ var specs = []stmtSpec{
 	{
 		name:    "alter_changefeed",
 		stmt:    "alter_changefeed_stmt",
-		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "( 'ADD' target ( ( ',' target ) )* ( 'WITH' ( initial_scan | no_initial_scan ) )? | 'DROP' target ( ( ',' target ) )* | ( 'SET' | 'UNSET' ) option ( ( ',' option ) )* )+"},
+		replace: map[string]string{"a_expr": "job_id", "alter_changefeed_cmds": "( 'ADD' target ( ( ',' target ) )* ( 'WITH' ( initial_scan | no_initial_scan ) )? | 'DROP' target ( ( ',' target ) )* | ( 'SET' | 'UNSET' ) option ( ( ',' option ) )* | 'RESCAN' ( 'TABLE' target )? ( 'WHERE' a_expr )? )+"},
 		unlink:  []string{"job_id", "target", "option", "initial_scan", "no_initial_scan"},
 	},
 	{
@@ -693,6 +693,17 @@ var specs = []stmtSpec{
 		inline: []string{"opt_transaction"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'COMMIT'|'END'")},
 	},
+	{
+		name:   "compact_backup",
+		stmt:   "compact_backup_stmt",
+		inline: []string{"opt_with_backup_options", "opt_as_of_clause", "as_of_clause", "backup_options_list"},
+		replace: map[string]string{
+			"string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
+			"string_or_placeholder":          "( 'LATEST' | subdirectory )",
+			"a_expr":                         "timestamp",
+		},
+		unlink: []string{"collectionURI", "timestamp", "localityURI", "subdirectory"},
+	},
 	{
 		name:    "copy_stmt",
 		inline:  []string{"opt_with_copy_options", "copy_options_list", "opt_with", "opt_where_clause", "where_clause"},
@@ -951,6 +962,16 @@ var specs = []stmtSpec{
 		unlink:  []string{"func_name"},
 		replace: map[string]string{"db_object_name": "func_name"},
 	},
+	{
+		name:   "drop_backup",
+		stmt:   "drop_backup_stmt",
+		inline: []string{"opt_with_backup_options", "backup_options_list"},
+		replace: map[string]string{
+			"string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
+			"string_or_placeholder":          "subdirectory",
+		},
+		unlink: []string{"collectionURI", "localityURI", "subdirectory"},
+	},
 	{
 		name:    "drop_external_connection_stmt",
 		replace: map[string]string{"string_or_placeholder": "connection_name"},
@@ -1155,6 +1176,24 @@ var specs = []stmtSpec{
 		name:   "like_table_option_list",
 		inline: []string{"like_table_option"},
 	},
+	{
+		name: "merge_stmt",
+		inline: []string{
+			"opt_with_clause",
+			"with_clause",
+			"cte_list",
+			"table_expr_opt_alias_idx",
+			"table_name_opt_idx",
+			"opt_only",
+			"opt_descendant",
+			"merge_when_list",
+			"returning_clause",
+		},
+		replace: map[string]string{
+			"relation_expr": "table_name",
+		},
+		nosplit: true,
+	},
 	{
 		name: "on_conflict",
 		inline: []string{"name_list", "set_clause_list", "insert_column_list",
@@ -1345,6 +1384,17 @@ var specs = []stmtSpec{
 		name:   "values_clause",
 		inline: []string{"expr_list"},
 	},
+	{
+		name:   "verify_backup",
+		stmt:   "verify_backup_stmt",
+		inline: []string{"opt_with_verify_backup_options", "opt_as_of_clause", "as_of_clause", "verify_backup_options_list"},
+		replace: map[string]string{
+			"string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
+			"string_or_placeholder":          "( 'LATEST' | subdirectory )",
+			"a_expr":                         "timestamp",
+		},
+		unlink: []string{"collectionURI", "timestamp", "localityURI", "subdirectory"},
+	},
 	{
 		name:    "simple_select_clause",
 		inline:  []string{"opt_all_clause", "distinct_clause", "distinct_on_clause", "opt_as_of_clause", "as_of_clause", "expr_list", "target_list", "from_clause", "opt_where_clause", "where_clause", "group_clause", "having_clause", "window_clause", "from_list"},
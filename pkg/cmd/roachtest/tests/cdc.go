@@ -1241,6 +1241,265 @@ func runCDCSchemaRegistry(ctx context.Context, t test.Test, c cluster.Cluster) {
 	}
 }
 
+// This test verifies that messages of the protobuf format can be read by the
+// confluent protobuf deserializer, which checks both the schemas registered
+// by the changefeed and the wire format of the messages.
+func runCDCSchemaRegistryProtobuf(ctx context.Context, t test.Test, c cluster.Cluster) {
+	crdbNodes, kafkaNode := c.Node(1), c.Node(1)
+	c.Start(ctx, t.L(), option.DefaultStartOpts(), install.MakeClusterSettings(), crdbNodes)
+	kafka := kafkaManager{
+		t:              t,
+		c:              c,
+		kafkaSinkNodes: kafkaNode,
+	}
+	kafka.install(ctx)
+	kafka.start(ctx, "schema-registry")
+	defer kafka.stop(ctx)
+
+	db := c.Conn(ctx, t.L(), 1)
+	defer stopFeeds(db)
+
+	if _, err := db.Exec(`CREATE TABLE foo (a INT PRIMARY KEY)`); err != nil {
+		t.Fatal(err)
+	}
+
+	options := map[string]string{
+		"format":                    "protobuf",
+		"confluent_schema_registry": "$2",
+	}
+	_, err := newChangefeedCreator(db, db, t.L(), globalRand, "foo", kafka.sinkURL(ctx), makeDefaultFeatureFlags()).
+		With(options).
+		Args(kafka.schemaRegistryURL(ctx)).
+		Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, stmt := range []string{
+		`INSERT INTO foo VALUES (1)`,
+		`ALTER TABLE foo ADD COLUMN b STRING`,
+		`INSERT INTO foo VALUES (2, '2')`,
+		`ALTER TABLE foo DROP COLUMN b`,
+		`INSERT INTO foo VALUES (3)`,
+	} {
+		t.L().Printf("Executing SQL: %s", stmt)
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to execute %s: %v", stmt, err)
+		}
+	}
+
+	// As in runCDCSchemaRegistry, sort and unique the output to ignore
+	// duplicates.
+	valuesMap := make(map[string]struct{})
+	pagesFetched := 0
+	pageSize := 5
+	for len(valuesMap) < 4 && pagesFetched < 5 {
+		result, err := c.RunWithDetailsSingleNode(ctx, t.L(), option.WithNodes(kafkaNode),
+			kafka.makeCommand("kafka-protobuf-console-consumer",
+				fmt.Sprintf("--offset=%d", pagesFetched*pageSize),
+				"--partition=0",
+				"--topic=foo",
+				fmt.Sprintf("--max-messages=%d", pageSize),
+				"--bootstrap-server=localhost:9092"))
+		t.L().Printf("\n%s\n", result.Stdout+result.Stderr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pagesFetched++
+
+		for _, line := range strings.Split(result.Stdout, "\n") {
+			if strings.Contains(line, `"after"`) {
+				valuesMap[line] = struct{}{}
+			}
+		}
+	}
+
+	values := make([]string, 0, len(valuesMap))
+	for v := range valuesMap {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+
+	// Int columns are int64 fields, which are printed as JSON strings, and NULL
+	// columns are unset optional fields, which are omitted. The rows are
+	// backfilled when the column is dropped.
+	expected := []string{
+		`{"after":{"a":"1"}}`,
+		`{"after":{"a":"2","b":"2"}}`,
+		`{"after":{"a":"2"}}`,
+		`{"after":{"a":"3"}}`,
+	}
+	sort.Strings(expected)
+	if strings.Join(expected, "\n") != strings.Join(values, "\n") {
+		t.Fatalf("expected\n%s\n\ngot\n%s\n\n",
+			strings.Join(expected, "\n"), strings.Join(values, "\n"))
+	}
+}
+
+// runCDCKafkaTransactions verifies that a changefeed writing to Kafka in
+// transactions commits the rows it emits, so that consumers reading with the
+// read_committed isolation level see every row, across pauses of the
+// changefeed and restarts of its nodes. It also verifies that the producers
+// of the changefeed use transactional IDs derived from the job, and that
+// closing the changefeed does not leave transactions open.
+func runCDCKafkaTransactions(ctx context.Context, t test.Test, c cluster.Cluster) {
+	crdbNodes, kafkaNode := c.Range(1, c.Spec().NodeCount-1), c.Node(c.Spec().NodeCount)
+	c.Start(ctx, t.L(), option.DefaultStartOpts(), install.MakeClusterSettings(), crdbNodes)
+	kafka := kafkaManager{
+		t:              t,
+		c:              c,
+		kafkaSinkNodes: kafkaNode,
+	}
+	kafka.install(ctx)
+	kafka.start(ctx, "kafka")
+	defer kafka.stop(ctx)
+
+	db := c.Conn(ctx, t.L(), 1)
+	defer stopFeeds(db)
+
+	tdb := sqlutils.MakeSQLRunner(db)
+	tdb.Exec(t, `SET CLUSTER SETTING changefeed.new_kafka_sink.enabled = true`)
+	tdb.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY)`)
+	// Split the table so that the changefeed runs several aggregators.
+	tdb.Exec(t, `ALTER TABLE t SPLIT AT VALUES (1000), (2000), (3000)`)
+	tdb.Exec(t, `ALTER TABLE t SCATTER`)
+
+	const rowsPerStep = 1000
+	var numRows int
+	insertRows := func() {
+		tdb.Exec(t, `INSERT INTO t SELECT generate_series($1::INT, $2::INT)`,
+			numRows, numRows+rowsPerStep-1)
+		numRows += rowsPerStep
+	}
+	insertRows()
+
+	jobID, err := newChangefeedCreator(db, db, t.L(), globalRand, "t", kafka.sinkURL(ctx), makeDefaultFeatureFlags()).
+		With(map[string]string{
+			"kafka_sink_config":        `'{"Transaction": {"Enabled": true}, "RequiredAcks": "ALL"}'`,
+			"min_checkpoint_frequency": `'5s'`,
+			"resolved":                 `'5s'`,
+		}).
+		Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForStatus := func(status string) {
+		testutils.SucceedsWithin(t, func() error {
+			info, err := getChangefeedInfo(db, jobID)
+			if err != nil {
+				return err
+			}
+			if info.GetStatus() != status {
+				return errors.Newf("job %d is %s, waiting for %s", jobID, info.GetStatus(), status)
+			}
+			return nil
+		}, 5*time.Minute)
+	}
+
+	insertRows()
+
+	// Pausing the changefeed closes its sinks, and resuming it starts new
+	// producers with the same transactional IDs.
+	tdb.Exec(t, `PAUSE JOB $1`, jobID)
+	waitForStatus("paused")
+	insertRows()
+	tdb.Exec(t, `RESUME JOB $1`, jobID)
+	waitForStatus("running")
+	insertRows()
+
+	// Restarting a node fails the aggregator running on it, whose open
+	// transaction is aborted.
+	restartNode := c.Node(len(crdbNodes))
+	c.Stop(ctx, t.L(), option.DefaultStopOpts(), restartNode)
+	insertRows()
+	startOpts := option.DefaultStartOpts()
+	startOpts.RoachprodOpts.IsRestart = true
+	c.Start(ctx, t.L(), startOpts, install.MakeClusterSettings(), restartNode)
+	insertRows()
+
+	var insertedAt time.Time
+	tdb.QueryRow(t, `SELECT now()`).Scan(&insertedAt)
+	if _, err := waitForChangefeed(ctx, db, jobID, t.L(), func(info changefeedInfo) (bool, error) {
+		return info.GetHighWater().After(insertedAt), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Consumers reading committed messages see every row, and the resolved
+	// timestamps, which are produced in transactions of their own.
+	result, err := c.RunWithDetailsSingleNode(ctx, t.L(), option.WithNodes(kafkaNode),
+		kafka.makeCommand("kafka-console-consumer",
+			"--bootstrap-server=localhost:9092",
+			"--topic=t",
+			"--from-beginning",
+			"--isolation-level=read_committed",
+			"--property=print.key=true",
+			"--timeout-ms=30000"))
+	// The consumer exits with an error once it times out waiting for more
+	// messages, so only its output matters.
+	if err != nil {
+		t.L().Printf("consumer exited: %v\n%s", err, result.Stderr)
+	}
+	seen := make(map[int]struct{}, numRows)
+	var resolved int
+	for _, line := range strings.Split(result.Stdout, "\n") {
+		key, value, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if strings.Contains(value, `"resolved"`) {
+			resolved++
+			continue
+		}
+		a, err := strconv.Atoi(strings.Trim(key, "[]"))
+		if err != nil {
+			t.Fatalf("unexpected message %q: %v", line, err)
+		}
+		seen[a] = struct{}{}
+	}
+	for a := 0; a < numRows; a++ {
+		if _, ok := seen[a]; !ok {
+			t.Fatalf("row %d was not committed to kafka", a)
+		}
+	}
+	if resolved == 0 {
+		t.Fatal("expected at least 1 committed resolved timestamp")
+	}
+
+	// The transactional IDs are derived from the job, and no transaction is
+	// left open once the changefeed is canceled.
+	tdb.Exec(t, `CANCEL JOB $1`, jobID)
+	waitForStatus("canceled")
+	idRE := regexp.MustCompile(fmt.Sprintf(`^crdb-changefeed-%d-(frontier|[0-9a-f]{16})$`, jobID))
+	testutils.SucceedsWithin(t, func() error {
+		result, err := c.RunWithDetailsSingleNode(ctx, t.L(), option.WithNodes(kafkaNode),
+			kafka.makeCommand("kafka-transactions", "--bootstrap-server=localhost:9092", "list"))
+		if err != nil {
+			return err
+		}
+		var frontier bool
+		for _, line := range strings.Split(result.Stdout, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 4 || !strings.HasPrefix(fields[0], fmt.Sprintf(`crdb-changefeed-%d-`, jobID)) {
+				continue
+			}
+			id, state := fields[0], fields[3]
+			if !idRE.MatchString(id) {
+				t.Fatalf("unexpected transactional id %s", id)
+			}
+			frontier = frontier || strings.HasSuffix(id, `-frontier`)
+			if state != "CompleteCommit" && state != "CompleteAbort" && state != "Empty" {
+				return errors.Newf("transaction %s is %s", id, state)
+			}
+		}
+		if !frontier {
+			return errors.New("no transaction of the change frontier")
+		}
+		return nil
+	}, 5*time.Minute)
+}
+
 func runCDCKafkaAuth(ctx context.Context, t test.Test, c cluster.Cluster) {
 	crdbNodes, kafkaNode := c.CRDBNodes(), c.Node(c.Spec().NodeCount)
 	c.Start(ctx, t.L(), option.DefaultStartOpts(), install.MakeClusterSettings(), crdbNodes)
@@ -2188,6 +2447,30 @@ func registerCDC(r registry.Registry) {
 			runCDCSchemaRegistry(ctx, t, c)
 		},
 	})
+	r.Add(registry.TestSpec{
+		Name:             "cdc/schemareg/protobuf",
+		Owner:            `cdc`,
+		Cluster:          r.MakeClusterSpec(1),
+		Leases:           registry.MetamorphicLeases,
+		CompatibleClouds: registry.AllExceptAWS,
+		Suites:           registry.Suites(registry.Nightly),
+		RequiresLicense:  true,
+		Run: func(ctx context.Context, t test.Test, c cluster.Cluster) {
+			runCDCSchemaRegistryProtobuf(ctx, t, c)
+		},
+	})
+	r.Add(registry.TestSpec{
+		Name:             "cdc/kafka-transactions",
+		Owner:            `cdc`,
+		Cluster:          r.MakeClusterSpec(4),
+		Leases:           registry.MetamorphicLeases,
+		CompatibleClouds: registry.AllExceptAWS,
+		Suites:           registry.Suites(registry.Nightly),
+		RequiresLicense:  true,
+		Run: func(ctx context.Context, t test.Test, c cluster.Cluster) {
+			runCDCKafkaTransactions(ctx, t, c)
+		},
+	})
 }
 
 const (
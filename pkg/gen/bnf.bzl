@@ -87,6 +87,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:column_table_def.bnf",
     "//docs/generated/sql/bnf:comment.bnf",
     "//docs/generated/sql/bnf:commit_transaction.bnf",
+    "//docs/generated/sql/bnf:compact_backup.bnf",
     "//docs/generated/sql/bnf:copy_stmt.bnf",
     "//docs/generated/sql/bnf:copy_to_stmt.bnf",
     "//docs/generated/sql/bnf:create_as_col_qual_list.bnf",
@@ -100,6 +101,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:create_index_stmt.bnf",
     "//docs/generated/sql/bnf:create_index_with_storage_param.bnf",
     "//docs/generated/sql/bnf:create_inverted_index_stmt.bnf",
+    "//docs/generated/sql/bnf:create_policy_stmt.bnf",
     "//docs/generated/sql/bnf:create_proc.bnf",
     "//docs/generated/sql/bnf:create_role_stmt.bnf",
     "//docs/generated/sql/bnf:create_schedule_for_backup_stmt.bnf",
@@ -120,6 +122,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:default_value_column_level.bnf",
     "//docs/generated/sql/bnf:delete_stmt.bnf",
     "//docs/generated/sql/bnf:discard_stmt.bnf",
+    "//docs/generated/sql/bnf:drop_backup.bnf",
     "//docs/generated/sql/bnf:drop_column.bnf",
     "//docs/generated/sql/bnf:drop_constraint.bnf",
     "//docs/generated/sql/bnf:drop_database.bnf",
@@ -128,6 +131,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:drop_func_stmt.bnf",
     "//docs/generated/sql/bnf:drop_index.bnf",
     "//docs/generated/sql/bnf:drop_owned_by_stmt.bnf",
+    "//docs/generated/sql/bnf:drop_policy_stmt.bnf",
     "//docs/generated/sql/bnf:drop_proc.bnf",
     "//docs/generated/sql/bnf:drop_role_stmt.bnf",
     "//docs/generated/sql/bnf:drop_schedule_stmt.bnf",
@@ -166,8 +170,11 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:legacy_transaction_stmt.bnf",
     "//docs/generated/sql/bnf:like_table_option_list.bnf",
     "//docs/generated/sql/bnf:limit_clause.bnf",
+    "//docs/generated/sql/bnf:listen_stmt.bnf",
+    "//docs/generated/sql/bnf:merge_stmt.bnf",
     "//docs/generated/sql/bnf:move_cursor_stmt.bnf",
     "//docs/generated/sql/bnf:not_null_column_level.bnf",
+    "//docs/generated/sql/bnf:notify_stmt.bnf",
     "//docs/generated/sql/bnf:offset_clause.bnf",
     "//docs/generated/sql/bnf:on_conflict.bnf",
     "//docs/generated/sql/bnf:opt_frame_clause.bnf",
@@ -289,6 +296,7 @@ BNF_SRCS = [
     "//docs/generated/sql/bnf:use_stmt.bnf",
     "//docs/generated/sql/bnf:validate_constraint.bnf",
     "//docs/generated/sql/bnf:values_clause.bnf",
+    "//docs/generated/sql/bnf:verify_backup.bnf",
     "//docs/generated/sql/bnf:window_definition.bnf",
     "//docs/generated/sql/bnf:with_clause.bnf",
 ]
@@ -316,6 +316,7 @@ var cockroachdbMetrics = map[string]string{
 	"changefeed_buffer_entries_out":                                       "changefeed.buffer_entries.out",
 	"changefeed_buffer_entries_released":                                  "changefeed.buffer_entries.released",
 	"changefeed_buffer_entries_resolved":                                  "changefeed.buffer_entries.resolved",
+	"changefeed_buffer_entries_schema_change":                             "changefeed.buffer_entries.schema_change",
 	"changefeed_buffer_pushback_nanos":                                    "changefeed.buffer_pushback",
 	"changefeed_bytes_messages_pushback_nanos":                            "changefeed.bytes.messages_pushback",
 	"changefeed_checkpoint_hist_nanos":                                    "changefeed.checkpoint_hist",
@@ -328,6 +329,7 @@ var cockroachdbMetrics = map[string]string{
 	"changefeed_commit_latency_bucket":                                    "changefeed.commit_latency.bucket",
 	"changefeed_commit_latency_count":                                     "changefeed.commit_latency.count",
 	"changefeed_commit_latency_sum":                                       "changefeed.commit_latency.sum",
+	"changefeed_dead_letter_queue_messages":                               "changefeed.dead_letter_queue_messages",
 	"changefeed_emitted_batch_sizes":                                      "changefeed.emitted_batch_sizes",
 	"changefeed_emitted_batch_sizes_bucket":                               "changefeed.emitted_batch_sizes.bucket",
 	"changefeed_emitted_batch_sizes_count":                                "changefeed.emitted_batch_sizes.count",
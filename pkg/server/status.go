@@ -3571,6 +3571,65 @@ func (s *statusServer) CancelQueryByKey(
 	return client.CancelQueryByKey(ctx, req)
 }
 
+// Notify delivers notifications raised by committed transactions to the
+// listening sessions. If a node is specified in the request, only that node
+// delivers the notifications; otherwise, the request is fanned out to every
+// node in the cluster, and the nodes that could not be reached are reported in
+// the response so that the caller can retry them.
+func (s *statusServer) Notify(
+	ctx context.Context, req *serverpb.NotifyRequest,
+) (*serverpb.NotifyResponse, error) {
+	ctx = authserver.ForwardSQLIdentityThroughRPCCalls(ctx)
+	ctx = s.AnnotateCtx(ctx)
+
+	localReq := &serverpb.NotifyRequest{
+		NodeID:        "local",
+		Notifications: req.Notifications,
+	}
+	resp := &serverpb.NotifyResponse{}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			resp.QueueFull = s.sqlServer.execCfg.NotificationRegistry.Deliver(req.Notifications)
+			return resp, nil
+		}
+
+		statusClient, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return statusClient.Notify(ctx, localReq)
+	}
+
+	notifyFn := func(ctx context.Context, statusClient serverpb.StatusClient, _ roachpb.NodeID) (*serverpb.NotifyResponse, error) {
+		return statusClient.Notify(ctx, localReq)
+	}
+
+	aggFn := func(_ roachpb.NodeID, nodeResp *serverpb.NotifyResponse) {
+		resp.QueueFull = resp.QueueFull || nodeResp.QueueFull
+	}
+
+	errFn := func(nodeID roachpb.NodeID, nodeFnError error) {
+		log.Warningf(ctx, "failed to deliver notifications to node %d: %v", nodeID, nodeFnError)
+		resp.FailedNodeIDs = append(resp.FailedNodeIDs, nodeID)
+	}
+
+	if err := iterateNodes(ctx,
+		s.serverIterator, s.stopper,
+		"Delivering notifications",
+		noTimeout,
+		s.dialNode,
+		notifyFn, aggFn, errFn); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // ListContentionEvents returns a list of contention events on all nodes in the
 // cluster.
 func (s *statusServer) ListContentionEvents(
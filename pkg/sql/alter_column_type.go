@@ -67,6 +67,12 @@ func AlterColumnType(
 			)
 		}
 	}
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].ReferencesColumn(col.GetID()) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot alter type of a column used in a policy definition")
+		}
+	}
 	if err := schemaexpr.ValidateTTLExpressionDoesNotDependOnColumn(tableDesc, tableDesc.GetRowLevelTTL(), col, tn, op); err != nil {
 		return err
 	}
@@ -142,6 +142,26 @@ func (p *planner) checkPasswordOptionConstraints(
 	return nil
 }
 
+// checkBypassRLSOptionConstraints checks that only admins can grant or revoke
+// the BYPASSRLS role option, since it exempts a role from every row-level
+// security policy.
+func (p *planner) checkBypassRLSOptionConstraints(
+	ctx context.Context, roleOptions roleoption.List,
+) error {
+	if !roleOptions.Contains(roleoption.BYPASSRLS) && !roleOptions.Contains(roleoption.NOBYPASSRLS) {
+		return nil
+	}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		return pgerror.New(pgcode.InsufficientPrivilege,
+			"only users with the admin role are allowed to change the BYPASSRLS option")
+	}
+	return nil
+}
+
 func (n *alterRoleNode) startExec(params runParams) error {
 	var opName string
 	if n.isRole {
@@ -190,6 +210,9 @@ func (n *alterRoleNode) startExec(params runParams) error {
 			return err
 		}
 	}
+	if err := params.p.checkBypassRLSOptionConstraints(params.ctx, n.roleOptions); err != nil {
+		return err
+	}
 
 	// Check if role exists.
 	row, err := params.p.InternalSQLTxn().QueryRowEx(
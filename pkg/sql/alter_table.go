@@ -715,6 +715,13 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableSetRowLevelSecurity:
+			changed, err := params.p.setRowLevelSecurityMode(params.ctx, n.tableDesc, t.Mode)
+			if err != nil {
+				return err
+			}
+			descriptorChanged = descriptorChanged || changed
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -922,6 +929,53 @@ func (p *planner) setAuditMode(
 	return desc.SetAuditMode(auditMode)
 }
 
+// setRowLevelSecurityMode enables, disables, forces or unforces row-level
+// security on the table. It returns whether the descriptor was changed.
+func (p *planner) setRowLevelSecurityMode(
+	ctx context.Context, desc *tabledesc.Mutable, mode tree.RowLevelSecurityMode,
+) (bool, error) {
+	// Unlike the other ALTER TABLE commands, postgres only allows the owner of
+	// the table to change its row-level security.
+	if err := p.checkPolicyTableOwnership(ctx, desc); err != nil {
+		return false, err
+	}
+
+	switch mode {
+	case tree.RowLevelSecurityEnable, tree.RowLevelSecurityForce:
+		if err := p.checkRowLevelSecuritySupported(
+			ctx, "ALTER TABLE ... "+mode.String()+" ROW LEVEL SECURITY",
+		); err != nil {
+			return false, err
+		}
+	}
+
+	switch mode {
+	case tree.RowLevelSecurityEnable:
+		if desc.RowLevelSecurityEnabled {
+			return false, nil
+		}
+		desc.RowLevelSecurityEnabled = true
+	case tree.RowLevelSecurityDisable:
+		if !desc.RowLevelSecurityEnabled {
+			return false, nil
+		}
+		desc.RowLevelSecurityEnabled = false
+	case tree.RowLevelSecurityForce:
+		if desc.RowLevelSecurityForced {
+			return false, nil
+		}
+		desc.RowLevelSecurityForced = true
+	case tree.RowLevelSecurityNoForce:
+		if !desc.RowLevelSecurityForced {
+			return false, nil
+		}
+		desc.RowLevelSecurityForced = false
+	default:
+		return false, errors.AssertionFailedf("unknown row-level security mode %d", mode)
+	}
+	return true, nil
+}
+
 func (n *alterTableNode) Next(runParams) (bool, error) { return false, nil }
 func (n *alterTableNode) Values() tree.Datums          { return tree.Datums{} }
 func (n *alterTableNode) Close(context.Context)        {}
@@ -1933,6 +1987,25 @@ func dropColumnImpl(
 		return nil, err
 	}
 
+	// You can't drop a column referenced by a row-level security policy unless
+	// CASCADE was specified, in which case the policy is dropped as well.
+	var policies []descpb.PolicyDescriptor
+	for _, policy := range tableDesc.Policies {
+		if !policy.ReferencesColumn(colToDrop.GetID()) {
+			policies = append(policies, policy)
+			continue
+		}
+		if t.DropBehavior != tree.DropCascade {
+			return nil, errors.WithHintf(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop column %s because policy %s on table %s depends on it",
+					colToDrop.ColName(), tree.Name(policy.Name), tree.Name(tableDesc.GetName())),
+				"Use DROP ... CASCADE to drop the dependent objects too.",
+			)
+		}
+	}
+	tableDesc.Policies = policies
+
 	if tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(colToDrop.GetID()) {
 		return nil, sqlerrors.NewColumnReferencedByPrimaryKeyError(colToDrop.GetName())
 	}
@@ -1808,7 +1808,12 @@ func countExpectedRowsForInvertedIndex(
 	) error {
 		var stmt string
 		geoConfig := idx.GetGeoConfig()
-		if geoConfig.IsEmpty() {
+		if vectorConfig := idx.GetVectorConfig(); !vectorConfig.IsEmpty() {
+			// Vector indexes have exactly one entry per non-NULL vector.
+			stmt = fmt.Sprintf(
+				`SELECT count(%s) FROM [%d AS t]`, colNameOrExpr, desc.GetID(),
+			)
+		} else if geoConfig.IsEmpty() {
 			stmt = fmt.Sprintf(
 				`SELECT coalesce(sum_int(crdb_internal.num_inverted_index_entries(%s, %d)), 0) FROM [%d AS t]`,
 				colNameOrExpr, idx.GetVersion(), desc.GetID(),
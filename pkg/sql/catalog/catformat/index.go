@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/errors"
 )
 
@@ -106,8 +107,13 @@ func indexForDisplay(
 	if index.Unique {
 		f.WriteString("UNIQUE ")
 	}
+	isVector := !index.VectorConfig.IsEmpty()
 	if !f.HasFlags(tree.FmtPGCatalog) && index.Type == descpb.IndexDescriptor_INVERTED {
-		f.WriteString("INVERTED ")
+		if isVector {
+			f.WriteString("VECTOR ")
+		} else {
+			f.WriteString("INVERTED ")
+		}
 	}
 	f.WriteString("INDEX ")
 	f.FormatNameP(&index.Name)
@@ -118,7 +124,9 @@ func indexForDisplay(
 
 	if f.HasFlags(tree.FmtPGCatalog) {
 		f.WriteString(" USING")
-		if index.Type == descpb.IndexDescriptor_INVERTED {
+		if isVector {
+			f.WriteString(" ivfflat")
+		} else if index.Type == descpb.IndexDescriptor_INVERTED {
 			f.WriteString(" gin")
 		} else {
 			f.WriteString(" btree")
@@ -249,6 +257,12 @@ func FormatIndexElements(
 			switch index.InvertedColumnKinds[0] {
 			case catpb.InvertedIndexColumnKind_TRIGRAM:
 				f.WriteString(" gin_trgm_ops")
+			case catpb.InvertedIndexColumnKind_VECTOR_L2:
+				f.WriteString(" vector_l2_ops")
+			case catpb.InvertedIndexColumnKind_VECTOR_COSINE:
+				f.WriteString(" vector_cosine_ops")
+			case catpb.InvertedIndexColumnKind_VECTOR_IP:
+				f.WriteString(" vector_ip_ops")
 			}
 		}
 		// The last column of an inverted index cannot have a DESC direction.
@@ -339,6 +353,15 @@ func formatStorageConfigs(
 		}
 	}
 
+	if !index.VectorConfig.IsEmpty() {
+		if lists := vecindex.Lists(index.VectorConfig); lists != vecindex.DefaultLists {
+			f.WriteString(" WITH (")
+			numCustomSettings++
+			f.WriteString(`lists=`)
+			f.WriteString(strconv.FormatInt(lists, 10))
+		}
+	}
+
 	if index.IsSharded() {
 		if numCustomSettings > 0 {
 			f.WriteString(", ")
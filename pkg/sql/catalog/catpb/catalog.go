@@ -131,3 +131,18 @@ func (rowLevelTTL *RowLevelTTL) GetTTLExpr() Expression {
 	}
 	return DefaultTTLExpirationExpr
 }
+
+// IsEmpty returns true if the config does not describe a vector index.
+func (cfg VectorIndexConfig) IsEmpty() bool {
+	return cfg.Dims == 0
+}
+
+// IsVector returns whether the kind is one of the vector index column kinds.
+func (k InvertedIndexColumnKind) IsVector() bool {
+	switch k {
+	case InvertedIndexColumnKind_VECTOR_L2, InvertedIndexColumnKind_VECTOR_COSINE,
+		InvertedIndexColumnKind_VECTOR_IP:
+		return true
+	}
+	return false
+}
@@ -167,6 +167,10 @@ func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 		return t.ArrayContents().Family() != types.RefCursorFamily
 	case types.JsonFamily, types.StringFamily:
 		return true
+	case types.PGVectorFamily:
+		// Vectors can be indexed by vector indexes, which are inverted indexes
+		// that partition the vector space.
+		return true
 	}
 	return ColumnTypeIsOnlyInvertedIndexable(t)
 }
@@ -12,6 +12,7 @@ package descpb
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/protoreflect"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
@@ -74,6 +75,12 @@ type IndexID = catid.IndexID
 // ConstraintID is a custom type for TableDescriptor constraint IDs.
 type ConstraintID = catid.ConstraintID
 
+// PolicyID is a custom type for TableDescriptor row-level security policy IDs.
+type PolicyID uint32
+
+// SafeValue implements the redact.SafeValue interface.
+func (PolicyID) SafeValue() {}
+
 // DescriptorVersion is a custom type for TableDescriptor Versions.
 type DescriptorVersion uint64
 
@@ -327,6 +334,21 @@ func (u *UniqueWithoutIndexConstraint) IsPartial() bool {
 	return u.Predicate != ""
 }
 
+// ReferencesColumn returns true if the policy's USING or WITH CHECK
+// expression references the given column.
+func (p *PolicyDescriptor) ReferencesColumn(colID ColumnID) bool {
+	return ColumnIDs(p.ColumnIDs).Contains(colID)
+}
+
+// Roles returns the roles that the policy applies to.
+func (p *PolicyDescriptor) Roles() []username.SQLUsername {
+	roles := make([]username.SQLUsername, len(p.RoleProtos))
+	for i, role := range p.RoleProtos {
+		roles[i] = role.Decode()
+	}
+	return roles
+}
+
 // GetParentID implements the catalog.NameKeyHaver interface.
 func (ni NameInfo) GetParentID() ID {
 	return ni.ParentID
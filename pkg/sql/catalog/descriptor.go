@@ -764,6 +764,14 @@ type TableDescriptor interface {
 	// IsSchemaLocked returns true if we don't allow performing schema changes
 	// on this table descriptor.
 	IsSchemaLocked() bool
+	// IsRowLevelSecurityEnabled returns true if the rows accessed by queries
+	// against the table are restricted by its row-level security policies.
+	IsRowLevelSecurityEnabled() bool
+	// IsRowLevelSecurityForced returns true if the row-level security policies
+	// of the table also apply to its owner.
+	IsRowLevelSecurityForced() bool
+	// GetPolicies returns the row-level security policies of the table.
+	GetPolicies() []descpb.PolicyDescriptor
 	// IsPrimaryKeySwapMutation returns true if the mutation is a primary key
 	// swap mutation or a secondary index used by the declarative schema changer
 	// for a primary index swap.
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -131,6 +132,40 @@ func DequalifyAndValidateExpr(
 		getAllNonDropColumnsFn, columnLookupByNameFn)
 }
 
+// MakeFilterExpr turns a boolean expression over the columns of tableDesc,
+// which has previously been validated with DequalifyAndValidateExpr, from a
+// string to a TypedExpr. Columns are replaced with IndexedVars that refer to
+// their ordinal in cols, so the expression can be evaluated against rows of
+// those columns.
+func MakeFilterExpr(
+	ctx context.Context,
+	filter string,
+	cols []catalog.Column,
+	tableDesc catalog.TableDescriptor,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	expr, err := parser.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tn := tree.NewUnqualifiedTableName(tree.Name(tableDesc.GetName()))
+	nr := newNameResolver(evalCtx, tableDesc.GetID(), tn, cols)
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	expr, err = nr.resolveNames(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Bool)
+	if err != nil {
+		return nil, err
+	}
+	var txCtx transform.ExprTransformContext
+	return txCtx.NormalizeExpr(ctx, evalCtx, typedExpr)
+}
+
 // ExtractColumnIDs returns the set of column IDs within the given expression.
 func ExtractColumnIDs(
 	desc catalog.TableDescriptor, rootExpr tree.Expr,
@@ -178,6 +178,7 @@ type Index interface {
 	GetPredicate() string
 	GetType() descpb.IndexDescriptor_Type
 	GetGeoConfig() geopb.Config
+	GetVectorConfig() catpb.VectorIndexConfig
 	GetVersion() descpb.IndexDescriptorVersion
 	GetEncodingType() catenumpb.IndexDescriptorEncodingType
 
@@ -269,6 +269,11 @@ func (w index) GetGeoConfig() geopb.Config {
 	return w.desc.GeoConfig
 }
 
+// GetVectorConfig returns the vector config in the index descriptor.
+func (w index) GetVectorConfig() catpb.VectorIndexConfig {
+	return w.desc.VectorConfig
+}
+
 // GetSharded returns the ShardedDescriptor in the index descriptor
 func (w index) GetSharded() catpb.ShardedDescriptor {
 	return w.desc.Sharded
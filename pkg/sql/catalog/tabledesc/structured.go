@@ -315,6 +315,21 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 	}
 
+	// Process row-level security policies.
+	for i := range desc.Policies {
+		p := &desc.Policies[i]
+		if p.UsingExpr != "" {
+			if err := f(&p.UsingExpr); err != nil {
+				return err
+			}
+		}
+		if p.WithCheckExpr != "" {
+			if err := f(&p.WithCheckExpr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Process all non-index mutations.
 	for _, mut := range desc.Mutations {
 		if c := mut.GetColumn(); c != nil {
@@ -1067,6 +1082,16 @@ func NewInvalidInvertedColumnError(colName, colType string) error {
 	)
 }
 
+// NewInvalidVectorColumnError returns an error for a column that cannot be
+// indexed by a vector index.
+func NewInvalidVectorColumnError(colName, colType string) error {
+	return pgerror.Newf(
+		pgcode.FeatureNotSupported,
+		"column %s of type %s cannot be indexed by a vector index",
+		colName, colType,
+	)
+}
+
 // AddColumn adds a column to the table.
 func (desc *Mutable) AddColumn(col *descpb.ColumnDescriptor) {
 	desc.Columns = append(desc.Columns, *col)
@@ -650,6 +650,16 @@ func (desc *wrapper) IsSchemaLocked() bool {
 	return desc.SchemaLocked
 }
 
+// IsRowLevelSecurityEnabled implements the TableDescriptor interface.
+func (desc *wrapper) IsRowLevelSecurityEnabled() bool {
+	return desc.RowLevelSecurityEnabled
+}
+
+// IsRowLevelSecurityForced implements the TableDescriptor interface.
+func (desc *wrapper) IsRowLevelSecurityForced() bool {
+	return desc.RowLevelSecurityForced
+}
+
 // IsPrimaryKeySwapMutation implements the TableDescriptor interface.
 func (desc *wrapper) IsPrimaryKeySwapMutation(m *descpb.DescriptorMutation) bool {
 	switch t := m.Descriptor_.(type) {
@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/interval"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
@@ -869,6 +870,7 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnsByID),
 			desc.validateCheckConstraints(columnsByID),
 			desc.validateUniqueWithoutIndexConstraints(columnsByID),
+			desc.validatePolicies(columnsByID),
 			desc.validateTableIndexes(columnsByID, vea.IsActive),
 			desc.validatePartitioning(),
 		}
@@ -1328,6 +1330,58 @@ func (desc *wrapper) validateCheckConstraints(
 	return nil
 }
 
+// validatePolicies validates that the row-level security policies are well
+// formed. Checks include validating the policy names and IDs, the column IDs,
+// and verifying that the policy expressions do not reference non-existent
+// columns.
+func (desc *wrapper) validatePolicies(columnsByID map[descpb.ColumnID]catalog.Column) error {
+	names := make(map[string]struct{}, len(desc.Policies))
+	ids := make(map[descpb.PolicyID]struct{}, len(desc.Policies))
+	for i := range desc.Policies {
+		p := &desc.Policies[i]
+		if len(p.Name) == 0 {
+			return pgerror.Newf(pgcode.Syntax, "empty policy name")
+		}
+		if _, ok := names[p.Name]; ok {
+			return errors.Newf("duplicate policy name: %q", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.ID == 0 || p.ID >= desc.NextPolicyID {
+			return errors.AssertionFailedf("policy %q has invalid ID %d", p.Name, p.ID)
+		}
+		if _, ok := ids[p.ID]; ok {
+			return errors.AssertionFailedf("duplicate policy ID: %d", p.ID)
+		}
+		ids[p.ID] = struct{}{}
+		if len(p.RoleProtos) == 0 {
+			return errors.AssertionFailedf("policy %q does not apply to any role", p.Name)
+		}
+		for _, colID := range p.ColumnIDs {
+			if _, ok := columnsByID[colID]; !ok {
+				return errors.Newf("policy %q contains unknown column \"%d\"", p.Name, colID)
+			}
+		}
+		for _, exprStr := range []string{p.UsingExpr, p.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(exprStr)
+			if err != nil {
+				return err
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf("policy %q refers to unknown columns in expression: %s",
+					p.Name, exprStr)
+			}
+		}
+	}
+	return nil
+}
+
 // validateUniqueWithoutIndexConstraints validates that unique without index
 // constraints are well formed. Checks include validating the column IDs and
 // column names.
@@ -1548,6 +1602,24 @@ func (desc *wrapper) validateTableIndexes(
 			}
 		}
 
+		isVectorIndex := idx.GetType() == descpb.IndexDescriptor_INVERTED && idx.InvertedColumnKind().IsVector()
+		if vectorConfig := idx.GetVectorConfig(); isVectorIndex || !vectorConfig.IsEmpty() {
+			if !isVectorIndex || vectorConfig.IsEmpty() {
+				return errors.Newf("vector index %q has an invalid configuration", idx.GetName())
+			}
+			col, ok := columnsByID[idx.InvertedColumnID()]
+			if !ok || col.GetType().Family() != types.PGVectorFamily {
+				return errors.Newf("vector index %q must index a vector column", idx.GetName())
+			}
+			if col.GetType().Width() != vectorConfig.Dims {
+				return errors.Newf("vector index %q has %d dimensions, but column %q has %d",
+					idx.GetName(), vectorConfig.Dims, col.GetName(), col.GetType().Width())
+			}
+			if err := vecindex.ValidateConfig(vectorConfig); err != nil {
+				return errors.Wrapf(err, "vector index %q has an invalid configuration", idx.GetName())
+			}
+		}
+
 		if !idx.IsMutation() {
 			if idx.IndexDesc().UseDeletePreservingEncoding {
 				return errors.Newf("public index %q is using the delete preserving encoding", idx.GetName())
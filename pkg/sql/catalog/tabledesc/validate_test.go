@@ -142,6 +142,10 @@ var validationMap = []struct {
 			"ImportType":                    {status: thisFieldReferencesNoObjects},
 			"External": {status: todoIAmKnowinglyAddingTechDebt,
 				reason: "TODO(features): add validation that TableID is sane within the same tenant"},
+			"Policies":                {status: iSolemnlySwearThisFieldIsValidated},
+			"NextPolicyID":            {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelSecurityEnabled": {status: thisFieldReferencesNoObjects},
+			"RowLevelSecurityForced":  {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -177,6 +181,7 @@ var validationMap = []struct {
 			"Sharded":                     {status: iSolemnlySwearThisFieldIsValidated},
 			"Disabled":                    {status: thisFieldReferencesNoObjects},
 			"GeoConfig":                   {status: thisFieldReferencesNoObjects},
+			"VectorConfig":                {status: iSolemnlySwearThisFieldIsValidated},
 			"Predicate":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"UseDeletePreservingEncoding": {status: thisFieldReferencesNoObjects},
 			"ConstraintID":                {status: iSolemnlySwearThisFieldIsValidated},
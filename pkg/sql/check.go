@@ -877,6 +877,13 @@ func checkMutationInput(
 		}
 		colIdx++
 	}
+	// The row-level security check, if any, follows the table's enforced
+	// checks. Unlike CHECK constraints, a NULL result is a violation.
+	if checkOrds.Contains(len(checks)) {
+		if res, err := tree.GetBool(checkVals[colIdx]); err != nil || !res {
+			return row.RowLevelSecurityCheckFailed(tabDesc)
+		}
+	}
 	return nil
 }
 
@@ -57,6 +57,7 @@ func (b *BatchEncoder) encodeInvertedSecondaryIndex(
 		vec = b.b.ColVecs()[i]
 	}
 	indexGeoConfig := index.GetGeoConfig()
+	indexVectorConfig := index.GetVectorConfig()
 	for row := 0; row < b.count; row++ {
 		if kys[row] == nil {
 			continue
@@ -67,6 +68,10 @@ func (b *BatchEncoder) encodeInvertedSecondaryIndex(
 			if keys, err = rowenc.EncodeGeoInvertedIndexTableKeys(ctx, val, kys[row], indexGeoConfig); err != nil {
 				return err
 			}
+		} else if !indexVectorConfig.IsEmpty() {
+			if keys, err = rowenc.EncodeVectorInvertedIndexTableKeys(val, kys[row], indexVectorConfig); err != nil {
+				return err
+			}
 		} else {
 			if keys, err = rowenc.EncodeInvertedIndexTableKeys(val, kys[row], index.GetVersion()); err != nil {
 				return err
@@ -222,5 +222,17 @@ func (v *vectorInserter) checkMutationInput(ctx context.Context, b coldata.Batch
 		}
 		colIdx++
 	}
+	// The row-level security check, if any, follows the table's enforced
+	// checks. Unlike CHECK constraints, a NULL result is a violation.
+	if v.checkOrds.Contains(len(checks)) {
+		vec := b.ColVec(colIdx + len(v.insertCols))
+		bools := vec.Bool()
+		nulls := vec.Nulls()
+		for r := 0; r < b.Length(); r++ {
+			if !bools[r] || nulls.NullAt(r) {
+				return row.RowLevelSecurityCheckFailed(v.desc)
+			}
+		}
+	}
 	return nil
 }
@@ -47,6 +47,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxrecommendations"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
@@ -1269,6 +1270,10 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	// Free any memory used by the stats collector.
 	ex.statsCollector.Free(ctx)
 
+	if ex.notificationListener != nil {
+		ex.notificationListener.Close()
+	}
+
 	var payloadErr error
 	if closeType == normalClose {
 		// We'll cleanup the SQL txn by creating a non-retriable (commit:true) event.
@@ -1635,6 +1640,11 @@ type connExecutor struct {
 		// The map key is the sequence descpb.ID.
 		createdSequences map[descpb.ID]struct{}
 
+		// notifications keeps track of the notifications raised and the LISTEN
+		// and UNLISTEN operations executed in the current transaction, which
+		// take effect when it commits.
+		notifications txnNotifications
+
 		// shouldLogToTelemetry indicates if the current transaction should be
 		// logged to telemetry. It is used in telemetry transaction sampling
 		// mode to emit all statement events for a particular transaction.
@@ -1741,6 +1751,10 @@ type connExecutor struct {
 	// pgwire cancellation protocol.
 	queryCancelKey pgwirecancel.BackendKeyData
 
+	// notificationListener receives the notifications sent on the channels
+	// that the session is listening on. It is created by the first LISTEN.
+	notificationListener *notify.Listener
+
 	// activated determines whether activate() was called already.
 	// When this is set, close() must be called to release resources.
 	activated bool
@@ -2069,6 +2083,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.extraTxnState.notifications.reset()
 
 	if ex.extraTxnState.skipResettingSchemaObjects {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -2536,6 +2551,10 @@ func (ex *connExecutor) execCmd() (retErr error) {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// The notifications are buffered right before the result is closed,
+		// once it's known whether the session is in a transaction.
+		res = ex.clientComm.CreateDeliverNotificationsResult(pos)
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -2625,6 +2644,12 @@ func (ex *connExecutor) execCmd() (retErr error) {
 				}
 			}
 		}
+		// Deliver the notifications received by the session right before a
+		// ReadyForQuery message, or asynchronously when the session is idle.
+		switch cmd.(type) {
+		case Sync, DeliverNotifications:
+			ex.bufferNotifications(res.(NotificationBuffer))
+		}
 		res.Close(ctx, stateToTxnStatusIndicator(ex.machine.CurState()))
 	} else {
 		res.Discard()
@@ -2805,6 +2830,8 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -3831,6 +3858,7 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sqlCursors = ex.getCursorAccessor()
 	p.storedProcTxnState = ex.getStoredProcTxnStateAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.notifications = ex.getNotificationsAccessor()
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -4016,6 +4044,7 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 			}
 		}
 		ex.notifyStatsRefresherOfNewTables(ex.Ctx())
+		ex.commitNotifications(ex.Ctx())
 
 		// If there is any descriptor has new version. We want to make sure there is
 		// only one version of the descriptor in all nodes. In schema changer jobs,
@@ -4338,6 +4367,12 @@ func (ex *connExecutor) getCreatedSequencesAccessor() createdSequences {
 	}
 }
 
+func (ex *connExecutor) getNotificationsAccessor() sessionNotifications {
+	return connExNotificationsAccessor{
+		ex: ex,
+	}
+}
+
 // sessionEventf logs a message to the session event log (if any).
 func (ex *connExecutor) sessionEventf(ctx context.Context, format string, args ...interface{}) {
 	if log.ExpensiveLogEnabled(ctx, 2) {
@@ -772,7 +772,7 @@ func (ex *connExecutor) execStmtInOpenState(
 			rowsAffected = ppInfo.dispatchToExecutionEngine.rowsAffected
 		} else {
 			switch p.stmt.AST.(type) {
-			case *tree.Import, *tree.Restore, *tree.Backup:
+			case *tree.Import, *tree.Restore, *tree.Backup, *tree.CompactBackup, *tree.VerifyBackup:
 				bulkJobId = res.GetBulkJobId()
 			}
 			// Note that for bulk job query (IMPORT, BACKUP and RESTORE), we don't
@@ -1561,6 +1561,10 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 
 	ex.extraTxnState.prepStmtsNamespace.closeAllPortals(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc)
 
+	if err := ex.checkNotificationQueue(ctx); err != nil {
+		return err
+	}
+
 	// We need to step the transaction's internal read sequence before committing
 	// if it has stepping enabled. If it doesn't have stepping enabled, then we
 	// just set the stepping mode back to what it was.
@@ -101,10 +101,11 @@ func (ex *connExecutor) execSavepointInOpenState(
 	}
 
 	sp := savepoint{
-		name:            s.Name,
-		commitOnRelease: commitOnRelease,
-		kvToken:         token,
-		numDDL:          ex.extraTxnState.numDDL,
+		name:             s.Name,
+		commitOnRelease:  commitOnRelease,
+		kvToken:          token,
+		numDDL:           ex.extraTxnState.numDDL,
+		notificationsPos: ex.extraTxnState.notifications.pos(),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -213,6 +214,7 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.notifications.rollbackTo(entry.notificationsPos)
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -293,6 +295,7 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.notifications.rollbackTo(entry.notificationsPos)
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -357,6 +360,11 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// The position in the notifications and LISTEN and UNLISTEN operations of
+	// the transaction at the time the savepoint was created. Rolling back to
+	// the savepoint discards the ones that came after.
+	notificationsPos txnNotificationsPos
 }
 
 type savepointStack []savepoint
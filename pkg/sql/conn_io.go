@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -452,6 +453,25 @@ func (DrainRequest) String() string {
 
 var _ Command = DrainRequest{}
 
+// DeliverNotifications is a command asking for the notifications received by
+// the session's listener to be delivered to the client. It is pushed to the
+// StmtBuf by the listener when notifications become available. The
+// notifications are only delivered if the session is not in a transaction;
+// otherwise, they are delivered by the Sync that ends the transaction.
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (e DeliverNotifications) isExtendedProtocolCmd() bool { return false }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // SendError is a command that, upon execution, send a specific error to the
 // client. This is used by pgwire to schedule errors to be sent at an
 // appropriate time.
@@ -762,6 +782,9 @@ type ClientComm interface {
 	CreateCopyOutResult(cmd CopyOut, pos CmdPos) CopyOutResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateDeliverNotificationsResult creates a result for a
+	// DeliverNotifications command.
+	CreateDeliverNotificationsResult(pos CmdPos) DeliverNotificationsResult
 
 	// LockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -958,6 +981,7 @@ type DeleteResult interface {
 // flushed.
 type SyncResult interface {
 	ResultBase
+	NotificationBuffer
 }
 
 // FlushResult represents the result of a Flush command. When this result is
@@ -972,6 +996,22 @@ type DrainResult interface {
 	ResultBase
 }
 
+// DeliverNotificationsResult represents the result of a DeliverNotifications
+// command. When closed, the buffered notifications are flushed to the client;
+// if there are none, closing this result produces no output.
+type DeliverNotificationsResult interface {
+	ResultBase
+	NotificationBuffer
+}
+
+// NotificationBuffer is implemented by results that can carry the
+// asynchronous notifications received by a session.
+type NotificationBuffer interface {
+	// BufferNotification buffers a notification to be sent to the client
+	// before the result is closed.
+	BufferNotification(notification serverpb.Notification)
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
@@ -1117,6 +1157,11 @@ func (r *streamingCommandResult) BufferNotice(notice pgnotice.Notice) {
 	// Unimplemented: the internal executor does not support notices.
 }
 
+// BufferNotification is part of the NotificationBuffer interface.
+func (r *streamingCommandResult) BufferNotification(notification serverpb.Notification) {
+	// Unimplemented: the internal executor does not support notifications.
+}
+
 // SendNotice is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) SendNotice(ctx context.Context, notice pgnotice.Notice) error {
 	// Unimplemented: the internal executor does not support notices.
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/storageparam/indexstorageparam"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
@@ -228,6 +229,9 @@ func makeIndexDescriptor(
 		if err != nil {
 			return nil, err
 		}
+		if n.Vector && column.GetType().Family() != types.PGVectorFamily {
+			return nil, tabledesc.NewInvalidVectorColumnError(column.GetName(), column.GetType().String())
+		}
 		if err := populateInvertedIndexDescriptor(
 			params.ctx, params.ExecCfg().Settings, column, &indexDesc, invCol); err != nil {
 			return nil, err
@@ -297,6 +301,9 @@ func makeIndexDescriptor(
 		if len(indexDesc.InvertedColumnKinds) > 0 && indexDesc.InvertedColumnKinds[0] == catpb.InvertedIndexColumnKind_TRIGRAM {
 			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 		}
+		if !indexDesc.VectorConfig.IsEmpty() {
+			telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+		}
 		if indexDesc.IsPartial() {
 			telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 		}
@@ -418,6 +425,22 @@ func populateInvertedIndexDescriptor(
 		default:
 			return newUndefinedOpclassError(invCol.OpClass)
 		}
+	case types.PGVectorFamily:
+		switch invCol.OpClass {
+		case "vector_l2_ops", "":
+			indexDesc.InvertedColumnKinds[0] = catpb.InvertedIndexColumnKind_VECTOR_L2
+		case "vector_cosine_ops":
+			indexDesc.InvertedColumnKinds[0] = catpb.InvertedIndexColumnKind_VECTOR_COSINE
+		case "vector_ip_ops":
+			indexDesc.InvertedColumnKinds[0] = catpb.InvertedIndexColumnKind_VECTOR_IP
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+		config, err := vecindex.MakeConfig(column.GetType().Width(), vecindex.DefaultLists)
+		if err != nil {
+			return errors.Wrapf(err, "cannot create vector index on column %q", column.GetName())
+		}
+		indexDesc.VectorConfig = config
 	default:
 		return tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name())
 	}
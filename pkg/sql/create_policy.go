@@ -0,0 +1,205 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createPolicyNode struct {
+	n         *tree.CreatePolicy
+	tableDesc *tabledesc.Mutable
+	roles     []username.SQLUsername
+}
+
+// checkRowLevelSecuritySupported returns an error if the cluster may have
+// nodes that do not enforce row-level security policies.
+func (p *planner) checkRowLevelSecuritySupported(ctx context.Context, stmt string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_RowLevelSecurity) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only supported after v24.2 upgrade is finalized", stmt)
+	}
+	return nil
+}
+
+// CreatePolicy creates a row-level security policy on a table.
+// Privileges: ownership of the table.
+//
+//	notes: postgres requires ownership of the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+	if err := p.checkRowLevelSecuritySupported(ctx, "CREATE POLICY"); err != nil {
+		return nil, err
+	}
+
+	switch n.Command {
+	case tree.PolicyCommandSelect, tree.PolicyCommandDelete:
+		if n.WithCheck != nil {
+			return nil, pgerror.New(pgcode.Syntax,
+				"WITH CHECK cannot be applied to SELECT or DELETE")
+		}
+	case tree.PolicyCommandInsert:
+		if n.Using != nil {
+			return nil, pgerror.New(pgcode.Syntax,
+				"only WITH CHECK expression allowed for INSERT")
+		}
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptorEx(
+		ctx, n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPolicyTableOwnership(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"policy %q for table %q already exists", n.Name, tableDesc.GetName())
+		}
+	}
+
+	roles, err := decodeusername.FromRoleSpecList(
+		p.SessionData(), username.PurposeValidation, n.Roles,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.IsPublicRole() {
+			continue
+		}
+		if err := p.CheckRoleExists(ctx, role); err != nil {
+			return nil, err
+		}
+	}
+	if len(roles) == 0 {
+		roles = []username.SQLUsername{username.PublicRoleName()}
+	}
+
+	return &createPolicyNode{n: n, tableDesc: tableDesc, roles: roles}, nil
+}
+
+// checkPolicyTableOwnership returns an error if the current user does not own
+// the given table. Only owners may manage the row-level security of a table.
+func (p *planner) checkPolicyTableOwnership(
+	ctx context.Context, tableDesc catalog.TableDescriptor,
+) error {
+	hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s", tree.Name(tableDesc.GetName()))
+	}
+	return nil
+}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("policy"))
+
+	tableDesc := n.tableDesc
+	resolved := params.p.ResolvedName(n.n.Table)
+	tn, ok := resolved.(*tree.TableName)
+	if !ok {
+		tmp := tree.MakeUnqualifiedTableName(tree.Name(tableDesc.GetName()))
+		tn = &tmp
+	}
+
+	if tableDesc.NextPolicyID == 0 {
+		tableDesc.NextPolicyID = 1
+	}
+	policy := descpb.PolicyDescriptor{
+		ID:      tableDesc.NextPolicyID,
+		Name:    string(n.n.Name),
+		Type:    descpb.PolicyDescriptor_Type(n.n.Type),
+		Command: descpb.PolicyDescriptor_Command(n.n.Command),
+	}
+	for _, role := range n.roles {
+		policy.RoleProtos = append(policy.RoleProtos, role.EncodeProto())
+	}
+
+	var referencedCols catalog.TableColSet
+	validate := func(expr tree.Expr, context tree.SchemaExprContext) (string, error) {
+		if expr == nil {
+			return "", nil
+		}
+		serialized, _, colIDs, err := schemaexpr.DequalifyAndValidateExpr(
+			params.ctx,
+			tableDesc,
+			expr,
+			types.Bool,
+			context,
+			&params.p.semaCtx,
+			volatility.Volatile,
+			tn,
+			params.ExecCfg().Settings.Version.ActiveVersion(params.ctx),
+		)
+		if err != nil {
+			return "", err
+		}
+		referencedCols.UnionWith(colIDs)
+		return serialized, nil
+	}
+	var err error
+	if policy.UsingExpr, err = validate(n.n.Using, tree.PolicyUsingExpr); err != nil {
+		return err
+	}
+	if policy.WithCheckExpr, err = validate(n.n.WithCheck, tree.PolicyWithCheckExpr); err != nil {
+		return err
+	}
+	policy.ColumnIDs = referencedCols.Ordered()
+
+	tableDesc.Policies = append(tableDesc.Policies, policy)
+	tableDesc.NextPolicyID++
+
+	if err := params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx,
+		tableDesc.ID,
+		&eventpb.AlterTable{
+			TableName: tn.FQString(),
+		})
+}
+
+func (n *createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPolicyNode) Close(context.Context)        {}
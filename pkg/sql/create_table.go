@@ -1868,6 +1868,9 @@ func NewTableDesc(
 				if err != nil {
 					return nil, err
 				}
+				if d.Vector && column.GetType().Family() != types.PGVectorFamily {
+					return nil, tabledesc.NewInvalidVectorColumnError(column.GetName(), column.GetType().String())
+				}
 				if err := populateInvertedIndexDescriptor(
 					ctx, evalCtx.Settings, column, &idx, columns[len(columns)-1]); err != nil {
 					return nil, err
@@ -2291,6 +2294,9 @@ func NewTableDesc(
 			if idx.InvertedColumnKind() == catpb.InvertedIndexColumnKind_TRIGRAM {
 				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
 			}
+			if vectorConfig := idx.GetVectorConfig(); !vectorConfig.IsEmpty() {
+				telemetry.Inc(sqltelemetry.VectorInvertedIndexCounter)
+			}
 			if idx.IsPartial() {
 				telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 			}
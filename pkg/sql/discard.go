@@ -67,6 +67,11 @@ func (n *discardNode) startExec(params runParams) error {
 			return err
 		}
 
+		// UNLISTEN *
+		if err := params.p.notifications.unlisten("" /* channel */); err != nil {
+			return err
+		}
+
 	case tree.DiscardModeSequences:
 		params.p.sessionDataMutatorIterator.applyOnEachMutator(func(m sessionDataMutator) {
 			m.data.SequenceState = sessiondata.NewSequenceState()
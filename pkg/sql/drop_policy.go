@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type dropPolicyNode struct {
+	n         *tree.DropPolicy
+	tableDesc *tabledesc.Mutable
+	// idx is the position of the policy in tableDesc.Policies, or -1 if the
+	// policy does not exist and IF EXISTS was specified.
+	idx int
+}
+
+// DropPolicy drops a row-level security policy from a table.
+// Privileges: ownership of the table.
+//
+//	notes: postgres requires ownership of the table.
+func (p *planner) DropPolicy(ctx context.Context, n *tree.DropPolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptorEx(
+		ctx, n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.checkPolicyTableOwnership(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	idx := -1
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name == string(n.Name) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 && !n.IfExists {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"policy %q for table %q does not exist", n.Name, tableDesc.GetName())
+	}
+
+	return &dropPolicyNode{n: n, tableDesc: tableDesc, idx: idx}, nil
+}
+
+func (n *dropPolicyNode) startExec(params runParams) error {
+	if n.idx == -1 {
+		return nil
+	}
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("policy"))
+
+	tableDesc := n.tableDesc
+	tableDesc.Policies = append(tableDesc.Policies[:n.idx], tableDesc.Policies[n.idx+1:]...)
+
+	if err := params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	return params.p.logEvent(params.ctx,
+		tableDesc.ID,
+		&eventpb.AlterTable{
+			TableName: params.p.ResolvedName(n.n.Table).FQString(),
+		})
+}
+
+func (n *dropPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropPolicyNode) Close(context.Context)        {}
@@ -122,6 +122,8 @@ func (n *DropRoleNode) startExec(params runParams) error {
 
 	privilegeObjectFormatter := tree.NewFmtCtx(tree.FmtSimple)
 	defer privilegeObjectFormatter.Close()
+	policyObjectFormatter := tree.NewFmtCtx(tree.FmtSimple)
+	defer policyObjectFormatter.Close()
 
 	// First check all the databases.
 	if err := forEachDatabaseDesc(params.ctx, params.p, nil /*nil prefix = all databases*/, true, /* requiresPrivileges */
@@ -190,6 +192,23 @@ func (n *DropRoleNode) startExec(params runParams) error {
 				break
 			}
 		}
+		for _, policy := range tableDescriptor.GetPolicies() {
+			for _, role := range policy.Roles() {
+				if _, ok := userNames[role]; !ok {
+					continue
+				}
+				if policyObjectFormatter.Len() > 0 {
+					policyObjectFormatter.WriteString(", ")
+				}
+				parentName := lCtx.getDatabaseName(tableDescriptor)
+				schemaName := lCtx.getSchemaName(tableDescriptor)
+				tn := tree.MakeTableNameWithSchema(tree.Name(parentName), tree.Name(schemaName), tree.Name(tableDescriptor.GetName()))
+				policyObjectFormatter.FormatName(policy.Name)
+				policyObjectFormatter.WriteString(" on ")
+				policyObjectFormatter.FormatNode(&tn)
+				break
+			}
+		}
 	}
 	for _, schemaDesc := range lCtx.schemaDescs {
 		if !descriptorIsVisible(schemaDesc, true /* allowAdding */) {
@@ -317,6 +336,23 @@ func (n *DropRoleNode) startExec(params runParams) error {
 		)
 	}
 
+	// Was there any row-level security policy applying to that user?
+	if policyObjectFormatter.Len() > 0 {
+		fnl := tree.NewFmtCtx(tree.FmtSimple)
+		defer fnl.Close()
+		for i, name := range n.roleNames {
+			if i > 0 {
+				fnl.WriteString(", ")
+			}
+			fnl.FormatName(name.Normalized())
+		}
+		return pgerror.Newf(pgcode.DependentObjectsStillExist,
+			"cannot drop role%s/user%s %s: policies still exist: %s",
+			util.Pluralize(int64(len(n.roleNames))), util.Pluralize(int64(len(n.roleNames))),
+			fnl.String(), policyObjectFormatter.String(),
+		)
+	}
+
 	hasDependentDefaultPrivilege := false
 	for _, name := range n.roleNames {
 		// Did the user own any objects?
@@ -236,7 +236,7 @@ func (p *planner) maybeLogStatementInternal(
 	// print out the number of changed rows along with the sampled query event.
 	// We emit it when the job succeeds in a recovery_event.
 	switch p.stmt.AST.(type) {
-	case *tree.Import, *tree.Restore, *tree.Backup:
+	case *tree.Import, *tree.Restore, *tree.Backup, *tree.CompactBackup, *tree.VerifyBackup:
 		execDetails.BulkJobId = bulkJobId
 	default:
 		execDetails.NumRows = int64(rows)
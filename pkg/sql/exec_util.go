@@ -77,6 +77,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -1365,6 +1366,10 @@ type ExecutorConfig struct {
 	// contention observability.
 	ContentionRegistry *contention.Registry
 
+	// NotificationRegistry delivers the notifications raised by NOTIFY and
+	// pg_notify to the sessions that are listening on their channels.
+	NotificationRegistry *notify.Registry
+
 	// RootMemoryMonitor is the root memory monitor of the entire server. Do not
 	// use this for normal purposes. It is to be used to establish any new
 	// root-level memory accounts that are not related to a user session.
@@ -3852,6 +3857,10 @@ func (m *sessionDataMutator) SetOptimizerUseConditionalHoistFix(val bool) {
 	m.data.OptimizerUseConditionalHoistFix = val
 }
 
+func (m *sessionDataMutator) SetVectorSearchProbes(val int64) {
+	m.data.VectorSearchProbes = val
+}
+
 // Utility functions related to scrubbing sensitive information on SQL Stats.
 
 // quantizeCounts ensures that the Count field in the
@@ -67,6 +67,11 @@ func (m *BackupDataSpec) User() username.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *VerifyBackupDataSpec) User() username.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ExportSpec) User() username.SQLUsername {
 	return m.UserProto.Decode()
@@ -505,6 +505,15 @@ func (post *PostProcessSpec) summary() []string {
 	return res
 }
 
+// summary implements the diagramCellType interface.
+func (m *VerifyBackupDataSpec) summary() (string, []string) {
+	details := []string{fmt.Sprintf("Files: %d", len(m.Files))}
+	if len(m.Entries) > 0 {
+		details = append(details, fmt.Sprintf("Fingerprinted entries: %d", len(m.Entries)))
+	}
+	return "VerifyBackupData", details
+}
+
 // summary implements the diagramCellType interface.
 func (c *RestoreDataSpec) summary() (string, []string) {
 	return "RestoreDataSpec", []string{}
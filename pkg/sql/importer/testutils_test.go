@@ -291,7 +291,7 @@ func (es *generatorExternalStorage) Size(ctx context.Context, basename string) (
 }
 
 func (es *generatorExternalStorage) Writer(
-	ctx context.Context, basename string,
+	ctx context.Context, basename string, _ ...cloud.WriteOption,
 ) (io.WriteCloser, error) {
 	return nil, errors.New("unsupported")
 }
@@ -2913,6 +2913,11 @@ func (r roleOptions) createRole() (tree.DBool, error) {
 	return tree.DBool(createRole), err
 }
 
+func (r roleOptions) bypassRLS() (tree.DBool, error) {
+	bypassRLS, err := r.Exists("BYPASSRLS")
+	return tree.DBool(bypassRLS), err
+}
+
 func forEachRoleQuery(ctx context.Context, p *planner) string {
 	return `
 SELECT
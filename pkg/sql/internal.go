@@ -1695,6 +1695,13 @@ func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
 }
 
+// CreateDeliverNotificationsResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateDeliverNotificationsResult(
+	pos CmdPos,
+) DeliverNotificationsResult {
+	panic("unimplemented")
+}
+
 // Close is part of the ClientLock interface.
 func (icc *internalClientComm) Close() {}
 
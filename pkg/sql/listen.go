@@ -0,0 +1,36 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Listen subscribes the session to a channel once the current transaction
+// commits.
+// Privileges: None.
+//
+//	Notes: postgres requires no privileges.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkListenNotifySupported(ctx, "LISTEN"); err != nil {
+		return nil, err
+	}
+	channel, err := notifyChannelName(n.ChannelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.notifications.listen(channel); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// connectPGX opens a pgx connection to the given SQL address.
+func connectPGX(
+	ctx context.Context, t *testing.T, addr string, name string,
+) (conn *pgx.Conn, cleanup func()) {
+	pgURL, cleanupURL := sqlutils.PGUrl(t, addr, name, url.User(username.RootUser))
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	return conn, func() {
+		require.NoError(t, conn.Close(ctx))
+		cleanupURL()
+	}
+}
+
+// waitForNotification waits for the next notification received by conn and
+// returns its channel, payload and the backend PID of its sender.
+func waitForNotification(
+	ctx context.Context, t *testing.T, conn *pgx.Conn,
+) (channel, payload string, pid uint32) {
+	ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+	n, err := conn.WaitForNotification(ctx)
+	require.NoError(t, err)
+	return n.Channel, n.Payload, n.PID
+}
+
+// TestListenNotifyPGWire tests that the notifications raised by NOTIFY and
+// pg_notify, including from the internal executor and internal planners, are
+// delivered through pgwire to the sessions listening on their channels.
+func TestListenNotifyPGWire(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv := serverutils.StartServerOnly(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	listener, cleanup := connectPGX(ctx, t, s.AdvSQLAddr(), "listener")
+	defer cleanup()
+	notifier, cleanup := connectPGX(ctx, t, s.AdvSQLAddr(), "notifier")
+	defer cleanup()
+
+	_, err := listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+	var notifierPID uint32
+	require.NoError(t, notifier.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&notifierPID))
+
+	expect := func(expectedPayload string, expectedPID uint32) {
+		t.Helper()
+		channel, payload, pid := waitForNotification(ctx, t, listener)
+		require.Equal(t, "foo", channel)
+		require.Equal(t, expectedPayload, payload)
+		require.Equal(t, expectedPID, pid)
+	}
+
+	_, err = notifier.Exec(ctx, "NOTIFY foo, 'hello'")
+	require.NoError(t, err)
+	expect("hello", notifierPID)
+
+	// Notifications on other channels, and those of transactions that do not
+	// commit, are not delivered.
+	_, err = notifier.Exec(ctx, "NOTIFY bar, 'other channel'")
+	require.NoError(t, err)
+	tx, err := notifier.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "NOTIFY foo, 'rolled back'")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+	_, err = notifier.Exec(ctx, "SELECT pg_notify('foo', 'committed')")
+	require.NoError(t, err)
+	expect("committed", notifierPID)
+
+	// The internal executor raises the notifications of an outer transaction
+	// when the transaction commits.
+	idb := s.InternalDB().(isql.DB)
+	require.NoError(t, idb.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := txn.Exec(ctx, "notify", txn.KV(), "SELECT pg_notify('foo', 'internal executor')")
+		return err
+	}))
+	expect("internal executor", 0)
+
+	// So does an internal planner.
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	txn := s.DB().NewTxn(ctx, "notify")
+	p, cleanupPlanner := sql.NewInternalPlanner(
+		"notify", txn, username.NodeUserName(), &sql.MemoryMetrics{}, &execCfg,
+		sql.NewInternalSessionData(ctx, execCfg.Settings, "notify"),
+	)
+	require.NoError(t, p.(eval.Planner).NotifyChannel(ctx, "foo", "internal planner"))
+	cleanupPlanner()
+	require.NoError(t, txn.Commit(ctx))
+	expect("internal planner", 0)
+
+	// Once the session unlistens, it no longer receives notifications.
+	_, err = listener.Exec(ctx, "UNLISTEN foo")
+	require.NoError(t, err)
+	_, err = notifier.Exec(ctx, "NOTIFY foo, 'unlistened'")
+	require.NoError(t, err)
+	_, err = listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+	_, err = notifier.Exec(ctx, "NOTIFY foo, 'listening again'")
+	require.NoError(t, err)
+	expect("listening again", notifierPID)
+}
+
+// TestListenNotifyMultiNode tests that notifications are delivered to the
+// sessions listening on every node of the cluster.
+func TestListenNotifyMultiNode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	var listeners []*pgx.Conn
+	for i := 0; i < tc.NumServers(); i++ {
+		conn, cleanup := connectPGX(ctx, t, tc.Server(i).ApplicationLayer().AdvSQLAddr(), "listener")
+		defer cleanup()
+		_, err := conn.Exec(ctx, "LISTEN foo")
+		require.NoError(t, err)
+		listeners = append(listeners, conn)
+	}
+
+	notifier, cleanup := connectPGX(ctx, t, tc.Server(1).ApplicationLayer().AdvSQLAddr(), "notifier")
+	defer cleanup()
+	var notifierPID uint32
+	require.NoError(t, notifier.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&notifierPID))
+	for _, payload := range []string{"1", "2", "3"} {
+		_, err := notifier.Exec(ctx, "NOTIFY foo, '"+payload+"'")
+		require.NoError(t, err)
+	}
+
+	// Every node receives the notifications, in the order they were raised.
+	for i, conn := range listeners {
+		for _, expected := range []string{"1", "2", "3"} {
+			channel, payload, pid := waitForNotification(ctx, t, conn)
+			require.Equal(t, "foo", channel, "node %d", i)
+			require.Equal(t, expected, payload, "node %d", i)
+			require.Equal(t, notifierPID, pid, "node %d", i)
+		}
+	}
+}
+
+// TestListenNotifyMixedVersion tests that LISTEN, NOTIFY and pg_notify are
+// rejected until the cluster is upgraded to a version whose nodes all serve
+// the Notify RPC.
+func TestListenNotifyMixedVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestNeedsTightIntegrationBetweenAPIsAndTestingKnobs,
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: make(chan struct{}),
+				ClusterVersionOverride:         clusterversion.MinSupported.Version(),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.ExpectErr(t, "LISTEN is only supported after v24.2 upgrade is finalized", "LISTEN foo")
+	sqlDB.ExpectErr(t, "NOTIFY is only supported after v24.2 upgrade is finalized", "NOTIFY foo")
+	sqlDB.ExpectErr(t, "NOTIFY is only supported after v24.2 upgrade is finalized",
+		"SELECT pg_notify('foo', 'bar')")
+
+	sqlDB.Exec(t, "SET CLUSTER SETTING version = crdb_internal.node_executable_version()")
+	sqlDB.Exec(t, "LISTEN foo")
+	sqlDB.Exec(t, "NOTIFY foo")
+	sqlDB.Exec(t, "SELECT pg_notify('foo', 'bar')")
+}
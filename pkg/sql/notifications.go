@@ -0,0 +1,235 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// sessionNotifications provides access to the notifications raised by the
+// current transaction and to the channels the session is listening on. Its
+// effects are transactional: they only take place when the transaction
+// commits.
+type sessionNotifications interface {
+	// notify raises a notification on the given channel.
+	notify(ctx context.Context, channel, payload string) error
+	// listen subscribes the session to the given channel.
+	listen(channel string) error
+	// unlisten unsubscribes the session from the given channel, or from every
+	// channel if the channel is empty.
+	unlisten(channel string) error
+}
+
+// notificationKey identifies duplicate notifications raised in the same
+// transaction, which are only delivered once.
+type notificationKey struct {
+	channel, payload string
+}
+
+// listenOp is a LISTEN or UNLISTEN operation to be applied when the
+// transaction commits.
+type listenOp struct {
+	// channel is the channel to listen on or unlisten from. If empty, and
+	// listen is false, the session unlistens from every channel.
+	channel string
+	listen  bool
+}
+
+// txnNotifications holds the notifications and the LISTEN and UNLISTEN
+// operations of a transaction until it commits.
+type txnNotifications struct {
+	// pending are the notifications raised by the transaction, in order.
+	pending []serverpb.Notification
+	// seen contains the keys of the pending notifications.
+	seen map[notificationKey]struct{}
+	// listenOps are the LISTEN and UNLISTEN operations of the transaction, in
+	// order.
+	listenOps []listenOp
+}
+
+// txnNotificationsPos is the position in a txnNotifications at the time a
+// savepoint was created.
+type txnNotificationsPos struct {
+	numPending, numListenOps int
+}
+
+func (t *txnNotifications) pos() txnNotificationsPos {
+	return txnNotificationsPos{
+		numPending:   len(t.pending),
+		numListenOps: len(t.listenOps),
+	}
+}
+
+// rollbackTo discards the notifications and the operations that were added
+// after the given position.
+func (t *txnNotifications) rollbackTo(pos txnNotificationsPos) {
+	for i := pos.numPending; i < len(t.pending); i++ {
+		delete(t.seen, notificationKey{channel: t.pending[i].Channel, payload: t.pending[i].Payload})
+	}
+	t.pending = t.pending[:pos.numPending]
+	t.listenOps = t.listenOps[:pos.numListenOps]
+}
+
+func (t *txnNotifications) reset() {
+	t.pending = nil
+	t.seen = nil
+	t.listenOps = nil
+}
+
+func (t *txnNotifications) add(n serverpb.Notification) {
+	key := notificationKey{channel: n.Channel, payload: n.Payload}
+	if _, ok := t.seen[key]; ok {
+		return
+	}
+	if t.seen == nil {
+		// Lazily allocate.
+		t.seen = make(map[notificationKey]struct{})
+	}
+	t.seen[key] = struct{}{}
+	t.pending = append(t.pending, n)
+}
+
+type connExNotificationsAccessor struct {
+	ex *connExecutor
+}
+
+var _ sessionNotifications = connExNotificationsAccessor{}
+
+func (c connExNotificationsAccessor) notify(ctx context.Context, channel, payload string) error {
+	if c.ex.executorType == executorTypeInternal && c.ex.extraTxnState.underOuterTxn {
+		// The transaction is committed by the owner of the outer transaction,
+		// rather than by the connExecutor.
+		return commitTriggerNotifications{
+			txn:      c.ex.state.mu.txn,
+			registry: c.ex.server.cfg.NotificationRegistry,
+		}.notify(ctx, channel, payload)
+	}
+	c.ex.extraTxnState.notifications.add(serverpb.Notification{
+		Channel: channel,
+		Payload: payload,
+		PID:     int32(c.ex.queryCancelKey.GetPGBackendPID()),
+	})
+	return nil
+}
+
+func (c connExNotificationsAccessor) listen(channel string) error {
+	if c.ex.executorType == executorTypeInternal {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is not supported by the internal executor")
+	}
+	ops := &c.ex.extraTxnState.notifications.listenOps
+	*ops = append(*ops, listenOp{channel: channel, listen: true})
+	return nil
+}
+
+func (c connExNotificationsAccessor) unlisten(channel string) error {
+	ops := &c.ex.extraTxnState.notifications.listenOps
+	*ops = append(*ops, listenOp{channel: channel})
+	return nil
+}
+
+// commitTriggerNotifications is the impl used by the planner when the
+// connExecutor is not available, or does not commit its transaction. It
+// publishes the notifications from a commit trigger of the transaction. There
+// is no session to deliver notifications to, so LISTEN is not supported.
+type commitTriggerNotifications struct {
+	txn      *kv.Txn
+	registry *notify.Registry
+}
+
+var _ sessionNotifications = commitTriggerNotifications{}
+
+func (c commitTriggerNotifications) notify(ctx context.Context, channel, payload string) error {
+	if c.txn == nil || c.txn.Type() != kv.RootTxn {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"notifications can only be raised in a root transaction")
+	}
+	// A commit trigger cannot fail the commit, so the queue is checked when the
+	// notification is raised instead.
+	if err := c.registry.CheckQueue(ctx); err != nil {
+		return err
+	}
+	n := serverpb.Notification{Channel: channel, Payload: payload}
+	c.txn.AddCommitTrigger(func(ctx context.Context) {
+		c.registry.Publish(ctx, []serverpb.Notification{n})
+	})
+	return nil
+}
+
+func (commitTriggerNotifications) listen(channel string) error {
+	return pgerror.New(pgcode.FeatureNotSupported, "LISTEN is only supported in a session")
+}
+
+func (commitTriggerNotifications) unlisten(channel string) error {
+	// The planner never listens on any channel.
+	return nil
+}
+
+// getNotificationListener returns the listener of the session, creating it if
+// necessary. The listener wakes up the session by pushing a
+// DeliverNotifications command to its StmtBuf.
+func (ex *connExecutor) getNotificationListener() *notify.Listener {
+	if ex.notificationListener == nil {
+		ex.notificationListener = ex.server.cfg.NotificationRegistry.NewListener(func() {
+			// The buffer is closed once the session is done, at which point
+			// there is nobody to deliver the notifications to.
+			_ = ex.stmtBuf.Push(context.Background(), DeliverNotifications{})
+		})
+	}
+	return ex.notificationListener
+}
+
+// checkNotificationQueue returns an error if the notifications raised by the
+// transaction that is about to commit cannot be queued for delivery.
+func (ex *connExecutor) checkNotificationQueue(ctx context.Context) error {
+	if len(ex.extraTxnState.notifications.pending) == 0 {
+		return nil
+	}
+	return ex.server.cfg.NotificationRegistry.CheckQueue(ctx)
+}
+
+// commitNotifications publishes the notifications raised by the transaction
+// that just committed and applies its LISTEN and UNLISTEN operations.
+func (ex *connExecutor) commitNotifications(ctx context.Context) {
+	notifications := &ex.extraTxnState.notifications
+	if len(notifications.listenOps) > 0 {
+		l := ex.getNotificationListener()
+		for _, op := range notifications.listenOps {
+			switch {
+			case op.listen:
+				l.Listen(op.channel)
+			case op.channel == "":
+				l.UnlistenAll()
+			default:
+				l.Unlisten(op.channel)
+			}
+		}
+	}
+	ex.server.cfg.NotificationRegistry.Publish(ctx, notifications.pending)
+}
+
+// bufferNotifications buffers the notifications received by the session's
+// listener into the given result. It is a no-op while a transaction is open,
+// since notifications are only delivered between transactions.
+func (ex *connExecutor) bufferNotifications(res NotificationBuffer) {
+	if ex.notificationListener == nil || !ex.idleConn() {
+		return
+	}
+	for _, n := range ex.notificationListener.Drain() {
+		res.BufferNotification(n)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// maxNotifyChannelLength is the maximum length of a notification channel
+// name. It matches the maximum length of an identifier in Postgres.
+const maxNotifyChannelLength = 63
+
+// maxNotifyPayloadLength is the maximum length of a notification payload,
+// which matches the limit of Postgres.
+const maxNotifyPayloadLength = 7999
+
+// Notify raises a notification on a channel, which is delivered to the
+// sessions listening on the channel once the current transaction commits.
+// Privileges: None.
+//
+//	Notes: postgres requires no privileges.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	channel, err := notifyChannelName(n.ChannelName)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.notify(ctx, channel, n.Payload); err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// NotifyChannel is part of the eval.Planner interface.
+func (p *planner) NotifyChannel(ctx context.Context, channel, payload string) error {
+	return p.notify(ctx, channel, payload)
+}
+
+func (p *planner) notify(ctx context.Context, channel, payload string) error {
+	if err := p.checkListenNotifySupported(ctx, "NOTIFY"); err != nil {
+		return err
+	}
+	if err := checkNotifyChannel(channel); err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	return p.notifications.notify(ctx, channel, payload)
+}
+
+// checkListenNotifySupported returns an error if the cluster may have nodes
+// that cannot deliver notifications, since they do not serve the Notify RPC.
+func (p *planner) checkListenNotifySupported(ctx context.Context, stmt string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_2_ListenNotify) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only supported after v24.2 upgrade is finalized", stmt)
+	}
+	return nil
+}
+
+// notifyChannelName returns the channel named by a LISTEN, UNLISTEN or NOTIFY
+// statement.
+func notifyChannelName(name *tree.UnresolvedObjectName) (string, error) {
+	if name.NumParts != 1 {
+		return "", pgerror.Newf(pgcode.Syntax, "invalid channel name: %s", name)
+	}
+	channel := name.Parts[0]
+	if err := checkNotifyChannel(channel); err != nil {
+		return "", err
+	}
+	return channel, nil
+}
+
+func checkNotifyChannel(channel string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > maxNotifyChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notify implements the delivery of asynchronous notifications
+// raised by NOTIFY statements to the sessions that are listening on their
+// channels with LISTEN.
+//
+// Notifications are published by the gateway of the session that raised them
+// once its transaction commits. The Registry of the gateway fans them out to
+// every SQL instance through the status server, and the Registry of each
+// instance hands them to the local Listeners that subscribed to the channel.
+//
+// As in Postgres, the notifications of committed transactions are never
+// dropped because of a lack of space. Instead, transactions that raise
+// notifications fail to commit while the queue of pending notifications of
+// their gateway, or the queue of any Listener in the cluster, is full.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// maxPendingNotifications is the maximum number of notifications that can be
+// waiting to be fanned out by a Registry before transactions that raise
+// notifications fail to commit.
+const maxPendingNotifications = 10000
+
+// maxQueuedNotifications is the maximum number of notifications that can be
+// waiting to be delivered to the client of a single Listener before
+// transactions that raise notifications fail to commit.
+const maxQueuedNotifications = 10000
+
+// fanOutRetryOptions are the options of the retries of a delivery of
+// notifications to a SQL instance that could not be reached.
+var fanOutRetryOptions = retry.Options{
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+}
+
+// fanOutMaxAttempts is the number of times the delivery of notifications to a
+// SQL instance is attempted before they are given up on.
+const fanOutMaxAttempts = 10
+
+// Registry tracks the Listeners of a SQL instance and fans out the
+// notifications published by its sessions to the Listeners of every SQL
+// instance in the cluster.
+type Registry struct {
+	statusServer serverpb.SQLStatusServer
+
+	// publishCh is signaled when notifications are added to mu.pending.
+	publishCh chan struct{}
+
+	mu struct {
+		syncutil.RWMutex
+		// pending are the published notifications that were not yet fanned out.
+		pending []serverpb.Notification
+		// queueFull is set if the queue of a Listener was full the last time
+		// notifications were delivered to it.
+		queueFull bool
+		// channels maps each channel to the Listeners subscribed to it.
+		channels map[string]map[*Listener]struct{}
+	}
+}
+
+// NewRegistry returns a new Registry that fans out notifications through the
+// given status server. If statusServer is nil, published notifications are
+// only delivered to the Listeners of this instance.
+func NewRegistry(statusServer serverpb.SQLStatusServer) *Registry {
+	r := &Registry{
+		statusServer: statusServer,
+		publishCh:    make(chan struct{}, 1),
+	}
+	r.mu.channels = make(map[string]map[*Listener]struct{})
+	return r
+}
+
+// Start starts the task that fans out published notifications.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	err := stopper.RunAsyncTask(ctx, "notification-publisher", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		for {
+			select {
+			case <-r.publishCh:
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+			r.mu.Lock()
+			batch := r.mu.pending
+			r.mu.pending = nil
+			r.mu.Unlock()
+			if len(batch) > 0 {
+				r.fanOut(ctx, batch)
+			}
+		}
+	})
+	if err != nil {
+		log.Warningf(ctx, "failed to start notification publisher: %v", err)
+	}
+}
+
+// fanOut delivers the given batch of notifications to the Listeners of every
+// SQL instance in the cluster. The delivery to the instances that cannot be
+// reached is retried, and only given up on, with an error, once they could not
+// be reached for a while.
+func (r *Registry) fanOut(ctx context.Context, batch []serverpb.Notification) {
+	if r.statusServer == nil {
+		r.setQueueFull(r.Deliver(batch))
+		return
+	}
+	var resp *serverpb.NotifyResponse
+	if err := retry.WithMaxAttempts(ctx, fanOutRetryOptions, fanOutMaxAttempts, func() (err error) {
+		resp, err = r.statusServer.Notify(ctx, &serverpb.NotifyRequest{Notifications: batch})
+		return err
+	}); err != nil {
+		log.Errorf(ctx, "failed to deliver %d notifications: %v", len(batch), err)
+		return
+	}
+	queueFull := resp.QueueFull
+	// The instances that could be reached have already delivered the
+	// notifications, so only retry the others.
+	for _, nodeID := range resp.FailedNodeIDs {
+		full, err := r.deliverTo(ctx, nodeID, batch)
+		if err != nil {
+			log.Errorf(ctx, "failed to deliver %d notifications to node %d: %v", len(batch), nodeID, err)
+		}
+		queueFull = queueFull || full
+	}
+	r.setQueueFull(queueFull)
+}
+
+// deliverTo delivers the given notifications to the Listeners of the given
+// node, and returns whether the queue of one of them is full.
+func (r *Registry) deliverTo(
+	ctx context.Context, nodeID roachpb.NodeID, notifications []serverpb.Notification,
+) (queueFull bool, _ error) {
+	req := &serverpb.NotifyRequest{NodeID: nodeID.String(), Notifications: notifications}
+	err := retry.WithMaxAttempts(ctx, fanOutRetryOptions, fanOutMaxAttempts, func() error {
+		resp, err := r.statusServer.Notify(ctx, req)
+		if err != nil {
+			return err
+		}
+		queueFull = resp.QueueFull
+		return nil
+	})
+	return queueFull, err
+}
+
+func (r *Registry) setQueueFull(queueFull bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.queueFull = queueFull
+}
+
+// Publish queues the given notifications to be delivered to the Listeners of
+// every SQL instance in the cluster. It does not block on the delivery.
+// Notifications published by a single Registry are delivered in order.
+func (r *Registry) Publish(ctx context.Context, notifications []serverpb.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	r.mu.Lock()
+	r.mu.pending = append(r.mu.pending, notifications...)
+	r.mu.Unlock()
+
+	select {
+	case r.publishCh <- struct{}{}:
+	default:
+	}
+}
+
+// CheckQueue returns an error if there is no room for more notifications,
+// because too many notifications are waiting to be fanned out, or because the
+// queue of a Listener in the cluster is full. It is called before committing a
+// transaction that raised notifications, which, like in Postgres, fails to
+// commit if the notifications cannot be queued.
+func (r *Registry) CheckQueue(ctx context.Context) error {
+	r.mu.RLock()
+	numPending, queueFull := len(r.mu.pending), r.mu.queueFull
+	r.mu.RUnlock()
+	if numPending >= maxPendingNotifications {
+		return queueFullError()
+	}
+	if !queueFull {
+		return nil
+	}
+	// The Listeners may have drained their queues since notifications were last
+	// delivered to them, so check their queues again by delivering no
+	// notifications.
+	if r.statusServer == nil {
+		queueFull = r.Deliver(nil /* notifications */)
+	} else {
+		resp, err := r.statusServer.Notify(ctx, &serverpb.NotifyRequest{})
+		if err != nil {
+			return err
+		}
+		queueFull = resp.QueueFull
+	}
+	r.setQueueFull(queueFull)
+	if queueFull {
+		return queueFullError()
+	}
+	return nil
+}
+
+func queueFullError() error {
+	return pgerror.New(pgcode.ProgramLimitExceeded, "too many notifications in the NOTIFY queue")
+}
+
+// Deliver hands the given notifications to the Listeners of this instance
+// that are subscribed to their channels, and returns whether the queue of any
+// Listener of this instance is full.
+func (r *Registry) Deliver(notifications []serverpb.Notification) (queueFull bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := range notifications {
+		for l := range r.mu.channels[notifications[i].Channel] {
+			l.enqueue(notifications[i])
+		}
+	}
+	for _, listeners := range r.mu.channels {
+		for l := range listeners {
+			if l.queueFull() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewListener returns a new Listener that is not subscribed to any channel.
+// The wake function is called when notifications become available to an
+// empty queue of the Listener; it must not block.
+func (r *Registry) NewListener(wake func()) *Listener {
+	return &Listener{
+		registry: r,
+		wake:     wake,
+		channels: make(map[string]struct{}),
+	}
+}
+
+// Listener receives the notifications sent on the channels that a session
+// is listening on.
+type Listener struct {
+	registry *Registry
+	wake     func()
+
+	// channels is the set of channels that the Listener is subscribed to. It
+	// is protected by registry.mu.
+	channels map[string]struct{}
+
+	mu struct {
+		syncutil.Mutex
+		// queue is the list of notifications received by the Listener that
+		// were not yet drained.
+		queue []serverpb.Notification
+		// woken is set if wake was called since the last call to Drain.
+		woken bool
+	}
+}
+
+// Listen subscribes the Listener to the given channel. It is a no-op if the
+// Listener is already subscribed to the channel.
+func (l *Listener) Listen(channel string) {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := l.channels[channel]; ok {
+		return
+	}
+	l.channels[channel] = struct{}{}
+	listeners, ok := r.mu.channels[channel]
+	if !ok {
+		listeners = make(map[*Listener]struct{})
+		r.mu.channels[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+}
+
+// Unlisten unsubscribes the Listener from the given channel. It is a no-op if
+// the Listener is not subscribed to the channel.
+func (l *Listener) Unlisten(channel string) {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l.unlistenLocked(channel)
+}
+
+// UnlistenAll unsubscribes the Listener from every channel.
+func (l *Listener) UnlistenAll() {
+	r := l.registry
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for channel := range l.channels {
+		l.unlistenLocked(channel)
+	}
+}
+
+func (l *Listener) unlistenLocked(channel string) {
+	r := l.registry
+	if _, ok := l.channels[channel]; !ok {
+		return
+	}
+	delete(l.channels, channel)
+	listeners := r.mu.channels[channel]
+	delete(listeners, l)
+	if len(listeners) == 0 {
+		delete(r.mu.channels, channel)
+	}
+}
+
+// Channels returns the channels that the Listener is subscribed to.
+func (l *Listener) Channels() []string {
+	r := l.registry
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	channels := make([]string, 0, len(l.channels))
+	for channel := range l.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// Close unsubscribes the Listener from every channel and discards its queued
+// notifications.
+func (l *Listener) Close() {
+	l.UnlistenAll()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.queue = nil
+}
+
+// Drain returns the notifications received by the Listener since the last
+// call to Drain.
+func (l *Listener) Drain() []serverpb.Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	queue := l.mu.queue
+	l.mu.queue = nil
+	l.mu.woken = false
+	return queue
+}
+
+// enqueue adds the given notification to the queue of the Listener. The queue
+// may grow beyond maxQueuedNotifications with the notifications of the
+// transactions that committed before the queue was found to be full.
+func (l *Listener) enqueue(n serverpb.Notification) {
+	var wake bool
+	func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.mu.queue = append(l.mu.queue, n)
+		if !l.mu.woken {
+			l.mu.woken = true
+			wake = true
+		}
+	}()
+	if wake {
+		l.wake()
+	}
+}
+
+// queueFull returns whether the queue of the Listener is full.
+func (l *Listener) queueFull() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.mu.queue) >= maxQueuedNotifications
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r := NewRegistry(nil /* statusServer */)
+	var wakes1, wakes2 int
+	l1 := r.NewListener(func() { wakes1++ })
+	l2 := r.NewListener(func() { wakes2++ })
+
+	a1 := serverpb.Notification{Channel: "a", Payload: "1", PID: 1}
+	a2 := serverpb.Notification{Channel: "a", Payload: "2", PID: 1}
+	b1 := serverpb.Notification{Channel: "b", Payload: "1", PID: 2}
+	c1 := serverpb.Notification{Channel: "c", Payload: "1", PID: 2}
+
+	l1.Listen("a")
+	l1.Listen("a")
+	l1.Listen("b")
+	l2.Listen("b")
+
+	channels := l1.Channels()
+	sort.Strings(channels)
+	require.Equal(t, []string{"a", "b"}, channels)
+
+	// Each Listener only receives the notifications of its channels, and is
+	// woken up once until it is drained.
+	r.Deliver([]serverpb.Notification{a1, b1, c1})
+	r.Deliver([]serverpb.Notification{a2})
+	require.Equal(t, 1, wakes1)
+	require.Equal(t, 1, wakes2)
+	require.Equal(t, []serverpb.Notification{a1, b1, a2}, l1.Drain())
+	require.Equal(t, []serverpb.Notification{b1}, l2.Drain())
+	require.Empty(t, l1.Drain())
+
+	r.Deliver([]serverpb.Notification{a1})
+	require.Equal(t, 2, wakes1)
+	require.Equal(t, []serverpb.Notification{a1}, l1.Drain())
+
+	l1.Unlisten("a")
+	l1.Unlisten("c")
+	r.Deliver([]serverpb.Notification{a1, b1})
+	require.Equal(t, []serverpb.Notification{b1}, l1.Drain())
+
+	l1.UnlistenAll()
+	require.Empty(t, l1.Channels())
+	l2.Close()
+	r.Deliver([]serverpb.Notification{a1, b1})
+	require.Empty(t, l1.Drain())
+	require.Empty(t, l2.Drain())
+	require.Empty(t, r.mu.channels)
+}
+
+func TestRegistryPublish(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	r := NewRegistry(nil /* statusServer */)
+	r.Start(ctx, stopper)
+
+	l := r.NewListener(func() {})
+	l.Listen("a")
+
+	var expected []serverpb.Notification
+	for i := 0; i < 10; i++ {
+		n := serverpb.Notification{Channel: "a", Payload: string(rune('0' + i))}
+		expected = append(expected, n)
+		r.Publish(ctx, []serverpb.Notification{n})
+	}
+
+	var received []serverpb.Notification
+	testutils.SucceedsSoon(t, func() error {
+		received = append(received, l.Drain()...)
+		if len(received) < len(expected) {
+			return errors.Newf("received %d of %d notifications", len(received), len(expected))
+		}
+		return nil
+	})
+	require.Equal(t, expected, received)
+}
+
+func TestRegistryQueueFull(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	r := NewRegistry(nil /* statusServer */)
+	l := r.NewListener(func() {})
+	l.Listen("a")
+	require.NoError(t, r.CheckQueue(ctx))
+
+	// Once the queue of a Listener is full, the notifications that are already
+	// committed are still delivered, but no more notifications are accepted
+	// until the Listener is drained.
+	batch := make([]serverpb.Notification, maxQueuedNotifications+1)
+	for i := range batch {
+		batch[i] = serverpb.Notification{Channel: "a"}
+	}
+	r.fanOut(ctx, batch)
+	err := r.CheckQueue(ctx)
+	require.Equal(t, pgcode.ProgramLimitExceeded, pgerror.GetPGCode(err))
+	require.Len(t, l.Drain(), len(batch))
+	require.NoError(t, r.CheckQueue(ctx))
+
+	// The same goes for the notifications waiting to be fanned out.
+	r.Publish(ctx, batch)
+	err = r.CheckQueue(ctx)
+	require.Equal(t, pgcode.ProgramLimitExceeded, pgerror.GetPGCode(err))
+}
+
+// flakyStatusServer is a SQLStatusServer whose fan out of notifications fails
+// to reach node 2 until it has been retried the given number of times.
+type flakyStatusServer struct {
+	serverpb.SQLStatusServer
+
+	mu struct {
+		syncutil.Mutex
+		failures  int
+		delivered map[string][]serverpb.Notification
+	}
+}
+
+func (s *flakyStatusServer) Notify(
+	_ context.Context, req *serverpb.NotifyRequest,
+) (*serverpb.NotifyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch req.NodeID {
+	case "":
+		s.mu.delivered["1"] = append(s.mu.delivered["1"], req.Notifications...)
+		return &serverpb.NotifyResponse{FailedNodeIDs: []roachpb.NodeID{2}}, nil
+	case "2":
+		if s.mu.failures > 0 {
+			s.mu.failures--
+			return nil, errors.New("node 2 is unavailable")
+		}
+		s.mu.delivered["2"] = append(s.mu.delivered["2"], req.Notifications...)
+		return &serverpb.NotifyResponse{QueueFull: true}, nil
+	default:
+		return nil, errors.Newf("unexpected node %s", req.NodeID)
+	}
+}
+
+func TestRegistryFanOutRetries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := &flakyStatusServer{}
+	s.mu.failures = 2
+	s.mu.delivered = make(map[string][]serverpb.Notification)
+	r := NewRegistry(s)
+
+	batch := []serverpb.Notification{{Channel: "a", Payload: "1"}}
+	r.fanOut(ctx, batch)
+	require.Equal(t, map[string][]serverpb.Notification{"1": batch, "2": batch}, s.mu.delivered)
+	require.Zero(t, s.mu.failures)
+	// The queue of a Listener of node 2 was reported to be full.
+	require.True(t, r.mu.queueFull)
+}
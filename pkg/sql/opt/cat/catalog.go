@@ -202,6 +202,14 @@ type Catalog interface {
 	// CheckRoleExists returns an error if the role does not exist.
 	CheckRoleExists(ctx context.Context, role username.SQLUsername) error
 
+	// IsOwner returns true if the current user owns the given catalog object,
+	// either directly or through role membership. Admins own every object.
+	IsOwner(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role or a
+	// member of it. Every user is a member of the public role.
+	IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error)
+
 	// Optimizer returns the query Optimizer used to optimize SQL statements
 	// referencing objects in this catalog, if any.
 	Optimizer() interface{}
@@ -13,6 +13,7 @@ package cat
 import (
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -180,6 +181,22 @@ type Table interface {
 	// IsHypothetical returns true if this is a hypothetical table (used when
 	// searching for index recommendations).
 	IsHypothetical() bool
+
+	// IsRowLevelSecurityEnabled returns true if queries on the table are
+	// subject to the table's row-level security policies.
+	IsRowLevelSecurityEnabled() bool
+
+	// IsRowLevelSecurityForced returns true if the table's row-level security
+	// policies also apply to the owner of the table.
+	IsRowLevelSecurityForced() bool
+
+	// PolicyCount returns the number of row-level security policies on the
+	// table.
+	PolicyCount() int
+
+	// Policy returns the ith row-level security policy on the table, where
+	// i < PolicyCount.
+	Policy(i int) Policy
 }
 
 // CheckConstraint represents a check constraint on a table. Check constraints
@@ -201,6 +218,40 @@ type CheckConstraint interface {
 	// ColumnOrdinal returns the table column ordinal of the ith column in this
 	// constraint.
 	ColumnOrdinal(i int) int
+
+	// IsRLSConstraint returns true if this is the placeholder for the
+	// row-level security check of the table. Its expression depends on the
+	// current user, so it is built by the optbuilder rather than taken from
+	// Constraint. It is never validated.
+	IsRLSConstraint() bool
+}
+
+// Policy represents a row-level security policy on a table. The USING
+// expression of a policy filters the rows that a query can read, update or
+// delete, and the WITH CHECK expression restricts the rows that can be written.
+type Policy interface {
+	// Name is the name of the policy.
+	Name() string
+
+	// IsPermissive returns true if the policy is permissive, or false if it is
+	// restrictive.
+	IsPermissive() bool
+
+	// Command returns the command that the policy applies to.
+	Command() tree.PolicyCommand
+
+	// RoleCount returns the number of roles that the policy applies to.
+	RoleCount() int
+
+	// Role returns the ith role that the policy applies to, where
+	// i < RoleCount.
+	Role(i int) username.SQLUsername
+
+	// UsingExpr returns the SQL text of the USING expression, if any.
+	UsingExpr() (string, bool)
+
+	// WithCheckExpr returns the SQL text of the WITH CHECK expression, if any.
+	WithCheckExpr() (string, bool)
 }
 
 // TableStatistic is an interface to a table statistic. Each statistic is
@@ -492,7 +492,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (_ execPlan, outputCols colO
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
 	cnt := len(ups.InsertCols) + len(ups.FetchCols) + len(ups.UpdateCols) + len(ups.CheckCols) +
-		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 1
+		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 2
 	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, ups.InsertCols)
 	colList = appendColsWhenPresent(colList, ups.FetchCols)
@@ -500,6 +500,9 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (_ execPlan, outputCols colO
 	if ups.CanaryCol != 0 {
 		colList = append(colList, ups.CanaryCol)
 	}
+	if ups.MergeActionCol != 0 {
+		colList = append(colList, ups.MergeActionCol)
+	}
 	colList = appendColsWhenPresent(colList, ups.CheckCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexPutCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexDelCols)
@@ -519,6 +522,13 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (_ execPlan, outputCols colO
 			return execPlan{}, colOrdMap{}, err
 		}
 	}
+	mergeActionCol := exec.NodeColumnOrdinal(-1)
+	if ups.MergeActionCol != 0 {
+		mergeActionCol, err = getNodeColumnOrdinal(inputCols, ups.MergeActionCol)
+		if err != nil {
+			return execPlan{}, colOrdMap{}, err
+		}
+	}
 	insertColOrds := ordinalSetFromColList(ups.InsertCols)
 	fetchColOrds := ordinalSetFromColList(ups.FetchCols)
 	updateColOrds := ordinalSetFromColList(ups.UpdateCols)
@@ -530,6 +540,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (_ execPlan, outputCols colO
 		ups.ArbiterIndexes,
 		ups.ArbiterConstraints,
 		canaryCol,
+		mergeActionCol,
 		insertColOrds,
 		fetchColOrds,
 		updateColOrds,
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geopb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -628,6 +629,26 @@ func (u *unknownTable) IsHypothetical() bool {
 	return false
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (u *unknownTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (u *unknownTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (u *unknownTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (u *unknownTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
 var _ cat.Table = &unknownTable{}
 
 // unknownTable implements the cat.Index interface and is used to represent
@@ -733,6 +754,14 @@ func (u *unknownIndex) GeoConfig() geopb.Config {
 	return geopb.Config{}
 }
 
+func (u *unknownIndex) VectorConfig() catpb.VectorIndexConfig {
+	return catpb.VectorIndexConfig{}
+}
+
+func (u *unknownIndex) InvertedColumnKind() catpb.InvertedIndexColumnKind {
+	return catpb.InvertedIndexColumnKind_DEFAULT
+}
+
 func (u *unknownIndex) Version() descpb.IndexDescriptorVersion {
 	return descpb.LatestIndexDescriptorVersion
 }
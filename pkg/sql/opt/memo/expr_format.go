@@ -676,6 +676,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			}
 			if t.CanaryCol != 0 {
 				f.formatRelColList(e, tp, "canary column:", opt.ColList{t.CanaryCol})
+				if t.MergeActionCol != 0 {
+					f.formatRelColList(e, tp, "merge action column:", opt.ColList{t.MergeActionCol})
+				}
 				f.formatOptionalColList(e, tp, "fetch columns:", t.FetchCols)
 				f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
 				f.formatMutationCols(e, tp, "update-mapping:", t.UpdateCols, t.Table)
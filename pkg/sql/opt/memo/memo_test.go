@@ -477,6 +477,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().OptSplitScanLimit = 0
 	notStale()
 
+	// Stale vector_search_probes.
+	evalCtx.SessionData().VectorSearchProbes = 10
+	stale()
+	evalCtx.SessionData().VectorSearchProbes = 0
+	notStale()
+
 	// Stale optimizer_use_improved_zigzag_join_costing.
 	evalCtx.SessionData().OptimizerUseImprovedZigzagJoinCosting = true
 	stale()
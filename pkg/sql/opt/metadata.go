@@ -16,12 +16,14 @@ import (
 	"math/bits"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -48,6 +50,34 @@ type udfDep struct {
 	invocationTypes []*types.T
 }
 
+// RowLevelSecurityDepKind identifies the kind of role check that was performed
+// in order to decide how row-level security policies apply to a query.
+type RowLevelSecurityDepKind uint8
+
+const (
+	// RowLevelSecurityBypass checks whether the current user has the BYPASSRLS
+	// role option.
+	RowLevelSecurityBypass RowLevelSecurityDepKind = iota
+	// RowLevelSecurityOwner checks whether the current user owns a table.
+	RowLevelSecurityOwner
+	// RowLevelSecurityMember checks whether the current user is a member of a
+	// role named by a policy.
+	RowLevelSecurityMember
+)
+
+// RowLevelSecurityDep records the result of a role check that determined which
+// row-level security policies were applied when building a query. The memo is
+// stale if re-running the check yields a different result, e.g. because the
+// query is re-used by a different user.
+type RowLevelSecurityDep struct {
+	Kind RowLevelSecurityDepKind
+	// Table is set for RowLevelSecurityOwner dependencies.
+	Table cat.DataSource
+	// Role is set for RowLevelSecurityMember dependencies.
+	Role   username.SQLUsername
+	Result bool
+}
+
 // Metadata assigns unique ids to the columns, tables, and other metadata used
 // for global identification within the scope of a particular query. These ids
 // tend to be small integers that can be efficiently stored and manipulated.
@@ -146,6 +176,10 @@ type Metadata struct {
 	// as a builtin function.
 	builtinRefsByName map[tree.UnresolvedName]struct{}
 
+	// rlsDeps stores the results of the role checks used to decide which
+	// row-level security policies apply to the query.
+	rlsDeps []RowLevelSecurityDep
+
 	// NOTE! When adding fields here, update Init (if reusing allocated
 	// data structures is desired), CopyFrom and TestMetadata.
 }
@@ -219,6 +253,11 @@ func (md *Metadata) Init() {
 		delete(md.builtinRefsByName, name)
 	}
 
+	rlsDeps := md.rlsDeps
+	for i := range rlsDeps {
+		rlsDeps[i] = RowLevelSecurityDep{}
+	}
+
 	// This initialization pattern ensures that fields are not unwittingly
 	// reused. Field reuse must be explicit.
 	*md = Metadata{}
@@ -232,6 +271,7 @@ func (md *Metadata) Init() {
 	md.objectRefsByName = objectRefsByName
 	md.privileges = privileges
 	md.builtinRefsByName = builtinRefsByName
+	md.rlsDeps = rlsDeps[:0]
 }
 
 // CopyFrom initializes the metadata with a copy of the provided metadata.
@@ -247,7 +287,7 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 		len(md.sequences) != 0 || len(md.views) != 0 || len(md.userDefinedTypes) != 0 ||
 		len(md.userDefinedTypesSlice) != 0 || len(md.dataSourceDeps) != 0 ||
 		len(md.udfDeps) != 0 || len(md.objectRefsByName) != 0 || len(md.privileges) != 0 ||
-		len(md.builtinRefsByName) != 0 {
+		len(md.builtinRefsByName) != 0 || len(md.rlsDeps) != 0 {
 		panic(errors.AssertionFailedf("CopyFrom requires empty destination"))
 	}
 	md.schemas = append(md.schemas, from.schemas...)
@@ -320,6 +360,7 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 		md.builtinRefsByName[name] = struct{}{}
 	}
 
+	md.rlsDeps = append(md.rlsDeps, from.rlsDeps...)
 	md.sequences = append(md.sequences, from.sequences...)
 	md.views = append(md.views, from.views...)
 	md.currUniqueID = from.currUniqueID
@@ -514,6 +555,27 @@ func (md *Metadata) CheckDependencies(
 		}
 	}
 
+	// Check that the same row-level security policies still apply to the
+	// current user.
+	for i := range md.rlsDeps {
+		dep := &md.rlsDeps[i]
+		var result bool
+		switch dep.Kind {
+		case RowLevelSecurityBypass:
+			result, err = optCatalog.HasRoleOption(ctx, roleoption.BYPASSRLS)
+		case RowLevelSecurityOwner:
+			result, err = optCatalog.IsOwner(ctx, dep.Table)
+		case RowLevelSecurityMember:
+			result, err = optCatalog.IsMemberOfRole(ctx, dep.Role)
+		}
+		if err != nil {
+			return false, err
+		}
+		if result != dep.Result {
+			return false, nil
+		}
+	}
+
 	return true, nil
 }
 
@@ -619,6 +681,18 @@ func (md *Metadata) AddUserDefinedFunction(
 	}
 }
 
+// AddRowLevelSecurityDep records the result of a role check that was used to
+// decide which row-level security policies apply to the query.
+func (md *Metadata) AddRowLevelSecurityDep(dep RowLevelSecurityDep) {
+	for i := range md.rlsDeps {
+		existing := &md.rlsDeps[i]
+		if existing.Kind == dep.Kind && existing.Table == dep.Table && existing.Role == dep.Role {
+			return
+		}
+	}
+	md.rlsDeps = append(md.rlsDeps, dep)
+}
+
 // AddBuiltin adds a name used to resolve a builtin function to the metadata for
 // this query. This is necessary to handle the case when changes to the search
 // path cause a function call to resolve as a UDF instead of a builtin function.
@@ -1206,3 +1280,8 @@ func (md *Metadata) TestingObjectRefsByName() map[cat.StableID][]*tree.Unresolve
 func (md *Metadata) TestingPrivileges() map[cat.StableID]privilegeBitmap {
 	return md.privileges
 }
+
+// TestingRowLevelSecurityDeps exposes the rlsDeps for testing.
+func (md *Metadata) TestingRowLevelSecurityDeps() []RowLevelSecurityDep {
+	return md.rlsDeps
+}
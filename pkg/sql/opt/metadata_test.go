@@ -114,6 +114,9 @@ func TestMetadata(t *testing.T) {
 		types.OneIntCol,
 		udfName.ToUnresolvedObjectName(),
 	)
+	md.AddRowLevelSecurityDep(opt.RowLevelSecurityDep{
+		Kind: opt.RowLevelSecurityOwner, Table: tab, Result: true,
+	})
 
 	// Call CopyFrom and verify that same objects are present in new metadata.
 	expr := &memo.ProjectExpr{}
@@ -201,6 +204,11 @@ func TestMetadata(t *testing.T) {
 		}
 	}
 
+	newRLSDeps, oldRLSDeps := mdNew.TestingRowLevelSecurityDeps(), md.TestingRowLevelSecurityDeps()
+	if len(newRLSDeps) != len(oldRLSDeps) || newRLSDeps[0] != oldRLSDeps[0] {
+		t.Fatalf("expected row-level security dependencies to be copied")
+	}
+
 	depsUpToDate, err = md.CheckDependencies(context.Background(), &evalCtx, testCat)
 	if err == nil || depsUpToDate {
 		t.Fatalf("expected table privilege to be revoked in metadata copy")
@@ -78,6 +78,13 @@ func (c *CustomFuncs) neededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.MergeActionCol != 0 {
+		cols.Add(private.MergeActionCol)
+	}
+	for i := range private.FKCascades {
+		cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -146,6 +153,26 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// addDeleteCols adds the columns needed to delete existing rows.
+	addDeleteCols := func() {
+		// Add in all strict key columns from all indexes, since these are needed
+		// to compose the keys of rows to delete. Include mutation indexes, since
+		// it is necessary to delete rows even from indexes that are being added
+		// or dropped.
+		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
+			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+		}
+
+		// Add inbound foreign keys that may require a check or cascade.
+		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
+			inboundFK := tabMeta.Table.InboundForeignKey(i)
+			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
+				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -223,23 +250,13 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 			}
 		}
 
-	case opt.DeleteOp:
-		// Add in all strict key columns from all indexes, since these are needed
-		// to compose the keys of rows to delete. Include mutation indexes, since
-		// it is necessary to delete rows even from indexes that are being added
-		// or dropped.
-		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
-			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+		// An Upsert built for a MERGE statement may also delete rows.
+		if private.MergeActionCol != 0 {
+			addDeleteCols()
 		}
 
-		// Add inbound foreign keys that may require a check or cascade.
-		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
-			inboundFK := tabMeta.Table.InboundForeignKey(i)
-			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
-				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
-				cols.Add(tabMeta.MetaID.ColumnID(ord))
-			}
-		}
+	case opt.DeleteOp:
+		addDeleteCols()
 	}
 
 	return cols
@@ -82,6 +82,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "delete", tab, alias)
+	mb.rlsApplies = b.rowLevelSecurityApplies(tab)
 
 	// Build the input expression that selects the rows that will be deleted:
 	//
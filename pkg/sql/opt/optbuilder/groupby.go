@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,8 +101,52 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets is set if the GROUP BY clause contains GROUPING SETS, ROLLUP
+	// or CUBE which expand to more than one grouping set.
+	groupingSets *groupingSetsInfo
+}
+
+// groupingSetsInfo contains information about the grouping sets of a GROUP BY
+// clause. A query with grouping sets is built as a single aggregation over an
+// "expanded" input, which contains a copy of each input row for each grouping
+// set:
+//
+//	SELECT a, b, count(*) FROM t GROUP BY ROLLUP (a, b)
+//
+//	pre-projection:  a, b
+//	expansion:       cross join with VALUES (0), (1), (2) (as set), and
+//	                 project CASE WHEN set IN (2) THEN NULL ELSE a END (as a'),
+//	                 CASE WHEN set IN (1, 2) THEN NULL ELSE b END (as b')
+//	aggregation:     group by set, a', b', calculate count(*)
+//
+// A grouping column which is part of every grouping set is not replaced.
+type groupingSetsInfo struct {
+	// sets contains the grouping columns of each grouping set, as columns of
+	// the aggOutScope. Duplicate grouping sets are allowed; each produces its
+	// own groups.
+	sets []opt.ColSet
+
+	// outCols contains the aggOutScope column for each of the grouping columns
+	// in the aggInScope, in the same order. If a grouping column is not part of
+	// every grouping set, the corresponding aggOutScope column is a new column
+	// which is NULL for the grouping sets that don't contain it.
+	outCols opt.ColList
+
+	// setCol is the column which contains the index of the grouping set of each
+	// row of the expanded input. It is an additional grouping column of the
+	// aggregation, and is used to compute GROUPING().
+	setCol opt.ColumnID
 }
 
+// maxGroupingSets is the maximum number of grouping sets in a GROUP BY clause.
+// It matches the limit in Postgres.
+const maxGroupingSets = 4096
+
+// maxCubeElements is the maximum number of elements of a CUBE, which produces
+// one grouping set for every subset of its elements.
+const maxCubeElements = 12
+
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
 // grouping column in an aggOutScope scope that projects that expression. It
 // is used to enforce scoping rules, since any non-aggregate, variable
@@ -279,6 +324,45 @@ func (a *aggregateInfo) ResolvedType() *types.T {
 var _ tree.Expr = &aggregateInfo{}
 var _ tree.TypedExpr = &aggregateInfo{}
 
+// groupingInfo stores information about a GROUPING expression. It replaces the
+// tree.GroupingExpr during analysis, and is built by buildGroupingFunc.
+type groupingInfo struct {
+	*tree.GroupingExpr
+
+	// args contains the type-checked arguments of the GROUPING expression. Each
+	// of them must be a GROUP BY expression.
+	args tree.TypedExprs
+}
+
+// Walk is part of the tree.Expr interface.
+func (g *groupingInfo) Walk(v tree.Visitor) tree.Expr {
+	return g
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (g *groupingInfo) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return g, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (g *groupingInfo) Eval(_ context.Context, _ tree.ExprEvaluator) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingInfo must be replaced before evaluation"))
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (g *groupingInfo) ResolvedType() *types.T {
+	return types.Int
+}
+
+var _ tree.Expr = &groupingInfo{}
+var _ tree.TypedExpr = &groupingInfo{}
+
+// maxGroupingArgs is the maximum number of arguments to GROUPING, so that the
+// result fits in a 32-bit integer as in Postgres.
+const maxGroupingArgs = 31
+
 func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 	// We have an aggregation if:
 	//  - we have a GROUP BY, or
@@ -336,6 +420,64 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
+
+	if g.groupingSets != nil {
+		b.buildGroupingSetCols(g)
+	}
+}
+
+// buildGroupingSetCols allocates the aggOutScope columns of grouping columns
+// which are not part of every grouping set, as well as the grouping set index
+// column. The aggOutScope columns were copied from the aggInScope by
+// buildGroupingColumns, and g.groupingSets.sets refers to the aggInScope
+// columns when buildGroupingSetCols is called.
+func (b *Builder) buildGroupingSetCols(g *groupby) {
+	info := g.groupingSets
+	md := b.factory.Metadata()
+
+	// The grouping set index column is added to the aggOutScope as an anonymous
+	// column, so that it is passed through by any projections needed to build
+	// GROUPING expressions.
+	info.setCol = b.synthesizeColumn(
+		g.aggOutScope, scopeColName("").WithMetadataName("grouping_set"), types.Int, nil, nil,
+	).id
+
+	inCols := g.groupingCols()
+	outCols := g.aggOutScope.cols[len(g.aggOutScope.cols)-len(inCols)-1 : len(g.aggOutScope.cols)-1]
+	commonCols := info.sets[0].Copy()
+	for i := 1; i < len(info.sets); i++ {
+		commonCols.IntersectionWith(info.sets[i])
+	}
+
+	info.outCols = make(opt.ColList, len(inCols))
+	for i := range outCols {
+		if !commonCols.Contains(inCols[i].id) {
+			outCols[i].id = md.AddColumn(outCols[i].name.MetadataName(), outCols[i].typ)
+		}
+		info.outCols[i] = outCols[i].id
+	}
+
+	// Map the grouping sets and the GROUP BY expressions to the aggOutScope
+	// columns, so that references to grouping columns in the SELECT list,
+	// HAVING and ORDER BY observe the NULLs of the grouping sets that don't
+	// contain them.
+	for i := range info.sets {
+		var set opt.ColSet
+		for j := range inCols {
+			if info.sets[i].Contains(inCols[j].id) {
+				set.Add(info.outCols[j])
+			}
+		}
+		info.sets[i] = set
+	}
+	for str, col := range g.groupStrs {
+		for i := range inCols {
+			if inCols[i].id == col.id {
+				g.groupStrs[str] = &outCols[i]
+				break
+			}
+		}
+	}
 }
 
 // buildAggregation builds the aggregation operators and constructs the
@@ -354,6 +496,10 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates with GROUPING SETS, ROLLUP or CUBE are not supported"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -430,8 +576,13 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
+	input := g.aggInScope.expr
+	if g.groupingSets != nil {
+		input, groupingColSet = b.constructGroupingSetsInput(input, g, aggCols)
+	}
+
 	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr,
+		input,
 		groupingColSet,
 		aggCols,
 		g.aggInScope.ordering,
@@ -447,6 +598,167 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	return g.aggOutScope
 }
 
+// constructGroupingSetsInput expands the input of an aggregation with grouping
+// sets so that it contains a copy of each input row for each grouping set (see
+// groupingSetsInfo). It returns the expanded input and the grouping columns of
+// the aggregation.
+//
+// If there is an empty grouping set, the aggregation must produce a row for it
+// even if the input is empty, like a scalar aggregation. In this case, the
+// grouping set indexes are left joined with the input, rows which are not
+// matched are discarded unless they belong to an empty grouping set, and the
+// aggregates in aggCols are updated to ignore such rows.
+func (b *Builder) constructGroupingSetsInput(
+	input memo.RelExpr, g *groupby, aggCols []scopeColumn,
+) (memo.RelExpr, opt.ColSet) {
+	f := b.factory
+	md := f.Metadata()
+	info := g.groupingSets
+
+	setIdx := make(memo.ScalarListExpr, len(info.sets))
+	rows := make(memo.ScalarListExpr, len(info.sets))
+	rowTyp := types.MakeTuple([]*types.T{types.Int})
+	var emptySets []int
+	for i := range info.sets {
+		setIdx[i] = f.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int)
+		rows[i] = f.ConstructTuple(memo.ScalarListExpr{setIdx[i]}, rowTyp)
+		if info.sets[i].Empty() {
+			emptySets = append(emptySets, i)
+		}
+	}
+	values := f.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{info.setCol},
+		ID:   md.NextUniqueID(),
+	})
+
+	// constructInSets returns a "setCol IN (...)" expression for the grouping
+	// sets with the given indexes.
+	constructInSets := func(sets []int) opt.ScalarExpr {
+		elems := make(memo.ScalarListExpr, len(sets))
+		typs := make([]*types.T, len(sets))
+		for i, idx := range sets {
+			elems[i] = setIdx[idx]
+			typs[i] = types.Int
+		}
+		return f.ConstructIn(
+			f.ConstructVariable(info.setCol), f.ConstructTuple(elems, types.MakeTuple(typs)),
+		)
+	}
+
+	if len(emptySets) == 0 {
+		input = f.ConstructInnerJoin(input, values, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		presentCol := md.AddColumn("present", types.Bool)
+		input = f.ConstructProject(
+			input,
+			memo.ProjectionsExpr{f.ConstructProjectionsItem(memo.TrueSingleton, presentCol)},
+			input.Relational().OutputCols,
+		)
+		input = f.ConstructLeftJoin(values, input, memo.TrueFilter, memo.EmptyJoinPrivate)
+		filter := f.ConstructOr(
+			f.ConstructIsNot(f.ConstructVariable(presentCol), memo.NullSingleton),
+			constructInSets(emptySets),
+		)
+		input = f.ConstructSelect(input, memo.FiltersExpr{f.ConstructFiltersItem(filter)})
+
+		// Aggregates with a FILTER already ignore the unmatched rows, since the
+		// filter column is NULL.
+		for i := range aggCols {
+			if _, ok := aggCols[i].scalar.(*memo.AggFilterExpr); !ok {
+				aggCols[i].scalar = f.ConstructAggFilter(aggCols[i].scalar, f.ConstructVariable(presentCol))
+			}
+		}
+	}
+
+	// Replace the grouping columns which are not part of every grouping set.
+	groupingColSet := opt.MakeColSet(info.setCol)
+	var projections memo.ProjectionsExpr
+	for i, inCol := range g.groupingCols() {
+		outCol := info.outCols[i]
+		groupingColSet.Add(outCol)
+		if outCol == inCol.id {
+			continue
+		}
+		var nullSets []int
+		for j := range info.sets {
+			if !info.sets[j].Contains(outCol) {
+				nullSets = append(nullSets, j)
+			}
+		}
+		replacement := f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{f.ConstructWhen(constructInSets(nullSets), f.ConstructNull(inCol.typ))},
+			f.ConstructVariable(inCol.id),
+		)
+		projections = append(projections, f.ConstructProjectionsItem(replacement, outCol))
+	}
+	input = f.ConstructProject(input, projections, input.Relational().OutputCols)
+
+	return input, groupingColSet
+}
+
+// buildGroupingFunc builds a GROUPING expression, which returns a bitmask with
+// a bit for each argument, from the most significant bit for the first argument
+// to the least significant bit for the last one. A bit is set if the argument
+// is not part of the grouping set of the current group.
+func (b *Builder) buildGroupingFunc(
+	t *groupingInfo, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	if inScope.inAgg {
+		panic(pgerror.Newf(pgcode.Grouping,
+			"aggregate function calls cannot contain grouping operations"))
+	}
+	if !inScope.inGroupingContext() {
+		panic(tree.NewGroupingArgError())
+	}
+	g := inScope.groupby
+	argCols := make([]opt.ColumnID, len(t.args))
+	for i, arg := range t.args {
+		col, ok := g.groupStrs[symbolicExprStr(arg)]
+		if !ok {
+			panic(tree.NewGroupingArgError())
+		}
+		argCols[i] = col.id
+	}
+
+	constructInt := func(i int) opt.ScalarExpr {
+		return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int)
+	}
+
+	// Without grouping sets, every argument is part of the only grouping set.
+	info := g.groupingSets
+	if info == nil {
+		return constructInt(0)
+	}
+
+	masks := make([]int, len(info.sets))
+	constant := true
+	for i := range info.sets {
+		for j, col := range argCols {
+			if !info.sets[i].Contains(col) {
+				masks[i] |= 1 << (len(argCols) - 1 - j)
+			}
+		}
+		constant = constant && masks[i] == masks[0]
+	}
+	if constant {
+		return constructInt(masks[0])
+	}
+
+	// Build a CASE on the grouping set index.
+	if colRefs != nil {
+		colRefs.Add(info.setCol)
+	}
+	last := len(masks) - 1
+	whens := make(memo.ScalarListExpr, last)
+	for i := range whens {
+		whens[i] = b.factory.ConstructWhen(constructInt(i), constructInt(masks[i]))
+	}
+	return b.factory.ConstructCase(
+		b.factory.ConstructVariable(info.setCol), whens, constructInt(masks[last]),
+	)
+}
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -510,12 +822,115 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if groupBy.HasGroupingSets() {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope, nil /* setCols */)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+// buildGroupingSets builds the grouping columns of a GROUP BY clause which
+// contains GROUPING SETS, ROLLUP or CUBE. The grouping columns of all grouping
+// sets are added to the aggInScope, and if there is more than one grouping set,
+// the grouping columns of each set are stored in g.groupingSets.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
+) {
+	g := fromScope.groupby
+
+	// The grouping sets of the GROUP BY clause are the concatenations of a
+	// grouping set of each item (the cartesian product of the items' sets).
+	sets := [][]tree.Expr{nil}
+	for _, e := range groupBy {
+		itemSets := expandGroupingSets(e)
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([][]tree.Expr, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				product = append(product, append(set[:len(set):len(set)], itemSet...))
+			}
+		}
+		sets = product
+	}
+
+	setCols := make([]opt.ColSet, len(sets))
+	for i, set := range sets {
+		for _, e := range set {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope, &setCols[i])
+		}
+	}
+
+	// A single grouping set is equivalent to a regular GROUP BY.
+	if len(setCols) > 1 {
+		g.groupingSets = &groupingSetsInfo{sets: setCols}
+	}
+}
+
+var errTooManyGroupingSets = pgerror.Newf(
+	pgcode.StatementTooComplex, "too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+// expandGroupingSets returns the grouping sets of an item of a GROUP BY clause
+// or of a GROUPING SETS. Each grouping set is returned as a list of expressions
+// which are built as regular GROUP BY expressions; in particular, a tuple is
+// a single element of a ROLLUP or CUBE, and the empty tuple is the empty
+// grouping set.
+func expandGroupingSets(e tree.Expr) [][]tree.Expr {
+	gs, ok := e.(*tree.GroupingSet)
+	if !ok {
+		return [][]tree.Expr{{e}}
+	}
+	n := len(gs.Exprs)
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (e1, ..., en) is (e1, ..., en), (e1, ..., en-1), ..., ().
+		sets := make([][]tree.Expr, n+1)
+		for i := range sets {
+			sets[i] = gs.Exprs[:n-i]
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (e1, ..., en) is every subset of (e1, ..., en), from the full set
+		// down to ().
+		if n > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		sets := make([][]tree.Expr, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set []tree.Expr
+			for i := range gs.Exprs {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, gs.Exprs[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	case tree.GroupingSets:
+		// GROUPING SETS (g1, ..., gn) is the union of the grouping sets of its
+		// items.
+		var sets [][]tree.Expr
+		for _, item := range gs.Exprs {
+			sets = append(sets, expandGroupingSets(item)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %v", gs.Type))
+	}
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
 // groupStrs and to the aggInScope.
@@ -536,8 +951,15 @@ func (b *Builder) buildGroupingList(
 // aggInScope       The scope that will contain the grouping expressions as well
 //
 //	as the aggregate function arguments.
+//
+// setCols          If not nil, the grouping columns of the expression are added
+//
+//	to it.
 func (b *Builder) buildGrouping(
-	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
+	groupBy tree.Expr,
+	selects tree.SelectExprs,
+	projectionsScope, fromScope, aggInScope *scope,
+	setCols *opt.ColSet,
 ) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
@@ -636,7 +1058,10 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			if setCols != nil {
+				setCols.Add(col.id)
+			}
 			continue
 		}
 
@@ -646,6 +1071,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		if setCols != nil {
+			setCols.Add(col.id)
+		}
 	}
 }
 
@@ -939,7 +1367,12 @@ func newGroupingError(name tree.Name) error {
 // not specified in the query.
 // In the unique index or unique without index cases, all key columns must be
 // marked as NOT NULL to allow the implicit grouping.
+//
+// As in Postgres, implicit grouping columns are not allowed with grouping sets.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
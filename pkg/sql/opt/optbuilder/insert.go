@@ -232,6 +232,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	} else {
 		mb.init(b, "insert", tab, alias)
 	}
+	mb.rlsApplies = b.rowLevelSecurityApplies(tab)
 
 	// Compute target columns in two cases:
 	//
@@ -376,6 +377,8 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //     values specified for them.
 //  4. Each update value is the same as the corresponding insert value.
 //  5. There are no inbound foreign keys containing non-key columns.
+//  6. Row-level security policies are not enforced on the table. Existing
+//     rows must be checked against the policies.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -387,6 +390,11 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// #6: Existing rows must be filtered by the row-level security policies.
+	if mb.rlsApplies {
+		return true
+	}
+
 	// If there are any implicit partitioning columns in the primary index,
 	// these columns will need to be fetched.
 	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
@@ -0,0 +1,872 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
+)
+
+// duplicateMergeErrText is error text used when a target row is matched by
+// more than one source row that would update or delete it.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for a MERGE statement. A MERGE statement is
+// built as an Upsert operator with an additional "merge action" column that
+// tells the execution engine whether to insert, update, or delete each input
+// row. For example:
+//
+//	CREATE TABLE ab (a INT PRIMARY KEY, b INT)
+//	CREATE TABLE xy (x INT, y INT)
+//
+//	MERGE INTO ab USING xy ON a = x
+//	WHEN MATCHED AND y < 0 THEN DELETE
+//	WHEN MATCHED THEN UPDATE SET b = y
+//	WHEN NOT MATCHED THEN INSERT VALUES (x, y)
+//
+// An input expression roughly equivalent to this SQL is built:
+//
+//	SELECT
+//	  ins_a, ins_b, fetch_a, fetch_b, upd_b, action,
+//	  CASE action WHEN 1 THEN ins_a WHEN 2 THEN fetch_a END AS merge_a,
+//	  CASE action WHEN 1 THEN ins_b WHEN 2 THEN upd_b END AS merge_b
+//	FROM (
+//	  SELECT
+//	    *,
+//	    CASE WHEN clause = 3 THEN x END AS ins_a,
+//	    CASE WHEN clause = 3 THEN y END AS ins_b,
+//	    CASE WHEN clause = 2 THEN y ELSE fetch_b END AS upd_b
+//	  FROM (
+//	    SELECT DISTINCT ON (fetch_a) *
+//	    FROM (
+//	      SELECT *, CASE clause WHEN 1 THEN 3 WHEN 2 THEN 2 WHEN 3 THEN 1 END AS action
+//	      FROM (
+//	        SELECT
+//	          *,
+//	          CASE
+//	            WHEN fetch_a IS NOT NULL AND y < 0 THEN 1
+//	            WHEN fetch_a IS NOT NULL THEN 2
+//	            WHEN fetch_a IS NULL THEN 3
+//	          END AS clause
+//	        FROM xy LEFT JOIN ab AS fetch ON a = x
+//	      )
+//	    )
+//	    WHERE action IS NOT NULL
+//	  )
+//	)
+//
+// The "clause" column identifies the first WHEN clause whose conditions are
+// satisfied by each joined row, and the "action" column maps it to the
+// tree.MergeActionType of that clause. Rows for which no clause applies, or
+// for which the clause is DO NOTHING, are filtered out. Like the canary column
+// of an Upsert, a primary key column of the target table tells matched rows
+// from unmatched ones.
+//
+// The DISTINCT ON raises an error if a target row would be updated or deleted
+// more than once, which happens when it is matched by multiple source rows.
+// This mirrors the cardinality violation error raised by Postgres.
+//
+// The merged columns combine the insert and update values of each column, and
+// are NULL for deleted rows. They are used to evaluate check constraints,
+// partial index predicates, and uniqueness and foreign key checks. Because
+// deleted rows have no new values, deletion-side foreign key checks detect
+// references to the deleted rows.
+//
+// Note that RETURNING can only reference columns of the target table;
+// Postgres also allows source columns and the merge_action() function.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Existing rows
+	// are always read in order to match them with the source rows.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot merge into view \"%s\"", tab.Name(),
+		))
+	}
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	// Check the privileges required by the actions of the WHEN clauses.
+	var hasInsert, hasUpdate, hasDelete bool
+	for _, when := range merge.Whens {
+		switch when.Action {
+		case tree.MergeActionInsert:
+			hasInsert = true
+		case tree.MergeActionUpdate:
+			hasUpdate = true
+		case tree.MergeActionDelete:
+			hasDelete = true
+		}
+	}
+	if hasInsert {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+	}
+	if hasUpdate {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+	}
+	if hasDelete {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, generalMutation)
+
+	// MERGE does not yet enforce row-level security policies, so reject it
+	// before building anything that reads or writes the table.
+	if b.rowLevelSecurityApplies(tab) {
+		panic(unimplemented.Newf("MERGE with row-level security",
+			"MERGE is not supported on table %s with row-level security enabled", tab.Name()))
+	}
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+
+	// Left-join the source rows to the target table using the ON condition.
+	sourceScope, matchedScope := mb.buildInputForMerge(inScope, merge.Table, merge.Source, merge.On)
+
+	// Decide which WHEN clause and action applies to each row.
+	clauseColID := mb.addMergeActionCols(merge.Whens, sourceScope, matchedScope, hasUpdate || hasDelete)
+
+	// Add the insert columns before the fetch columns are set, so that computed
+	// insert columns are built from the insert values rather than from the
+	// existing values.
+	if hasInsert {
+		mb.addMergeInsertCols(merge.Whens, sourceScope, clauseColID)
+	}
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	if hasUpdate {
+		mb.addMergeUpdateCols(merge.Whens, matchedScope, clauseColID)
+	}
+
+	// Build the final merge statement, including any returned expressions.
+	if resultsNeeded(merge.Returning) {
+		mb.buildMerge(merge.Returning.(*tree.ReturningExprs), hasDelete)
+	} else {
+		mb.buildMerge(nil /* returning */, hasDelete)
+	}
+
+	return mb.outScope
+}
+
+// buildInputForMerge constructs a left outer join between the MERGE source and
+// the target table, using the given ON condition. The canary column is set to
+// a primary key column of the target table, which is null if a source row is
+// not matched.
+//
+// buildInputForMerge returns the scope of the source columns, which is used to
+// build the expressions of WHEN NOT MATCHED clauses, and the scope of the
+// joined columns, which is used to build the expressions of WHEN MATCHED
+// clauses.
+func (mb *mutationBuilder) buildInputForMerge(
+	inScope *scope, texpr tree.TableExpr, source tree.TableExpr, on tree.Expr,
+) (sourceScope, matchedScope *scope) {
+	var indexFlags *tree.IndexFlags
+	if t, ok := texpr.(*tree.AliasedTableExpr); ok && t.IndexFlags != nil {
+		indexFlags = t.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	sourceScope = mb.b.buildFromTables(tree.TableExprs{source}, noLocking, inScope)
+
+	// Fetch columns from different instance of the table metadata, so that it's
+	// possible to remap columns.
+	//
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	mb.fetchScope = mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations: true,
+			includeSystem:    true,
+			includeInverted:  false,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+		false, /* disableNotVisibleIndex */
+	)
+
+	// Check that the same table name is not used on both sides.
+	mb.b.validateJoinTableNames(sourceScope, mb.fetchScope)
+
+	// The ON condition can reference both the source and the target columns.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(sourceScope)
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	filter := mb.b.resolveAndBuildScalar(
+		on,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications|tree.RejectProcedures,
+		mb.outScope,
+	)
+	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
+		sourceScope.expr,
+		mb.fetchScope.expr,
+		memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(filter)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Record a not-null "canary" column. After the left-join, this will be null
+	// if the source row was not matched, or not null otherwise.
+	canaryOrd := mb.tab.Index(cat.PrimaryIndex).Column(0).Ordinal()
+	mb.canaryColID = mb.fetchScope.cols[canaryOrd].id
+
+	matchedScope = mb.outScope.replace()
+	matchedScope.appendColumnsFromScope(mb.outScope)
+	return sourceScope, matchedScope
+}
+
+// addMergeActionCols projects the column that identifies the WHEN clause that
+// applies to each row, and the merge action column derived from it. Rows
+// without an action are filtered out. If checkCardinality is true, an error
+// is raised if any target row is matched by more than one of the remaining
+// rows. The ID of the clause column is returned.
+func (mb *mutationBuilder) addMergeActionCols(
+	whens tree.MergeWhens, sourceScope, matchedScope *scope, checkCardinality bool,
+) (clauseColID opt.ColumnID) {
+	f := mb.b.factory
+	canary := f.ConstructVariable(mb.canaryColID)
+
+	// The conditions of WHEN NOT MATCHED clauses can only reference the source
+	// columns, since the target columns are always null.
+	notMatchedScope := sourceScope.replace()
+	notMatchedScope.appendColumnsFromScope(sourceScope)
+
+	// Build a CASE expression that returns the 1-based ordinal of the first
+	// WHEN clause whose conditions hold.
+	clauseWhens := make(memo.ScalarListExpr, len(whens))
+	actionWhens := make(memo.ScalarListExpr, 0, len(whens))
+	for i, when := range whens {
+		var cond opt.ScalarExpr
+		condScope := matchedScope
+		if when.Matched {
+			cond = f.ConstructIsNot(canary, memo.NullSingleton)
+		} else {
+			cond = f.ConstructIs(canary, memo.NullSingleton)
+			condScope = notMatchedScope
+		}
+		if when.Cond != nil {
+			cond = f.ConstructAnd(cond, mb.b.resolveAndBuildScalar(
+				when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, condScope,
+			))
+		}
+		clause := f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int)
+		clauseWhens[i] = f.ConstructWhen(cond, clause)
+
+		// DO NOTHING clauses have no action.
+		if when.Action != tree.MergeActionDoNothing {
+			action := f.ConstructConstVal(tree.NewDInt(tree.DInt(when.Action)), types.Int)
+			actionWhens = append(actionWhens, f.ConstructWhen(clause, action))
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	clauseCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_clause"),
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(memo.TrueSingleton, clauseWhens, f.ConstructNull(types.Int)),
+	)
+	clauseColID = clauseCol.id
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	projectionsScope = mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	actionCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_action"),
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(f.ConstructVariable(clauseColID), actionWhens, f.ConstructNull(types.Int)),
+	)
+	mb.mergeActionColID = actionCol.id
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Filter out rows for which no action is taken.
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructIsNot(f.ConstructVariable(mb.mergeActionColID), memo.NullSingleton),
+		)},
+	)
+
+	// Ensure that each target row is updated or deleted at most once. Rows that
+	// were not matched have null primary key values, and are never considered
+	// duplicates.
+	if checkCardinality {
+		var pkCols opt.ColSet
+		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+			col := primaryIndex.Column(i)
+			pkCols.Add(mb.fetchScope.cols[col.Ordinal()].id)
+		}
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+		)
+	}
+
+	return clauseColID
+}
+
+// addMergeInsertCols projects one insert column for each column of the target
+// table. Each insert column is a CASE expression that chooses the value given
+// by the WHEN NOT MATCHED clause that applies to the row, or the column's
+// default value if the clause does not specify one. Insert columns are null
+// for rows that are not inserted.
+func (mb *mutationBuilder) addMergeInsertCols(
+	whens tree.MergeWhens, sourceScope *scope, clauseColID opt.ColumnID,
+) {
+	// INSERT values should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE INSERT", tree.RejectSpecial)
+
+	f := mb.b.factory
+	n := mb.tab.ColumnCount()
+
+	// branches holds the CASE branches of each insert column, and needsDefault
+	// is true for columns which are not given a value by at least one INSERT.
+	branches := make([]memo.ScalarListExpr, n)
+	needsDefault := make([]bool, n)
+	for i, when := range whens {
+		if when.Action != tree.MergeActionInsert {
+			continue
+		}
+
+		mb.targetColList = mb.targetColList[:0]
+		mb.targetColSet = opt.ColSet{}
+		if len(when.Columns) != 0 {
+			mb.addTargetNamedColsForInsert(when.Columns)
+			if !when.DefaultValues() {
+				mb.checkNumCols(len(mb.targetColList), len(when.Values))
+			}
+		} else if !when.DefaultValues() {
+			mb.addTargetTableColsForInsert(len(when.Values))
+		}
+
+		clause := f.ConstructEq(
+			f.ConstructVariable(clauseColID),
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+		)
+		var hasValue intsets.Fast
+		for j, colID := range mb.targetColList {
+			ord := mb.tabID.ColumnOrdinal(colID)
+			if when.DefaultValues() {
+				continue
+			}
+			if _, ok := when.Values[j].(tree.DefaultVal); ok {
+				continue
+			}
+			hasValue.Add(ord)
+
+			// Raise an error if the target column is a `GENERATED ALWAYS AS
+			// IDENTITY` column. Such a column is not allowed to be explicitly
+			// written to.
+			if col := mb.tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+				panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(col.ColName())))
+			}
+
+			val := mb.buildMergeValue(when.Values[j], ord, sourceScope)
+			branches[ord] = append(branches[ord], f.ConstructWhen(clause, val))
+		}
+		for ord := range needsDefault {
+			if !hasValue.Contains(ord) {
+				needsDefault[ord] = true
+			}
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for i := 0; i < n; i++ {
+		tabCol := mb.tab.Column(i)
+		if tabCol.Kind() != cat.Ordinary || tabCol.IsComputed() {
+			continue
+		}
+
+		// Columns that are not given a value use their default value. Only
+		// inserted rows use the default value, so that default expressions with
+		// side effects are not evaluated for other rows.
+		colBranches := branches[i]
+		if needsDefault[i] {
+			expr := mb.parseDefaultExpr(mb.tabID.ColumnID(i))
+			if expr != tree.DNull {
+				isInsert := f.ConstructEq(
+					f.ConstructVariable(mb.mergeActionColID),
+					f.ConstructConstVal(tree.NewDInt(tree.DInt(tree.MergeActionInsert)), types.Int),
+				)
+				val := mb.buildMergeValue(expr, i, sourceScope)
+				colBranches = append(colBranches, f.ConstructWhen(isInsert, val))
+			}
+		}
+
+		var scalar opt.ScalarExpr
+		if len(colBranches) == 0 {
+			scalar = f.ConstructNull(tabCol.DatumType())
+		} else {
+			scalar = f.ConstructCase(memo.TrueSingleton, colBranches, f.ConstructNull(tabCol.DatumType()))
+		}
+		name := scopeColName(tabCol.ColName()).WithMetadataName(
+			fmt.Sprintf("%s_ins", tabCol.ColName()),
+		)
+		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, tabCol.DatumType(), nil /* expr */, scalar)
+		mb.insertColIDs[i] = scopeCol.id
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add write-only mutation columns with default values, and all computed
+	// columns.
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	mb.addSynthesizedDefaultCols(
+		mb.insertColIDs,
+		false, /* includeOrdinary */
+		false, /* applyOnUpdate */
+	)
+	mb.addAssignmentCasts(mb.insertColIDs)
+	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
+	mb.addAssignmentCasts(mb.insertColIDs)
+
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+}
+
+// addMergeUpdateCols projects one update column for each column of the target
+// table that is set by at least one WHEN MATCHED THEN UPDATE clause. Each
+// update column is a CASE expression that chooses the value given by the
+// clause that applies to the row, or the existing value if the clause does not
+// set the column. Computed columns and columns with ON UPDATE expressions are
+// then added as for an UPDATE statement.
+func (mb *mutationBuilder) addMergeUpdateCols(
+	whens tree.MergeWhens, matchedScope *scope, clauseColID opt.ColumnID,
+) {
+	// SET expressions should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE UPDATE SET", tree.RejectSpecial)
+
+	f := mb.b.factory
+	branches := make([]memo.ScalarListExpr, mb.tab.ColumnCount())
+	for i, when := range whens {
+		if when.Action != tree.MergeActionUpdate {
+			continue
+		}
+
+		// Decompose tuple SET expressions into individual values.
+		mb.targetColList = mb.targetColList[:0]
+		mb.targetColSet = opt.ColSet{}
+		var exprs tree.Exprs
+		for _, set := range when.Exprs {
+			mb.addTargetColsByName(set.Names)
+			if !set.Tuple {
+				exprs = append(exprs, set.Expr)
+				continue
+			}
+			t, ok := set.Expr.(*tree.Tuple)
+			if !ok {
+				panic(unimplementedWithIssueDetailf(35713, fmt.Sprintf("%T", set.Expr),
+					"source for a multiple-column MERGE UPDATE item must be a ROW() expression; not supported: %T", set.Expr))
+			}
+			if len(set.Names) != len(t.Exprs) {
+				panic(pgerror.Newf(pgcode.Syntax,
+					"number of columns (%d) does not match number of values (%d)",
+					len(set.Names), len(t.Exprs)))
+			}
+			exprs = append(exprs, t.Exprs...)
+		}
+
+		clause := f.ConstructEq(
+			f.ConstructVariable(clauseColID),
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+		)
+		for j, colID := range mb.targetColList {
+			ord := mb.tabID.ColumnOrdinal(colID)
+			expr := exprs[j]
+
+			// Allow right side of SET to be DEFAULT.
+			if _, ok := expr.(tree.DefaultVal); ok {
+				expr = mb.parseDefaultExpr(colID)
+			} else if col := mb.tab.Column(ord); col.IsGeneratedAlwaysAsIdentity() {
+				panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnUpdateError(string(col.ColName())))
+			}
+
+			val := mb.buildMergeValue(expr, ord, matchedScope)
+			branches[ord] = append(branches[ord], f.ConstructWhen(clause, val))
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for ord := range branches {
+		if len(branches[ord]) == 0 {
+			continue
+		}
+		tabCol := mb.tab.Column(ord)
+		caseExpr := f.ConstructCase(
+			memo.TrueSingleton, branches[ord], f.ConstructVariable(mb.fetchColIDs[ord]),
+		)
+		name := scopeColName(tabCol.ColName()).WithMetadataName(
+			fmt.Sprintf("%s_new", tabCol.ColName()),
+		)
+		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, tabCol.DatumType(), nil /* expr */, caseExpr)
+		mb.updateColIDs[ord] = scopeCol.id
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add additional columns for computed expressions that may depend on the
+	// updated columns, as well as mutation columns and columns with ON UPDATE
+	// expressions.
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	mb.addSynthesizedColsForUpdate()
+}
+
+// buildMergeValue builds the given INSERT or SET expression of a WHEN clause,
+// which provides a value for the target table column at the given ordinal. An
+// assignment cast is added if the type of the expression is not identical to
+// the type of the column.
+func (mb *mutationBuilder) buildMergeValue(expr tree.Expr, ord int, inScope *scope) opt.ScalarExpr {
+	targetCol := mb.tab.Column(ord)
+	targetType := targetCol.DatumType()
+	texpr := inScope.resolveType(expr, targetType)
+	val := mb.b.buildScalar(texpr, inScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+
+	srcType := val.DataType()
+	if srcType.Identical(targetType) {
+		return val
+	}
+	if !cast.ValidCast(srcType, targetType, cast.ContextAssignment) {
+		panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(targetCol.ColName())))
+	}
+	return mb.b.factory.ConstructAssignmentCast(val, targetType)
+}
+
+// buildMerge constructs an Upsert operator for a MERGE statement, possibly
+// wrapped by a Project operator that corresponds to the given RETURNING
+// clause.
+func (mb *mutationBuilder) buildMerge(returning *tree.ReturningExprs, hasDelete bool) {
+	// Merge input insert and update columns using CASE expressions.
+	mb.projectMergeColumns()
+
+	// Disambiguate names so that references in any expressions, such as a
+	// check constraint, refer to the correct columns.
+	mb.disambiguateColumns()
+
+	// Add any check constraint boolean columns to the input.
+	mb.addCheckConstraintCols(false /* isUpdate */)
+
+	// Add the partial index predicate expressions to the table metadata.
+	// These expressions are used to prune fetch columns during
+	// normalization.
+	mb.b.addPartialIndexPredicatesForTable(mb.md.TableMeta(mb.tabID), nil /* scan */)
+
+	// Project partial index PUT and DEL boolean columns.
+	mb.projectPartialIndexPutAndDelCols()
+
+	// Project the old values of foreign key cascades. This must happen before
+	// any checks are built, since they buffer the input of the mutation.
+	mb.projectMergeCascadeCols(hasDelete)
+
+	mb.buildUniqueChecksForUpsert()
+
+	mb.buildFKChecksForMerge(hasDelete)
+
+	private := mb.makeMutationPrivate(returning != nil)
+	mb.outScope.expr = mb.b.factory.ConstructUpsert(
+		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
+	)
+
+	mb.buildReturning(returning)
+}
+
+// projectMergeColumns projects a set of merged columns that contain the new
+// value of each target table column, depending on the merge action:
+//
+//	CASE action WHEN 1 THEN ins_col WHEN 2 THEN upd_col END
+//
+// The merged columns are null for deleted rows. See projectUpsertColumns for
+// the equivalent columns of an Upsert.
+func (mb *mutationBuilder) projectMergeColumns() {
+	f := mb.b.factory
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+
+	action := f.ConstructVariable(mb.mergeActionColID)
+	insertAction := f.ConstructConstVal(tree.NewDInt(tree.DInt(tree.MergeActionInsert)), types.Int)
+	updateAction := f.ConstructConstVal(tree.NewDInt(tree.DInt(tree.MergeActionUpdate)), types.Int)
+	for i, n := 0, mb.tab.ColumnCount(); i < n; i++ {
+		col := mb.tab.Column(i)
+		// Skip system columns.
+		if col.Kind() == cat.System {
+			continue
+		}
+
+		insertColID := mb.insertColIDs[i]
+		updateColID := mb.updateColIDs[i]
+		if updateColID == 0 {
+			updateColID = mb.fetchColIDs[i]
+		}
+		if insertColID == 0 && updateColID == 0 {
+			continue
+		}
+
+		whens := make(memo.ScalarListExpr, 0, 2)
+		if insertColID != 0 {
+			whens = append(whens, f.ConstructWhen(insertAction, f.ConstructVariable(insertColID)))
+		}
+		if updateColID != 0 {
+			whens = append(whens, f.ConstructWhen(updateAction, f.ConstructVariable(updateColID)))
+		}
+		caseExpr := f.ConstructCase(action, whens, f.ConstructNull(col.DatumType()))
+
+		name := scopeColName(col.ColName()).WithMetadataName(
+			fmt.Sprintf("merge_%s", col.ColName()),
+		)
+		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, col.DatumType(), nil /* expr */, caseExpr)
+
+		// The new columns are used by the Upsert operator in place of the
+		// original update columns. They are also used by RETURNING columns.
+		if mb.updateColIDs[i] != 0 {
+			mb.updateColIDs[i] = scopeCol.id
+		}
+		mb.upsertColIDs[i] = scopeCol.id
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// projectMergeCascadeCols projects the columns that provide the old values of
+// the foreign key cascades of a MERGE statement:
+//
+//	CASE action WHEN 2 THEN fetch_col END AS col_upd_old
+//	CASE action WHEN 3 THEN fetch_col END AS col_del_old
+//
+// The ON UPDATE action of a foreign key applies only to updated rows, and the
+// ON DELETE action applies only to deleted rows. The fetched values of
+// inserted rows are null, so they never match any child rows. However, the
+// fetched values of updated and deleted rows must be separated so that each
+// cascade only acts on the rows that its action applies to.
+func (mb *mutationBuilder) projectMergeCascadeCols(hasDelete bool) {
+	var updatedOrds, deletedOrds intsets.Fast
+	for i, n := 0, mb.tab.InboundForeignKeyCount(); i < n; i++ {
+		fk := mb.tab.InboundForeignKey(i)
+		a := fk.UpdateReferenceAction()
+		updated := mb.inboundFKColsUpdated(i) && a != tree.Restrict && a != tree.NoAction
+		a = fk.DeleteReferenceAction()
+		deleted := hasDelete && a != tree.Restrict && a != tree.NoAction
+		for j, m := 0, fk.ColumnCount(); j < m; j++ {
+			ord := fk.ReferencedColumnOrdinal(mb.tab, j)
+			if updated {
+				updatedOrds.Add(ord)
+			}
+			if deleted {
+				deletedOrds.Add(ord)
+			}
+		}
+	}
+	if updatedOrds.Empty() && deletedOrds.Empty() {
+		return
+	}
+
+	f := mb.b.factory
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+
+	projectCols := func(ords intsets.Fast, action tree.MergeActionType, suffix string) opt.OptionalColList {
+		if ords.Empty() {
+			return nil
+		}
+		colIDs := make(opt.OptionalColList, mb.tab.ColumnCount())
+		actionVal := f.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int)
+		ords.ForEach(func(ord int) {
+			col := mb.tab.Column(ord)
+			caseExpr := f.ConstructCase(
+				f.ConstructVariable(mb.mergeActionColID),
+				memo.ScalarListExpr{f.ConstructWhen(actionVal, f.ConstructVariable(mb.fetchColIDs[ord]))},
+				f.ConstructNull(col.DatumType()),
+			)
+			name := scopeColName("").WithMetadataName(fmt.Sprintf("%s_%s", col.ColName(), suffix))
+			scopeCol := mb.b.synthesizeColumn(projectionsScope, name, col.DatumType(), nil /* expr */, caseExpr)
+			colIDs[ord] = scopeCol.id
+		})
+		return colIDs
+	}
+	mb.mergeUpdatedColIDs = projectCols(updatedOrds, tree.MergeActionUpdate, "upd_old")
+	mb.mergeDeletedColIDs = projectCols(deletedOrds, tree.MergeActionDelete, "del_old")
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildFKChecksForMerge builds FK check queries and cascades for a MERGE
+// statement.
+//
+// See the comment at the top of mutation_builder_fk.go for general information
+// on checks and cascades.
+//
+// MERGE is similar to upsert (see buildFKChecksForUpsert), except that it can
+// also delete rows. Since the merged columns are null for deleted rows, the
+// "old" values of deleted rows are never removed by the Except expression of
+// the deletion-side checks. Therefore, if the MERGE can delete rows, the
+// deletion-side check is built for every inbound FK whose ON DELETE action is
+// not a cascading action.
+//
+// The ON UPDATE and ON DELETE actions of an inbound FK are built as separate
+// cascades, which read the old values of the updated and deleted rows from the
+// columns projected by projectMergeCascadeCols. The deletion cascade is built
+// first, so that child rows which are moved by the update cascade to a key
+// that was deleted by the same MERGE are not deleted along with it. If only
+// one of the two actions is a cascading action, the deletion-side check is
+// still built; since checks run after all cascades, it only fails if a child
+// row references a removed row after the cascade has run.
+func (mb *mutationBuilder) buildFKChecksForMerge(hasDelete bool) {
+	numOutbound := mb.tab.OutboundForeignKeyCount()
+	numInbound := mb.tab.InboundForeignKeyCount()
+
+	if numOutbound == 0 && numInbound == 0 {
+		return
+	}
+
+	h := &mb.fkCheckHelper
+	for i := 0; i < numOutbound; i++ {
+		if h.initWithOutboundFK(mb, i) {
+			mb.fkChecks = append(mb.fkChecks, h.buildInsertionCheck())
+		}
+	}
+
+	for i := 0; i < numInbound; i++ {
+		updated := mb.inboundFKColsUpdated(i)
+		if !updated && !hasDelete {
+			continue
+		}
+
+		if !h.initWithInboundFK(mb, i) {
+			continue
+		}
+
+		needsCheck := false
+		if a := h.fk.DeleteReferenceAction(); hasDelete {
+			if a != tree.Restrict && a != tree.NoAction {
+				telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+				mb.ensureWithID()
+				var builder memo.CascadeBuilder
+				switch a {
+				case tree.Cascade:
+					builder = newOnDeleteCascadeBuilder(mb.tab, i, h.otherTab)
+				case tree.SetNull, tree.SetDefault:
+					builder = newOnDeleteSetBuilder(mb.tab, i, h.otherTab, a)
+				default:
+					panic(errors.AssertionFailedf("unhandled action type %s", a))
+				}
+
+				cols := make(opt.ColList, len(h.tabOrdinals))
+				for j, tabOrd := range h.tabOrdinals {
+					cols[j] = mb.mergeDeletedColIDs[tabOrd]
+				}
+				mb.cascades = append(mb.cascades, memo.FKCascade{
+					FKConstraint: h.fk,
+					Builder:      builder,
+					WithID:       mb.withID,
+					OldValues:    cols,
+					NewValues:    nil,
+				})
+			} else {
+				needsCheck = true
+			}
+		}
+
+		if a := h.fk.UpdateReferenceAction(); updated {
+			if a != tree.Restrict && a != tree.NoAction {
+				telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+				mb.ensureWithID()
+				builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
+
+				// Only updated rows have non-null old values, so the cascade
+				// ignores inserted and deleted rows.
+				oldCols := make(opt.ColList, len(h.tabOrdinals))
+				newCols := make(opt.ColList, len(h.tabOrdinals))
+				for j, tabOrd := range h.tabOrdinals {
+					updateColID := mb.updateColIDs[tabOrd]
+					if updateColID == 0 {
+						updateColID = mb.fetchColIDs[tabOrd]
+					}
+
+					oldCols[j] = mb.mergeUpdatedColIDs[tabOrd]
+					newCols[j] = updateColID
+				}
+				mb.cascades = append(mb.cascades, memo.FKCascade{
+					FKConstraint: h.fk,
+					Builder:      builder,
+					WithID:       mb.withID,
+					OldValues:    oldCols,
+					NewValues:    newCols,
+				})
+			} else {
+				needsCheck = true
+			}
+		}
+
+		if !needsCheck {
+			continue
+		}
+
+		// Construct an Except expression for the set difference between "old" FK
+		// values and "new" FK values. See buildFKChecksForUpdate for more details.
+		oldRowsScope, _ := mb.buildCheckInputScan(checkInputScanFetchedVals, h.tabOrdinals, true /* isFK */)
+		newRowsScope, _ := mb.buildCheckInputScan(checkInputScanNewVals, h.tabOrdinals, true /* isFK */)
+		colsForOldRow := oldRowsScope.colList()
+		colsForNewRow := newRowsScope.colList()
+
+		// The rows that no longer exist are the ones that were deleted or
+		// updated _from_, minus the ones that were inserted or updated _to_.
+		deletedRows := mb.b.factory.ConstructExcept(
+			oldRowsScope.expr,
+			newRowsScope.expr,
+			&memo.SetPrivate{
+				LeftCols:  colsForOldRow,
+				RightCols: colsForNewRow,
+				OutCols:   colsForOldRow,
+			},
+		)
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, colsForOldRow))
+	}
+	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
+}
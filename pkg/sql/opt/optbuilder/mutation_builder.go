@@ -146,6 +146,26 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// mergeActionColID is the ID of the column that is used by a MERGE
+	// statement to decide whether to insert, update, or delete each row. Its
+	// values are those of tree.MergeActionType. It is 0 for all other
+	// statements.
+	mergeActionColID opt.ColumnID
+
+	// mergeUpdatedColIDs and mergeDeletedColIDs list the input column IDs that
+	// hold the fetched values of the rows that are updated or deleted by a MERGE
+	// statement, respectively, and are null for all other rows. They provide
+	// the old values of foreign key cascades. Table columns which are not
+	// referenced by a cascading foreign key are set to 0. Both are nil for all
+	// other statements.
+	mergeUpdatedColIDs opt.OptionalColList
+	mergeDeletedColIDs opt.OptionalColList
+
+	// rlsApplies is true if the row-level security policies of the target table
+	// must be enforced for the current user. It is always false for mutations
+	// built for foreign key cascades, which bypass row-level security.
+	rlsApplies bool
+
 	// arbiters is the set of indexes and unique constraints that are used to
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
@@ -314,6 +334,9 @@ func (mb *mutationBuilder) buildInputForUpdate(
 		inScope,
 		false, /* disableNotVisibleIndex */
 	)
+	if mb.rlsApplies {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandUpdate, mb.fetchScope)
+	}
 
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
@@ -425,6 +448,9 @@ func (mb *mutationBuilder) buildInputForDelete(
 		inScope,
 		false, /* disableNotVisibleIndex */
 	)
+	if mb.rlsApplies {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandDelete, mb.fetchScope)
+	}
 
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
@@ -814,6 +840,12 @@ func (mb *mutationBuilder) addCheckConstraintCols(isUpdate bool) {
 
 		for i, n := 0, mb.tab.CheckCount(); i < n; i++ {
 			check := mb.tab.Check(i)
+			if check.IsRLSConstraint() {
+				if mb.rlsApplies {
+					mb.addRowLevelSecurityCheckCol(projectionsScope, i)
+				}
+				continue
+			}
 			expr, err := parser.ParseExpr(check.Constraint())
 			if err != nil {
 				panic(err)
@@ -881,6 +913,51 @@ func (mb *mutationBuilder) addCheckConstraintCols(isUpdate bool) {
 	}
 }
 
+// addRowLevelSecurityCheckCol synthesizes the boolean output column for the
+// row-level security check of the target table, which is the ith check
+// constraint of the table. The column is false or null if a new row violates
+// the WITH CHECK expressions of the policies that apply to the current user.
+//
+// For UPSERT and INSERT .. ON CONFLICT DO UPDATE statements, the canary column
+// determines whether the INSERT or the UPDATE policies are checked.
+func (mb *mutationBuilder) addRowLevelSecurityCheckCol(projectionsScope *scope, i int) {
+	var scalar opt.ScalarExpr
+	switch {
+	case mb.canaryColID != 0:
+		insertCheck := mb.b.buildPolicyScalar(
+			mb.tab, tree.PolicyCommandInsert, true /* withCheck */, mb.outScope,
+		)
+		updateCheck := mb.b.buildPolicyScalar(
+			mb.tab, tree.PolicyCommandUpdate, true /* withCheck */, mb.outScope,
+		)
+		scalar = mb.b.factory.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{
+				mb.b.factory.ConstructWhen(
+					mb.b.factory.ConstructIs(
+						mb.b.factory.ConstructVariable(mb.canaryColID),
+						memo.NullSingleton,
+					),
+					insertCheck,
+				),
+			},
+			updateCheck,
+		)
+	case mb.opName == "update":
+		scalar = mb.b.buildPolicyScalar(
+			mb.tab, tree.PolicyCommandUpdate, true /* withCheck */, mb.outScope,
+		)
+	default:
+		scalar = mb.b.buildPolicyScalar(
+			mb.tab, tree.PolicyCommandInsert, true /* withCheck */, mb.outScope,
+		)
+	}
+
+	colName := scopeColName("").WithMetadataName(fmt.Sprintf("check%d", i+1))
+	scopeCol := mb.b.synthesizeColumn(projectionsScope, colName, types.Bool, nil /* expr */, scalar)
+	mb.checkColIDs[i] = scopeCol.id
+}
+
 // getColumnFamilySet gets the set of column families represented in colOrdinals.
 func getColumnFamilySet(colOrdinals intsets.Fast, tab cat.Table) intsets.Fast {
 	families := intsets.Fast{}
@@ -1067,6 +1144,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:           checkEmptyList(mb.fetchColIDs),
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
+		MergeActionCol:      mb.mergeActionColID,
 		ArbiterIndexes:      mb.arbiters.IndexOrdinals(),
 		ArbiterConstraints:  mb.arbiters.UniqueConstraintOrdinals(),
 		CheckCols:           checkEmptyList(mb.checkColIDs),
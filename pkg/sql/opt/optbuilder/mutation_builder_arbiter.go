@@ -478,6 +478,11 @@ func (mb *mutationBuilder) buildLeftJoinForUpsertArbiter(
 		inScope,
 		true, /* disableNotVisibleIndex */
 	)
+	// Existing rows that the current user cannot update are not visible as
+	// conflicts. Inserting such a row then fails with a uniqueness violation.
+	if mb.rlsApplies {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandUpdate, mb.fetchScope)
+	}
 	// Set fetchColIDs to reference the columns created for the fetch values.
 	mb.setFetchColIDs(mb.fetchScope.cols)
 
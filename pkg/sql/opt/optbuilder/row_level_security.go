@@ -0,0 +1,189 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// rowLevelSecurityApplies returns true if the row-level security policies of
+// the given table must be enforced for the current user. Policies are not
+// enforced if row-level security is not enabled for the table, if the user has
+// the BYPASSRLS role option (which admin users always have), or if the user
+// owns the table and row-level security is not forced for the table owner.
+//
+// Policies are also not enforced while building view and function
+// definitions, since only the SQL text of those definitions is stored and the
+// policies are applied when the definition is used.
+//
+// The results of the role checks are recorded in the metadata, so that a
+// cached memo is invalidated if it is re-used by a user for whom different
+// policies apply.
+func (b *Builder) rowLevelSecurityApplies(tab cat.Table) bool {
+	if !tab.IsRowLevelSecurityEnabled() || b.trackSchemaDeps {
+		return false
+	}
+	md := b.factory.Metadata()
+	bypass, err := b.catalog.HasRoleOption(b.ctx, roleoption.BYPASSRLS)
+	if err != nil {
+		panic(err)
+	}
+	md.AddRowLevelSecurityDep(opt.RowLevelSecurityDep{
+		Kind: opt.RowLevelSecurityBypass, Result: bypass,
+	})
+	if bypass {
+		return false
+	}
+	if tab.IsRowLevelSecurityForced() {
+		return true
+	}
+	isOwner, err := b.catalog.IsOwner(b.ctx, tab)
+	if err != nil {
+		panic(err)
+	}
+	md.AddRowLevelSecurityDep(opt.RowLevelSecurityDep{
+		Kind: opt.RowLevelSecurityOwner, Table: tab, Result: isOwner,
+	})
+	return !isOwner
+}
+
+// addRowLevelSecurityFilter wraps the expression of the given scope, which
+// must be a scan of the given table, in a Select that filters out the rows
+// that the USING expressions of the table's policies for the given command do
+// not allow the current user to access.
+//
+// As in Postgres, an UPDATE reads the rows it updates, so the USING
+// expressions of the SELECT policies are applied as well as those of the
+// UPDATE policies. Rows that the current user cannot see are never updated.
+//
+// The Select is wrapped in a Barrier so that it acts as a security barrier:
+// filters and join conditions of the query, which may call functions that
+// leak the values they are passed (e.g. through errors), cannot be pushed
+// below it and evaluated on rows that the policies reject.
+func (b *Builder) addRowLevelSecurityFilter(tab cat.Table, cmd tree.PolicyCommand, s *scope) {
+	filter := b.buildPolicyScalar(tab, cmd, false /* withCheck */, s)
+	filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)}
+	if cmd == tree.PolicyCommandUpdate {
+		selectFilter := b.buildPolicyScalar(tab, tree.PolicyCommandSelect, false /* withCheck */, s)
+		filters = append(filters, b.factory.ConstructFiltersItem(selectFilter))
+	}
+	s.expr = b.factory.ConstructSelect(s.expr, filters)
+	s.expr = b.factory.ConstructBarrier(s.expr)
+}
+
+// buildPolicyScalar builds the combined policy expression of the given table
+// for the given command (see policyExpr) as a scalar expression, resolving
+// column references in the given scope.
+func (b *Builder) buildPolicyScalar(
+	tab cat.Table, cmd tree.PolicyCommand, withCheck bool, inScope *scope,
+) opt.ScalarExpr {
+	// Policy expressions can contain subqueries. Detect a policy that refers
+	// back to its own table, which would otherwise recurse forever.
+	if _, ok := b.policyTables[tab.ID()]; ok {
+		panic(pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"infinite recursion detected in policy for relation %q", tab.Name()))
+	}
+	if b.policyTables == nil {
+		b.policyTables = make(map[cat.StableID]struct{})
+	}
+	b.policyTables[tab.ID()] = struct{}{}
+	defer delete(b.policyTables, tab.ID())
+
+	expr := b.policyExpr(tab, cmd, withCheck)
+	texpr := inScope.resolveAndRequireType(expr, types.Bool)
+	return b.buildScalar(texpr, inScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+}
+
+// policyExpr returns an expression that combines the policies of the given
+// table that apply to the given command and to the current user. If withCheck
+// is true, the WITH CHECK expressions of the policies are used, falling back
+// to their USING expressions. Otherwise, only the USING expressions are used.
+//
+// As in Postgres, the expressions of permissive policies are combined with OR,
+// and the result is combined with the expressions of restrictive policies
+// using AND. If no permissive policy applies, the expression is false so that
+// all rows are rejected.
+func (b *Builder) policyExpr(tab cat.Table, cmd tree.PolicyCommand, withCheck bool) tree.Expr {
+	var permissive, restrictive tree.Expr
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		policy := tab.Policy(i)
+		if policy.Command() != tree.PolicyCommandAll && policy.Command() != cmd {
+			continue
+		}
+		if !b.policyAppliesToCurrentUser(policy) {
+			continue
+		}
+		var exprStr string
+		var ok bool
+		if withCheck {
+			exprStr, ok = policy.WithCheckExpr()
+		}
+		if !ok {
+			exprStr, ok = policy.UsingExpr()
+		}
+		if !ok {
+			continue
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.ParenExpr{Expr: expr}
+		if policy.IsPermissive() {
+			if permissive == nil {
+				permissive = expr
+			} else {
+				permissive = &tree.OrExpr{Left: permissive, Right: expr}
+			}
+		} else {
+			if restrictive == nil {
+				restrictive = expr
+			} else {
+				restrictive = &tree.AndExpr{Left: restrictive, Right: expr}
+			}
+		}
+	}
+	if permissive == nil {
+		return tree.DBoolFalse
+	}
+	if restrictive == nil {
+		return permissive
+	}
+	return &tree.AndExpr{Left: &tree.ParenExpr{Expr: permissive}, Right: restrictive}
+}
+
+// policyAppliesToCurrentUser returns true if the current user is a member of
+// any of the roles that the given policy applies to.
+func (b *Builder) policyAppliesToCurrentUser(policy cat.Policy) bool {
+	md := b.factory.Metadata()
+	for i, n := 0, policy.RoleCount(); i < n; i++ {
+		role := policy.Role(i)
+		isMember, err := b.catalog.IsMemberOfRole(b.ctx, role)
+		if err != nil {
+			panic(err)
+		}
+		md.AddRowLevelSecurityDep(opt.RowLevelSecurityDep{
+			Kind: opt.RowLevelSecurityMember, Role: role, Result: isMember,
+		})
+		if isMember {
+			return true
+		}
+	}
+	return false
+}
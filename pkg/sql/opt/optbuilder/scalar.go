@@ -120,6 +120,9 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingInfo:
+		out = b.buildGroupingFunc(t, inScope, colRefs)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(reType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(reType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
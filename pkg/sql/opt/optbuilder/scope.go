@@ -130,6 +130,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -153,6 +154,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -1103,6 +1105,9 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.GroupingExpr:
+		expr = s.replaceGrouping(t)
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
@@ -1311,6 +1316,29 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.ResolvedFunctionDef
 	return s.builder.buildAggregateFunction(f, &private, tempScope, s)
 }
 
+// replaceGrouping returns a groupingInfo struct that can be used to replace a
+// GROUPING expression. The arguments are resolved and type-checked here, but
+// they are only matched to the GROUP BY expressions when the groupingInfo is
+// built, since the grouping columns have not been built yet.
+func (s *scope) replaceGrouping(t *tree.GroupingExpr) *groupingInfo {
+	if s.builder.semaCtx.Properties.IsSet(tree.RejectAggregates) {
+		panic(tree.NewInvalidGroupingUsageError(s.builder.semaCtx.Properties.Context()))
+	}
+	// Like aggregates, GROUPING is evaluated after the WHERE clause.
+	if s.context == exprKindWhere {
+		panic(tree.NewInvalidGroupingUsageError(s.context.String()))
+	}
+	if len(t.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1))
+	}
+	info := &groupingInfo{GroupingExpr: t, args: make(tree.TypedExprs, len(t.Exprs))}
+	for i, e := range t.Exprs {
+		info.args[i] = s.resolveType(e, types.Any)
+	}
+	return info
+}
+
 func (s *scope) lookupWindowDef(name tree.Name) *tree.WindowDef {
 	for i := range s.windowDefs {
 		if s.windowDefs[i].Name == name {
@@ -183,7 +183,7 @@ func (b *Builder) buildDataSource(
 					locking = nil
 				}
 			}
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations: false,
@@ -193,6 +193,10 @@ func (b *Builder) buildDataSource(
 				indexFlags, locking, inScope,
 				false, /* disableNotVisibleIndex */
 			)
+			if b.rowLevelSecurityApplies(t) {
+				b.addRowLevelSecurityFilter(t, tree.PolicyCommandSelect, outScope)
+			}
+			return outScope
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
@@ -532,9 +536,19 @@ func (b *Builder) buildScanFromTableRef(
 			locking = nil
 		}
 	}
-	return b.buildScan(
+	outScope = b.buildScan(
 		tabMeta, ordinals, indexFlags, locking, inScope, false, /* disableNotVisibleIndex */
 	)
+	if b.rowLevelSecurityApplies(tab) {
+		if ref.Columns != nil {
+			// The policy expressions may refer to columns that are not scanned.
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"numeric column references are not supported on table %q with row-level security",
+				tab.Name()))
+		}
+		b.addRowLevelSecurityFilter(tab, tree.PolicyCommandSelect, outScope)
+	}
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
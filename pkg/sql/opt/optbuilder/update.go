@@ -96,6 +96,7 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
+	mb.rlsApplies = b.rowLevelSecurityApplies(tab)
 
 	// Build the input expression that selects the rows that will be updated:
 	//
@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 )
 
@@ -950,6 +951,26 @@ func (tt *Table) addIndexWithVersion(
 						MaxCells: 3,
 					}},
 				}
+
+			case types.PGVectorFamily:
+				// Use a small number of partitions to keep the number of spans small.
+				cfg, err := vecindex.MakeConfig(
+					tt.Columns[col.InvertedSourceColumnOrdinal()].DatumType().Width(), 4,
+				)
+				if err != nil {
+					panic(err)
+				}
+				idx.vectorConfig = cfg
+				switch colDef.OpClass {
+				case "", "vector_l2_ops":
+					idx.invertedKind = catpb.InvertedIndexColumnKind_VECTOR_L2
+				case "vector_cosine_ops":
+					idx.invertedKind = catpb.InvertedIndexColumnKind_VECTOR_COSINE
+				case "vector_ip_ops":
+					idx.invertedKind = catpb.InvertedIndexColumnKind_VECTOR_IP
+				default:
+					panic(fmt.Errorf("unsupported operator class %s", colDef.OpClass))
+				}
 			}
 		}
 	}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -327,6 +328,16 @@ func (tc *Catalog) CheckRoleExists(ctx context.Context, role username.SQLUsernam
 	return nil
 }
 
+// IsOwner is part of the cat.Catalog interface.
+func (tc *Catalog) IsOwner(ctx context.Context, o cat.Object) (bool, error) {
+	return true, nil
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (tc *Catalog) IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error) {
+	return true, nil
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (tc *Catalog) Optimizer() interface{} {
 	return nil
@@ -962,6 +973,26 @@ func (tt *Table) IsHypothetical() bool {
 	return false
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (tt *Table) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (tt *Table) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // FindOrdinal returns the ordinal of the column with the given name.
 func (tt *Table) FindOrdinal(name string) int {
 	for i, col := range tt.Columns {
@@ -1080,6 +1111,14 @@ type Index struct {
 	// inverted index.
 	geoConfig geopb.Config
 
+	// vectorConfig is the vector index configuration, if this is a vector
+	// inverted index.
+	vectorConfig catpb.VectorIndexConfig
+
+	// invertedKind is the kind of the inverted column, if this is an inverted
+	// index.
+	invertedKind catpb.InvertedIndexColumnKind
+
 	// version is the index descriptor version of the index.
 	version descpb.IndexDescriptorVersion
 
@@ -1196,6 +1235,16 @@ func (ti *Index) GeoConfig() geopb.Config {
 	return ti.geoConfig
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (ti *Index) VectorConfig() catpb.VectorIndexConfig {
+	return ti.vectorConfig
+}
+
+// InvertedColumnKind is part of the cat.Index interface.
+func (ti *Index) InvertedColumnKind() catpb.InvertedIndexColumnKind {
+	return ti.invertedKind
+}
+
 // Version is part of the cat.Index interface.
 func (ti *Index) Version() descpb.IndexDescriptorVersion {
 	return ti.version
@@ -1275,6 +1324,11 @@ func (c *CheckConstraint) ColumnOrdinal(i int) int {
 	return c.columnOrdinals[i]
 }
 
+// IsRLSConstraint is part of the cat.CheckConstraint interface.
+func (c *CheckConstraint) IsRLSConstraint() bool {
+	return false
+}
+
 // TableStat implements the cat.TableStatistic interface for testing purposes.
 type TableStat struct {
 	js            stats.JSONStatistic
@@ -11,6 +11,10 @@
 package xform
 
 import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
@@ -18,6 +22,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
 	"github.com/cockroachdb/errors"
 )
 
@@ -426,3 +432,143 @@ func (c *CustomFuncs) GeneratePartialOrderTopK(
 func (c *CustomFuncs) CanPushOffsetIntoIndexJoin() bool {
 	return c.e.evalCtx.SessionData().OptimizerPushOffsetIntoIndexJoin
 }
+
+// GenerateVectorSearch generates a nearest neighbor search over each vector
+// index that can provide the first column of the required ordering. That
+// column must be projected by an ascending distance between the indexed column
+// and a constant query vector, using the distance operator of the index's
+// operator class. The search scans the partitions of the vector index that are
+// most likely to contain the nearest neighbors of the query vector, fetches the
+// rows in them with an index join, applies the given filters, and reconstructs
+// the Project and Limit operators on top of the result. The number of
+// partitions scanned is determined by the vector_search_probes session setting.
+//
+// input is the original input of the Project operator. The indexed column must
+// be non-NULL in it, since vector indexes do not contain NULL vectors and NULLs
+// are ordered first.
+func (c *CustomFuncs) GenerateVectorSearch(
+	grp memo.RelExpr,
+	required *physical.Required,
+	input memo.RelExpr,
+	scanPrivate *memo.ScanPrivate,
+	filters memo.FiltersExpr,
+	projections memo.ProjectionsExpr,
+	passthrough opt.ColSet,
+	limit opt.ScalarExpr,
+	requiredOrdering props.OrderingChoice,
+) {
+	// Vector indexes do not contain the indexed vectors, so the search always
+	// requires an index join.
+	if scanPrivate.Flags.NoIndexJoin || len(requiredOrdering.Columns) == 0 {
+		return
+	}
+	orderCol := &requiredOrdering.Columns[0]
+	if orderCol.Descending {
+		return
+	}
+	var distance opt.ScalarExpr
+	for i := range projections {
+		if orderCol.Group.Contains(projections[i].Col) {
+			distance = projections[i].Element
+			break
+		}
+	}
+	col, query, kind, ok := vectorDistanceOperands(distance)
+	if !ok || !input.Relational().NotNullCols.Contains(col) {
+		return
+	}
+
+	probes := int(c.e.evalCtx.SessionData().VectorSearchProbes)
+	var pkCols opt.ColSet
+	var sb indexScanBuilder
+	sb.Init(c, scanPrivate.Table)
+
+	// Iterate over all non-partial inverted indexes, looking for vector indexes
+	// on the column.
+	var iter scanIndexIter
+	iter.Init(c.e.evalCtx, c.e, c.e.mem, &c.im, scanPrivate, nil /* filters */, rejectNonInvertedIndexes|rejectPartialIndexes)
+	iter.ForEach(func(index cat.Index, _ memo.FiltersExpr, _ opt.ColSet, _ bool, _ memo.ProjectionsExpr) {
+		config := index.VectorConfig()
+		if config.IsEmpty() || index.InvertedColumnKind() != kind ||
+			index.NonInvertedPrefixColumnCount() > 0 {
+			return
+		}
+		srcCol := scanPrivate.Table.ColumnID(index.InvertedColumn().InvertedSourceColumnOrdinal())
+		if srcCol != col {
+			return
+		}
+		partitions, err := vecindex.MakePartitioner(config).SearchPartitions(query, probes)
+		if err != nil {
+			// The query vector does not have the dimensions of the indexed
+			// vectors. The distance computation will return the error during
+			// execution.
+			return
+		}
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+		spans := make(inverted.Spans, len(partitions))
+		for i, partition := range partitions {
+			spans[i] = inverted.MakeSingleValSpan(vecindex.EncodeKey(nil, partition))
+		}
+
+		// Calculate the PK columns once.
+		if pkCols.Empty() {
+			pkCols = c.PrimaryKeyCols(scanPrivate.Table)
+		}
+
+		// Each row is stored in a single partition of the vector index, so the
+		// scan does not produce duplicate primary keys and does not need an
+		// inverted filter.
+		newScanPrivate := *scanPrivate
+		newScanPrivate.Distribution.Regions = nil
+		newScanPrivate.Index = index.Ordinal()
+		newScanPrivate.Cols = pkCols.Copy()
+		newScanPrivate.InvertedConstraint = spans
+		sb.SetScan(&newScanPrivate)
+		sb.AddIndexJoin(scanPrivate.Cols)
+		sb.AddSelect(filters)
+		newInput := sb.BuildNewExpr()
+
+		// Reconstruct the Project and Limit so the new expression in the memo is
+		// equivalent.
+		newInput = c.e.f.ConstructProject(newInput, projections, passthrough)
+		grp.Memo().AddLimitToGroup(&memo.LimitExpr{Limit: limit, Ordering: requiredOrdering, Input: newInput}, grp)
+	})
+}
+
+// vectorDistanceOperands returns the column and constant query vector of the
+// given distance expression between a vector column and a constant vector,
+// along with the kind of vector index that can search by the distance. ok is
+// false if the expression is not such a distance.
+func vectorDistanceOperands(
+	distance opt.ScalarExpr,
+) (col opt.ColumnID, query vector.T, kind catpb.InvertedIndexColumnKind, ok bool) {
+	var left, right opt.ScalarExpr
+	switch t := distance.(type) {
+	case *memo.VectorDistanceExpr:
+		left, right, kind = t.Left, t.Right, catpb.InvertedIndexColumnKind_VECTOR_L2
+	case *memo.VectorCosDistanceExpr:
+		left, right, kind = t.Left, t.Right, catpb.InvertedIndexColumnKind_VECTOR_COSINE
+	case *memo.VectorNegInnerProductExpr:
+		left, right, kind = t.Left, t.Right, catpb.InvertedIndexColumnKind_VECTOR_IP
+	default:
+		return 0, nil, 0, false
+	}
+	// The distance operators are symmetric, so the column can be on either
+	// side.
+	if left.Op() == opt.ConstOp {
+		left, right = right, left
+	}
+	variable, ok := left.(*memo.VariableExpr)
+	if !ok {
+		return 0, nil, 0, false
+	}
+	constant, ok := right.(*memo.ConstExpr)
+	if !ok {
+		return 0, nil, 0, false
+	}
+	vec, ok := constant.Value.(*tree.DPGVector)
+	if !ok {
+		return 0, nil, 0, false
+	}
+	return variable.Col, vec.T, kind, true
+}
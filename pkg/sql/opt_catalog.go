@@ -495,6 +495,29 @@ func (oc *optCatalog) CheckRoleExists(ctx context.Context, role username.SQLUser
 	return oc.planner.CheckRoleExists(ctx, role)
 }
 
+// IsOwner is part of the cat.Catalog interface.
+func (oc *optCatalog) IsOwner(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error) {
+	user := oc.planner.User()
+	if role.IsPublicRole() || role == user {
+		return true, nil
+	}
+	memberOf, err := oc.planner.MemberOfWithAdminOption(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	_, ok := memberOf[role]
+	return ok, nil
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (oc *optCatalog) Optimizer() interface{} {
 	if oc.planner == nil {
@@ -768,6 +791,9 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []optCheckConstraint
 
+	// policies are the row-level security policies on the table.
+	policies []optPolicy
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -1077,8 +1103,24 @@ func newOptTable(
 			},
 		})
 	}
+	// The row-level security check immediately follows the enforced checks,
+	// since the execution engine identifies it by that ordinal.
+	if desc.IsRowLevelSecurityEnabled() {
+		ot.checkConstraints = append(ot.checkConstraints, optCheckConstraint{
+			constraint: "true",
+			isRLS:      true,
+			lookupColumnOrdinal: func(i int) (int, error) {
+				return 0, errors.AssertionFailedf("row-level security check has no columns")
+			},
+		})
+	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	ot.policies = make([]optPolicy, len(desc.GetPolicies()))
+	for i := range ot.policies {
+		ot.policies[i].policy = &desc.GetPolicies()[i]
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1389,6 +1431,26 @@ func (ot *optTable) IsHypothetical() bool {
 	return false
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.IsRowLevelSecurityEnabled()
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityForced() bool {
+	return ot.desc.IsRowLevelSecurityForced()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.policies)
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	return &ot.policies[i]
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1700,6 +1762,16 @@ func (oi *optIndex) GeoConfig() geopb.Config {
 	return oi.idx.IndexDesc().GeoConfig
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optIndex) VectorConfig() catpb.VectorIndexConfig {
+	return oi.idx.GetVectorConfig()
+}
+
+// InvertedColumnKind is part of the cat.Index interface.
+func (oi *optIndex) InvertedColumnKind() catpb.InvertedIndexColumnKind {
+	return oi.idx.InvertedColumnKind()
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optIndex) Version() descpb.IndexDescriptorVersion {
 	return oi.idx.GetVersion()
@@ -1746,6 +1818,7 @@ type optCheckConstraint struct {
 	constraint  string
 	validated   bool
 	columnCount int
+	isRLS       bool
 
 	// lookupColumnOrdinal returns the table column ordinal of the ith column in
 	// this constraint.
@@ -1778,6 +1851,54 @@ func (oc *optCheckConstraint) ColumnOrdinal(i int) int {
 	return ord
 }
 
+// IsRLSConstraint is part of the cat.CheckConstraint interface.
+func (oc *optCheckConstraint) IsRLSConstraint() bool {
+	return oc.isRLS
+}
+
+// optPolicy implements cat.Policy. See that interface for more information on
+// the fields.
+type optPolicy struct {
+	policy *descpb.PolicyDescriptor
+}
+
+var _ cat.Policy = &optPolicy{}
+
+// Name is part of the cat.Policy interface.
+func (op *optPolicy) Name() string {
+	return op.policy.Name
+}
+
+// IsPermissive is part of the cat.Policy interface.
+func (op *optPolicy) IsPermissive() bool {
+	return op.policy.Type == descpb.PolicyDescriptor_PERMISSIVE
+}
+
+// Command is part of the cat.Policy interface.
+func (op *optPolicy) Command() tree.PolicyCommand {
+	return tree.PolicyCommand(op.policy.Command)
+}
+
+// RoleCount is part of the cat.Policy interface.
+func (op *optPolicy) RoleCount() int {
+	return len(op.policy.RoleProtos)
+}
+
+// Role is part of the cat.Policy interface.
+func (op *optPolicy) Role(i int) username.SQLUsername {
+	return op.policy.RoleProtos[i].Decode()
+}
+
+// UsingExpr is part of the cat.Policy interface.
+func (op *optPolicy) UsingExpr() (string, bool) {
+	return op.policy.UsingExpr, op.policy.UsingExpr != ""
+}
+
+// WithCheckExpr is part of the cat.Policy interface.
+func (op *optPolicy) WithCheckExpr() (string, bool) {
+	return op.policy.WithCheckExpr, op.policy.WithCheckExpr != ""
+}
+
 type optTableStat struct {
 	stat           *stats.TableStatistic
 	columnOrdinals []int
@@ -2449,6 +2570,26 @@ func (ot *optVirtualTable) IsHypothetical() bool {
 	return false
 }
 
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // CollectTypes is part of the cat.DataSource interface.
 func (ot *optVirtualTable) CollectTypes(ord int) (descpb.IDs, error) {
 	col := ot.desc.AllColumns()[ord]
@@ -2628,6 +2769,16 @@ func (oi *optVirtualIndex) GeoConfig() geopb.Config {
 	return geopb.Config{}
 }
 
+// VectorConfig is part of the cat.Index interface.
+func (oi *optVirtualIndex) VectorConfig() catpb.VectorIndexConfig {
+	return catpb.VectorIndexConfig{}
+}
+
+// InvertedColumnKind is part of the cat.Index interface.
+func (oi *optVirtualIndex) InvertedColumnKind() catpb.InvertedIndexColumnKind {
+	return catpb.InvertedIndexColumnKind_DEFAULT
+}
+
 // Version is part of the cat.Index interface.
 func (oi *optVirtualIndex) Version() descpb.IndexDescriptorVersion {
 	return 0
@@ -1618,6 +1618,7 @@ func (ef *execFactory) ConstructUpsert(
 	arbiterIndexes cat.IndexOrdinals,
 	arbiterConstraints cat.UniqueOrdinals,
 	canaryCol exec.NodeColumnOrdinal,
+	mergeActionCol exec.NodeColumnOrdinal,
 	insertColOrdSet exec.TableColumnOrdinalSet,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	updateColOrdSet exec.TableColumnOrdinalSet,
@@ -1675,15 +1676,29 @@ func (ef *execFactory) ConstructUpsert(
 			checkOrds:  checks,
 			insertCols: ri.InsertCols,
 			tw: optTableUpserter{
-				ri:            ri,
-				canaryOrdinal: int(canaryCol),
-				fetchCols:     fetchCols,
-				updateCols:    updateCols,
-				ru:            ru,
+				ri:                 ri,
+				canaryOrdinal:      int(canaryCol),
+				mergeActionOrdinal: int(mergeActionCol),
+				fetchCols:          fetchCols,
+				updateCols:         updateCols,
+				ru:                 ru,
 			},
 		},
 	}
 
+	// A MERGE statement may also delete matched rows, so it needs a table
+	// deleter as well.
+	if mergeActionCol != -1 {
+		ups.run.tw.rd = row.MakeDeleter(
+			ef.planner.ExecCfg().Codec,
+			tabDesc,
+			fetchCols,
+			&ef.planner.ExecCfg().Settings.SV,
+			internal,
+			ef.planner.ExecCfg().GetRowMetrics(internal),
+		)
+	}
+
 	// If rows are not needed, no columns are returned.
 	if rowsNeeded {
 		returnCols := makeColList(table, returnColOrdSet)
@@ -299,6 +299,11 @@ func TestContextualHelp(t *testing.T) {
 		{`MOVE ??`, `MOVE`},
 		{`MOVE 1 ??`, `MOVE`},
 
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -306,6 +311,10 @@ func TestContextualHelp(t *testing.T) {
 		{`INSERT INTO blah VALUES (1) ??`, `VALUES`},
 		{`INSERT INTO blah TABLE foo ??`, `TABLE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN MATCHED THEN DELETE RETURNING ??`, `MERGE`},
+
 		{`UPSERT INTO ??`, `UPSERT`},
 		{`UPSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`UPSERT INTO blah VALUES (1) RETURNING ??`, `UPSERT`},
@@ -570,6 +579,15 @@ func TestContextualHelp(t *testing.T) {
 		{`BACKUP DATABASE ??`, `BACKUP`},
 		{`BACKUP foo TO 'bar' AS OF SYSTEM ??`, `BACKUP`},
 
+		{`COMPACT ??`, `COMPACT BACKUP`},
+		{`COMPACT BACKUP FROM LATEST IN 'bar' ??`, `COMPACT BACKUP`},
+
+		{`VERIFY ??`, `VERIFY BACKUP`},
+		{`VERIFY BACKUP FROM LATEST IN 'bar' ??`, `VERIFY BACKUP`},
+
+		{`DROP BACKUP ??`, `DROP BACKUP`},
+		{`DROP BACKUP 'foo' IN 'bar' ??`, `DROP BACKUP`},
+
 		{`RESTORE foo FROM 'bar' ??`, `RESTORE`},
 		{`RESTORE DATABASE ??`, `RESTORE`},
 
@@ -598,6 +616,10 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TRIGGER foo ??`, `CREATE TRIGGER`},
 		{`CREATE TRIGGER foo AFTER INSERT ON bar ??`, `CREATE TRIGGER`},
 		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`CREATE POLICY p ON t ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
 	}
 
 	// The following checks that the test definition above exercises all
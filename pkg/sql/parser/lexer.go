@@ -143,7 +143,7 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 		}
 		afterCommaOrParen := prevID == ',' || prevID == '('
 		afterCommaOrOPTIONS := prevID == ',' || prevID == OPTIONS
-		afterCommaOrParenThenINVERTED := prevID == INVERTED && (pprevID == ',' || pprevID == '(')
+		afterCommaOrParenThenINVERTEDOrVECTOR := (prevID == INVERTED || prevID == VECTOR) && (pprevID == ',' || pprevID == '(')
 		followedByParen := nextID == '('
 		followedByNonPunctThenParen := nextID > 255 /* non-punctuation */ && secondID == '('
 		if //
@@ -155,7 +155,8 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 			(afterCommaOrOPTIONS && followedByParen) ||
 			// CREATE ... (INVERTED INDEX (
 			// CREATE ... (x INT, y INT, INVERTED INDEX (
-			(afterCommaOrParenThenINVERTED && followedByParen) {
+			// CREATE ... (x INT, y INT, VECTOR INDEX (
+			(afterCommaOrParenThenINVERTEDOrVECTOR && followedByParen) {
 			lval.id = INDEX_BEFORE_PAREN
 			break
 		}
@@ -165,7 +166,8 @@ func (l *lexer) Lex(lval *sqlSymType) int {
 		(afterCommaOrParen && followedByNonPunctThenParen) ||
 			// CREATE ... (INVERTED INDEX abc (
 			// CREATE ... (x INT, y INT, INVERTED INDEX abc (
-			(afterCommaOrParenThenINVERTED && followedByNonPunctThenParen) {
+			// CREATE ... (x INT, y INT, VECTOR INDEX abc (
+			(afterCommaOrParenThenINVERTEDOrVECTOR && followedByNonPunctThenParen) {
 			lval.id = INDEX_BEFORE_NAME_THEN_PAREN
 			break
 		}
@@ -556,6 +556,12 @@ func (u *sqlSymUnion) privilegeList() privilege.List {
 func (u *sqlSymUnion) onConflict() *tree.OnConflict {
     return u.val.(*tree.OnConflict)
 }
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
 func (u *sqlSymUnion) orderBy() tree.OrderBy {
     return u.val.(tree.OrderBy)
 }
@@ -712,6 +718,9 @@ func (u *sqlSymUnion) kvOptions() []tree.KVOption {
 func (u *sqlSymUnion) backupOptions() *tree.BackupOptions {
   return u.val.(*tree.BackupOptions)
 }
+func (u *sqlSymUnion) verifyBackupOptions() *tree.VerifyBackupOptions {
+  return u.val.(*tree.VerifyBackupOptions)
+}
 func (u *sqlSymUnion) copyOptions() *tree.CopyOptions {
   return u.val.(*tree.CopyOptions)
 }
@@ -766,6 +775,9 @@ func (u *sqlSymUnion) listOfStringOrPlaceholderOptList() []tree.StringOrPlacehol
 func (u *sqlSymUnion) fullBackupClause() *tree.FullBackupClause {
     return u.val.(*tree.FullBackupClause)
 }
+func (u *sqlSymUnion) backupRetentionClause() *tree.BackupRetentionClause {
+    return u.val.(*tree.BackupRetentionClause)
+}
 func (u *sqlSymUnion) scheduleLabelSpec() *tree.LabelSpec {
     return u.val.(*tree.LabelSpec)
 }
@@ -914,6 +926,15 @@ func (u *sqlSymUnion) triggerTransitions() []*tree.TriggerTransition {
 func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
   return u.val.(tree.TriggerForEach)
 }
+func (u *sqlSymUnion) policyType() tree.PolicyType {
+  return u.val.(tree.PolicyType)
+}
+func (u *sqlSymUnion) policyCommand() tree.PolicyCommand {
+  return u.val.(tree.PolicyCommand)
+}
+func (u *sqlSymUnion) rowLevelSecurityMode() tree.RowLevelSecurityMode {
+  return u.val.(tree.RowLevelSecurityMode)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -939,7 +960,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 
 %token <str> BACKUP BACKUPS BACKWARD BATCH BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
-%token <str> BOOLEAN BOTH BOX2D BUNDLE BY
+%token <str> BOOLEAN BOTH BOX2D BUNDLE BY BYPASSRLS
 
 %token <str> CACHE CALL CALLED CANCEL CANCELQUERY CAPABILITIES CAPABILITY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CHECK_FILES CLOSE
@@ -952,17 +973,17 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEC DEBUG_DUMP_METADATA_SST DECIMAL DEFAULT DEFAULTS DEFINER
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
-%token <str> DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DEALLOCATE DECLARE DEDUPLICATE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
+%token <str> DISABLE DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> EACH ELSE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENABLE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
 %token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTERNAL EXTRACT EXTRACT_DURATION EXTREMES
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
-%token <str> FILES FILTER
+%token <str> FILES FILTER FINGERPRINT
 %token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FORCE_INVERTED_INDEX
 %token <str> FORCE_NOT_NULL FORCE_NULL FORCE_QUOTE FORCE_ZIGZAG
 %token <str> FOREIGN FORMAT FORWARD FREEZE FROM FULL FUNCTION FUNCTIONS
@@ -989,25 +1010,25 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> LABEL LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEAKPROOF LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGICAL LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGICAL LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MODE MONTH MOVE
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MODE MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
 %token <str> NAN NAME NAMES NATURAL NEG_INNER_PRODUCT NEVER NEW NEW_DB_NAME NEW_KMS NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
-%token <str> NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NODE NOLOGIN NOMODIFYCLUSTERSETTING NOREPLICATION
+%token <str> NOBYPASSRLS NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NODE NOLOGIN NOMODIFYCLUSTERSETTING NOREPLICATION
 %token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT
-%token <str> NOTHING NOTHING_AFTER_RETURNING
+%token <str> NOTHING NOTHING_AFTER_RETURNING NOTIFY
 %token <str> NOTNULL
 %token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOWAIT NULL NULLIF NULLS NUMERIC
 
-%token <str> OF OFF OFFSET OID OIDS OIDVECTOR OLD OLD_KMS ON ONLY OPT OPTION OPTIONS OR
+%token <str> OBJECT_LOCK_RETENTION OF OFF OFFSET OID OIDS OIDVECTOR OLD OLD_KMS ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
 %token <str> PARALLEL PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PER PHYSICAL PLACEMENT PLACING
-%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
+%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLICY POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PROCEDURE PROCEDURES PUBLIC PUBLICATION
 
@@ -1016,8 +1037,8 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFERENCING REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
-%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
+%token <str> RELEASE RESCAN RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
+%token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS ROW_FILTER RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
 %token <str> SEARCH SECOND SECONDARY SECURITY SELECT SEQUENCE SEQUENCES
@@ -1037,7 +1058,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSAFE_RESTORE_INCOMPATIBLE_VERSION UNSPLIT
 %token <str> UPDATE UPDATES_CLUSTER_MONITORING_METRICS UPSERT UNSET UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VECTOR VERIFY_BACKUP_TABLE_DATA VIEW VARIABLES VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VECTOR VERIFY VERIFY_BACKUP_TABLE_DATA VIEW VARIABLES VARYING VIEWACTIVITY VIEWACTIVITYREDACTED VIEWDEBUG
 %token <str> VIEWCLUSTERMETADATA VIEWCLUSTERSETTING VIRTUAL VISIBLE INVISIBLE VISIBILITY VOLATILE VOTERS
 %token <str> VIRTUAL_CLUSTER_NAME VIRTUAL_CLUSTER
 
@@ -1191,6 +1212,8 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> alter_proc_owner_stmt
 
 %type <tree.Statement> backup_stmt
+%type <tree.Statement> compact_backup_stmt
+%type <tree.Statement> verify_backup_stmt
 %type <tree.Statement> begin_stmt
 
 %type <tree.Statement> call_stmt
@@ -1233,6 +1256,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> create_sequence_stmt
 %type <tree.Statement> create_func_stmt
 %type <tree.Statement> create_proc_stmt
+%type <tree.Statement> create_policy_stmt
 %type <tree.Statement> create_trigger_stmt
 
 %type <*tree.LikeTenantSpec> opt_like_virtual_cluster
@@ -1249,6 +1273,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> discard_stmt
 
 %type <tree.Statement> drop_stmt
+%type <tree.Statement> drop_backup_stmt
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
 %type <tree.Statement> drop_external_connection_stmt
@@ -1261,6 +1286,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> drop_sequence_stmt
 %type <tree.Statement> drop_func_stmt
 %type <tree.Statement> drop_proc_stmt
+%type <tree.Statement> drop_policy_stmt
 %type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_virtual_cluster_stmt
 %type <bool>           opt_immediate
@@ -1277,6 +1303,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> deallocate_stmt
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
+%type <tree.Statement> merge_stmt
 %type <tree.Statement> import_stmt
 %type <tree.Statement> pause_stmt pause_jobs_stmt pause_schedules_stmt pause_all_jobs_stmt
 %type <*tree.Select>   for_schedules_clause
@@ -1365,6 +1392,8 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 
 %type <tree.Statement> transaction_stmt legacy_transaction_stmt legacy_begin_stmt legacy_end_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> unlisten_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
@@ -1387,6 +1416,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.KVOption> kv_option
 %type <[]tree.KVOption> kv_option_list opt_with_options var_set_list opt_with_schedule_options
 %type <*tree.BackupOptions> opt_with_backup_options backup_options backup_options_list
+%type <*tree.VerifyBackupOptions> opt_with_verify_backup_options verify_backup_options verify_backup_options_list
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
 %type <*tree.TenantReplicationOptions> opt_with_replication_options replication_options replication_options_list
 %type <tree.ShowBackupDetails> show_backup_details
@@ -1551,7 +1581,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <*tree.TenantSpec> virtual_cluster_spec virtual_cluster_spec_opt_all
 
 %type <bool> opt_unique opt_concurrently opt_cluster opt_without_index
-%type <bool> opt_index_access_method
+%type <str> opt_index_access_method
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
 %type <tree.Expr> select_fetch_first_value
@@ -1562,6 +1592,9 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.ColumnDefList> opt_col_def_list col_def_list opt_col_def_list_no_types col_def_list_no_types
 %type <tree.ColumnDef> col_def
 %type <*tree.OnConflict> on_conflict
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause merge_when_matched_action merge_when_not_matched_action
+%type <tree.Expr> opt_merge_when_condition
 
 %type <tree.Statement> begin_transaction
 %type <tree.TransactionModes> transaction_mode_list transaction_mode
@@ -1711,6 +1744,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <*tree.LabelSpec> schedule_label_spec
 %type <tree.Expr>  cron_expr sconst_or_placeholder
 %type <*tree.FullBackupClause> opt_full_backup_clause
+%type <*tree.BackupRetentionClause> opt_backup_retention_clause
 %type <tree.ScheduleState> schedule_state
 %type <tree.ScheduledJobExecutorType> opt_schedule_executor_type
 
@@ -1746,6 +1780,11 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <[]*tree.TriggerTransition> trigger_transition_list opt_trigger_transition_list
 %type <bool> transition_is_new transition_is_row
 %type <tree.TriggerForEach> trigger_for_each trigger_for_type
+%type <tree.PolicyType> opt_policy_type
+%type <tree.PolicyCommand> opt_policy_command
+%type <tree.RoleSpecList> opt_policy_roles
+%type <tree.Expr> opt_policy_using opt_policy_with_check
+%type <tree.RowLevelSecurityMode> row_level_security_mode
 %type <tree.Expr> trigger_when
 %type <str> trigger_func_arg opt_as function_or_procedure
 %type <[]string> trigger_func_args
@@ -1863,7 +1902,9 @@ stmt_without_legacy_transaction:
 | fetch_cursor_stmt          // EXTEND WITH HELP: FETCH
 | move_cursor_stmt           // EXTEND WITH HELP: MOVE
 | reindex_stmt
-| unlisten_stmt
+| listen_stmt                // EXTEND WITH HELP: LISTEN
+| notify_stmt                // EXTEND WITH HELP: NOTIFY
+| unlisten_stmt              // EXTEND WITH HELP: UNLISTEN
 | show_commit_timestamp_stmt // EXTEND WITH HELP: SHOW COMMIT TIMESTAMP
 
 // %Help: ALTER
@@ -1929,6 +1970,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... { ENABLE | DISABLE | FORCE | NO FORCE } ROW LEVEL SECURITY
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2999,6 +3041,17 @@ alter_table_cmd:
       Params: $3.storageParamKeys(),
     }
   }
+  // ALTER TABLE <name> {ENABLE | DISABLE | FORCE | NO FORCE} ROW LEVEL SECURITY
+| row_level_security_mode ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Mode: $1.rowLevelSecurityMode()}
+  }
+
+row_level_security_mode:
+  ENABLE   { $$.val = tree.RowLevelSecurityEnable }
+| DISABLE  { $$.val = tree.RowLevelSecurityDisable }
+| FORCE    { $$.val = tree.RowLevelSecurityForce }
+| NO FORCE { $$.val = tree.RowLevelSecurityNoForce }
 
 audit_mode:
   READ WRITE { $$.val = tree.AuditModeReadWrite }
@@ -3350,6 +3403,8 @@ opt_clear_data:
 //    detached: execute backup job asynchronously, without waiting for its completion
 //    incremental_location: specify a different path to store the incremental backup
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
+//    deduplicate: store data files by content in the collection and skip uploading files that already exist there
+//    object_lock_retention='[interval]': lock the backup files against deletion and overwrites for the interval
 //
 // %SeeAlso: RESTORE, WEBDOCS/backup.html
 backup_stmt:
@@ -3398,6 +3453,153 @@ backup_stmt:
   }
 | BACKUP error // SHOW HELP: BACKUP
 
+// %Help: COMPACT BACKUP - merge a backup chain into a new full backup
+// %Category: CCL
+// %Text:
+// COMPACT BACKUP FROM <subdir> IN <collection...>
+//        [ AS OF SYSTEM TIME <expr> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Merges the full backup in <subdir> of the collection, and the incremental
+// backups appended to it up to the AS OF SYSTEM TIME, into a new full backup in
+// the collection. <subdir> may be LATEST to compact the most recent chain.
+//
+// Options:
+//    encryption_passphrase="secret": decrypt and encrypt the backups
+//    kms="[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : decrypt and encrypt the backups using KMS
+//    detached: execute compaction job asynchronously, without waiting for its completion
+//    incremental_location: specify the path the incremental backups are stored in
+//
+// %SeeAlso: BACKUP, RESTORE
+compact_backup_stmt:
+  COMPACT BACKUP FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_with_backup_options
+  {
+    $$.val = &tree.CompactBackup{
+      Subdir: $4.expr(),
+      In: $6.stringOrPlaceholderOptList(),
+      AsOf: $7.asOfClause(),
+      Options: *$8.backupOptions(),
+    }
+  }
+| COMPACT error // SHOW HELP: COMPACT BACKUP
+
+// %Help: DROP BACKUP - remove a backup chain from a collection
+// %Category: CCL
+// %Text:
+// DROP BACKUP <subdir> IN <collection...>
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Removes the full backup in <subdir> of the collection, and all incremental
+// backups that were appended to it, from external storage. The latest backup
+// chain of the collection and chains that are still being written to cannot be
+// dropped.
+//
+// Options:
+//    incremental_location: specify the path the incremental backups are stored in
+//
+// %SeeAlso: BACKUP, SHOW BACKUP
+drop_backup_stmt:
+  DROP BACKUP string_or_placeholder IN string_or_placeholder_opt_list opt_with_backup_options
+  {
+    $$.val = &tree.DropBackup{
+      Subdir: $3.expr(),
+      In: $5.stringOrPlaceholderOptList(),
+      Options: *$6.backupOptions(),
+    }
+  }
+| DROP BACKUP error // SHOW HELP: DROP BACKUP
+
+// %Help: VERIFY BACKUP - check that a backup chain can be restored
+// %Category: CCL
+// %Text:
+// VERIFY BACKUP FROM <subdir> IN <collection...>
+//        [ AS OF SYSTEM TIME <expr> ]
+//        [ WITH <option> [= <value>] [, ...] ]
+//
+// Reads every file of the full backup in <subdir> of the collection, and of the
+// incremental backups appended to it up to the AS OF SYSTEM TIME, and checks
+// that it matches the checksum recorded in the backup manifest and that all its
+// keys fall within the spans recorded for it. <subdir> may be LATEST to verify
+// the most recent chain.
+//
+// Options:
+//    encryption_passphrase="secret": decrypt the backups
+//    kms="[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : decrypt the backups using KMS
+//    detached: execute verification job asynchronously, without waiting for its completion
+//    incremental_location: specify the path the incremental backups are stored in
+//    fingerprint: also return the fingerprint of every table in the backup
+//
+// %SeeAlso: BACKUP, RESTORE, SHOW BACKUP
+verify_backup_stmt:
+  VERIFY BACKUP FROM string_or_placeholder IN string_or_placeholder_opt_list opt_as_of_clause opt_with_verify_backup_options
+  {
+    $$.val = &tree.VerifyBackup{
+      Subdir: $4.expr(),
+      In: $6.stringOrPlaceholderOptList(),
+      AsOf: $7.asOfClause(),
+      Options: *$8.verifyBackupOptions(),
+    }
+  }
+| VERIFY error // SHOW HELP: VERIFY BACKUP
+
+opt_with_verify_backup_options:
+  WITH verify_backup_options_list
+  {
+    $$.val = $2.verifyBackupOptions()
+  }
+| WITH OPTIONS '(' verify_backup_options_list ')'
+  {
+    $$.val = $4.verifyBackupOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = &tree.VerifyBackupOptions{}
+  }
+
+verify_backup_options_list:
+  // Require at least one option
+  verify_backup_options
+  {
+    $$.val = $1.verifyBackupOptions()
+  }
+| verify_backup_options_list ',' verify_backup_options
+  {
+    if err := $1.verifyBackupOptions().CombineWith($3.verifyBackupOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+  }
+
+// List of valid verify backup options.
+verify_backup_options:
+  ENCRYPTION_PASSPHRASE '=' string_or_placeholder
+  {
+    $$.val = &tree.VerifyBackupOptions{EncryptionPassphrase: $3.expr()}
+  }
+| KMS '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.VerifyBackupOptions{DecryptionKMSURI: $3.stringOrPlaceholderOptList()}
+  }
+| INCREMENTAL_LOCATION '=' string_or_placeholder_opt_list
+  {
+    $$.val = &tree.VerifyBackupOptions{IncrementalStorage: $3.stringOrPlaceholderOptList()}
+  }
+| DETACHED
+  {
+    $$.val = &tree.VerifyBackupOptions{Detached: tree.MakeDBool(true)}
+  }
+| DETACHED '=' TRUE
+  {
+    $$.val = &tree.VerifyBackupOptions{Detached: tree.MakeDBool(true)}
+  }
+| DETACHED '=' FALSE
+  {
+    $$.val = &tree.VerifyBackupOptions{Detached: tree.MakeDBool(false)}
+  }
+| FINGERPRINT
+  {
+    $$.val = &tree.VerifyBackupOptions{Fingerprint: true}
+  }
+
 opt_backup_targets:
   /* EMPTY -- full cluster */
   {
@@ -3492,6 +3694,18 @@ backup_options:
   {
     $$.val = &tree.BackupOptions{UpdatesClusterMonitoringMetrics: $3.expr()}
   }
+| DEDUPLICATE
+  {
+    $$.val = &tree.BackupOptions{Deduplicate: tree.MakeDBool(true)}
+  }
+| DEDUPLICATE '=' a_expr
+  {
+    $$.val = &tree.BackupOptions{Deduplicate: $3.expr()}
+  }
+| OBJECT_LOCK_RETENTION '=' string_or_placeholder
+  {
+    $$.val = &tree.BackupOptions{ObjectLockRetention: $3.expr()}
+  }
 
 include_all_clusters:
   INCLUDE_ALL_SECONDARY_TENANTS { /* SKIP DOC */ }
@@ -3505,6 +3719,7 @@ include_all_clusters:
 // FOR BACKUP [<targets>] INTO <location...>
 // [WITH <backup_option>[=<value>] [, ...]]
 // RECURRING [crontab|NEVER] [FULL BACKUP <crontab|ALWAYS>]
+// [RETENTION <n> FULL BACKUPS | RETENTION <interval>]
 // [WITH EXPERIMENTAL SCHEDULE OPTIONS <schedule_option>[= <value>] [, ...] ]
 //
 // All backups run in UTC timezone.
@@ -3546,6 +3761,13 @@ include_all_clusters:
 //      * RECURRING <= 1 day:  we default to FULL BACKUP '@weekly';
 //      * Otherwise: we default to FULL BACKUP ALWAYS.
 //
+// RETENTION <n> FULL BACKUPS | RETENTION <interval>:
+//   The optional RETENTION clause specifies which backups the schedule keeps in
+//   the collection. Whenever the schedule completes a full backup, older backup
+//   chains are removed if they are not among the <n> most recent full backups,
+//   or, respectively, if they are not needed to restore to any time in the last
+//   <interval>. The latest backup chain is never removed.
+//
 //  SCHEDULE OPTIONS:
 //   The schedule can be modified by specifying the following options (which are considered
 //   to be experimental at this time):
@@ -3573,16 +3795,18 @@ include_all_clusters:
 create_schedule_for_backup_stmt:
  CREATE SCHEDULE /*$3=*/schedule_label_spec FOR BACKUP /*$6=*/opt_backup_targets INTO
   /*$8=*/string_or_placeholder_opt_list /*$9=*/opt_with_backup_options
-  /*$10=*/cron_expr /*$11=*/opt_full_backup_clause /*$12=*/opt_with_schedule_options
+  /*$10=*/cron_expr /*$11=*/opt_full_backup_clause /*$12=*/opt_backup_retention_clause
+  /*$13=*/opt_with_schedule_options
   {
   $$.val = &tree.ScheduledBackup{
         ScheduleLabelSpec:    *($3.scheduleLabelSpec()),
         Recurrence:           $10.expr(),
         FullBackup:           $11.fullBackupClause(),
+        Retention:            $12.backupRetentionClause(),
         To:                   $8.stringOrPlaceholderOptList(),
         Targets:              $6.backupTargetListPtr(),
         BackupOptions:        *($9.backupOptions()),
-        ScheduleOptions:      $12.kvOptions(),
+        ScheduleOptions:      $13.kvOptions(),
       }
   }
  | CREATE SCHEDULE schedule_label_spec FOR BACKUP error // SHOW HELP: CREATE SCHEDULE FOR BACKUP
@@ -3734,6 +3958,20 @@ opt_full_backup_clause:
     $$.val = (*tree.FullBackupClause)(nil)
   }
 
+opt_backup_retention_clause:
+  RETENTION iconst64 FULL BACKUPS
+  {
+    $$.val = &tree.BackupRetentionClause{FullBackups: $2.int64()}
+  }
+| RETENTION sconst_or_placeholder
+  {
+    $$.val = &tree.BackupRetentionClause{Duration: $2.expr()}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.BackupRetentionClause)(nil)
+  }
+
 opt_with_schedule_options:
   WITH SCHEDULE OPTIONS kv_option_list
   {
@@ -3792,6 +4030,10 @@ drop_external_connection_stmt:
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 // or
+// RESTORE TABLE <tablename> AS <newtablename> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         [ WITH <option> [= <value>] [, ...] ]
+// or
 // RESTORE SYSTEM USERS FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
@@ -3816,6 +4058,7 @@ drop_external_connection_stmt:
 //    skip_localities_check: ignore difference of zone configuration between restore cluster and backup cluster
 //    new_db_name: renames the restored database. only applies to database restores
 //    include_all_virtual_clusters: enable backups of all virtual clusters during a cluster backup
+//    row_filter: only restore the rows of the restored table that satisfy the given predicate
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
   RESTORE FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
@@ -3856,6 +4099,35 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
+| RESTORE TABLE table_pattern AS table_name FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+  {
+    pattern := $3.unresolvedName()
+    if pattern.Star {
+      return setErr(sqllex, errors.New("RESTORE TABLE ... AS requires a single table name"))
+    }
+    $$.val = &tree.Restore{
+      Targets: tree.BackupTargetList{Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{pattern}}},
+      AsTable: $5.unresolvedObjectName(),
+      From: $7.listOfStringOrPlaceholderOptList(),
+      AsOf: $8.asOfClause(),
+      Options: *($9.restoreOptions()),
+    }
+  }
+| RESTORE TABLE table_pattern AS table_name FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+  {
+    pattern := $3.unresolvedName()
+    if pattern.Star {
+      return setErr(sqllex, errors.New("RESTORE TABLE ... AS requires a single table name"))
+    }
+    $$.val = &tree.Restore{
+      Targets: tree.BackupTargetList{Tables: tree.TableAttrs{TablePatterns: tree.TablePatterns{pattern}}},
+      AsTable: $5.unresolvedObjectName(),
+      Subdir: $7.expr(),
+      From: $9.listOfStringOrPlaceholderOptList(),
+      AsOf: $10.asOfClause(),
+      Options: *($11.restoreOptions()),
+    }
+  }
 | RESTORE SYSTEM USERS FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
@@ -3939,6 +4211,10 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{IntoDB: $3.expr()}
   }
+| ROW_FILTER '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{RowFilter: $3.expr()}
+  }
 | SKIP_MISSING_FOREIGN_KEYS
   {
     $$.val = &tree.RestoreOptions{SkipMissingFKs: true}
@@ -5597,6 +5873,131 @@ drop_trigger_stmt:
   }
 | DROP TRIGGER error // SHOW HELP: DROP TRIGGER
 
+// %Help: CREATE POLICY - define a new row-level security policy for a table
+// %Category: DDL
+// %Text:
+// CREATE POLICY name ON table_name
+//  [ AS { PERMISSIVE | RESTRICTIVE } ]
+//  [ FOR { ALL | SELECT | INSERT | UPDATE | DELETE } ]
+//  [ TO role_name [, ...] ]
+//  [ USING ( using_expression ) ]
+//  [ WITH CHECK ( check_expression ) ]
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON table_name opt_policy_type opt_policy_command opt_policy_roles
+  opt_policy_using opt_policy_with_check
+  {
+    $$.val = &tree.CreatePolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      Type: $6.policyType(),
+      Command: $7.policyCommand(),
+      Roles: $8.roleSpecList(),
+      Using: $9.expr(),
+      WithCheck: $10.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_type:
+  AS name
+  {
+    switch strings.ToLower($2) {
+    case "permissive":
+      $$.val = tree.PolicyTypePermissive
+    case "restrictive":
+      $$.val = tree.PolicyTypeRestrictive
+    default:
+      return setErr(sqllex, pgerror.Newf(pgcode.Syntax,
+        "unrecognized row security option %q", $2))
+    }
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.PolicyTypePermissive
+  }
+
+opt_policy_command:
+  FOR ALL
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+| FOR SELECT
+  {
+    $$.val = tree.PolicyCommandSelect
+  }
+| FOR INSERT
+  {
+    $$.val = tree.PolicyCommandInsert
+  }
+| FOR UPDATE
+  {
+    $$.val = tree.PolicyCommandUpdate
+  }
+| FOR DELETE
+  {
+    $$.val = tree.PolicyCommandDelete
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+
+opt_policy_roles:
+  TO role_spec_list
+  {
+    $$.val = $2.roleSpecList()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.RoleSpecList(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+// %Help: DROP POLICY - remove a row-level security policy from a table
+// %Category: DDL
+// %Text:
+// DROP POLICY [ IF EXISTS ] name ON table_name [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP POLICY IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      IfExists: true,
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName(),
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP POLICY error // SHOW HELP: DROP POLICY
+
 create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
 | CREATE AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "create aggregate") }
@@ -5656,6 +6057,7 @@ create_ddl_stmt:
 | create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
 | create_proc_stmt     // EXTEND WITH HELP: CREATE PROCEDURE
 | create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -6025,6 +6427,7 @@ discard_stmt:
 // DROP USER, DROP ROLE, DROP TYPE
 drop_stmt:
   drop_ddl_stmt                 // help texts in sub-rule
+| drop_backup_stmt              // EXTEND WITH HELP: DROP BACKUP
 | drop_role_stmt                // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
@@ -6043,6 +6446,7 @@ drop_ddl_stmt:
 | drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
 | drop_proc_stmt     // EXTEND WITH HELP: DROP FUNCTION
 | drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -6391,6 +6795,8 @@ explainable_stmt:
 preparable_stmt:
   alter_stmt     // help texts in sub-rule
 | backup_stmt    // EXTEND WITH HELP: BACKUP
+| compact_backup_stmt // EXTEND WITH HELP: COMPACT BACKUP
+| verify_backup_stmt // EXTEND WITH HELP: VERIFY BACKUP
 | cancel_stmt    // help texts in sub-rule
 | create_stmt    // help texts in sub-rule
 | delete_stmt    // EXTEND WITH HELP: DELETE
@@ -6398,6 +6804,7 @@ preparable_stmt:
 | explain_stmt   // EXTEND WITH HELP: EXPLAIN
 | import_stmt    // EXTEND WITH HELP: IMPORT
 | insert_stmt    // EXTEND WITH HELP: INSERT
+| merge_stmt     // EXTEND WITH HELP: MERGE
 | pause_stmt     // help texts in sub-rule
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
@@ -6451,7 +6858,7 @@ explain_option_list:
 // %Help: ALTER CHANGEFEED - alter an existing changefeed
 // %Category: CCL
 // %Text:
-// ALTER CHANGEFEED <job_id> {{ADD|DROP <targets...>} | SET <options...>}...
+// ALTER CHANGEFEED <job_id> {{ADD|DROP <targets...>} | SET <options...> | RESCAN [TABLE <target>] [WHERE <expr>]}...
 alter_changefeed_stmt:
   ALTER CHANGEFEED a_expr alter_changefeed_cmds
   {
@@ -6500,6 +6907,23 @@ alter_changefeed_cmd:
       Options: $2.nameList(),
     }
   }
+  // ALTER CHANGEFEED <job_id> RESCAN [WHERE ...]
+| RESCAN opt_where_clause
+  {
+    $$.val = &tree.AlterChangefeedRescan{
+      Where: tree.NewWhere(tree.AstWhere, $2.expr()),
+    }
+  }
+  // ALTER CHANGEFEED <job_id> RESCAN TABLE <target> [WHERE ...]
+| RESCAN TABLE table_name opt_where_clause
+  {
+    $$.val = &tree.AlterChangefeedRescan{
+      Target: &tree.ChangefeedTarget{
+        TableName: $3.unresolvedObjectName().ToUnresolvedName(),
+      },
+      Where: tree.NewWhere(tree.AstWhere, $4.expr()),
+    }
+  }
 
 // %Help: ALTER BACKUP - alter an existing backup's encryption keys
 // %Category: CCL
@@ -10790,6 +11214,32 @@ index_def:
       Invisibility:     $10.indexInvisibility(),
     }
   }
+| VECTOR INDEX_BEFORE_PAREN '(' index_params ')' opt_partition_by_index opt_with_storage_parameter_list opt_where_clause opt_index_visible
+  {
+    $$.val = &tree.IndexTableDef{
+      Name:             "",
+      Columns:          $4.idxElems(),
+      Inverted:         true,
+      Vector:           true,
+      PartitionByIndex: $6.partitionByIndex(),
+      StorageParams:    $7.storageParams(),
+      Predicate:        $8.expr(),
+      Invisibility:     $9.indexInvisibility(),
+    }
+  }
+| VECTOR INDEX_BEFORE_NAME_THEN_PAREN name '(' index_params ')' opt_partition_by_index opt_with_storage_parameter_list opt_where_clause opt_index_visible
+  {
+    $$.val = &tree.IndexTableDef{
+      Name:             tree.Name($3),
+      Columns:          $5.idxElems(),
+      Inverted:         true,
+      Vector:           true,
+      PartitionByIndex: $7.partitionByIndex(),
+      StorageParams:    $8.storageParams(),
+      Predicate:        $9.expr(),
+      Invisibility:     $10.indexInvisibility(),
+    }
+  }
 
 family_def:
   FAMILY opt_family_name '(' name_list ')'
@@ -11540,6 +11990,14 @@ role_option:
   {
     $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
   }
+| BYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOBYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
 
 role_options:
   role_option
@@ -11689,7 +12147,7 @@ composite_type_list:
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
-// CREATE [UNIQUE | INVERTED] INDEX [CONCURRENTLY] [IF NOT EXISTS] [<idxname>]
+// CREATE [UNIQUE | INVERTED | VECTOR] INDEX [CONCURRENTLY] [IF NOT EXISTS] [<idxname>]
 //        ON <tablename> ( <colname> [ASC | DESC] [, ...] )
 //        [USING HASH] [STORING ( <colnames...> )]
 //        [PARTITION BY <partition params>]
@@ -11711,7 +12169,8 @@ create_index_stmt:
       PartitionByIndex: $14.partitionByIndex(),
       StorageParams:    $15.storageParams(),
       Predicate:        $16.expr(),
-      Inverted:         $8.bool(),
+      Inverted:         $8 != "btree",
+      Vector:           $8 == "vector",
       Concurrently:     $4.bool(),
       Invisibility:     $17.indexInvisibility(),
     }
@@ -11728,7 +12187,8 @@ create_index_stmt:
       Sharded:          $15.shardedIndexDef(),
       Storing:          $16.nameList(),
       PartitionByIndex: $17.partitionByIndex(),
-      Inverted:         $11.bool(),
+      Inverted:         $11 != "btree",
+      Vector:           $11 == "vector",
       StorageParams:    $18.storageParams(),
       Predicate:        $19.expr(),
       Concurrently:     $4.bool(),
@@ -11770,6 +12230,43 @@ create_index_stmt:
       Invisibility:     $19.indexInvisibility(),
     }
   }
+| CREATE opt_unique VECTOR INDEX opt_concurrently opt_index_name ON table_name '(' index_params ')' opt_storing opt_partition_by_index opt_with_storage_parameter_list opt_where_clause opt_index_visible
+  {
+    table := $8.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateIndex{
+      Name:             tree.Name($6),
+      Table:            table,
+      Unique:           $2.bool(),
+      Inverted:         true,
+      Vector:           true,
+      Columns:          $10.idxElems(),
+      Storing:          $12.nameList(),
+      PartitionByIndex: $13.partitionByIndex(),
+      StorageParams:    $14.storageParams(),
+      Predicate:        $15.expr(),
+      Concurrently:     $5.bool(),
+      Invisibility:     $16.indexInvisibility(),
+    }
+  }
+| CREATE opt_unique VECTOR INDEX opt_concurrently IF NOT EXISTS index_name ON table_name '(' index_params ')' opt_storing opt_partition_by_index opt_with_storage_parameter_list opt_where_clause opt_index_visible
+  {
+    table := $11.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateIndex{
+      Name:             tree.Name($9),
+      Table:            table,
+      Unique:           $2.bool(),
+      Inverted:         true,
+      Vector:           true,
+      IfNotExists:      true,
+      Columns:          $13.idxElems(),
+      Storing:          $15.nameList(),
+      PartitionByIndex: $16.partitionByIndex(),
+      StorageParams:    $17.storageParams(),
+      Predicate:        $18.expr(),
+      Concurrently:     $5.bool(),
+      Invisibility:     $19.indexInvisibility(),
+    }
+  }
 | CREATE opt_unique INDEX error // SHOW HELP: CREATE INDEX
 
 opt_index_access_method:
@@ -11778,9 +12275,11 @@ opt_index_access_method:
     /* FORCE DOC */
     switch $2 {
       case "gin", "gist":
-        $$.val = true
+        $$ = "inverted"
       case "btree":
-        $$.val = false
+        $$ = "btree"
+      case "hnsw", "ivfflat":
+        $$ = "vector"
       case "hash", "spgist", "brin":
         return unimplemented(sqllex, "index using " + $2)
       default:
@@ -11790,7 +12289,7 @@ opt_index_access_method:
   }
 | /* EMPTY */
   {
-    $$.val = false
+    $$ = "btree"
   }
 
 opt_concurrently:
@@ -13045,6 +13544,98 @@ returning_clause:
     $$.val = tree.AbsentReturningClause
   }
 
+// %Help: MERGE - conditionally insert, update or delete rows of a table
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <expr>
+//        WHEN MATCHED [AND <expr>] THEN {UPDATE SET ... | DELETE | DO NOTHING}
+//        WHEN NOT MATCHED [AND <expr>] THEN
+//          {INSERT [( <colnames...> )] {VALUES ( <exprs...> ) | DEFAULT VALUES} | DO NOTHING}
+//        [...]
+//        [RETURNING <exprs...>]
+// %SeeAlso: INSERT, UPSERT, UPDATE, DELETE
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list returning_clause
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+      Returning: $10.retClause(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_when_condition THEN merge_when_matched_action
+  {
+    when := $5.mergeWhen()
+    when.Matched = true
+    when.Cond = $3.expr()
+    $$.val = when
+  }
+| WHEN NOT MATCHED opt_merge_when_condition THEN merge_when_not_matched_action
+  {
+    when := $6.mergeWhen()
+    when.Cond = $4.expr()
+    $$.val = when
+  }
+
+opt_merge_when_condition:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+merge_when_matched_action:
+  UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionUpdate, Exprs: $3.updateExprs()}
+  }
+| DELETE
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDelete}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
+merge_when_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Values: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Columns: $3.nameList(), Values: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
 // %Help: UPDATE - update rows of a table
 // %Category: DML
 // %Text:
@@ -13790,11 +14381,22 @@ group_by_list:
 // Note the '(' is required as CUBE and ROLLUP rely on setting precedence
 // of CUBE and ROLLUP below that of '(', so that they shift in these rules
 // rather than reducing the conflicting unreserved_keyword rule.
+//
+// The empty grouping set "()" is parsed by a_expr as an empty tuple.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -14373,7 +14975,36 @@ relation_expr_list:
     $$.val = append($1.tableNames(), name)
   }
 
-// UNLISTEN
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN type_name
+  {
+    $$.val = &tree.Listen{ChannelName: $2.unresolvedObjectName()}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: NOTIFY - send a notification on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload> ]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY type_name
+  {
+    $$.val = &tree.Notify{ChannelName: $2.unresolvedObjectName()}
+  }
+| NOTIFY type_name ',' SCONST
+  {
+    $$.val = &tree.Notify{ChannelName: $2.unresolvedObjectName(), Payload: $4}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
+// %Help: UNLISTEN - stop listening for notifications on a channel
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
 unlisten_stmt:
    UNLISTEN type_name
     {
@@ -14383,6 +15014,7 @@ unlisten_stmt:
       {
           $$.val = &tree.Unlisten{ ChannelName:nil, Star: true}
       }
+| UNLISTEN error // SHOW HELP: UNLISTEN
 
 
 // Given "UPDATE foo set set ...", we have to decide without looking any
@@ -15848,7 +16480,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_application_name '(' ')'
@@ -17471,6 +18106,7 @@ unreserved_keyword:
 | BUCKET_COUNT
 | BUNDLE
 | BY
+| BYPASSRLS
 | CACHE
 | CALL
 | CALLED
@@ -17522,6 +18158,7 @@ unreserved_keyword:
 | DEBUG_IDS
 | DEBUG_DUMP_METADATA_SST
 | DECLARE
+| DEDUPLICATE
 | DELETE
 | DEFAULTS
 | DEFERRED
@@ -17531,11 +18168,13 @@ unreserved_keyword:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
 | EACH
+| ENABLE
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -17561,6 +18200,7 @@ unreserved_keyword:
 | FAILURE
 | FILES
 | FILTER
+| FINGERPRINT
 | FIRST
 | FOLLOWING
 | FORMAT
@@ -17640,6 +18280,7 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGICAL
@@ -17648,6 +18289,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -17679,8 +18321,10 @@ unreserved_keyword:
 | NEW_KMS
 | NEXT
 | NO
+| NOBYPASSRLS
 | NORMAL
 | NOTHING
+| NOTIFY
 | NO_INDEX_JOIN
 | NO_ZIGZAG_JOIN
 | NO_FULL_SCAN
@@ -17703,6 +18347,7 @@ unreserved_keyword:
 | NULLS
 | IGNORE_FOREIGN_KEYS
 | INSENSITIVE
+| OBJECT_LOCK_RETENTION
 | OF
 | OFF
 | OIDS
@@ -17733,6 +18378,7 @@ unreserved_keyword:
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGONM
 | POLYGONZ
 | POLYGONZM
@@ -17772,6 +18418,7 @@ unreserved_keyword:
 | REPEATABLE
 | REPLACE
 | REPLICATION
+| RESCAN
 | RESET
 | RESTART
 | RESTORE
@@ -17790,6 +18437,7 @@ unreserved_keyword:
 | ROLLUP
 | ROUTINES
 | ROWS
+| ROW_FILTER
 | RULE
 | RUNNING
 | SCHEDULE
@@ -17900,6 +18548,7 @@ unreserved_keyword:
 | VALUE
 | VARIABLES
 | VARYING
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
 | VIEW
 | VIEWACTIVITY
@@ -17966,6 +18615,7 @@ bare_label_keywords:
 | BUCKET_COUNT
 | BUNDLE
 | BY
+| BYPASSRLS
 | CACHE
 | CALL
 | CALLED
@@ -18035,6 +18685,7 @@ bare_label_keywords:
 | DEC
 | DECIMAL
 | DECLARE
+| DEDUPLICATE
 | DEFAULT
 | DEFAULTS
 | DEFERRABLE
@@ -18047,6 +18698,7 @@ bare_label_keywords:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DISABLE
 | DISCARD
 | DISTINCT
 | DO
@@ -18055,6 +18707,7 @@ bare_label_keywords:
 | DROP
 | EACH
 | ELSE
+| ENABLE
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_INFO_DIR
@@ -18085,6 +18738,7 @@ bare_label_keywords:
 | FALSE
 | FAMILY
 | FILES
+| FINGERPRINT
 | FIRST
 | FLOAT
 | FOLLOWING
@@ -18194,6 +18848,7 @@ bare_label_keywords:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCALITY
 | LOCALTIME
@@ -18204,6 +18859,7 @@ bare_label_keywords:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -18234,6 +18890,7 @@ bare_label_keywords:
 | NEW_KMS
 | NEXT
 | NO
+| NOBYPASSRLS
 | NOCANCELQUERY
 | NOCONTROLCHANGEFEED
 | NOCONTROLJOB
@@ -18251,6 +18908,7 @@ bare_label_keywords:
 | NOT
 | NOTHING
 | NOTHING_AFTER_RETURNING
+| NOTIFY
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
@@ -18262,6 +18920,7 @@ bare_label_keywords:
 | NULLIF
 | NULLS
 | NUMERIC
+| OBJECT_LOCK_RETENTION
 | OF
 | OFF
 | OIDS
@@ -18298,6 +18957,7 @@ bare_label_keywords:
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGON
 | POLYGONM
 | POLYGONZ
@@ -18342,6 +19002,7 @@ bare_label_keywords:
 | REPEATABLE
 | REPLACE
 | REPLICATION
+| RESCAN
 | RESET
 | RESTART
 | RESTORE
@@ -18362,6 +19023,7 @@ bare_label_keywords:
 | ROUTINES
 | ROW
 | ROWS
+| ROW_FILTER
 | RULE
 | RUNNING
 | SAVEPOINT
@@ -18497,6 +19159,7 @@ bare_label_keywords:
 | VARIABLES
 | VARIADIC
 | VECTOR
+| VERIFY
 | VERIFY_BACKUP_TABLE_DATA
 | VIEW
 | VIEWACTIVITY
@@ -640,6 +640,10 @@ https://www.postgresql.org/docs/9.5/catalog-pg-authid.html`,
 				return err
 			}
 
+			bypassRLS, err := options.bypassRLS()
+			if err != nil {
+				return err
+			}
 			isSuper, err := userIsSuper(ctx, p, userName)
 			if err != nil {
 				return err
@@ -654,7 +658,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-authid.html`,
 				tree.MakeDBool(isRoot || createDB),   // rolcreatedb
 				tree.MakeDBool(roleCanLogin),         // rolcanlogin.
 				tree.DBoolFalse,                      // rolreplication
-				tree.DBoolFalse,                      // rolbypassrls
+				tree.MakeDBool(bypassRLS),            // rolbypassrls
 				negOneVal,                            // rolconnlimit
 				passwdStarString,                     // rolpassword
 				rolValidUntil,                        // rolvaliduntil
@@ -765,6 +769,8 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 		}
 		implicitTypOID := typedesc.TableIDToImplicitTypeOID(table.GetID())
 		namespaceOid := schemaOid(sc.GetID())
+		relRowSecurity := tree.MakeDBool(tree.DBool(table.IsRowLevelSecurityEnabled()))
+		relForceRowSecurity := tree.MakeDBool(tree.DBool(table.IsRowLevelSecurityForced()))
 		if err := addRow(
 			tableOid(table.GetID()),        // oid
 			tree.NewDName(table.GetName()), // relname
@@ -795,12 +801,12 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 			tree.DNull,      // relacl
 			relOptions,      // reloptions
 			// These columns were automatically created by pg_catalog_test's missing column generator.
-			tree.DNull,                 // relforcerowsecurity
+			relForceRowSecurity,        // relforcerowsecurity
 			tree.DNull,                 // relispartition
 			tree.DNull,                 // relispopulated
 			tree.NewDString(replIdent), // relreplident
 			tree.DNull,                 // relrewrite
-			tree.DNull,                 // relrowsecurity
+			relRowSecurity,             // relrowsecurity
 			tree.DNull,                 // relpartbound
 			// These columns were automatically created by pg_catalog_test's missing column generator.
 			tree.DNull, // relminmxid
@@ -2934,6 +2940,10 @@ https://www.postgresql.org/docs/9.5/view-pg-roles.html`,
 				if err != nil {
 					return err
 				}
+				bypassRLS, err := options.bypassRLS()
+				if err != nil {
+					return err
+				}
 				isSuper, err := userIsSuper(ctx, p, userName)
 				if err != nil {
 					return err
@@ -2952,7 +2962,7 @@ https://www.postgresql.org/docs/9.5/view-pg-roles.html`,
 					negOneVal,                             // rolconnlimit
 					passwdStarString,                      // rolpassword
 					rolValidUntil,                         // rolvaliduntil
-					tree.DBoolFalse,                       // rolbypassrls
+					tree.MakeDBool(bypassRLS),             // rolbypassrls
 					settings,                              // rolconfig
 				)
 			})
@@ -3221,7 +3231,7 @@ https://www.postgresql.org/docs/9.5/view-pg-tables.html`,
 					tree.MakeDBool(tree.DBool(table.IsPhysicalTable())), // hasindexes
 					tree.DBoolFalse, // hasrules
 					tree.DBoolFalse, // hastriggers
-					tree.DBoolFalse, // rowsecurity
+					tree.MakeDBool(tree.DBool(table.IsRowLevelSecurityEnabled())), // rowsecurity
 				)
 			})
 	},
@@ -3741,6 +3751,10 @@ https://www.postgresql.org/docs/9.5/view-pg-user.html`,
 				if err != nil {
 					return err
 				}
+				bypassRLS, err := options.bypassRLS()
+				if err != nil {
+					return err
+				}
 				isSuper, err := userIsSuper(ctx, p, userName)
 				if err != nil {
 					return err
@@ -3752,7 +3766,7 @@ https://www.postgresql.org/docs/9.5/view-pg-user.html`,
 					tree.MakeDBool(isSuper || createDB),  // usecreatedb
 					tree.MakeDBool(isRoot || isSuper),    // usesuper
 					tree.DBoolFalse,                      // userepl
-					tree.DBoolFalse,                      // usebypassrls
+					tree.MakeDBool(bypassRLS),            // usebypassrls
 					passwdStarString,                     // passwd
 					validUntil,                           // valuntil
 					settings,                             // useconfig
@@ -3863,6 +3877,10 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 			if err != nil {
 				return err
 			}
+			bypassRLS, err := options.bypassRLS()
+			if err != nil {
+				return err
+			}
 			isSuper, err := userIsSuper(ctx, p, userName)
 			if err != nil {
 				return err
@@ -3874,7 +3892,7 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 				tree.MakeDBool(isRoot || createDB),   // usecreatedb
 				tree.MakeDBool(isRoot || isSuper),    // usesuper
 				tree.DBoolFalse,                      // userepl
-				tree.DBoolFalse,                      // usebypassrls
+				tree.MakeDBool(bypassRLS),            // usebypassrls
 				passwdStarString,                     // passwd
 				rolValidUntil,                        // valuntil
 				settings,                             // useconfig
@@ -4046,12 +4064,36 @@ var pgCatalogStatioAllSequencesTable = virtualSchemaTable{
 }
 
 var pgCatalogPoliciesTable = virtualSchemaTable{
-	comment: "pg_policies was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPolicies,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `row-level security policies
+https://www.postgresql.org/docs/9.5/view-pg-policies.html`,
+	schema: vtable.PgCatalogPolicies,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachPolicy(ctx, p, dbContext, func(
+			sc catalog.SchemaDescriptor,
+			table catalog.TableDescriptor,
+			policy *descpb.PolicyDescriptor,
+			qual, withCheck tree.Datum,
+		) error {
+			roles := tree.NewDArray(types.Name)
+			for _, role := range policy.Roles() {
+				if err := roles.Append(tree.NewDName(role.Normalized())); err != nil {
+					return err
+				}
+			}
+			permissive := tree.NewDString(tree.PolicyType(policy.Type).String())
+			cmd := tree.NewDString(tree.PolicyCommand(policy.Command).String())
+			return addRow(
+				tree.NewDName(sc.GetName()),    // schemaname
+				tree.NewDName(table.GetName()), // tablename
+				tree.NewDName(policy.Name),     // policyname
+				permissive,                     // permissive
+				roles,                          // roles
+				cmd,                            // cmd
+				qual,                           // qual
+				withCheck,                      // with_check
+			)
+		})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatsExtTable = virtualSchemaTable{
@@ -4189,13 +4231,109 @@ var pgCatalogStatProgressBasebackupTable = virtualSchemaTable{
 	unimplemented: true,
 }
 
+var (
+	polCmdAll    = tree.NewDString("*")
+	polCmdSelect = tree.NewDString("r")
+	polCmdInsert = tree.NewDString("a")
+	polCmdUpdate = tree.NewDString("w")
+	polCmdDelete = tree.NewDString("d")
+)
+
 var pgCatalogPolicyTable = virtualSchemaTable{
-	comment: "pg_policy was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPolicy,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `row-level security policies
+https://www.postgresql.org/docs/9.5/catalog-pg-policy.html`,
+	schema: vtable.PgCatalogPolicy,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachPolicy(ctx, p, dbContext, func(
+			sc catalog.SchemaDescriptor,
+			table catalog.TableDescriptor,
+			policy *descpb.PolicyDescriptor,
+			qual, withCheck tree.Datum,
+		) error {
+			var polcmd tree.Datum
+			switch tree.PolicyCommand(policy.Command) {
+			case tree.PolicyCommandSelect:
+				polcmd = polCmdSelect
+			case tree.PolicyCommandInsert:
+				polcmd = polCmdInsert
+			case tree.PolicyCommandUpdate:
+				polcmd = polCmdUpdate
+			case tree.PolicyCommandDelete:
+				polcmd = polCmdDelete
+			default:
+				polcmd = polCmdAll
+			}
+			polroles := tree.NewDArray(types.Oid)
+			for _, role := range policy.Roles() {
+				roleOid := oidZero
+				if !role.IsPublicRole() {
+					roleOid = h.UserOid(role)
+				}
+				if err := polroles.Append(roleOid); err != nil {
+					return err
+				}
+			}
+			polpermissive := tree.MakeDBool(tree.DBool(tree.PolicyType(policy.Type) == tree.PolicyTypePermissive))
+			return addRow(
+				h.PolicyOid(table.GetID(), policy.ID), // oid
+				tree.NewDName(policy.Name),            // polname
+				tableOid(table.GetID()),               // polrelid
+				polcmd,                                // polcmd
+				polpermissive,                         // polpermissive
+				polroles,                              // polroles
+				qual,                                  // polqual
+				withCheck,                             // polwithcheck
+			)
+		})
 	},
-	unimplemented: true,
+}
+
+// forEachPolicy calls fn for every row-level security policy on the tables
+// in the given database, along with the display form of the policy's USING
+// and WITH CHECK expressions (or NULL if absent).
+func forEachPolicy(
+	ctx context.Context,
+	p *planner,
+	dbContext catalog.DatabaseDescriptor,
+	fn func(
+		sc catalog.SchemaDescriptor,
+		table catalog.TableDescriptor,
+		policy *descpb.PolicyDescriptor,
+		qual, withCheck tree.Datum,
+	) error,
+) error {
+	formatExpr := func(table catalog.TableDescriptor, expr string) (tree.Datum, error) {
+		if expr == "" {
+			return tree.DNull, nil
+		}
+		displayExpr, err := schemaexpr.FormatExprForDisplay(
+			ctx, table, expr, p.EvalContext(), &p.semaCtx, p.SessionData(), tree.FmtPGCatalog,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return tree.NewDString(displayExpr), nil
+	}
+	return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables have no policies */
+		func(ctx context.Context, _ catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+			policies := table.GetPolicies()
+			for i := range policies {
+				policy := &policies[i]
+				qual, err := formatExpr(table, policy.UsingExpr)
+				if err != nil {
+					return err
+				}
+				withCheck, err := formatExpr(table, policy.WithCheckExpr)
+				if err != nil {
+					return err
+				}
+				if err := fn(sc, table, policy, qual, withCheck); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 }
 
 var pgCatalogStatArchiverTable = virtualSchemaTable{
@@ -5000,6 +5138,7 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	policyTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -5174,6 +5313,15 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+// PolicyOid creates an OID for the row-level security policy with the given
+// ID on the given table.
+func (h oidHasher) PolicyOid(tableID descpb.ID, policyID descpb.PolicyID) *tree.DOid {
+	h.writeTypeTag(policyTypeTag)
+	h.writeTable(tableID)
+	h.writeUInt32(uint32(policyID))
+	return h.getOid()
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(oid.Oid(id))
 }
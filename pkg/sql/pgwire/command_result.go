@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
@@ -41,6 +42,8 @@ const (
 	emptyQueryResponse
 	readyForQuery
 	flush
+	// deliverNotifications flushes the buffered notifications, if any.
+	deliverNotifications
 	// Some commands, like Describe, don't need a completion message.
 	noCompletionMsg
 )
@@ -59,6 +62,7 @@ type commandResult struct {
 	// buffer contains items that are sent before the connection is closed.
 	buffer struct {
 		notices            []pgnotice.Notice
+		notifications      []serverpb.Notification
 		paramStatusUpdates []paramStatusUpdate
 	}
 
@@ -148,6 +152,12 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		}
 	}
 
+	for _, notification := range r.buffer.notifications {
+		if err := r.conn.bufferNotification(notification); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err when sending notification"))
+		}
+	}
+
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
@@ -172,6 +182,12 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
 		r.conn.maybeReallocate()
+	case deliverNotifications:
+		if len(r.buffer.notifications) > 0 {
+			// The error is saved on conn.err.
+			_ /* err */ = r.conn.Flush(r.pos)
+			r.conn.maybeReallocate()
+		}
 	case noCompletionMsg:
 		// nothing to do
 	default:
@@ -332,6 +348,11 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 	r.buffer.notices = append(r.buffer.notices, notice)
 }
 
+// BufferNotification is part of the sql.NotificationBuffer interface.
+func (r *commandResult) BufferNotification(notification serverpb.Notification) {
+	r.buffer.notifications = append(r.buffer.notifications, notification)
+}
+
 // SendNotice is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SendNotice(ctx context.Context, notice pgnotice.Notice) error {
 	if err := r.conn.bufferNotice(ctx, notice); err != nil {
@@ -635,6 +656,10 @@ func (r *limitedCommandResult) moreResultsNeeded(ctx context.Context) error {
 			if err := r.conn.Flush(r.pos); err != nil {
 				return err
 			}
+		case sql.DeliverNotifications:
+			// Notifications are not delivered inside of a transaction, so there
+			// is nothing to do until the transaction of the portal finishes.
+			r.conn.stmtBuf.AdvanceOne()
 		case sql.Flush:
 			// Flush has no client response, so just advance the position and flush
 			// any existing results.
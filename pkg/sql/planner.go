@@ -241,6 +241,8 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	notifications sessionNotifications
+
 	// autoCommit indicates whether the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations. Committing the txn
 	// might be beneficial because it may enable the 1PC optimization. Note that
@@ -459,6 +461,7 @@ func newInternalPlanner(
 	p.sqlCursors = emptySqlCursors{}
 	p.preparedStatements = emptyPreparedStatements{}
 	p.createdSequences = emptyCreatedSequences{}
+	p.notifications = commitTriggerNotifications{txn: p.txn, registry: execCfg.NotificationRegistry}
 
 	p.schemaResolver.descCollection = p.Descriptors()
 	p.schemaResolver.sessionDataStack = sds
@@ -37,6 +37,8 @@ func _() {
 	_ = x[VIEWCLUSTERSETTING-27]
 	_ = x[NOVIEWCLUSTERSETTING-28]
 	_ = x[SUBJECT-29]
+	_ = x[BYPASSRLS-30]
+	_ = x[NOBYPASSRLS-31]
 }
 
 func (i Option) String() string {
@@ -99,6 +101,10 @@ func (i Option) String() string {
 		return "NOVIEWCLUSTERSETTING"
 	case SUBJECT:
 		return "SUBJECT"
+	case BYPASSRLS:
+		return "BYPASSRLS"
+	case NOBYPASSRLS:
+		return "NOBYPASSRLS"
 	default:
 		return "Option(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -321,3 +321,10 @@ func CheckFailed(
 		pgcode.CheckViolation, "failed to satisfy CHECK constraint (%s)", expr,
 	), check.GetName())
 }
+
+// RowLevelSecurityCheckFailed returns the error reported when a row written to
+// the table does not satisfy the table's row-level security policies.
+func RowLevelSecurityCheckFailed(tabDesc catalog.TableDescriptor) error {
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"new row violates row-level security policy for table %q", tabDesc.GetName())
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// TestRowLevelSecurityMixedVersion tests that row-level security cannot be
+// enabled, and that policies cannot be created, until the cluster is upgraded
+// to a version whose nodes all enforce the policies.
+func TestRowLevelSecurityMixedVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestNeedsTightIntegrationBetweenAPIsAndTestingKnobs,
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				DisableAutomaticVersionUpgrade: make(chan struct{}),
+				ClusterVersionOverride:         clusterversion.MinSupported.Version(),
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, "CREATE TABLE t (k INT PRIMARY KEY, owner STRING)")
+	sqlDB.ExpectErr(t, "CREATE POLICY is only supported after v24.2 upgrade is finalized",
+		"CREATE POLICY p ON t USING (owner = current_user)")
+	sqlDB.ExpectErr(t,
+		"ALTER TABLE ... ENABLE ROW LEVEL SECURITY is only supported after v24.2 upgrade is finalized",
+		"ALTER TABLE t ENABLE ROW LEVEL SECURITY")
+	sqlDB.ExpectErr(t,
+		"ALTER TABLE ... FORCE ROW LEVEL SECURITY is only supported after v24.2 upgrade is finalized",
+		"ALTER TABLE t FORCE ROW LEVEL SECURITY")
+
+	sqlDB.Exec(t, "SET CLUSTER SETTING version = crdb_internal.node_executable_version()")
+	sqlDB.Exec(t, "CREATE POLICY p ON t USING (owner = current_user)")
+	sqlDB.Exec(t, "ALTER TABLE t ENABLE ROW LEVEL SECURITY")
+	sqlDB.Exec(t, "ALTER TABLE t FORCE ROW LEVEL SECURITY")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/sql/vecindex"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
@@ -628,6 +630,9 @@ func EncodeInvertedIndexKeys(
 	if !indexGeoConfig.IsEmpty() {
 		return EncodeGeoInvertedIndexTableKeys(ctx, val, keyPrefix, indexGeoConfig)
 	}
+	if indexVectorConfig := index.GetVectorConfig(); !indexVectorConfig.IsEmpty() {
+		return EncodeVectorInvertedIndexTableKeys(val, keyPrefix, indexVectorConfig)
+	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.GetVersion())
 }
 
@@ -1087,6 +1092,29 @@ func EncodeGeoInvertedIndexTableKeys(
 	}
 }
 
+// EncodeVectorInvertedIndexTableKeys is the equivalent of
+// EncodeInvertedIndexTableKeys for vectors. It produces a single key, which
+// identifies the partition of the vector index that the vector belongs to.
+func EncodeVectorInvertedIndexTableKeys(
+	val tree.Datum, inKey []byte, indexVectorConfig catpb.VectorIndexConfig,
+) (key [][]byte, err error) {
+	if val == tree.DNull {
+		return nil, nil
+	}
+	v, ok := tree.UnwrapDOidWrapper(val).(*tree.DPGVector)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected type: %s", val.ResolvedType().SQLStringForError())
+	}
+	partition, err := vecindex.MakePartitioner(indexVectorConfig).Partition(v.T)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure to copy inKey into a new byte slice to avoid aliasing.
+	outKey := make([]byte, len(inKey), len(inKey)+encoding.MaxVarintLen)
+	copy(outKey, inKey)
+	return [][]byte{vecindex.EncodeKey(outKey, partition)}, nil
+}
+
 func encodeGeoKeys(
 	inKey []byte, geoKeys []geoindex.Key, bbox geopb.BoundingBox,
 ) (keys [][]byte, err error) {
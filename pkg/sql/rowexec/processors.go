@@ -259,6 +259,15 @@ func NewProcessor(
 		}
 		return NewBackupDataProcessor(ctx, flowCtx, processorID, *core.BackupData, post)
 	}
+	if core.VerifyBackupData != nil {
+		if err := checkNumIn(inputs, 0); err != nil {
+			return nil, err
+		}
+		if NewVerifyBackupDataProcessor == nil {
+			return nil, errors.New("VerifyBackupData processor unimplemented")
+		}
+		return NewVerifyBackupDataProcessor(ctx, flowCtx, processorID, *core.VerifyBackupData, post)
+	}
 	if core.RestoreData != nil {
 		if err := checkNumIn(inputs, 1); err != nil {
 			return nil, err
@@ -405,6 +414,9 @@ var NewIngestStoppedProcessor func(context.Context, *execinfra.FlowCtx, int32, e
 // NewBackupDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewBackupDataProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.BackupDataSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
 
+// NewVerifyBackupDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
+var NewVerifyBackupDataProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.VerifyBackupDataSpec, *execinfrapb.PostProcessSpec) (execinfra.Processor, error)
+
 // NewRestoreDataProcessor is implemented in the non-free (CCL) codebase and then injected here via runtime initialization.
 var NewRestoreDataProcessor func(context.Context, *execinfra.FlowCtx, int32, execinfrapb.RestoreDataSpec, *execinfrapb.PostProcessSpec, execinfra.RowSource) (execinfra.Processor, error)
 
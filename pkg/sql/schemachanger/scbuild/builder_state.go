@@ -1060,6 +1060,14 @@ func (b *builderState) resolveRelation(
 		if t.IsTemporary() {
 			panic(scerrors.NotImplementedErrorf(nil /* n */, "dropping a temporary table"))
 		}
+		if len(t.GetPolicies()) > 0 {
+			panic(scerrors.NotImplementedErrorf(nil /* n */, "table with row-level security policies"))
+		}
+		for _, idx := range t.NonDropIndexes() {
+			if vectorConfig := idx.GetVectorConfig(); !vectorConfig.IsEmpty() {
+				panic(scerrors.NotImplementedErrorf(nil /* n */, "table with vector indexes"))
+			}
+		}
 	} else if typ, isType := rel.(catalog.TypeDescriptor); isType {
 		if typ.GetKind() == descpb.TypeDescriptor_ALIAS && typ.GetID() == descpb.InvalidID {
 			// This case handles the types in types.PublicSchemaAliases -- BOX2D,
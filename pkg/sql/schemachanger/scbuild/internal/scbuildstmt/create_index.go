@@ -318,6 +318,9 @@ func processColNodeType(
 		panic(pgerror.New(pgcode.FeatureNotSupported,
 			"the last column in an inverted index cannot have the DESC option"))
 	}
+	if n.Vector && lastColIdx && columnType.Type.Family() != types.PGVectorFamily {
+		panic(tabledesc.NewInvalidVectorColumnError(colName, columnType.Type.String()))
+	}
 	if n.Inverted && lastColIdx {
 		switch columnType.Type.Family() {
 		case types.ArrayFamily:
@@ -367,6 +370,8 @@ func processColNodeType(
 			}
 			invertedKind = catpb.InvertedIndexColumnKind_TRIGRAM
 			b.IncrementSchemaChangeIndexCounter("trigram_inverted")
+		case types.PGVectorFamily:
+			panic(scerrors.NotImplementedErrorf(n, "vector indexes are not supported in the declarative schema changer"))
 
 		}
 		relationElts := b.QueryByID(indexSpec.secondary.TableID)
@@ -457,6 +457,11 @@ type Planner interface {
 
 	// ClearTableStatsCache removes all entries from the node's table stats cache.
 	ClearTableStatsCache()
+
+	// NotifyChannel raises a notification with the given payload on the given
+	// channel. The notification is delivered to the listening sessions once the
+	// current transaction commits. It is used to implement pg_notify.
+	NotifyChannel(ctx context.Context, channel, payload string) error
 }
 
 // InternalRows is an iterator interface that's exposed by the internal
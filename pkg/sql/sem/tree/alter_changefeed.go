@@ -50,11 +50,13 @@ func (*AlterChangefeedAddTarget) alterChangefeedCmd()    {}
 func (*AlterChangefeedDropTarget) alterChangefeedCmd()   {}
 func (*AlterChangefeedSetOptions) alterChangefeedCmd()   {}
 func (*AlterChangefeedUnsetOptions) alterChangefeedCmd() {}
+func (*AlterChangefeedRescan) alterChangefeedCmd()       {}
 
 var _ AlterChangefeedCmd = &AlterChangefeedAddTarget{}
 var _ AlterChangefeedCmd = &AlterChangefeedDropTarget{}
 var _ AlterChangefeedCmd = &AlterChangefeedSetOptions{}
 var _ AlterChangefeedCmd = &AlterChangefeedUnsetOptions{}
+var _ AlterChangefeedCmd = &AlterChangefeedRescan{}
 
 // AlterChangefeedAddTarget represents an ADD <targets> command
 type AlterChangefeedAddTarget struct {
@@ -112,3 +114,24 @@ func (node *AlterChangefeedUnsetOptions) Format(ctx *FmtCtx) {
 	ctx.WriteString(" UNSET ")
 	ctx.FormatNode(&node.Options)
 }
+
+// AlterChangefeedRescan represents a RESCAN [TABLE <target>] [WHERE <expr>]
+// command.
+type AlterChangefeedRescan struct {
+	// Target is nil if all the targets of the changefeed are rescanned.
+	Target *ChangefeedTarget
+	Where  *Where
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterChangefeedRescan) Format(ctx *FmtCtx) {
+	ctx.WriteString(" RESCAN")
+	if node.Target != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Target)
+	}
+	if node.Where != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Where)
+	}
+}
@@ -57,30 +57,31 @@ type AlterTableCmd interface {
 	alterTableCmd()
 }
 
-func (*AlterTableAddColumn) alterTableCmd()          {}
-func (*AlterTableAddConstraint) alterTableCmd()      {}
-func (*AlterTableAlterColumnType) alterTableCmd()    {}
-func (*AlterTableAlterPrimaryKey) alterTableCmd()    {}
-func (*AlterTableDropColumn) alterTableCmd()         {}
-func (*AlterTableDropConstraint) alterTableCmd()     {}
-func (*AlterTableDropNotNull) alterTableCmd()        {}
-func (*AlterTableDropStored) alterTableCmd()         {}
-func (*AlterTableSetNotNull) alterTableCmd()         {}
-func (*AlterTableRenameColumn) alterTableCmd()       {}
-func (*AlterTableRenameConstraint) alterTableCmd()   {}
-func (*AlterTableSetAudit) alterTableCmd()           {}
-func (*AlterTableSetDefault) alterTableCmd()         {}
-func (*AlterTableSetOnUpdate) alterTableCmd()        {}
-func (*AlterTableSetVisible) alterTableCmd()         {}
-func (*AlterTableValidateConstraint) alterTableCmd() {}
-func (*AlterTablePartitionByTable) alterTableCmd()   {}
-func (*AlterTableInjectStats) alterTableCmd()        {}
-func (*AlterTableSetStorageParams) alterTableCmd()   {}
-func (*AlterTableResetStorageParams) alterTableCmd() {}
-func (*AlterTableAddIdentity) alterTableCmd()        {}
-func (*AlterTableSetIdentity) alterTableCmd()        {}
-func (*AlterTableIdentity) alterTableCmd()           {}
-func (*AlterTableDropIdentity) alterTableCmd()       {}
+func (*AlterTableAddColumn) alterTableCmd()           {}
+func (*AlterTableAddConstraint) alterTableCmd()       {}
+func (*AlterTableAlterColumnType) alterTableCmd()     {}
+func (*AlterTableAlterPrimaryKey) alterTableCmd()     {}
+func (*AlterTableDropColumn) alterTableCmd()          {}
+func (*AlterTableDropConstraint) alterTableCmd()      {}
+func (*AlterTableDropNotNull) alterTableCmd()         {}
+func (*AlterTableDropStored) alterTableCmd()          {}
+func (*AlterTableSetNotNull) alterTableCmd()          {}
+func (*AlterTableRenameColumn) alterTableCmd()        {}
+func (*AlterTableRenameConstraint) alterTableCmd()    {}
+func (*AlterTableSetAudit) alterTableCmd()            {}
+func (*AlterTableSetRowLevelSecurity) alterTableCmd() {}
+func (*AlterTableSetDefault) alterTableCmd()          {}
+func (*AlterTableSetOnUpdate) alterTableCmd()         {}
+func (*AlterTableSetVisible) alterTableCmd()          {}
+func (*AlterTableValidateConstraint) alterTableCmd()  {}
+func (*AlterTablePartitionByTable) alterTableCmd()    {}
+func (*AlterTableInjectStats) alterTableCmd()         {}
+func (*AlterTableSetStorageParams) alterTableCmd()    {}
+func (*AlterTableResetStorageParams) alterTableCmd()  {}
+func (*AlterTableAddIdentity) alterTableCmd()         {}
+func (*AlterTableSetIdentity) alterTableCmd()         {}
+func (*AlterTableIdentity) alterTableCmd()            {}
+func (*AlterTableDropIdentity) alterTableCmd()        {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -93,6 +94,7 @@ var _ AlterTableCmd = &AlterTableSetNotNull{}
 var _ AlterTableCmd = &AlterTableRenameColumn{}
 var _ AlterTableCmd = &AlterTableRenameConstraint{}
 var _ AlterTableCmd = &AlterTableSetAudit{}
+var _ AlterTableCmd = &AlterTableSetRowLevelSecurity{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
 var _ AlterTableCmd = &AlterTableSetOnUpdate{}
 var _ AlterTableCmd = &AlterTableSetVisible{}
@@ -603,6 +605,52 @@ func (node *AlterTableSetAudit) Format(ctx *FmtCtx) {
 	ctx.WriteString(node.Mode.String())
 }
 
+// RowLevelSecurityMode represents a change to the row-level security of a
+// table.
+type RowLevelSecurityMode int
+
+const (
+	// RowLevelSecurityEnable enables row-level security on the table.
+	RowLevelSecurityEnable RowLevelSecurityMode = iota
+	// RowLevelSecurityDisable disables row-level security on the table.
+	RowLevelSecurityDisable
+	// RowLevelSecurityForce applies row-level security to the owner of the
+	// table as well.
+	RowLevelSecurityForce
+	// RowLevelSecurityNoForce exempts the owner of the table from row-level
+	// security.
+	RowLevelSecurityNoForce
+)
+
+var rowLevelSecurityModeName = [...]string{
+	RowLevelSecurityEnable:  "ENABLE",
+	RowLevelSecurityDisable: "DISABLE",
+	RowLevelSecurityForce:   "FORCE",
+	RowLevelSecurityNoForce: "NO FORCE",
+}
+
+func (m RowLevelSecurityMode) String() string {
+	return rowLevelSecurityModeName[m]
+}
+
+// AlterTableSetRowLevelSecurity represents an ALTER TABLE {ENABLE | DISABLE |
+// FORCE | NO FORCE} ROW LEVEL SECURITY command.
+type AlterTableSetRowLevelSecurity struct {
+	Mode RowLevelSecurityMode
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableSetRowLevelSecurity) TelemetryName() string {
+	return strings.ReplaceAll(strings.ToLower(node.Mode.String()), " ", "_") + "_row_level_security"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetRowLevelSecurity) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ")
+	ctx.WriteString(node.Mode.String())
+	ctx.WriteString(" ROW LEVEL SECURITY")
+}
+
 // AlterTableInjectStats represents an ALTER TABLE INJECT STATISTICS statement.
 type AlterTableInjectStats struct {
 	Stats Expr
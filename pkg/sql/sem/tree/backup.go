@@ -48,6 +48,8 @@ type BackupOptions struct {
 	IncrementalStorage              StringOrPlaceholderOptList
 	ExecutionLocality               Expr
 	UpdatesClusterMonitoringMetrics Expr
+	Deduplicate                     Expr
+	ObjectLockRetention             Expr
 }
 
 var _ NodeFormatter = &BackupOptions{}
@@ -131,6 +133,100 @@ func (node Backup) Coverage() DescriptorCoverage {
 	return RequestedDescriptors
 }
 
+// CompactBackup represents a COMPACT BACKUP statement.
+type CompactBackup struct {
+	// Subdir is the subdirectory of the full backup of the chain to compact in
+	// the collection, or LATEST.
+	Subdir Expr
+
+	// In contains the URIs of the collection, which are locality aware if
+	// there is more than one.
+	In StringOrPlaceholderOptList
+
+	AsOf    AsOfClause
+	Options BackupOptions
+}
+
+var _ Statement = &CompactBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *CompactBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("COMPACT BACKUP FROM ")
+	ctx.FormatNode(node.Subdir)
+	ctx.WriteString(" IN ")
+	ctx.FormatURIs(node.In)
+	if node.AsOf.Expr != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(&node.AsOf)
+	}
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// DropBackup represents a DROP BACKUP statement.
+type DropBackup struct {
+	// Subdir is the subdirectory of the full backup of the chain to drop in the
+	// collection.
+	Subdir Expr
+
+	// In contains the URIs of the collection, which are locality aware if
+	// there is more than one.
+	In StringOrPlaceholderOptList
+
+	Options BackupOptions
+}
+
+var _ Statement = &DropBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP BACKUP ")
+	ctx.FormatNode(node.Subdir)
+	ctx.WriteString(" IN ")
+	ctx.FormatURIs(node.In)
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// VerifyBackup represents a VERIFY BACKUP statement.
+type VerifyBackup struct {
+	// Subdir is the subdirectory of the full backup of the chain to verify in
+	// the collection, or LATEST.
+	Subdir Expr
+
+	// In contains the URIs of the collection, which are locality aware if
+	// there is more than one.
+	In StringOrPlaceholderOptList
+
+	AsOf    AsOfClause
+	Options VerifyBackupOptions
+}
+
+var _ Statement = &VerifyBackup{}
+
+// Format implements the NodeFormatter interface.
+func (node *VerifyBackup) Format(ctx *FmtCtx) {
+	ctx.WriteString("VERIFY BACKUP FROM ")
+	ctx.FormatNode(node.Subdir)
+	ctx.WriteString(" IN ")
+	ctx.FormatURIs(node.In)
+	if node.AsOf.Expr != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(&node.AsOf)
+	}
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH OPTIONS (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
 // RestoreOptions describes options for the RESTORE execution.
 type RestoreOptions struct {
 	EncryptionPassphrase             Expr
@@ -153,6 +249,7 @@ type RestoreOptions struct {
 	ExecutionLocality                Expr
 	ExperimentalOnline               bool
 	RemoveRegions                    bool
+	RowFilter                        Expr
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -177,6 +274,11 @@ type Restore struct {
 	// ... FROM 'from' IN 'subdir'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM 'from' IN LATEST...`
 	Subdir Expr
+
+	// AsTable is set by the parser when the SQL query is of the form `RESTORE
+	// TABLE t AS new_t FROM ...`, which restores the single table t under the
+	// name new_t.
+	AsTable *UnresolvedObjectName
 }
 
 var _ Statement = &Restore{}
@@ -186,6 +288,10 @@ func (node *Restore) Format(ctx *FmtCtx) {
 	ctx.WriteString("RESTORE ")
 	if node.DescriptorCoverage == RequestedDescriptors {
 		ctx.FormatNode(&node.Targets)
+		if node.AsTable != nil {
+			ctx.WriteString(" AS ")
+			ctx.FormatNode(node.AsTable)
+		}
 		ctx.WriteString(" ")
 	}
 	ctx.WriteString("FROM ")
@@ -331,6 +437,18 @@ func (o *BackupOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("updates_cluster_monitoring_metrics = ")
 		ctx.FormatNode(o.UpdatesClusterMonitoringMetrics)
 	}
+
+	if o.Deduplicate != nil {
+		maybeAddSep()
+		ctx.WriteString("deduplicate = ")
+		ctx.FormatNode(o.Deduplicate)
+	}
+
+	if o.ObjectLockRetention != nil {
+		maybeAddSep()
+		ctx.WriteString("object_lock_retention = ")
+		ctx.FormatNode(o.ObjectLockRetention)
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -391,6 +509,22 @@ func (o *BackupOptions) CombineWith(other *BackupOptions) error {
 	} else {
 		o.UpdatesClusterMonitoringMetrics = other.UpdatesClusterMonitoringMetrics
 	}
+
+	if o.Deduplicate != nil {
+		if other.Deduplicate != nil {
+			return errors.New("deduplicate option specified multiple times")
+		}
+	} else {
+		o.Deduplicate = other.Deduplicate
+	}
+
+	if o.ObjectLockRetention != nil {
+		if other.ObjectLockRetention != nil {
+			return errors.New("object_lock_retention option specified multiple times")
+		}
+	} else {
+		o.ObjectLockRetention = other.ObjectLockRetention
+	}
 	return nil
 }
 
@@ -404,7 +538,106 @@ func (o BackupOptions) IsDefault() bool {
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.IncludeAllSecondaryTenants == options.IncludeAllSecondaryTenants &&
-		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics
+		o.UpdatesClusterMonitoringMetrics == options.UpdatesClusterMonitoringMetrics &&
+		o.Deduplicate == options.Deduplicate &&
+		o.ObjectLockRetention == options.ObjectLockRetention
+}
+
+// VerifyBackupOptions describes options for the VERIFY BACKUP execution.
+type VerifyBackupOptions struct {
+	EncryptionPassphrase Expr
+	DecryptionKMSURI     StringOrPlaceholderOptList
+	IncrementalStorage   StringOrPlaceholderOptList
+	Detached             *DBool
+	Fingerprint          bool
+}
+
+var _ NodeFormatter = &VerifyBackupOptions{}
+
+// Format implements the NodeFormatter interface.
+func (o *VerifyBackupOptions) Format(ctx *FmtCtx) {
+	var addSep bool
+	maybeAddSep := func() {
+		if addSep {
+			ctx.WriteString(", ")
+		}
+		addSep = true
+	}
+	if o.EncryptionPassphrase != nil {
+		maybeAddSep()
+		ctx.WriteString("encryption_passphrase = ")
+		if ctx.flags.HasFlags(FmtShowPasswords) {
+			ctx.FormatNode(o.EncryptionPassphrase)
+		} else {
+			ctx.WriteString(PasswordSubstitution)
+		}
+	}
+
+	if o.DecryptionKMSURI != nil {
+		maybeAddSep()
+		ctx.WriteString("kms = ")
+		ctx.FormatURIs(o.DecryptionKMSURI)
+	}
+
+	if o.IncrementalStorage != nil {
+		maybeAddSep()
+		ctx.WriteString("incremental_location = ")
+		ctx.FormatURIs(o.IncrementalStorage)
+	}
+
+	if o.Detached != nil {
+		maybeAddSep()
+		ctx.WriteString("detached")
+		if o.Detached != DBoolTrue {
+			ctx.WriteString(" = FALSE")
+		}
+	}
+
+	if o.Fingerprint {
+		maybeAddSep()
+		ctx.WriteString("fingerprint")
+	}
+}
+
+// CombineWith merges other verify backup options into this struct. An error
+// is returned if the same option is merged multiple times.
+func (o *VerifyBackupOptions) CombineWith(other *VerifyBackupOptions) error {
+	var err error
+	o.EncryptionPassphrase, err = combineExpr(o.EncryptionPassphrase, other.EncryptionPassphrase,
+		"encryption_passphrase")
+	if err != nil {
+		return err
+	}
+	o.DecryptionKMSURI, err = combineStringOrPlaceholderOptList(o.DecryptionKMSURI,
+		other.DecryptionKMSURI, "kms")
+	if err != nil {
+		return err
+	}
+	o.IncrementalStorage, err = combineStringOrPlaceholderOptList(o.IncrementalStorage,
+		other.IncrementalStorage, "incremental_location")
+	if err != nil {
+		return err
+	}
+	if o.Detached != nil {
+		if other.Detached != nil {
+			return errors.New("detached option specified multiple times")
+		}
+	} else {
+		o.Detached = other.Detached
+	}
+	o.Fingerprint, err = combineBools(o.Fingerprint, other.Fingerprint, "fingerprint")
+	return err
+}
+
+// IsDefault returns true if this verify backup options struct has default
+// value.
+func (o VerifyBackupOptions) IsDefault() bool {
+	options := VerifyBackupOptions{}
+	return o.EncryptionPassphrase == options.EncryptionPassphrase &&
+		cmp.Equal(o.DecryptionKMSURI, options.DecryptionKMSURI) &&
+		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
+		(o.Detached == nil || o.Detached == DBoolFalse) &&
+		o.Fingerprint == options.Fingerprint
 }
 
 // Format implements the NodeFormatter interface.
@@ -526,6 +759,12 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		maybeAddSep()
 		ctx.WriteString("remove_regions")
 	}
+
+	if o.RowFilter != nil {
+		maybeAddSep()
+		ctx.WriteString("row_filter = ")
+		ctx.FormatNode(o.RowFilter)
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -675,6 +914,12 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		o.RemoveRegions = other.RemoveRegions
 	}
 
+	if o.RowFilter == nil {
+		o.RowFilter = other.RowFilter
+	} else if other.RowFilter != nil {
+		return errors.New("row_filter specified multiple times")
+	}
+
 	return nil
 }
 
@@ -700,7 +945,8 @@ func (o RestoreOptions) IsDefault() bool {
 		o.UnsafeRestoreIncompatibleVersion == options.UnsafeRestoreIncompatibleVersion &&
 		o.ExecutionLocality == options.ExecutionLocality &&
 		o.ExperimentalOnline == options.ExperimentalOnline &&
-		o.RemoveRegions == options.RemoveRegions
+		o.RemoveRegions == options.RemoveRegions &&
+		o.RowFilter == options.RowFilter
 }
 
 // BackupTargetList represents a list of targets.
@@ -250,6 +250,9 @@ type CreateIndex struct {
 	Predicate        Expr
 	Concurrently     bool
 	Invisibility     IndexInvisibility
+	// Vector is set for vector indexes, which are inverted indexes on vector
+	// columns. Inverted is always set along with Vector.
+	Vector bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -261,7 +264,9 @@ func (node *CreateIndex) Format(ctx *FmtCtx) {
 	if node.Unique {
 		ctx.WriteString("UNIQUE ")
 	}
-	if node.Inverted {
+	if node.Vector {
+		ctx.WriteString("VECTOR ")
+	} else if node.Inverted {
 		ctx.WriteString("INVERTED ")
 	}
 	ctx.WriteString("INDEX ")
@@ -1040,11 +1045,16 @@ type IndexTableDef struct {
 	StorageParams    StorageParams
 	Predicate        Expr
 	Invisibility     IndexInvisibility
+	// Vector is set for vector indexes. Inverted is always set along with
+	// Vector.
+	Vector bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *IndexTableDef) Format(ctx *FmtCtx) {
-	if node.Inverted {
+	if node.Vector {
+		ctx.WriteString("VECTOR ")
+	} else if node.Inverted {
 		ctx.WriteString("INVERTED ")
 	}
 	ctx.WriteString("INDEX ")
@@ -628,6 +628,9 @@ const (
 	TTLExpirationExpr               SchemaExprContext = "TTL EXPIRATION EXPRESSION"
 	TTLDefaultExpr                  SchemaExprContext = "TTL DEFAULT"
 	TTLUpdateExpr                   SchemaExprContext = "TTL UPDATE"
+	RestoreRowFilterExpr            SchemaExprContext = "RESTORE ROW FILTER"
+	PolicyUsingExpr                 SchemaExprContext = "POLICY USING EXPRESSION"
+	PolicyWithCheckExpr             SchemaExprContext = "POLICY WITH CHECK EXPRESSION"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
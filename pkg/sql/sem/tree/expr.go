@@ -630,6 +630,21 @@ func (node *IfErrExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// GroupingExpr represents a GROUPING(e1, ..., en) expression. It returns a
+// bitmask in which bit n-i is set if ei is not part of the grouping set of the
+// current row. It can only be used in a query with a GROUP BY clause, where it
+// is replaced by the optimizer; it is never evaluated directly.
+type GroupingExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING(")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // IfExpr represents an IF expression.
 type IfExpr struct {
 	Cond Expr
@@ -1758,6 +1773,8 @@ func (node *DVoid) String() string            { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingExpr) String() string     { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
@@ -0,0 +1,132 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// PolicyType represents whether a row-level security policy is permissive or
+// restrictive.
+type PolicyType uint8
+
+const (
+	// PolicyTypePermissive policies are combined with OR: a row is visible if
+	// any of them allows it.
+	PolicyTypePermissive PolicyType = iota
+	// PolicyTypeRestrictive policies are combined with AND: a row is visible
+	// only if all of them allow it.
+	PolicyTypeRestrictive
+)
+
+var policyTypeName = [...]string{
+	PolicyTypePermissive:  "PERMISSIVE",
+	PolicyTypeRestrictive: "RESTRICTIVE",
+}
+
+func (t PolicyType) String() string {
+	return policyTypeName[t]
+}
+
+// PolicyCommand represents the command that a row-level security policy
+// applies to.
+type PolicyCommand uint8
+
+const (
+	// PolicyCommandAll applies the policy to every command.
+	PolicyCommandAll PolicyCommand = iota
+	// PolicyCommandSelect applies the policy to SELECT.
+	PolicyCommandSelect
+	// PolicyCommandInsert applies the policy to INSERT.
+	PolicyCommandInsert
+	// PolicyCommandUpdate applies the policy to UPDATE.
+	PolicyCommandUpdate
+	// PolicyCommandDelete applies the policy to DELETE.
+	PolicyCommandDelete
+)
+
+var policyCommandName = [...]string{
+	PolicyCommandAll:    "ALL",
+	PolicyCommandSelect: "SELECT",
+	PolicyCommandInsert: "INSERT",
+	PolicyCommandUpdate: "UPDATE",
+	PolicyCommandDelete: "DELETE",
+}
+
+func (c PolicyCommand) String() string {
+	return policyCommandName[c]
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name    Name
+	Table   *UnresolvedObjectName
+	Type    PolicyType
+	Command PolicyCommand
+	// Roles is the list of roles that the policy applies to. If empty, the
+	// policy applies to every role.
+	Roles     RoleSpecList
+	Using     Expr
+	WithCheck Expr
+}
+
+var _ Statement = &CreatePolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE POLICY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.Type != PolicyTypePermissive {
+		ctx.WriteString(" AS ")
+		ctx.WriteString(node.Type.String())
+	}
+	if node.Command != PolicyCommandAll {
+		ctx.WriteString(" FOR ")
+		ctx.WriteString(node.Command.String())
+	}
+	if len(node.Roles) > 0 {
+		ctx.WriteString(" TO ")
+		ctx.FormatNode(&node.Roles)
+	}
+	if node.Using != nil {
+		ctx.WriteString(" USING (")
+		ctx.FormatNode(node.Using)
+		ctx.WriteString(")")
+	}
+	if node.WithCheck != nil {
+		ctx.WriteString(" WITH CHECK (")
+		ctx.FormatNode(node.WithCheck)
+		ctx.WriteString(")")
+	}
+}
+
+// DropPolicy represents a DROP POLICY statement.
+type DropPolicy struct {
+	IfExists     bool
+	Name         Name
+	Table        *UnresolvedObjectName
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropPolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP POLICY ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteString(" ")
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
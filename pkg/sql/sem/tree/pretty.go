@@ -1647,7 +1647,9 @@ func (node *CreateIndex) doc(p *PrettyCfg) pretty.Doc {
 	if node.Unique {
 		title = append(title, pretty.Keyword("UNIQUE"))
 	}
-	if node.Inverted {
+	if node.Vector {
+		title = append(title, pretty.Keyword("VECTOR"))
+	} else if node.Inverted {
 		title = append(title, pretty.Keyword("INVERTED"))
 	}
 	title = append(title, pretty.Keyword("INDEX"))
@@ -1740,7 +1742,9 @@ func (node *IndexTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.Name != "" {
 		title = pretty.ConcatSpace(title, p.Doc(&node.Name))
 	}
-	if node.Inverted {
+	if node.Vector {
+		title = pretty.ConcatSpace(pretty.Keyword("VECTOR"), title)
+	} else if node.Inverted {
 		title = pretty.ConcatSpace(pretty.Keyword("INVERTED"), title)
 	}
 	title = pretty.ConcatSpace(title, p.bracket("(", p.Doc(&node.Columns), ")"))
@@ -2191,6 +2195,9 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	items = append(items, p.row("RESTORE", pretty.Nil))
 	if node.DescriptorCoverage == RequestedDescriptors {
 		items = append(items, node.Targets.docRow(p))
+		if node.AsTable != nil {
+			items = append(items, p.row("AS", p.Doc(node.AsTable)))
+		}
 	}
 	from := make([]pretty.Doc, len(node.From))
 	for i := range node.From {
@@ -16,6 +16,28 @@ type FullBackupClause struct {
 	Recurrence Expr
 }
 
+// BackupRetentionClause describes which backups of a backup schedule are kept
+// in its collection. Older backups are removed.
+type BackupRetentionClause struct {
+	// FullBackups, if non-zero, is the number of most recent full backups that
+	// are kept, along with their incremental backups.
+	FullBackups int64
+	// Duration, if set, is the interval for which backups are kept.
+	Duration Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *BackupRetentionClause) Format(ctx *FmtCtx) {
+	ctx.WriteString("RETENTION ")
+	if node.Duration != nil {
+		ctx.FormatNode(node.Duration)
+	} else {
+		ctx.Printf("%d FULL BACKUPS", node.FullBackups)
+	}
+}
+
+var _ NodeFormatter = &BackupRetentionClause{}
+
 // LabelSpec describes the labeling specification for an object.
 type LabelSpec struct {
 	IfNotExists bool
@@ -39,8 +61,9 @@ var _ NodeFormatter = &LabelSpec{}
 type ScheduledBackup struct {
 	ScheduleLabelSpec LabelSpec
 	Recurrence        Expr
-	FullBackup        *FullBackupClause /* nil implies choose default */
-	Targets           *BackupTargetList /* nil implies tree.AllDescriptors coverage */
+	FullBackup        *FullBackupClause      /* nil implies choose default */
+	Retention         *BackupRetentionClause /* nil implies keep all backups */
+	Targets           *BackupTargetList      /* nil implies tree.AllDescriptors coverage */
 	To                StringOrPlaceholderOptList
 	BackupOptions     BackupOptions
 	ScheduleOptions   KVOptions
@@ -84,6 +107,11 @@ func (node *ScheduledBackup) Format(ctx *FmtCtx) {
 		}
 	}
 
+	if node.Retention != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Retention)
+	}
+
 	if node.ScheduleOptions != nil {
 		ctx.WriteString(" WITH SCHEDULE OPTIONS ")
 		ctx.FormatNode(&node.ScheduleOptions)
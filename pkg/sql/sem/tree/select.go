@@ -775,11 +775,82 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	prefix := "GROUP BY "
 	for _, n := range *node {
 		ctx.WriteString(prefix)
-		ctx.FormatNode(n)
+		formatGroupingItem(ctx, n)
 		prefix = ", "
 	}
 }
 
+// formatGroupingItem formats an item of a GROUP BY clause or GROUPING SETS.
+// GroupingSets are not expressions in their own right, so they are never
+// enclosed in parentheses.
+func formatGroupingItem(ctx *FmtCtx, e Expr) {
+	if gs, ok := e.(*GroupingSet); ok {
+		gs.Format(ctx)
+		return
+	}
+	ctx.FormatNode(e)
+}
+
+// GroupingSetType is the type of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// RollupGroupingSet represents ROLLUP (e1, ..., en), which groups by each
+	// prefix of the expressions: (e1, ..., en), ..., (e1), ().
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (e1, ..., en), which groups by every
+	// subset of the expressions.
+	CubeGroupingSet
+	// GroupingSets represents GROUPING SETS (g1, ..., gn), which groups by each
+	// of the given grouping sets.
+	GroupingSets
+)
+
+var groupingSetTypeName = [...]string{
+	RollupGroupingSet: "ROLLUP",
+	CubeGroupingSet:   "CUBE",
+	GroupingSets:      "GROUPING SETS",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. A parenthesized list of expressions in Exprs is parsed as a Tuple,
+// which is treated as a single unit; the empty Tuple is the empty grouping set.
+// The items of a GROUPING SETS can themselves be GroupingSets.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+var _ Expr = &GroupingSet{}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	for i, e := range node.Exprs {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		formatGroupingItem(ctx, e)
+	}
+	ctx.WriteByte(')')
+}
+
+// HasGroupingSets returns true if the GROUP BY clause contains a ROLLUP, CUBE
+// or GROUPING SETS item.
+func (node GroupBy) HasGroupingSets() bool {
+	for _, e := range node {
+		if _, ok := e.(*GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
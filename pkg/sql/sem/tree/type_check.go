@@ -944,6 +944,25 @@ func (expr *CoalesceExpr) TypeCheck(
 	return expr, nil
 }
 
+// TypeCheck implements the Expr interface. GROUPING expressions are replaced
+// by the optimizer when they are used in a query with a GROUP BY clause, so
+// type checking one is always an error.
+func (expr *GroupingExpr) TypeCheck(
+	ctx context.Context, semaCtx *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	if semaCtx != nil && semaCtx.Properties.IsSet(RejectAggregates) {
+		return nil, NewInvalidGroupingUsageError(semaCtx.Properties.Context())
+	}
+	return nil, NewGroupingArgError()
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	ctx context.Context, semaCtx *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgcode.Syntax, "%s is only allowed in GROUP BY", expr.Type)
+}
+
 // TypeCheck implements the Expr interface.
 func (expr *ComparisonExpr) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
@@ -1016,6 +1035,18 @@ func NewAggInAggError() error {
 	return pgerror.Newf(pgcode.Grouping, "aggregate function calls cannot be nested")
 }
 
+// NewInvalidGroupingUsageError creates a rejection for a GROUPING expression.
+func NewInvalidGroupingUsageError(context string) error {
+	return pgerror.Newf(pgcode.Grouping, "grouping operations are not allowed in %s", context)
+}
+
+// NewGroupingArgError creates an error for the case when an argument to
+// GROUPING is not a grouping expression of the query.
+func NewGroupingArgError() error {
+	return pgerror.New(pgcode.Grouping,
+		"arguments to GROUPING must be grouping expressions of the associated query level")
+}
+
 // NewInvalidNestedSRFError creates a rejection for a nested SRF.
 func NewInvalidNestedSRFError(context string) error {
 	return pgerror.Newf(pgcode.FeatureNotSupported,
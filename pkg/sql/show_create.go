@@ -198,6 +198,12 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if err := showRowLevelSecurity(
+		ctx, tn, desc, p.EvalContext(), &p.semaCtx, p.SessionData(), f,
+	); err != nil {
+		return "", err
+	}
+
 	if !displayOptions.IgnoreComments {
 		if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
 			return "", err
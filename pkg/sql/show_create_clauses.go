@@ -468,6 +468,71 @@ func formatFunctionQueryTypesForDisplay(
 
 // showComments prints out the COMMENT statements sufficient to populate a
 // table's comments, including its index and column comments.
+// showRowLevelSecurity appends the ALTER TABLE and CREATE POLICY statements
+// needed to recreate the row-level security of the table.
+func showRowLevelSecurity(
+	ctx context.Context,
+	tn *tree.TableName,
+	table catalog.TableDescriptor,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+	f *tree.FmtCtx,
+) error {
+	un := tn.ToUnresolvedObjectName()
+	showMode := func(mode tree.RowLevelSecurityMode) {
+		f.WriteString(";\n")
+		f.FormatNode(&tree.AlterTable{
+			Table: un,
+			Cmds:  tree.AlterTableCmds{&tree.AlterTableSetRowLevelSecurity{Mode: mode}},
+		})
+	}
+	if table.IsRowLevelSecurityEnabled() {
+		showMode(tree.RowLevelSecurityEnable)
+	}
+	if table.IsRowLevelSecurityForced() {
+		showMode(tree.RowLevelSecurityForce)
+	}
+
+	parseExpr := func(expr string) (tree.Expr, error) {
+		if expr == "" {
+			return nil, nil
+		}
+		displayExpr, err := schemaexpr.FormatExprForDisplay(
+			ctx, table, expr, evalCtx, semaCtx, sessionData, tree.FmtParsable,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return parser.ParseExpr(displayExpr)
+	}
+	for _, policy := range table.GetPolicies() {
+		n := tree.CreatePolicy{
+			Name:    tree.Name(policy.Name),
+			Table:   un,
+			Type:    tree.PolicyType(policy.Type),
+			Command: tree.PolicyCommand(policy.Command),
+		}
+		for _, role := range policy.Roles() {
+			// Policies that apply to everyone are shown without a TO clause.
+			if role.IsPublicRole() && len(policy.RoleProtos) == 1 {
+				break
+			}
+			n.Roles = append(n.Roles, tree.MakeRoleSpecWithRoleName(role.Normalized()))
+		}
+		var err error
+		if n.Using, err = parseExpr(policy.UsingExpr); err != nil {
+			return err
+		}
+		if n.WithCheck, err = parseExpr(policy.WithCheckExpr); err != nil {
+			return err
+		}
+		f.WriteString(";\n")
+		f.FormatNode(&n)
+	}
+	return nil
+}
+
 func showComments(
 	tn *tree.TableName, table catalog.TableDescriptor, tc *tableComments, buf *bytes.Buffer,
 ) error {
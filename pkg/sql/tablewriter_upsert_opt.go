@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 // optTableUpserter implements the upsert operation when it is planned by the
@@ -79,12 +80,22 @@ type optTableUpserter struct {
 	// an update is performed. This column will always be one of the fetchCols.
 	canaryOrdinal int
 
+	// mergeActionOrdinal is the ordinal position of the column within the
+	// input row that is used by a MERGE statement to decide whether to insert,
+	// update, or delete the row. It is -1 for all other statements, in which
+	// case the canary column alone determines the operation.
+	mergeActionOrdinal int
+
 	// resultRow is a reusable slice of Datums used to store result rows.
 	resultRow tree.Datums
 
 	// ru is used when updating rows.
 	ru row.Updater
 
+	// rd is used when deleting rows. It is only initialized if
+	// mergeActionOrdinal is not -1.
+	rd row.Deleter
+
 	// tabColIdxToRetIdx is the mapping from the columns in the table to the
 	// columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the table column at that index is not part of the resultRowBuffer
@@ -175,6 +186,9 @@ func (tu *optTableUpserter) row(
 		// the insert and update columns are the same, so no need to choose).
 		return tu.insertNonConflictingRow(ctx, row[:insertEnd], pm, true /* overwrite */, traceKV)
 	}
+	if tu.mergeActionOrdinal != -1 {
+		return tu.mergeRow(ctx, row, pm, traceKV)
+	}
 	if row[tu.canaryOrdinal] == tree.DNull {
 		// No conflict, so insert a new row.
 		return tu.insertNonConflictingRow(ctx, row[:insertEnd], pm, false /* overwrite */, traceKV)
@@ -202,6 +216,62 @@ func (tu *optTableUpserter) row(
 	)
 }
 
+// mergeRow performs the insert, update, or delete operation chosen by the
+// merge action column of the given MERGE source row. Rows for which no action
+// is taken are filtered out by the optimizer, so they never reach here.
+func (tu *optTableUpserter) mergeRow(
+	ctx context.Context, row tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
+) error {
+	insertEnd := len(tu.ri.InsertCols)
+	fetchEnd := insertEnd + len(tu.fetchCols)
+	updateEnd := fetchEnd + len(tu.updateCols)
+	switch action := tree.MergeActionType(tree.MustBeDInt(row[tu.mergeActionOrdinal])); action {
+	case tree.MergeActionInsert:
+		return tu.insertNonConflictingRow(ctx, row[:insertEnd], pm, false /* overwrite */, traceKV)
+
+	case tree.MergeActionUpdate:
+		return tu.updateConflictingRow(
+			ctx,
+			tu.b,
+			row[insertEnd:fetchEnd],
+			row[fetchEnd:updateEnd],
+			pm,
+			traceKV,
+		)
+
+	case tree.MergeActionDelete:
+		return tu.deleteMatchedRow(ctx, row[insertEnd:fetchEnd], pm, traceKV)
+
+	default:
+		return errors.AssertionFailedf("unexpected merge action %d", action)
+	}
+}
+
+// deleteMatchedRow deletes an existing row from the table that was matched by
+// a MERGE statement. If the RETURNING clause was specified, then the deleted
+// row is stored in the rowsUpserted collection.
+func (tu *optTableUpserter) deleteMatchedRow(
+	ctx context.Context, fetchRow tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
+) error {
+	if err := tu.rd.DeleteRow(ctx, tu.b, fetchRow, pm, traceKV); err != nil {
+		return err
+	}
+
+	if !tu.rowsNeeded {
+		return nil
+	}
+
+	// Map the deleted columns into the result row before adding it.
+	tableRow := tu.makeResultFromRow(fetchRow, tu.rd.FetchColIDtoRowIndex)
+	for tabIdx := range tableRow {
+		if retIdx := tu.tabColIdxToRetIdx[tabIdx]; retIdx >= 0 {
+			tu.resultRow[retIdx] = tableRow[tabIdx]
+		}
+	}
+	_, err := tu.rows.AddRow(ctx, tu.resultRow)
+	return err
+}
+
 // insertNonConflictingRow inserts the given source row into the table when
 // there was no conflict. If the RETURNING clause was specified, then the
 // inserted row is stored in the rowsUpserted collection.
@@ -13,17 +13,24 @@ package sql
 import (
 	"context"
 
-	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
+// Unlisten unsubscribes the session from a channel, or from every channel,
+// once the current transaction commits.
+// Privileges: None.
+//
+//	Notes: postgres requires no privileges.
 func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
-	p.BufferClientNotice(ctx,
-		pgerror.WithSeverity(
-			unimplemented.NewWithIssuef(41522, "CRDB does not support LISTEN, making UNLISTEN a no-op"),
-			"NOTICE",
-		),
-	)
+	var channel string
+	if !n.Star {
+		var err error
+		if channel, err = notifyChannelName(n.ChannelName); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.notifications.unlisten(channel); err != nil {
+		return nil, err
+	}
 	return newZeroNode(nil /* columns */), nil
 }
@@ -138,8 +138,16 @@ func (n *upsertNode) BatchedNext(params runParams) (bool, error) {
 // processSourceRow processes one row from the source for upsertion.
 // The table writer is in charge of accumulating the result rows.
 func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) error {
-	if err := enforceLocalColumnConstraints(rowVals, n.run.insertCols); err != nil {
-		return err
+	// A MERGE statement only provides insert values for rows that it inserts,
+	// and it does not write any new values for rows that it deletes.
+	mergeAction := tree.MergeActionInsert
+	if n.run.tw.mergeActionOrdinal != -1 {
+		mergeAction = tree.MergeActionType(tree.MustBeDInt(rowVals[n.run.tw.mergeActionOrdinal]))
+	}
+	if mergeAction == tree.MergeActionInsert {
+		if err := enforceLocalColumnConstraints(rowVals, n.run.insertCols); err != nil {
+			return err
+		}
 	}
 
 	// Create a set of partial index IDs to not add or remove entries from.
@@ -149,6 +157,9 @@ func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) err
 		if n.run.tw.canaryOrdinal != -1 {
 			offset++
 		}
+		if n.run.tw.mergeActionOrdinal != -1 {
+			offset++
+		}
 		partialIndexVals := rowVals[offset:]
 		partialIndexPutVals := partialIndexVals[:numPartialIndexes]
 		partialIndexDelVals := partialIndexVals[numPartialIndexes : numPartialIndexes*2]
@@ -170,12 +181,17 @@ func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) err
 		if n.run.tw.canaryOrdinal != -1 {
 			ord++
 		}
-		checkVals := rowVals[ord:]
-		if err := checkMutationInput(
-			params.ctx, params.p.EvalContext(), &params.p.semaCtx, params.p.SessionData(),
-			n.run.tw.tableDesc(), n.run.checkOrds, checkVals,
-		); err != nil {
-			return err
+		if n.run.tw.mergeActionOrdinal != -1 {
+			ord++
+		}
+		if mergeAction != tree.MergeActionDelete {
+			checkVals := rowVals[ord:]
+			if err := checkMutationInput(
+				params.ctx, params.p.EvalContext(), &params.p.semaCtx, params.p.SessionData(),
+				n.run.tw.tableDesc(), n.run.checkOrds, checkVals,
+			); err != nil {
+				return err
+			}
 		}
 		rowVals = rowVals[:ord]
 	}
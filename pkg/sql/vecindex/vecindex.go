@@ -0,0 +1,292 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package vecindex implements the structure of vector indexes, which speed up
+// nearest neighbor searches over vector columns.
+//
+// A vector index is an inverted index on a vector column. The vector space is
+// divided into partitions by a set of random hyperplanes through the origin:
+// the partition of a vector is given by the sides of the hyperplanes that it
+// lies on. The hyperplanes are generated when the index is created and stored
+// in its descriptor, so every node assigns a vector to the same partition.
+// Each row is stored in the index under the key of the partition of its
+// vector, so the index is maintained transactionally by the regular index
+// write path and backfilled by the regular index backfiller.
+//
+// Vectors that are close to each other are likely to lie in the same
+// partition, or in partitions that differ only by hyperplanes that both
+// vectors are close to. A nearest neighbor search therefore scans the
+// partitions that are most likely to contain the neighbors of the query vector
+// (see Partitioner.SearchPartitions) and ranks the rows it finds by their
+// exact distance to the query vector. The search is approximate: scanning more
+// partitions increases the recall of the search at the expense of its cost.
+package vecindex
+
+import (
+	"container/heap"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/cockroachdb/errors"
+)
+
+// DefaultLists is the default number of partitions of a vector index.
+const DefaultLists = 64
+
+// MaxLists is the maximum number of partitions of a vector index.
+const MaxLists = 1 << 16
+
+// DefaultProbes is the default number of partitions that are scanned by a
+// nearest neighbor search.
+const DefaultProbes = 4
+
+// MakeConfig returns the configuration of a vector index over vectors with the
+// given number of dimensions, which has the given number of partitions. The
+// hyperplanes that divide the vector space into partitions are generated
+// randomly.
+func MakeConfig(dims int32, lists int64) (catpb.VectorIndexConfig, error) {
+	if dims <= 0 {
+		return catpb.VectorIndexConfig{}, pgerror.New(pgcode.InvalidParameterValue,
+			"column does not have dimensions")
+	}
+	if lists < 1 || lists > MaxLists {
+		return catpb.VectorIndexConfig{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"lists must be between 1 and %d", MaxLists)
+	}
+	// Generate the normals of the hyperplanes from a standard normal
+	// distribution, so that their directions are uniformly distributed.
+	hyperplanes := make([]float32, partitionBits(lists)*int(dims))
+	for i := range hyperplanes {
+		hyperplanes[i] = float32(rand.NormFloat64())
+	}
+	return catpb.VectorIndexConfig{
+		Dims:        dims,
+		Lists:       lists,
+		Hyperplanes: hyperplanes,
+	}, nil
+}
+
+// ValidateConfig returns an error if the given vector index configuration is
+// not valid.
+func ValidateConfig(config catpb.VectorIndexConfig) error {
+	if config.Dims <= 0 {
+		return errors.Newf("invalid number of dimensions %d", config.Dims)
+	}
+	if config.Lists < 1 || config.Lists > MaxLists {
+		return errors.Newf("invalid number of lists %d", config.Lists)
+	}
+	if expected := partitionBits(config.Lists) * int(config.Dims); len(config.Hyperplanes) != expected {
+		return errors.Newf("expected %d hyperplane coefficients, found %d",
+			expected, len(config.Hyperplanes))
+	}
+	return nil
+}
+
+// Lists returns the number of partitions of the vector index with the given
+// configuration.
+func Lists(config catpb.VectorIndexConfig) int64 {
+	return config.Lists
+}
+
+// partitionBits returns the number of hyperplanes needed to divide the vector
+// space into the given number of partitions.
+func partitionBits(lists int64) int {
+	return bits.Len64(uint64(lists - 1))
+}
+
+// EncodeKey appends the inverted index key of the given partition to the given
+// byte slice.
+func EncodeKey(appendTo []byte, partition uint64) []byte {
+	return encoding.EncodeUvarintAscending(appendTo, partition)
+}
+
+// Partitioner assigns vectors to the partitions of a vector index.
+type Partitioner struct {
+	config catpb.VectorIndexConfig
+}
+
+// MakePartitioner returns the Partitioner for the given vector index
+// configuration. It does not copy the hyperplanes of the configuration, so it
+// is cheap enough to call for every vector.
+func MakePartitioner(config catpb.VectorIndexConfig) Partitioner {
+	return Partitioner{config: config}
+}
+
+// project returns the projections of the given vector onto the normals of the
+// hyperplanes.
+func (p Partitioner) project(v vector.T) ([]float64, error) {
+	if len(v) != int(p.config.Dims) {
+		return nil, pgerror.Newf(pgcode.DataException,
+			"expected %d dimensions, not %d", p.config.Dims, len(v))
+	}
+	dims := int(p.config.Dims)
+	projections := make([]float64, len(p.config.Hyperplanes)/dims)
+	for i := range projections {
+		h := p.config.Hyperplanes[i*dims : (i+1)*dims]
+		var dot float64
+		for j := range h {
+			dot += float64(h[j]) * float64(v[j])
+		}
+		projections[i] = dot
+	}
+	return projections, nil
+}
+
+// cell returns the cell of the vector space, given by the sides of all the
+// hyperplanes, that corresponds to the given projections.
+func cell(projections []float64) uint64 {
+	var c uint64
+	for i, dot := range projections {
+		if dot >= 0 {
+			c |= 1 << i
+		}
+	}
+	return c
+}
+
+// partitionOf returns the partition that contains the given cell. The
+// hyperplanes divide the vector space into the smallest power of two cells
+// that is at least the number of partitions. If the number of partitions is
+// not a power of two, the cells past the last partition are merged with the
+// cells on the other side of the last hyperplane, whose partition is given by
+// the other hyperplanes alone.
+func (p Partitioner) partitionOf(c uint64) uint64 {
+	if c >= uint64(p.config.Lists) {
+		c &^= 1 << (partitionBits(p.config.Lists) - 1)
+	}
+	return c
+}
+
+// Partition returns the partition that the given vector belongs to.
+func (p Partitioner) Partition(v vector.T) (uint64, error) {
+	projections, err := p.project(v)
+	if err != nil {
+		return 0, err
+	}
+	return p.partitionOf(cell(projections)), nil
+}
+
+// SearchPartitions returns the given number of partitions that are most likely
+// to contain the nearest neighbors of the given query vector, in decreasing
+// order of likelihood. The first partition is the partition of the query
+// vector itself.
+//
+// The partitions are ranked by the distance of the query vector to the
+// hyperplanes that separate them from the query vector's partition: a
+// partition on the other side of hyperplanes that the query vector is close to
+// is more likely to contain its neighbors. A partition that is made of several
+// cells (see partitionOf) is ranked by its closest cell. This is the
+// multi-probe scheme described in "Multi-Probe LSH: Efficient Indexing for
+// High-Dimensional Similarity Search" by Lv et al.
+func (p Partitioner) SearchPartitions(q vector.T, probes int) ([]uint64, error) {
+	projections, err := p.project(q)
+	if err != nil {
+		return nil, err
+	}
+	if lists := int(Lists(p.config)); probes > lists {
+		probes = lists
+	}
+	if probes < 1 {
+		probes = 1
+	}
+
+	// Order the hyperplanes by their distance to the query vector.
+	order := make([]int, len(projections))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return math.Abs(projections[order[i]]) < math.Abs(projections[order[j]])
+	})
+	margins := make([]float64, len(order))
+	for i, hyperplane := range order {
+		margins[i] = math.Abs(projections[hyperplane])
+	}
+
+	// Generate the sets of hyperplanes to cross, starting from the cell of the
+	// query vector, in increasing order of their total distance to the query
+	// vector. Each set is a sorted list of
+	// positions in order. Every set is generated exactly once, from the set
+	// without its largest position, by either replacing its largest position
+	// with the next position ("shift") or by adding the next position
+	// ("expand"). Since several cells can belong to the same partition, the
+	// partitions of the cells are deduplicated.
+	base := cell(projections)
+	partitions := make([]uint64, 0, probes)
+	partitions = append(partitions, p.partitionOf(base))
+	seen := map[uint64]struct{}{partitions[0]: {}}
+	var h probeHeap
+	if len(margins) > 0 {
+		heap.Push(&h, probeSet{positions: []int{0}, score: margins[0]})
+	}
+	for len(partitions) < probes && h.Len() > 0 {
+		set := heap.Pop(&h).(probeSet)
+		c := base
+		for _, pos := range set.positions {
+			c ^= 1 << order[pos]
+		}
+		if partition := p.partitionOf(c); !isSeen(seen, partition) {
+			partitions = append(partitions, partition)
+		}
+
+		last := set.positions[len(set.positions)-1]
+		if next := last + 1; next < len(margins) {
+			shifted := append([]int(nil), set.positions...)
+			shifted[len(shifted)-1] = next
+			heap.Push(&h, probeSet{
+				positions: shifted, score: set.score - margins[last] + margins[next],
+			})
+			expanded := append(append([]int(nil), set.positions...), next)
+			heap.Push(&h, probeSet{positions: expanded, score: set.score + margins[next]})
+		}
+	}
+	return partitions, nil
+}
+
+// isSeen returns whether the given partition is in the given set, and adds it
+// to the set.
+func isSeen(seen map[uint64]struct{}, partition uint64) bool {
+	if _, ok := seen[partition]; ok {
+		return true
+	}
+	seen[partition] = struct{}{}
+	return false
+}
+
+// probeSet is a set of hyperplanes to cross, identified by their positions in
+// the order of increasing distance to the query vector.
+type probeSet struct {
+	positions []int
+	score     float64
+}
+
+// probeHeap is a min-heap of probe sets ordered by score.
+type probeHeap []probeSet
+
+func (h probeHeap) Len() int           { return len(h) }
+func (h probeHeap) Less(i, j int) bool { return h[i].score < h[j].score }
+func (h probeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *probeHeap) Push(x interface{}) { *h = append(*h, x.(probeSet)) }
+
+func (h *probeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
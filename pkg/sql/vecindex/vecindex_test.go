@@ -0,0 +1,159 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package vecindex
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/cockroachdb/cockroach/pkg/util/vector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeConfig(t *testing.T) {
+	testCases := []struct {
+		lists       int64
+		hyperplanes int
+	}{
+		{lists: 1, hyperplanes: 0},
+		{lists: 2, hyperplanes: 1},
+		{lists: 3, hyperplanes: 2},
+		{lists: 64, hyperplanes: 6},
+		{lists: 100, hyperplanes: 7},
+		{lists: MaxLists, hyperplanes: 16},
+	}
+	for _, tc := range testCases {
+		config, err := MakeConfig(3, tc.lists)
+		require.NoError(t, err)
+		require.Equal(t, tc.lists, Lists(config))
+		require.Len(t, config.Hyperplanes, tc.hyperplanes*3)
+		require.NoError(t, ValidateConfig(config))
+	}
+
+	_, err := MakeConfig(0, DefaultLists)
+	require.Error(t, err)
+	_, err = MakeConfig(3, 0)
+	require.Error(t, err)
+	_, err = MakeConfig(3, MaxLists+1)
+	require.Error(t, err)
+
+	config, err := MakeConfig(3, DefaultLists)
+	require.NoError(t, err)
+	config.Hyperplanes = config.Hyperplanes[1:]
+	require.Error(t, ValidateConfig(config))
+}
+
+func randomVector(rng interface{ NormFloat64() float64 }, dims int) vector.T {
+	v := make(vector.T, dims)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+func TestPartition(t *testing.T) {
+	rng, _ := randutil.NewTestRand()
+	config, err := MakeConfig(8, 16)
+	require.NoError(t, err)
+	p := MakePartitioner(config)
+
+	for i := 0; i < 100; i++ {
+		v := randomVector(rng, 8)
+		partition, err := p.Partition(v)
+		require.NoError(t, err)
+		require.Less(t, partition, uint64(Lists(config)))
+
+		// Partitions are defined by hyperplanes through the origin, so scaling
+		// a vector does not change its partition.
+		scaled := make(vector.T, len(v))
+		for j := range v {
+			scaled[j] = v[j] * 3
+		}
+		scaledPartition, err := p.Partition(scaled)
+		require.NoError(t, err)
+		require.Equal(t, partition, scaledPartition)
+	}
+
+	_, err = p.Partition(vector.T{1, 2})
+	require.Error(t, err)
+}
+
+func TestSearchPartitions(t *testing.T) {
+	rng, _ := randutil.NewTestRand()
+	config, err := MakeConfig(8, 32)
+	require.NoError(t, err)
+	p := MakePartitioner(config)
+
+	for i := 0; i < 100; i++ {
+		q := randomVector(rng, 8)
+		projections, err := p.project(q)
+		require.NoError(t, err)
+		base := cell(projections)
+
+		// score returns the sum of the distances of the query vector to the
+		// hyperplanes that separate the given partition from its own.
+		score := func(partition uint64) float64 {
+			var s float64
+			for j, dot := range projections {
+				if (partition^base)&(1<<j) != 0 {
+					s += math.Abs(dot)
+				}
+			}
+			return s
+		}
+
+		probes := 1 + rng.Intn(int(Lists(config))+4)
+		partitions, err := p.SearchPartitions(q, probes)
+		require.NoError(t, err)
+		expected := probes
+		if lists := int(Lists(config)); expected > lists {
+			expected = lists
+		}
+		require.Len(t, partitions, expected)
+		require.Equal(t, base, partitions[0])
+
+		seen := make(map[uint64]bool)
+		for j, partition := range partitions {
+			require.Less(t, partition, uint64(Lists(config)))
+			require.False(t, seen[partition], "partition %d returned twice", partition)
+			seen[partition] = true
+			if j > 0 {
+				require.LessOrEqual(t, score(partitions[j-1]), score(partition)+1e-9)
+			}
+		}
+	}
+}
+
+// TestPartitionsNotPowerOfTwo tests that a vector index whose number of
+// partitions is not a power of two uses every partition, and that its searches
+// return each partition once.
+func TestPartitionsNotPowerOfTwo(t *testing.T) {
+	rng, _ := randutil.NewTestRand()
+	config, err := MakeConfig(4, 5)
+	require.NoError(t, err)
+	p := MakePartitioner(config)
+
+	used := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		v := randomVector(rng, 4)
+		partition, err := p.Partition(v)
+		require.NoError(t, err)
+		require.Less(t, partition, uint64(5))
+		used[partition] = true
+
+		partitions, err := p.SearchPartitions(v, 5)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint64{0, 1, 2, 3, 4}, partitions)
+		require.Equal(t, partition, partitions[0])
+	}
+	require.Len(t, used, 5)
+}
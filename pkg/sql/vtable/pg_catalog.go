@@ -1254,7 +1254,8 @@ CREATE TABLE pg_catalog.pg_stat_gssapi (
 	encrypted BOOL
 )`
 
-// PgCatalogPolicies is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPolicies describes the schema of the pg_catalog.pg_policies table.
+// https://www.postgresql.org/docs/9.5/view-pg-policies.html
 const PgCatalogPolicies = `
 CREATE TABLE pg_catalog.pg_policies (
 	schemaname NAME,
@@ -1501,7 +1502,8 @@ CREATE TABLE pg_catalog.pg_stat_progress_basebackup (
 	tablespaces_streamed INT
 )`
 
-// PgCatalogPolicy is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPolicy describes the schema of the pg_catalog.pg_policy table.
+// https://www.postgresql.org/docs/9.5/catalog-pg-policy.html
 const PgCatalogPolicy = `
 CREATE TABLE pg_catalog.pg_policy (
 	oid OID,